@@ -38,7 +38,7 @@ func (ap *AssetProperties) Bytes() ([]byte, error) {
 type AgreementToSell struct {
 	TradeID string        `json:"trade_id"`
 	ID      string        `json:"asset_id"`
-	Price   int           `json:"price"`
+	Price   int           `state:"private" collection:"prices" json:"price"`
 	Owner   view.Identity `json:"owner"`
 }
 
@@ -53,7 +53,7 @@ func (a *AgreementToSell) Owners() state.Identities {
 type AgreementToBuy struct {
 	TradeID string        `json:"trade_id"`
 	ID      string        `json:"asset_id"`
-	Price   int           `json:"price"`
+	Price   int           `state:"private" collection:"prices" json:"price"`
 	Owner   view.Identity `json:"owner"`
 }
 
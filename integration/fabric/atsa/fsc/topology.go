@@ -20,7 +20,8 @@ func Topology() []api.Topology {
 	fabricTopology.AddOrganizationsByName("Org1", "Org2", "Org3")
 	// Deploy a dummy chaincode to setup the namespace
 	fabricTopology.SetNamespaceApproverOrgs("Org1")
-	fabricTopology.AddNamespaceWithUnanimity("asset_transfer", "Org1").SetStateChaincode()
+	// The prices of the agreements are shared among the asset owners only
+	fabricTopology.AddNamespaceWithUnanimity("asset_transfer", "Org1").SetStateChaincode().AddCollection("prices", "Org2")
 
 	// Create an empty FSC topology
 	fscTopology := fsc.NewTopology()
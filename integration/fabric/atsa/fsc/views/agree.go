@@ -61,9 +61,13 @@ func (a *AgreeToSellView) Call(context view.Context) (interface{}, error) {
 	// The asset owner is ready to collect all the required signatures.
 	// Namely from the asset owner itself and the approver. In this order.
 	// All signatures are required.
-	_, err = context.RunView(state.NewCollectEndorsementsView(tx, asset.Owner, a.Approver))
+	_, err = context.RunView(state.NewCollectEndorsementsView(tx, asset.Owner))
 	assert.NoError(err, "failed collecting endorsement")
 
+	// The approver does not see the price, it is kept in a private data collection
+	_, err = context.RunView(state.NewCollectApprovesView(tx, a.Approver))
+	assert.NoError(err, "failed collecting approves")
+
 	// Send to the ordering service and wait for confirmation
 	_, err = context.RunView(state.NewOrderingAndFinalityView(tx))
 	assert.NoError(err, "failed asking ordering")
@@ -271,6 +271,10 @@ func (n *Network) DeployChaincode(chaincode *topology.ChannelChaincode) {
 		PackageChaincode(n, &chaincode.Chaincode, peers[0])
 	}
 
+	if len(chaincode.Collections) != 0 && len(chaincode.Chaincode.CollectionsConfig) == 0 {
+		chaincode.Chaincode.CollectionsConfig = n.WriteCollectionsConfig(chaincode)
+	}
+
 	PackageAndInstallChaincode(n, &chaincode.Chaincode, peers...)
 	ApproveChaincodeForMyOrg(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers...)
 	CheckCommitReadinessUntilReady(n, chaincode.Channel, &chaincode.Chaincode, n.PeerOrgsByPeers(peers), peers...)
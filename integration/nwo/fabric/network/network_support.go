@@ -8,6 +8,7 @@ package network
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -136,6 +137,38 @@ func (n *Network) WriteOrdererConfig(o *topology.Orderer, config *fabricconfig.O
 	Expect(err).NotTo(HaveOccurred())
 }
 
+// WriteCollectionsConfig writes the collections configuration of the passed chaincode, in the format
+// expected by the peer CLI, and returns its path
+func (n *Network) WriteCollectionsConfig(chaincode *topology.ChannelChaincode) string {
+	type collectionConfig struct {
+		Name              string `json:"name"`
+		Policy            string `json:"policy"`
+		RequiredPeerCount int32  `json:"requiredPeerCount"`
+		MaxPeerCount      int32  `json:"maxPeerCount"`
+		BlockToLive       uint64 `json:"blockToLive"`
+		MemberOnlyRead    bool   `json:"memberOnlyRead"`
+		MemberOnlyWrite   bool   `json:"memberOnlyWrite"`
+	}
+	var configs []collectionConfig
+	for _, collection := range chaincode.Collections {
+		var members []string
+		for _, org := range collection.Organizations {
+			members = append(members, "'"+n.Organization(org).MSPID+".member'")
+		}
+		configs = append(configs, collectionConfig{
+			Name:   collection.Name,
+			Policy: "OR(" + strings.Join(members, ",") + ")",
+		})
+	}
+	raw, err := json.MarshalIndent(configs, "", "  ")
+	Expect(err).NotTo(HaveOccurred())
+
+	path := filepath.Join(n.Context.RootDir(), n.Prefix, chaincode.Chaincode.Name+"_collections.json")
+	err = ioutil.WriteFile(path, raw, 0644)
+	Expect(err).NotTo(HaveOccurred())
+	return path
+}
+
 // ReadConfigTxConfig  unmarshals the configtx.yaml and returns an
 // object approximating its contents.
 func (n *Network) ReadConfigTxConfig() *fabricconfig.ConfigTx {
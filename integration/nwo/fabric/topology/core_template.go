@@ -287,6 +287,10 @@ fabric:
         tlsRootCertFile: {{ CACertsBundlePath }}
         serverNameOverride:
     {{- end }} 
+    ordering:
+      selection: roundrobin
+      numRetries: 3
+      retryInterval: 500ms
     peers: {{ range Peers }}
       - address: {{ PeerAddress . "Listen" }}
         connectionTimeout: 10s
@@ -298,8 +302,17 @@ fabric:
       - name: {{ .Name }}
         default: {{ .Default }}
     {{- end }}
+    delivery:
+      mode: full
+    finality:
+      # Number of recently finalized transactions answered without waiting for the peers
+      cacheSize: 1000
+      # How long a remote party is given to notify the finality of a transaction
+      partyTimeout: 30s
     vault:
       persistence:
+        # Persistence type can be \'file\', \'memory\' or \'sql\'.
+        # The sql persistence type requires the driver, postgres or sqlite, and the dataSource opts
         type: file
         opts:
           path: {{ FSCNodeVaultPath }}
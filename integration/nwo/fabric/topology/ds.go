@@ -82,10 +82,17 @@ func (o Orderer) ID() string {
 }
 
 type ChannelChaincode struct {
-	Chaincode Chaincode `yaml:"chaincode,omitempty"`
-	Path      string    `yaml:"path,omitempty"`
-	Channel   string    `yaml:"channel,omitempty"`
-	Peers     []string  `yaml:"peers,omitempty"`
+	Chaincode   Chaincode     `yaml:"chaincode,omitempty"`
+	Path        string        `yaml:"path,omitempty"`
+	Channel     string        `yaml:"channel,omitempty"`
+	Peers       []string      `yaml:"peers,omitempty"`
+	Collections []*Collection `yaml:"collections,omitempty"`
+}
+
+// Collection defines a private data collection of a chaincode, shared among the members of the listed organizations.
+type Collection struct {
+	Name          string   `yaml:"name,omitempty"`
+	Organizations []string `yaml:"organizations,omitempty"`
 }
 
 type Policy struct {
@@ -360,3 +360,9 @@ func (n *namespace) SetStateChaincode() *namespace {
 	n.cc.Chaincode.Path = "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/state/cc/query"
 	return n
 }
+
+// AddCollection adds to the namespace a private data collection whose members are the passed organizations
+func (n *namespace) AddCollection(name string, orgs ...string) *namespace {
+	n.cc.Collections = append(n.cc.Collections, &Collection{Name: name, Organizations: orgs})
+	return n
+}
@@ -36,12 +36,57 @@ fsc:
     # Private key matching the X.509 certificate
     key:
       file: {{ .NodeLocalPrivateKeyPath Peer }}
+    # CRL files, or folders of CRL files, checked together with the CRLs in the Fabric channel configurations.
+    # They are read again, with the renewed credentials, by the view 'fsc.identity.reload'.
+    # crls:
+    # - path/to/crls
+    # How long before their expiration the certificates of this node are reported, 720h by default
+    # expiryWarning: 720h
+  # Key management of the private keys of the node identity and of the Fabric identities.
+  # When not set, the private keys are read in plaintext.
+  # kms:
+  #   # default applies to the identities without their own entry
+  #   default:
+  #     # file: the keys are read from the configured files, in plaintext or encrypted with 'node kms encrypt'
+  #     # pkcs11: the keys are in a PKCS#11 token, looked up by SKI. It requires the pkcs11 build tag.
+  #     type: file
+  #     # the password, or the key encryption key, the keys are encrypted with
+  #     passwordFile: path/to/password
+  #     # kekFile: path/to/kek
+  #     # fail on the keys not encrypted
+  #     rejectPlaintext: true
+  #   # identities selects the key store of each identity by label.
+  #   # 'fsc' is the node identity, the others are the ids of the Fabric MSPs ('default' for the local MSP).
+  #   identities:
+  #     fsc:
+  #       type: pkcs11
+  #       pkcs11:
+  #         library: /usr/lib/softhsm/libsofthsm2.so
+  #         label: fsc
+  #         pin: 98765432
   # Admin X.509 certificates
   admin:
     certs:
     {{- range Peer.Admins }}
     - {{ . }} 
     {{- end }}
+  # View access control.
+  # The default identity and the admins can initiate any view. Each policy allows the listed principals
+  # to initiate the listed views as well. A principal is an identity or an MSP ID, optionally restricted to an OU role.
+  # Commands that do not initiate a view, such as tracking and listing views, are reserved to the node and the admins.
+  # The same rules apply to the REST API, whose callers sign each request with their identity.
+  # view:
+  #   acl:
+  #     msps:
+  #     - id: Org1MSP
+  #       rootCerts:
+  #       - path/to/ca.pem
+  #     policies:
+  #     - views: [ "pay", "*" ]
+  #       principals:
+  #       - mspID: Org1MSP
+  #         ou: client
+  #       - identity: path/to/client.pem
   # TLS Settings
   # (We use here the same set of properties as Hyperledger Fabric)
   tls:
@@ -89,22 +134,101 @@ fsc:
     timeout: 600s
   # P2P configuration
   p2p:
+    # Transport type can be \'libp2p\' (default) or \'grpc\'.
+    # The grpc transport reaches the nodes directly at the P2P address of their resolvers, with the TLS settings
+    # of the grpc server, the bootstrap node is not used. It requires authentication to be enabled.
+    # type: libp2p
     # Listening address
     listenAddress: /ip4/127.0.0.1/tcp/{{ .NodePort Peer "P2P" }}
     # If empty, this is a P2P boostrap node. Otherwise, it contains the name of the FCS node that is a bootstrap node
     bootstrapNode: {{ .BootstrapNode Peer }}
+    authentication:
+      # If true, the nodes prove the control of their identity with a handshake when a session opens, then every
+      # message exchanged on the session is signed by the identity of the sending node, with a counter against replays.
+      # Messages not signed by an identity bound to the sender are dropped. All the nodes must enable it.
+      enabled: false
+    # Largest payload, in bytes, a session sends or receives
+    maxMessageSize: 67108864
+    # Payloads larger than chunkSize bytes are split into chunks of at most this size
+    chunkSize: 524288
+    # Number of chunked payloads a stream reassembles at once, the payloads started beyond that are dropped
+    maxPendingChunked: 64
+    # Time an incomplete chunked payload waits for its next chunk before being dropped
+    chunkTimeout: 60s
+    session:
+      # Number of received messages a session buffers before its overflow policy applies
+      queueSize: 100
+      # Overflow policy can be \'drop\' (the message is dropped) or \'close\' (the session is closed).
+      # Messages never wait for room, so that a session nobody reads does not stall the others
+      overflow: drop
   # The Key-Value Store is used to store various information related to the FSC node
   kvs:
     persistence:
-      # Persistence type can be \'badger\' (on disk) or \'memory\'
+      # Persistence type can be \'badger\' (on disk), \'memory\' or \'sql\'.
+      # The sql persistence type requires the driver, postgres or sqlite, and the dataSource opts
       type: badger
       opts:
         path: {{ NodeKVSPath }}
+        # The sql persistence type stores the kvs in the database identified by dataSource,
+        # a connection string for postgres or a file path for sqlite
+        # driver: postgres
+        # dataSource: host=localhost port=5432 user=fsc dbname=fsc sslmode=disable
+  # The view tracker keeps the statuses of the terminated views for this long (default 24h)
+  # tracker:
+  #   retention: 24h
   # HTML Server configuration for REST calls
   web:
     enabled: true
     # HTTPS server listener address
     address: 127.0.0.1:{{ .NodePort Peer "Web" }}
+  # The operations endpoint serves /metrics, /healthz, /logspec and /version over HTTP
+  # operations:
+  #   enabled: true
+  #   listenAddress: 127.0.0.1:9443
+  #   # prometheus or disabled
+  #   metrics:
+  #     provider: prometheus
+  #   # When enabled, the clients must present a certificate issued by one of the clientRootCAs
+  #   tls:
+  #     enabled: false
+  #     cert:
+  #       file: path/to/tls/server.crt
+  #     key:
+  #       file: path/to/tls/server.key
+  #     clientRootCAs:
+  #       files:
+  #       - path/to/tls/ca.crt
+  # Tracing records a span for each view, chaincode call, orderer broadcast and finality wait.
+  # The span context travels with the p2p messages, the responders on the other nodes become child spans.
+  # tracing:
+  #   # none, memory or file. The memory exporter is meant for tests.
+  #   exporter: file
+  #   # File the spans are appended to, one JSON object per span
+  #   file: path/to/traces.json
+  #   # Fraction of the traces started by this node that are recorded
+  #   sampling:
+  #     ratio: 1
+  # The discovery service lets nodes publish signed endpoint records at a registry node, and look up at runtime
+  # the nodes that are not among the endpoint resolvers below.
+  discovery:
+    enabled: false
+    # Name of the registry node, it must be among the endpoint resolvers. If empty, this is the registry node.
+    # The registry node drops the authenticated p2p messages of the nodes it cannot resolve: if p2p authentication
+    # is enabled, the registry node must list the publishing nodes among its resolvers.
+    # registry: fsc-registry
+    # Validity of the published records, they are refreshed after half of it
+    ttl: 10m
+    # Timeout of the requests to the registry node
+    timeout: 30s
+    # PEM files of the CA certificates the identities of the records must chain to. The name and the aliases of
+    # a record must be the common name or DNS names of the certificate of its identity.
+    # trustedRoots:
+    #   - path/to/ca.pem
+    # Addresses published in the record of this node. If empty, the node only looks up the other nodes.
+    # addresses:
+    #   P2P: 127.0.0.1:{{ .NodePort Peer "P2P" }}
+    #   Listen: 127.0.0.1:{{ .NodePort Peer "Listen" }}
+    # aliases: []
   # The endpoint section tells how to reach other FSC node in the network.
   # For each node, the name, the domain, the identity of the node, and its addresses must be specified.
   endpoint:
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver/file"
+)
+
+// Cmd returns the Cobra Command for the key management
+func Cmd() *cobra.Command {
+	cobraCommand := &cobra.Command{
+		Use:   "kms",
+		Short: "Manage the private keys.",
+		Long:  `Manage the private keys of the node and of its Fabric identities.`,
+	}
+	cobraCommand.AddCommand(encryptCmd())
+	return cobraCommand
+}
+
+func encryptCmd() *cobra.Command {
+	var keyFile, outputFile, passwordFile, kekFile string
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt a private key.",
+		Long:  `Encrypt a private key with a password or a key encryption key, for the file key store.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("trailing args detected")
+			}
+			if len(keyFile) == 0 {
+				return errors.New("no key file passed")
+			}
+			// Parsing of the command line is done so silence cmd usage
+			cmd.SilenceUsage = true
+
+			var password, kek []byte
+			if len(passwordFile) != 0 {
+				raw, err := ioutil.ReadFile(passwordFile)
+				if err != nil {
+					return errors.Wrapf(err, "failed reading password file [%s]", passwordFile)
+				}
+				password = bytes.TrimSpace(raw)
+			}
+			if len(kekFile) != 0 {
+				var err error
+				kek, err = file.LoadKEK(kekFile)
+				if err != nil {
+					return err
+				}
+			}
+			keyPEM, err := ioutil.ReadFile(keyFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed reading key file [%s]", keyFile)
+			}
+			encrypted, err := file.Encrypt(keyPEM, password, kek)
+			if err != nil {
+				return err
+			}
+			if len(outputFile) == 0 {
+				outputFile = keyFile
+			}
+			return ioutil.WriteFile(outputFile, encrypted, 0600)
+		},
+	}
+	cmd.Flags().StringVarP(&keyFile, "key", "k", "", "the PEM file of the private key to encrypt")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "the file the encrypted key is written to, the key file if empty")
+	cmd.Flags().StringVarP(&passwordFile, "password-file", "p", "", "the file containing the password")
+	cmd.Flags().StringVarP(&kekFile, "kek-file", "e", "", "the file containing the key encryption key, 32 bytes raw or hex encoded")
+	return cmd
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/hyperledger-labs/fabric-smart-client/node/kms"
 	node2 "github.com/hyperledger-labs/fabric-smart-client/node/node"
 	"github.com/hyperledger-labs/fabric-smart-client/node/version"
 	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
@@ -65,6 +66,7 @@ func NewFromConfPath(confPath string) *node {
 	mainFlags.MarkHidden("logging-level")
 
 	mainCmd.AddCommand(version.Cmd())
+	mainCmd.AddCommand(kms.Cmd())
 	mainCmd.AddCommand(node2.Cmd(node))
 
 	return node
@@ -11,6 +11,8 @@ import (
 	"runtime"
 
 	"github.com/spf13/cobra"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 )
 
 // ProgramName is the program name
@@ -38,8 +40,8 @@ var cobraCommand = &cobra.Command{
 
 // GetInfo returns version information for the peer
 func GetInfo() string {
-	return fmt.Sprintf("%s:\n Go version: %s\n"+
+	return fmt.Sprintf("%s:\n Version: %s\n Commit SHA: %s\n Go version: %s\n"+
 		" OS/Arch: %s\n",
-		ProgramName, runtime.Version(),
+		ProgramName, operations.Version, operations.CommitSHA, runtime.Version(),
 		fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH))
 }
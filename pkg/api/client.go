@@ -13,6 +13,13 @@ type ViewClient interface {
 	// an error is returned.
 	CallView(fid string, in []byte) (interface{}, error)
 
+	// StreamView takes in input a view factory identifier, fid, and an input, in, and invokes the
+	// factory f bound to fid on input in. The view returned by the factory is invoked on
+	// a freshly created context. The returned ViewStream delivers the status reports and the intermediate
+	// outputs of the view as they happen, and the final result. It can also be used to send further input
+	// to the running view.
+	StreamView(fid string, in []byte) (ViewStream, error)
+
 	// Initiate takes in input a view factory identifier, fid, and an input, in, and invokes the
 	// factory f bound to fid on input in. The view returned by the factory is invoked on
 	// a freshly created context whose identifier, cid, is immediately returned.
@@ -21,9 +28,38 @@ type ViewClient interface {
 
 	// Track takes in input a context identifier, cid, and returns the latest
 	// status of the context as set by the views using it.
+	// The status is json encoded and contains the reports, the result or error, and the start and end times.
 	Track(cid string) string
 
 	// IsTxFinal takes in input a transaction id and return nil if the transaction has been committed,
 	// an error otherwise.
 	IsTxFinal(txid string) error
 }
+
+// ViewStreamEvent is an event produced by a view invoked with StreamView
+type ViewStreamEvent struct {
+	// Report is a status report produced by the view, if any
+	Report string
+	// Output is an intermediate output sent by the view, if any
+	Output []byte
+	// Result is the result of the view, set when Done is true
+	Result []byte
+	// Done is true if the view terminated
+	Done bool
+}
+
+// ViewStream is the client side of a view invoked with StreamView
+type ViewStream interface {
+	// ContextID returns the identifier of the context in which the view runs
+	ContextID() string
+
+	// Recv blocks until the next event produced by the view is available.
+	// An error is returned if the view failed.
+	Recv() (*ViewStreamEvent, error)
+
+	// Send delivers further input to the running view
+	Send(in []byte) error
+
+	// Close closes the stream
+	Close() error
+}
@@ -21,6 +21,7 @@ import (
 	view3 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	viewsdk "github.com/hyperledger-labs/fabric-smart-client/platform/view/sdk"
 	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracker"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -211,10 +212,30 @@ func (n *node) Context(contextID string) (view.Context, error) {
 	return manager.Context(contextID)
 }
 
+// StreamView is not supported in-process, the view streams are served by the view service
+func (n *node) StreamView(fid string, in []byte) (api.ViewStream, error) {
+	return nil, errors.Errorf("streaming view [%s] is not supported in-process, use the view service client", fid)
+}
+
 func (n *node) Initiate(fid string, in []byte) (string, error) {
 	panic("implement me")
 }
 
 func (n *node) Track(cid string) string {
-	panic("implement me")
+	trackerService, err := tracker.GetService(n.registry)
+	if err != nil {
+		logger.Errorf("failed getting tracker for context [%s]: [%s]", cid, err)
+		return ""
+	}
+	status, err := trackerService.ViewStatus(cid)
+	if err != nil {
+		logger.Errorf("failed retrieving status of context [%s]: [%s]", cid, err)
+		return ""
+	}
+	raw, err := json.Marshal(status)
+	if err != nil {
+		logger.Errorf("failed marshalling status of context [%s]: [%s]", cid, err)
+		return ""
+	}
+	return string(raw)
 }
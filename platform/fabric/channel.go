@@ -41,6 +41,10 @@ func (c *Channel) Finality() *Finality {
 	return &Finality{ch: c.ch}
 }
 
+func (c *Channel) Events() *Events {
+	return &Events{ch: c.ch}
+}
+
 func (c *Channel) Chaincode(name string) *Chaincode {
 	return &Chaincode{
 		chaincode: c.ch.Chaincode(name),
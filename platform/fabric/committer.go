@@ -6,7 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 
 package fabric
 
-import "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
 
 type Committer struct {
 	ch driver.Channel
@@ -15,3 +21,135 @@ type Committer struct {
 func (c *Committer) ProcessNamespace(nss ...string) error {
 	return c.ch.ProcessNamespace(nss...)
 }
+
+// BlockListener is notified of the blocks processed by the committer
+type BlockListener interface {
+	OnBlock(block *CommittedBlock)
+}
+
+// TransactionListener is notified of the transactions processed by the committer
+type TransactionListener interface {
+	OnTransaction(tx *CommittedTransaction)
+}
+
+// ListenerRegistration removes the listener it has been returned for,
+// and tells if the committer dropped the listener for falling too far behind
+type ListenerRegistration = driver.ListenerRegistration
+
+// AddBlockListener registers a listener of all the blocks processed by the committer.
+// Listeners are notified in commit order from a goroutine of their own, they do not block the commit pipeline.
+func (c *Committer) AddBlockListener(listener BlockListener) ListenerRegistration {
+	return c.ch.AddBlockListener(&blockListener{ch: c.ch, l: listener})
+}
+
+// AddConfigListener registers a listener of the blocks carrying a configuration transaction
+func (c *Committer) AddConfigListener(listener BlockListener) ListenerRegistration {
+	return c.ch.AddConfigListener(&blockListener{ch: c.ch, l: listener})
+}
+
+// AddTransactionListener registers a listener of the transactions whose rwset touches any of the passed namespaces.
+// If no namespace is passed, the listener is notified of all the transactions, valid or not.
+func (c *Committer) AddTransactionListener(listener TransactionListener, namespaces ...string) ListenerRegistration {
+	return c.ch.AddTransactionListener(&transactionListener{ch: c.ch, l: listener}, namespaces...)
+}
+
+// CommittedBlock is a block processed by the committer
+type CommittedBlock struct {
+	ch driver.Channel
+	b  *driver.CommittedBlock
+}
+
+func (b *CommittedBlock) Number() uint64 {
+	return b.b.Number
+}
+
+// IsConfig returns true if the block carries a configuration transaction
+func (b *CommittedBlock) IsConfig() bool {
+	return b.b.Config
+}
+
+func (b *CommittedBlock) Transactions() []*CommittedTransaction {
+	txs := make([]*CommittedTransaction, len(b.b.Transactions))
+	for i, tx := range b.b.Transactions {
+		txs[i] = &CommittedTransaction{ch: b.ch, tx: tx}
+	}
+	return txs
+}
+
+// CommittedTransaction is a transaction of a block processed by the committer
+type CommittedTransaction struct {
+	ch driver.Channel
+	tx *driver.CommittedTransaction
+}
+
+func (t *CommittedTransaction) TxID() string {
+	return t.tx.TxID
+}
+
+func (t *CommittedTransaction) BlockNumber() uint64 {
+	return t.tx.BlockNumber
+}
+
+func (t *CommittedTransaction) IndexInBlock() int {
+	return t.tx.IndexInBlock
+}
+
+// ValidationCode returns the code Fabric assigned to the transaction when validating it
+func (t *CommittedTransaction) ValidationCode() int32 {
+	return t.tx.ValidationCode
+}
+
+// IsValid returns true if Fabric validated the transaction
+func (t *CommittedTransaction) IsValid() bool {
+	return t.tx.ValidationCode == int32(pb.TxValidationCode_VALID)
+}
+
+// IsConfig returns true if this is a configuration transaction
+func (t *CommittedTransaction) IsConfig() bool {
+	return t.tx.Type == int32(common.HeaderType_CONFIG)
+}
+
+// Namespaces returns the namespaces the rwset of the transaction touches
+func (t *CommittedTransaction) Namespaces() []string {
+	return t.tx.Namespaces
+}
+
+// Envelope returns the marshalled envelope of the transaction
+func (t *CommittedTransaction) Envelope() []byte {
+	return t.tx.Envelope
+}
+
+// Results returns the marshalled rwset of the transaction
+func (t *CommittedTransaction) Results() []byte {
+	return t.tx.Results
+}
+
+// RWSet returns the rwset of the transaction, the caller must call Done on it once done
+func (t *CommittedTransaction) RWSet() (*RWSet, error) {
+	if len(t.tx.Results) == 0 {
+		return nil, errors.Errorf("transaction [%s] carries no rwset", t.tx.TxID)
+	}
+	rws, err := t.ch.GetEphemeralRWSet(t.tx.Results)
+	if err != nil {
+		return nil, err
+	}
+	return &RWSet{rws: rws}, nil
+}
+
+type blockListener struct {
+	ch driver.Channel
+	l  BlockListener
+}
+
+func (b *blockListener) OnBlock(block *driver.CommittedBlock) {
+	b.l.OnBlock(&CommittedBlock{ch: b.ch, b: block})
+}
+
+type transactionListener struct {
+	ch driver.Channel
+	l  TransactionListener
+}
+
+func (t *transactionListener) OnTransaction(tx *driver.CommittedTransaction) {
+	t.l.OnTransaction(&CommittedTransaction{ch: t.ch, tx: tx})
+}
@@ -34,6 +34,7 @@ import (
 	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -46,6 +47,7 @@ const (
 	GetBlockByNumber   string = "GetBlockByNumber"
 	GetTransactionByID string = "GetTransactionByID"
 	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo       string = "GetChainInfo"
 )
 
 type channel struct {
@@ -57,9 +59,12 @@ type channel struct {
 	vault              *vault.Vault
 	processNamespaces  []string
 	externalCommitter  *committer.ExternalCommitter
+	chaincodeEvents    driver.ChaincodeEvents
+	commitListeners    driver.CommitListeners
 	envelopeService    driver.EnvelopeService
 	transactionService driver.EndorserTransactionService
 	metadataService    driver.MetadataService
+	peerSelector       *peer2.Selector
 	driver.TXIDStore
 
 	// applyLock is used to serialize calls to CommitConfig and bundle update processing.
@@ -82,8 +87,10 @@ func newChannel(network *network, name string, quiet bool) (*channel, error) {
 	fabricFinality, err := finality2.NewFabricFinality(
 		name,
 		network,
+		network.peerSelector,
 		hash.GetHasher(sp),
 		waitForEventTimeout,
+		network.config.FinalityCacheSize(),
 	)
 	if err != nil {
 		return nil, err
@@ -101,13 +108,18 @@ func newChannel(network *network, name string, quiet bool) (*channel, error) {
 	}
 
 	// Delivery
-	deliveryService, err := delivery2.New(name, sp, network, committerInst, txIDStore, waitForEventTimeout)
+	deliveryService, err := delivery2.New(name, sp, network, network.peerSelector, committerInst, txIDStore, waitForEventTimeout, network.config.DeliveryMode())
 	if err != nil {
 		return nil, err
 	}
+	operations.RegisterChecker(sp, "fabric.delivery."+network.Name()+"."+name, deliveryService)
 
 	// Finality
-	fs, err := finality2.NewService(sp, network, name, committerInst)
+	partyTimeout := network.config.FinalityPartyTimeout()
+	if partyTimeout == 0 {
+		partyTimeout = waitForEventTimeout
+	}
+	fs, err := finality2.NewService(sp, network, name, committerInst, partyTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +132,13 @@ func newChannel(network *network, name string, quiet bool) (*channel, error) {
 		sp:                 sp,
 		finality:           fs,
 		externalCommitter:  externalCommitter,
+		chaincodeEvents:    committerInst,
+		commitListeners:    committerInst,
 		TXIDStore:          txIDStore,
 		envelopeService:    transaction.NewEnvelopeService(sp, network.Name(), name),
 		transactionService: transaction.NewEndorseTransactionService(sp, network.Name(), name),
 		metadataService:    transaction.NewMetadataService(sp, network.Name(), name),
+		peerSelector:       network.peerSelector,
 	}
 	if err := c.init(); err != nil {
 		return nil, errors.WithMessagef(err, "failed initializing channel [%s]", name)
@@ -257,7 +272,7 @@ func (c *channel) GetClientConfig(tlsRootCerts [][]byte) (*grpc.ClientConfig, st
 func (c *channel) GetTransactionByID(txID string) (driver.ProcessedTransaction, error) {
 	res, err := c.Chaincode("qscc").NewInvocation(driver.ChaincodeQuery, GetTransactionByID, c.name, txID).WithSignerIdentity(
 		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	).WithEndorsersByConnConfig(c.peerSelector.Current()).Call()
 	if err != nil {
 		return nil, err
 	}
@@ -271,19 +286,27 @@ func (c *channel) GetTransactionByID(txID string) (driver.ProcessedTransaction,
 }
 
 func (c *channel) GetBlockNumberByTxID(txID string) (uint64, error) {
+	block, err := c.getBlockByTxID(txID)
+	if err != nil {
+		return 0, err
+	}
+	return block.Header.Number, nil
+}
+
+func (c *channel) getBlockByTxID(txID string) (*common.Block, error) {
 	res, err := c.Chaincode("qscc").NewInvocation(driver.ChaincodeQuery, GetBlockByTxID, c.name, txID).WithSignerIdentity(
 		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	).WithEndorsersByConnConfig(c.peerSelector.Current()).Call()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	block := &common.Block{}
 	err = proto.Unmarshal(res.([]byte), block)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return block.Header.Number, nil
+	return block, nil
 }
 
 func (c *channel) init() error {
@@ -351,6 +374,7 @@ func (c *channel) init() error {
 			c.lock.Lock()
 			c.resources = bundle
 			c.lock.Unlock()
+			c.updateCRLs(ctx.Config)
 
 			sequence = sequence + 1
 			continue
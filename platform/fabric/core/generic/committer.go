@@ -248,3 +248,15 @@ func (c *channel) postProcessTx(txid string) error {
 	}
 	return nil
 }
+
+func (c *channel) AddBlockListener(listener driver.BlockListener) driver.ListenerRegistration {
+	return c.commitListeners.AddBlockListener(listener)
+}
+
+func (c *channel) AddConfigListener(listener driver.BlockListener) driver.ListenerRegistration {
+	return c.commitListeners.AddConfigListener(listener)
+}
+
+func (c *channel) AddTransactionListener(listener driver.TransactionListener, namespaces ...string) driver.ListenerRegistration {
+	return c.commitListeners.AddTransactionListener(listener, namespaces...)
+}
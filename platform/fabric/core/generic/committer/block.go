@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// fullBlock wraps a block received from the delivery service
+type fullBlock struct {
+	*common.Block
+}
+
+// DataAt returns the data stored at the passed index
+func (b *fullBlock) DataAt(i int) []byte {
+	return b.Data.Data[i]
+}
+
+// filterBlock extracts from the passed block the same information carried by a filtered block
+func filterBlock(channel string, block *common.Block) (*pb.FilteredBlock, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("invalid block, missing header or data")
+	}
+	var txFilter []byte
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+	if len(txFilter) != len(block.Data.Data) {
+		return nil, errors.Errorf("invalid block [%d], transactions filter has length [%d], expected [%d]", block.Header.Number, len(txFilter), len(block.Data.Data))
+	}
+
+	filteredBlock := &pb.FilteredBlock{
+		ChannelId: channel,
+		Number:    block.Header.Number,
+	}
+	for i, data := range block.Data.Data {
+		env, err := protoutil.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling envelope at index [%d]", i)
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling payload at index [%d]", i)
+		}
+		if payload.Header == nil {
+			return nil, errors.Errorf("invalid payload at index [%d], missing header", i)
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling channel header at index [%d]", i)
+		}
+		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, &pb.FilteredTransaction{
+			Txid:             chdr.TxId,
+			Type:             common.HeaderType(chdr.Type),
+			TxValidationCode: pb.TxValidationCode(txFilter[i]),
+		})
+	}
+	for _, tx := range filteredBlock.FilteredTransactions {
+		if tx.Type == common.HeaderType_CONFIG && len(filteredBlock.FilteredTransactions) != 1 {
+			return nil, errors.Errorf("invalid block [%d], config transaction [%s] must be alone in its block", block.Header.Number, tx.Txid)
+		}
+	}
+	return filteredBlock, nil
+}
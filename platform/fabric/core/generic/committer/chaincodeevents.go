@@ -0,0 +1,362 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+const (
+	// eventsBufferSize is the number of events a stream buffers for its consumer
+	eventsBufferSize = 100
+	// maxQueuedEvents is the number of events a stream queues for its consumer before being dropped
+	maxQueuedEvents = 10000
+	// replayRetryInterval is the time a stream waits before fetching again a block it failed to fetch from the ledger
+	replayRetryInterval = 5 * time.Second
+)
+
+// fabricBlock is implemented by the ledger blocks that wrap a Fabric block
+type fabricBlock interface {
+	GetHeader() *common.BlockHeader
+	GetData() *common.BlockData
+	GetMetadata() *common.BlockMetadata
+}
+
+// chaincodeEvents dispatches the chaincode events of the committed blocks to the streams subscribed to them
+type chaincodeEvents struct {
+	channel string
+	network Network
+
+	lock sync.Mutex
+	// lastBlock is the number of the last block dispatched, valid if hasLastBlock is true
+	lastBlock    uint64
+	hasLastBlock bool
+	streams      map[*chaincodeEventStream]struct{}
+}
+
+func newChaincodeEvents(channel string, network Network) *chaincodeEvents {
+	return &chaincodeEvents{
+		channel: channel,
+		network: network,
+		streams: map[*chaincodeEventStream]struct{}{},
+	}
+}
+
+func (e *chaincodeEvents) Subscribe(request *driver.ChaincodeEventsRequest) (driver.ChaincodeEventStream, error) {
+	if len(request.ChaincodeName) == 0 {
+		return nil, errors.New("no chaincode specified")
+	}
+	s := &chaincodeEventStream{
+		request: request,
+		names:   map[string]struct{}{},
+		events:  make(chan *driver.ChaincodeEvent, eventsBufferSize),
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, name := range request.EventNames {
+		s.names[name] = struct{}{}
+	}
+
+	e.lock.Lock()
+	if e.hasLastBlock {
+		s.liveFrom, s.hasLiveFrom = e.lastBlock+1, true
+	}
+	e.streams[s] = struct{}{}
+	e.lock.Unlock()
+
+	logger.Debugf("new subscription to the events of chaincode [%s:%s] %v", e.channel, request.ChaincodeName, request.EventNames)
+	go e.run(s)
+	return s, nil
+}
+
+// dispatch passes the chaincode events of the block with the passed number to the streams
+func (e *chaincodeEvents) dispatch(number uint64, events []*driver.ChaincodeEvent) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.lastBlock, e.hasLastBlock = number, true
+	for s := range e.streams {
+		s.push(number, events)
+	}
+}
+
+// run feeds the passed stream: first the events of the requested blocks committed before the subscription,
+// fetched from the ledger, then the events of the blocks committed since.
+func (e *chaincodeEvents) run(s *chaincodeEventStream) {
+	defer func() {
+		e.lock.Lock()
+		delete(e.streams, s)
+		e.lock.Unlock()
+		close(s.events)
+	}()
+
+	liveFrom, ok := e.liveFrom(s)
+	if !ok {
+		return
+	}
+	next := s.request.StartBlock
+	if s.request.FromNewest {
+		next = liveFrom
+	}
+
+	// replay
+	for next < liveFrom {
+		events, err := e.fetch(next)
+		if err != nil {
+			logger.Errorf("failed fetching events of block [%s:%d], retry in [%s]: [%s]", e.channel, next, replayRetryInterval, err)
+			select {
+			case <-time.After(replayRetryInterval):
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		if !s.send(events) {
+			return
+		}
+		next++
+	}
+
+	// live
+	for {
+		for _, block := range s.pop() {
+			if block.number < next {
+				s.consumed(len(block.events))
+				continue
+			}
+			if !s.send(block.events) {
+				return
+			}
+			s.consumed(len(block.events))
+			next = block.number + 1
+		}
+		select {
+		case <-s.signal:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// liveFrom returns the number of the first block the passed stream gets from the committer.
+// If the committer has not dispatched any block yet, the ledger height is returned instead,
+// so that the replay does not wait for the next block.
+// It returns false if the stream is closed in the meantime.
+func (e *chaincodeEvents) liveFrom(s *chaincodeEventStream) (uint64, bool) {
+	for {
+		s.lock.Lock()
+		liveFrom, ok := s.liveFrom, s.hasLiveFrom
+		s.lock.Unlock()
+		if ok {
+			return liveFrom, true
+		}
+		height, err := e.height()
+		if err == nil {
+			return height, true
+		}
+		logger.Errorf("failed getting ledger height of [%s], retry in [%s]: [%s]", e.channel, replayRetryInterval, err)
+		select {
+		case <-time.After(replayRetryInterval):
+		case <-s.signal:
+		case <-s.done:
+			return 0, false
+		}
+	}
+}
+
+// height returns the number of blocks in the ledger
+func (e *chaincodeEvents) height() (uint64, error) {
+	ledger, err := e.network.Ledger(e.channel)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed getting ledger of [%s]", e.channel)
+	}
+	return ledger.GetLedgerHeight()
+}
+
+// fetch returns the chaincode events of the block with the passed number, fetched from the ledger
+func (e *chaincodeEvents) fetch(number uint64) ([]*driver.ChaincodeEvent, error) {
+	ledger, err := e.network.Ledger(e.channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting ledger of [%s]", e.channel)
+	}
+	block, err := ledger.GetBlockByNumber(number)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting block [%d]", number)
+	}
+	fb, ok := block.(fabricBlock)
+	if !ok {
+		return nil, errors.Errorf("block [%d] of type [%T] does not carry a fabric block", number, block)
+	}
+	filteredBlock, err := filterBlock(e.channel, &common.Block{Header: fb.GetHeader(), Data: fb.GetData(), Metadata: fb.GetMetadata()})
+	if err != nil {
+		return nil, err
+	}
+	return chaincodeEventsOf(block, filteredBlock), nil
+}
+
+// chaincodeEventsOf returns the chaincode events set by the valid endorser transactions of the passed block
+func chaincodeEventsOf(block Block, filteredBlock *pb.FilteredBlock) []*driver.ChaincodeEvent {
+	var events []*driver.ChaincodeEvent
+	for i, tx := range filteredBlock.FilteredTransactions {
+		if tx.Type != common.HeaderType_ENDORSER_TRANSACTION || tx.TxValidationCode != pb.TxValidationCode_VALID {
+			continue
+		}
+		action, err := protoutil.GetActionFromEnvelope(block.DataAt(i))
+		if err != nil {
+			logger.Warnf("failed getting chaincode action of [%s] in block [%d]: [%s]", tx.Txid, filteredBlock.Number, err)
+			continue
+		}
+		if len(action.Events) == 0 {
+			continue
+		}
+		event, err := protoutil.UnmarshalChaincodeEvents(action.Events)
+		if err != nil {
+			logger.Warnf("failed unmarshalling chaincode event of [%s] in block [%d]: [%s]", tx.Txid, filteredBlock.Number, err)
+			continue
+		}
+		if len(event.EventName) == 0 {
+			continue
+		}
+		events = append(events, &driver.ChaincodeEvent{
+			BlockNumber:   filteredBlock.Number,
+			TxID:          tx.Txid,
+			ChaincodeName: event.ChaincodeId,
+			EventName:     event.EventName,
+			Payload:       event.Payload,
+		})
+	}
+	return events
+}
+
+// blockEvents are the events of a block a stream has still to deliver
+type blockEvents struct {
+	number uint64
+	events []*driver.ChaincodeEvent
+}
+
+// chaincodeEventStream is a subscription to chaincode events.
+// The committer queues the events of the blocks it commits without waiting for the consumer.
+// A consumer that lets more than maxQueuedEvents events pile up is dropped.
+type chaincodeEventStream struct {
+	request   *driver.ChaincodeEventsRequest
+	names     map[string]struct{}
+	events    chan *driver.ChaincodeEvent
+	done      chan struct{}
+	closeOnce sync.Once
+
+	lock sync.Mutex
+	// signal is notified when blocks are queued
+	signal chan struct{}
+	queue  []*blockEvents
+	queued int
+	err    error
+	// liveFrom is the number of the first block queued by the committer, valid if hasLiveFrom is true
+	liveFrom    uint64
+	hasLiveFrom bool
+}
+
+func (s *chaincodeEventStream) Events() <-chan *driver.ChaincodeEvent {
+	return s.events
+}
+
+func (s *chaincodeEventStream) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.err
+}
+
+func (s *chaincodeEventStream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// push queues the selected events of the block with the passed number
+func (s *chaincodeEventStream) push(number uint64, events []*driver.ChaincodeEvent) {
+	var selected []*driver.ChaincodeEvent
+	for _, event := range events {
+		if s.selects(event) {
+			selected = append(selected, event)
+		}
+	}
+
+	s.lock.Lock()
+	if !s.hasLiveFrom {
+		s.liveFrom, s.hasLiveFrom = number, true
+	} else if len(selected) == 0 {
+		s.lock.Unlock()
+		return
+	}
+	if s.queued+len(selected) > maxQueuedEvents {
+		if s.err == nil {
+			s.err = errors.Errorf("subscription to the events of chaincode [%s] dropped, more than [%d] events not consumed", s.request.ChaincodeName, maxQueuedEvents)
+			logger.Warnf("%s", s.err)
+		}
+		s.queue, s.queued = nil, 0
+		s.lock.Unlock()
+		s.Close()
+		return
+	}
+	s.queue = append(s.queue, &blockEvents{number: number, events: selected})
+	s.queued += len(selected)
+	s.lock.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the queued blocks, emptying the queue
+func (s *chaincodeEventStream) pop() []*blockEvents {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	queue := s.queue
+	s.queue = nil
+	return queue
+}
+
+// consumed releases the room taken in the queue by the passed number of events, once delivered
+func (s *chaincodeEventStream) consumed(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.queued -= n
+}
+
+// send delivers the selected events among the passed ones to the consumer.
+// It returns false if the stream is closed in the meantime.
+func (s *chaincodeEventStream) send(events []*driver.ChaincodeEvent) bool {
+	for _, event := range events {
+		if !s.selects(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		case <-s.done:
+			return false
+		}
+	}
+	return true
+}
+
+func (s *chaincodeEventStream) selects(event *driver.ChaincodeEvent) bool {
+	if event.ChaincodeName != s.request.ChaincodeName {
+		return false
+	}
+	if len(s.names) == 0 {
+		return true
+	}
+	_, ok := s.names[event.EventName]
+	return ok
+}
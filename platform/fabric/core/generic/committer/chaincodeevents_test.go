@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+type tx struct {
+	id         string
+	chaincode  string
+	event      string
+	valid      bool
+	config     bool
+	namespaces []string
+}
+
+func marshal(t *testing.T, m proto.Message) []byte {
+	raw, err := proto.Marshal(m)
+	assert.NoError(t, err)
+	return raw
+}
+
+func newBlock(t *testing.T, number uint64, txs ...tx) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	txFilter := make([]byte, len(txs))
+	for i, tx := range txs {
+		txRWSet := &rwsetutil.TxRwSet{}
+		for _, ns := range tx.namespaces {
+			txRWSet.NsRwSets = append(txRWSet.NsRwSets, &rwsetutil.NsRwSet{NameSpace: ns, KvRwSet: &kvrwset.KVRWSet{}})
+		}
+		results, err := txRWSet.ToProtoBytes()
+		assert.NoError(t, err)
+		action := &pb.ChaincodeAction{Results: results}
+		if len(tx.event) != 0 {
+			action.Events = marshal(t, &pb.ChaincodeEvent{ChaincodeId: tx.chaincode, TxId: tx.id, EventName: tx.event, Payload: []byte(tx.id)})
+		}
+		headerType := common.HeaderType_ENDORSER_TRANSACTION
+		if tx.config {
+			headerType = common.HeaderType_CONFIG
+		}
+		payload := &common.Payload{
+			Header: &common.Header{ChannelHeader: marshal(t, &common.ChannelHeader{
+				Type: int32(headerType),
+				TxId: tx.id,
+			})},
+			Data: marshal(t, &pb.Transaction{Actions: []*pb.TransactionAction{{
+				Payload: marshal(t, &pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{
+					ProposalResponsePayload: marshal(t, &pb.ProposalResponsePayload{Extension: marshal(t, action)}),
+				}}),
+			}}}),
+		}
+		block.Data.Data = append(block.Data.Data, marshal(t, &common.Envelope{Payload: marshal(t, payload)}))
+		txFilter[i] = byte(pb.TxValidationCode_MVCC_READ_CONFLICT)
+		if tx.valid {
+			txFilter[i] = byte(pb.TxValidationCode_VALID)
+		}
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+	return block
+}
+
+type ledgerBlock struct {
+	*common.Block
+}
+
+func (b *ledgerBlock) DataAt(i int) []byte {
+	return b.Data.Data[i]
+}
+
+func (b *ledgerBlock) ProcessedTransaction(int) (driver.ProcessedTransaction, error) {
+	panic("not needed")
+}
+
+type network struct {
+	lock      sync.Mutex
+	blocks    map[uint64]*common.Block
+	committer driver.Committer
+}
+
+func (n *network) Committer(string) (driver.Committer, error) {
+	if n.committer == nil {
+		panic("not needed")
+	}
+	return n.committer, nil
+}
+
+func (n *network) Ledger(string) (driver.Ledger, error) {
+	return n, nil
+}
+
+func (n *network) GetTransactionByID(string) (driver.ProcessedTransaction, error) {
+	panic("not needed")
+}
+
+func (n *network) GetBlockNumberByTxID(string) (uint64, error) {
+	panic("not needed")
+}
+
+func (n *network) GetBlockByNumber(number uint64) (driver.Block, error) {
+	n.lock.Lock()
+	block, ok := n.blocks[number]
+	n.lock.Unlock()
+	if !ok {
+		return nil, errors.Errorf("block [%d] not found", number)
+	}
+	return &ledgerBlock{Block: block}, nil
+}
+
+func (n *network) GetLedgerHeight() (uint64, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	height := uint64(0)
+	for number := range n.blocks {
+		if number+1 > height {
+			height = number + 1
+		}
+	}
+	return height, nil
+}
+
+// commit adds the passed block to the ledger and dispatches its events as the committer does
+func (n *network) commit(t *testing.T, events *chaincodeEvents, block *common.Block) {
+	n.lock.Lock()
+	n.blocks[block.Header.Number] = block
+	n.lock.Unlock()
+	filteredBlock, err := filterBlock("channel", block)
+	assert.NoError(t, err)
+	events.dispatch(block.Header.Number, chaincodeEventsOf(&fullBlock{Block: block}, filteredBlock))
+}
+
+func next(t *testing.T, s driver.ChaincodeEventStream) *driver.ChaincodeEvent {
+	select {
+	case event := <-s.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no event received")
+		return nil
+	}
+}
+
+func TestChaincodeEvents(t *testing.T) {
+	n := &network{blocks: map[uint64]*common.Block{}}
+	events := newChaincodeEvents("channel", n)
+
+	_, err := events.Subscribe(&driver.ChaincodeEventsRequest{})
+	assert.Error(t, err)
+
+	n.commit(t, events, newBlock(t, 0))
+	n.commit(t, events, newBlock(t, 1,
+		tx{id: "tx1", chaincode: "asset", event: "transfer", valid: true},
+		tx{id: "tx2", chaincode: "asset", event: "transfer", valid: false},
+		tx{id: "tx3", chaincode: "other", event: "transfer", valid: true},
+	))
+	n.commit(t, events, newBlock(t, 2,
+		tx{id: "tx4", chaincode: "asset", event: "issue", valid: true},
+		tx{id: "tx5", chaincode: "asset", valid: true},
+		tx{id: "tx6", chaincode: "asset", event: "transfer", valid: true},
+	))
+
+	// past blocks are fetched from the ledger, new blocks come from the committer
+	fromStart, err := events.Subscribe(&driver.ChaincodeEventsRequest{ChaincodeName: "asset", EventNames: []string{"transfer"}, StartBlock: 1})
+	assert.NoError(t, err)
+	defer fromStart.Close()
+	newest, err := events.Subscribe(&driver.ChaincodeEventsRequest{ChaincodeName: "asset", FromNewest: true})
+	assert.NoError(t, err)
+
+	n.commit(t, events, newBlock(t, 3, tx{id: "tx7", chaincode: "asset", event: "transfer", valid: true}))
+
+	event := next(t, fromStart)
+	assert.Equal(t, &driver.ChaincodeEvent{BlockNumber: 1, TxID: "tx1", ChaincodeName: "asset", EventName: "transfer", Payload: []byte("tx1")}, event)
+	assert.Equal(t, "tx6", next(t, fromStart).TxID)
+	assert.Equal(t, "tx7", next(t, fromStart).TxID)
+	assert.Equal(t, "tx7", next(t, newest).TxID)
+
+	// closing a stream closes its channel
+	newest.Close()
+	for range newest.Events() {
+	}
+	n.commit(t, events, newBlock(t, 4, tx{id: "tx8", chaincode: "asset", event: "issue", valid: true}))
+	n.commit(t, events, newBlock(t, 5, tx{id: "tx9", chaincode: "asset", event: "transfer", valid: true}))
+	assert.Equal(t, "tx9", next(t, fromStart).TxID)
+}
+
+func TestChaincodeEventsReplayWithoutNewBlocks(t *testing.T) {
+	// the blocks are in the ledger but the committer has not dispatched any block since the start
+	n := &network{blocks: map[uint64]*common.Block{
+		0: newBlock(t, 0),
+		1: newBlock(t, 1, tx{id: "tx1", chaincode: "asset", event: "transfer", valid: true}),
+		2: newBlock(t, 2, tx{id: "tx2", chaincode: "asset", event: "transfer", valid: true}),
+	}}
+	events := newChaincodeEvents("channel", n)
+
+	s, err := events.Subscribe(&driver.ChaincodeEventsRequest{ChaincodeName: "asset", StartBlock: 1})
+	assert.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, "tx1", next(t, s).TxID)
+	assert.Equal(t, "tx2", next(t, s).TxID)
+
+	// the blocks after the ledger height come from the committer
+	n.commit(t, events, newBlock(t, 3, tx{id: "tx3", chaincode: "asset", event: "transfer", valid: true}))
+	assert.Equal(t, "tx3", next(t, s).TxID)
+}
+
+func TestChaincodeEventsSlowConsumer(t *testing.T) {
+	n := &network{blocks: map[uint64]*common.Block{}}
+	events := newChaincodeEvents("channel", n)
+	n.commit(t, events, newBlock(t, 0))
+
+	slow, err := events.Subscribe(&driver.ChaincodeEventsRequest{ChaincodeName: "asset", FromNewest: true})
+	assert.NoError(t, err)
+	fast, err := events.Subscribe(&driver.ChaincodeEventsRequest{ChaincodeName: "asset", FromNewest: true})
+	assert.NoError(t, err)
+	defer fast.Close()
+
+	batch := func(number uint64) []*driver.ChaincodeEvent {
+		batch := make([]*driver.ChaincodeEvent, maxQueuedEvents/4)
+		for i := range batch {
+			batch[i] = &driver.ChaincodeEvent{BlockNumber: number, ChaincodeName: "asset", EventName: "transfer"}
+		}
+		return batch
+	}
+	for number := uint64(1); number <= 6; number++ {
+		events.dispatch(number, batch(number))
+		// the fast consumer keeps up
+		for i := 0; i < maxQueuedEvents/4; i++ {
+			assert.Equal(t, number, next(t, fast).BlockNumber)
+		}
+	}
+	assert.NoError(t, fast.Err())
+
+	// the slow consumer, which read nothing, has been dropped
+	for range slow.Events() {
+	}
+	assert.Error(t, slow.Err())
+	assert.Contains(t, slow.Err().Error(), "events not consumed")
+}
@@ -18,7 +18,6 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
-	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 )
 
 const (
@@ -28,26 +27,27 @@ const (
 var logger = flogging.MustGetLogger("fabric-sdk.committer")
 
 type Finality interface {
-	IsFinal(txID string, address string) error
+	IsFinal(txID string) error
 }
 
 type Network interface {
 	Committer(channel string) (driver.Committer, error)
-	Peers() []*grpc.ConnectionConfig
 	Ledger(channel string) (driver.Ledger, error)
 }
 
 type committer struct {
-	channel              string
-	network              Network
-	finality             Finality
-	peerConnectionConfig *grpc.ConnectionConfig
-	waitForEventTimeout  time.Duration
+	channel             string
+	network             Network
+	finality            Finality
+	waitForEventTimeout time.Duration
 
 	quietNotifier bool
 
 	listeners map[string][]chan TxEvent
 	mutex     sync.Mutex
+
+	chaincodeEvents *chaincodeEvents
+	commitListeners *commitListeners
 }
 
 func New(channel string, network Network, finality Finality, waitForEventTimeout time.Duration, quiet bool) (*committer, error) {
@@ -56,19 +56,27 @@ func New(channel string, network Network, finality Finality, waitForEventTimeout
 	}
 
 	d := &committer{
-		channel:              channel,
-		network:              network,
-		waitForEventTimeout:  waitForEventTimeout,
-		quietNotifier:        quiet,
-		peerConnectionConfig: network.Peers()[0],
-		listeners:            map[string][]chan TxEvent{},
-		mutex:                sync.Mutex{},
-		finality:             finality,
+		channel:             channel,
+		network:             network,
+		waitForEventTimeout: waitForEventTimeout,
+		quietNotifier:       quiet,
+		listeners:           map[string][]chan TxEvent{},
+		mutex:               sync.Mutex{},
+		finality:            finality,
+		chaincodeEvents:     newChaincodeEvents(channel, network),
+		commitListeners:     newCommitListeners(),
 	}
 	return d, nil
 }
 
-// Commit commits the transaction in the passed block
+// Block models a block whose transactions can be accessed by index
+type Block interface {
+	// DataAt returns the data stored at the passed index
+	DataAt(i int) []byte
+}
+
+// Commit commits the transaction in the passed filtered block.
+// The corresponding full block is fetched from the ledger.
 func (c *committer) Commit(filteredBlock *pb.FilteredBlock) {
 	ledger, err := c.network.Ledger(c.channel)
 	if err != nil {
@@ -79,6 +87,22 @@ func (c *committer) Commit(filteredBlock *pb.FilteredBlock) {
 		logger.Panicf("cannot get filteredBlock [%s]", err)
 	}
 
+	c.commit(block, filteredBlock)
+}
+
+// CommitBlock commits the transaction in the passed block.
+// Malformed blocks are rejected without committing any of their transactions.
+func (c *committer) CommitBlock(block *common.Block) error {
+	filteredBlock, err := filterBlock(c.channel, block)
+	if err != nil {
+		return errors.WithMessage(err, "cannot filter block")
+	}
+
+	c.commit(&fullBlock{Block: block}, filteredBlock)
+	return nil
+}
+
+func (c *committer) commit(block Block, filteredBlock *pb.FilteredBlock) {
 	filteredTransactions := filteredBlock.FilteredTransactions
 	for i, tx := range filteredTransactions {
 		logger.Debugf("commit transaction [%s] in filteredBlock [%d]", tx.Txid, filteredBlock.Number)
@@ -97,6 +121,16 @@ func (c *committer) Commit(filteredBlock *pb.FilteredBlock) {
 
 		c.notify(*event)
 	}
+
+	c.chaincodeEvents.dispatch(filteredBlock.Number, chaincodeEventsOf(block, filteredBlock))
+	if !c.commitListeners.empty() {
+		c.commitListeners.dispatch(committedBlock(block, filteredBlock))
+	}
+}
+
+// SubscribeChaincodeEvents returns a stream of the chaincode events selected by the passed request
+func (c *committer) SubscribeChaincodeEvents(request *driver.ChaincodeEventsRequest) (driver.ChaincodeEventStream, error) {
+	return c.chaincodeEvents.Subscribe(request)
 }
 
 // IsFinal takes in input a transaction id and waits for its confirmation.
@@ -143,9 +177,9 @@ func (c *committer) IsFinal(txid string) error {
 				}
 				return nil
 			}
-			return c.finality.IsFinal(txid, c.peerConnectionConfig.Address)
+			return c.finality.IsFinal(txid)
 		case driver.Unknown:
-			return c.finality.IsFinal(txid, c.peerConnectionConfig.Address)
+			return c.finality.IsFinal(txid)
 		default:
 			panic(fmt.Sprintf("invalid status code, got %c", vd))
 		}
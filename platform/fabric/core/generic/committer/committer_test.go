@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+// vault records the calls of the committer
+type vault struct {
+	driver.Committer
+	committed []string
+	discarded []string
+	configs   []uint64
+}
+
+func (v *vault) Status(txid string) (driver.ValidationCode, []string, error) {
+	return driver.Busy, nil, nil
+}
+
+func (v *vault) CommitTX(txid string, block uint64, indexInBloc int, envelope []byte) error {
+	v.committed = append(v.committed, txid)
+	return nil
+}
+
+func (v *vault) DiscardTx(txid string) error {
+	v.discarded = append(v.discarded, txid)
+	return nil
+}
+
+func (v *vault) CommitConfig(blockNumber uint64, envelope []byte) error {
+	v.configs = append(v.configs, blockNumber)
+	return nil
+}
+
+func TestCommitBlock(t *testing.T) {
+	withFilter := func(block *common.Block, filter []byte) *common.Block {
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = filter
+		return block
+	}
+
+	tests := []struct {
+		name      string
+		block     func(t *testing.T) *common.Block
+		err       string
+		committed []string
+		discarded []string
+		configs   []uint64
+	}{
+		{
+			name: "valid transactions",
+			block: func(t *testing.T) *common.Block {
+				return newBlock(t, 1, tx{id: "tx1", valid: true}, tx{id: "tx2", valid: true})
+			},
+			committed: []string{"tx1", "tx2"},
+		},
+		{
+			name: "invalid transactions",
+			block: func(t *testing.T) *common.Block {
+				return newBlock(t, 1, tx{id: "tx1", valid: true}, tx{id: "tx2", valid: false})
+			},
+			committed: []string{"tx1"},
+			discarded: []string{"tx2"},
+		},
+		{
+			name: "config transaction",
+			block: func(t *testing.T) *common.Block {
+				return newBlock(t, 1, tx{id: "config", config: true, valid: true})
+			},
+			configs: []uint64{1},
+		},
+		{
+			name:  "nil block",
+			block: func(t *testing.T) *common.Block { return nil },
+			err:   "missing header or data",
+		},
+		{
+			name: "missing header",
+			block: func(t *testing.T) *common.Block {
+				block := newBlock(t, 1, tx{id: "tx1", valid: true})
+				block.Header = nil
+				return block
+			},
+			err: "missing header or data",
+		},
+		{
+			name: "missing data",
+			block: func(t *testing.T) *common.Block {
+				block := newBlock(t, 1, tx{id: "tx1", valid: true})
+				block.Data = nil
+				return block
+			},
+			err: "missing header or data",
+		},
+		{
+			name: "missing metadata",
+			block: func(t *testing.T) *common.Block {
+				block := newBlock(t, 1, tx{id: "tx1", valid: true})
+				block.Metadata = nil
+				return block
+			},
+			err: "transactions filter has length [0], expected [1]",
+		},
+		{
+			name: "short transactions filter",
+			block: func(t *testing.T) *common.Block {
+				return withFilter(newBlock(t, 1, tx{id: "tx1", valid: true}, tx{id: "tx2", valid: true}), []byte{0})
+			},
+			err: "transactions filter has length [1], expected [2]",
+		},
+		{
+			name: "malformed envelope",
+			block: func(t *testing.T) *common.Block {
+				block := newBlock(t, 1, tx{id: "tx1", valid: true}, tx{id: "tx2", valid: true})
+				block.Data.Data[1] = []byte("garbage")
+				return block
+			},
+			err: "failed unmarshalling envelope at index [1]",
+		},
+		{
+			name: "config transaction not alone",
+			block: func(t *testing.T) *common.Block {
+				return newBlock(t, 1, tx{id: "config", config: true, valid: true}, tx{id: "tx1", valid: true})
+			},
+			err: "config transaction [config] must be alone in its block",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &vault{}
+			c, err := New("channel", &network{blocks: map[uint64]*common.Block{}, committer: v}, nil, time.Second, true)
+			assert.NoError(t, err)
+			events := make(chan TxEvent, 10)
+			for _, id := range []string{"tx1", "tx2", "config"} {
+				c.addListener(id, events)
+			}
+
+			err = c.CommitBlock(test.block(t))
+			if len(test.err) != 0 {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				assert.Len(t, events, 0)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.committed, v.committed)
+			assert.Equal(t, test.discarded, v.discarded)
+			assert.Equal(t, test.configs, v.configs)
+
+			close(events)
+			for event := range events {
+				if contains(test.discarded, event.Txid) {
+					assert.Error(t, event.Err)
+				} else {
+					assert.NoError(t, event.Err)
+				}
+			}
+		})
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
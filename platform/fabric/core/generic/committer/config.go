@@ -8,11 +8,9 @@ package committer
 
 import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
-
-	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 )
 
-func (c *committer) handleConfig(block driver.Block, fBlock *pb.FilteredBlock, transactions []*pb.FilteredTransaction, i int, event *TxEvent) {
+func (c *committer) handleConfig(block Block, fBlock *pb.FilteredBlock, transactions []*pb.FilteredTransaction, i int, event *TxEvent) {
 	tx := transactions[i]
 
 	logger.Debugf("Committing config transaction [%s]", tx.Txid)
@@ -0,0 +1,259 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+// AddBlockListener registers a listener of all the blocks
+func (c *committer) AddBlockListener(listener driver.BlockListener) driver.ListenerRegistration {
+	return c.commitListeners.add(listener.OnBlock)
+}
+
+// AddConfigListener registers a listener of the blocks carrying a configuration transaction
+func (c *committer) AddConfigListener(listener driver.BlockListener) driver.ListenerRegistration {
+	return c.commitListeners.add(func(block *driver.CommittedBlock) {
+		if block.Config {
+			listener.OnBlock(block)
+		}
+	})
+}
+
+// AddTransactionListener registers a listener of the transactions whose rwset touches any of the passed namespaces
+func (c *committer) AddTransactionListener(listener driver.TransactionListener, namespaces ...string) driver.ListenerRegistration {
+	selected := map[string]struct{}{}
+	for _, ns := range namespaces {
+		selected[ns] = struct{}{}
+	}
+	return c.commitListeners.add(func(block *driver.CommittedBlock) {
+		for _, tx := range block.Transactions {
+			if touches(tx, selected) {
+				listener.OnTransaction(tx)
+			}
+		}
+	})
+}
+
+// touches returns true if the passed transaction touches any of the passed namespaces, or if no namespace is passed
+func touches(tx *driver.CommittedTransaction, namespaces map[string]struct{}) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+	for _, ns := range tx.Namespaces {
+		if _, ok := namespaces[ns]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// committedBlock describes the passed block for the listeners
+func committedBlock(block Block, filteredBlock *pb.FilteredBlock) *driver.CommittedBlock {
+	cb := &driver.CommittedBlock{Number: filteredBlock.Number}
+	for i, tx := range filteredBlock.FilteredTransactions {
+		ct := &driver.CommittedTransaction{
+			TxID:           tx.Txid,
+			Type:           int32(tx.Type),
+			BlockNumber:    filteredBlock.Number,
+			IndexInBlock:   i,
+			ValidationCode: int32(tx.TxValidationCode),
+			Envelope:       block.DataAt(i),
+		}
+		switch tx.Type {
+		case common.HeaderType_CONFIG:
+			cb.Config = true
+		case common.HeaderType_ENDORSER_TRANSACTION:
+			action, err := protoutil.GetActionFromEnvelope(ct.Envelope)
+			if err != nil {
+				logger.Warnf("failed getting chaincode action of [%s] in block [%d]: [%s]", tx.Txid, filteredBlock.Number, err)
+				break
+			}
+			ct.Results = action.Results
+			ct.Namespaces, err = namespacesOf(action.Results)
+			if err != nil {
+				logger.Warnf("failed getting namespaces of [%s] in block [%d]: [%s]", tx.Txid, filteredBlock.Number, err)
+			}
+		}
+		cb.Transactions = append(cb.Transactions, ct)
+	}
+	return cb
+}
+
+// namespacesOf returns the namespaces touched by the passed marshalled rwset
+func namespacesOf(results []byte) ([]string, error) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(results); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling rwset")
+	}
+	namespaces := make([]string, 0, len(txRWSet.NsRwSets))
+	for _, ns := range txRWSet.NsRwSets {
+		namespaces = append(namespaces, ns.NameSpace)
+	}
+	return namespaces, nil
+}
+
+// maxQueuedBlocks is the number of blocks a listener queues before being dropped
+const maxQueuedBlocks = 1000
+
+// commitListeners notifies the listeners registered by the applications of the blocks processed by the committer
+type commitListeners struct {
+	lock      sync.RWMutex
+	listeners map[*queuedListener]struct{}
+}
+
+func newCommitListeners() *commitListeners {
+	return &commitListeners{listeners: map[*queuedListener]struct{}{}}
+}
+
+func (c *commitListeners) add(notify func(block *driver.CommittedBlock)) *queuedListener {
+	l := &queuedListener{
+		owner:  c,
+		notify: notify,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	c.lock.Lock()
+	c.listeners[l] = struct{}{}
+	c.lock.Unlock()
+	go l.run()
+	return l
+}
+
+func (c *commitListeners) remove(l *queuedListener) {
+	c.lock.Lock()
+	delete(c.listeners, l)
+	c.lock.Unlock()
+}
+
+// empty returns true if no listener is registered, the blocks need not be described then
+func (c *commitListeners) empty() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.listeners) == 0
+}
+
+// dispatch queues the passed block to all the listeners, without waiting for them.
+// The listeners that cannot keep up are dropped.
+func (c *commitListeners) dispatch(block *driver.CommittedBlock) {
+	var dropped []*queuedListener
+	c.lock.RLock()
+	for l := range c.listeners {
+		if !l.push(block) {
+			dropped = append(dropped, l)
+		}
+	}
+	c.lock.RUnlock()
+
+	for _, l := range dropped {
+		l.Remove()
+	}
+}
+
+// queuedListener notifies a listener from a goroutine of its own, the blocks queue up while the listener is busy.
+// A listener with more than maxQueuedBlocks blocks queued is dropped.
+type queuedListener struct {
+	owner  *commitListeners
+	notify func(block *driver.CommittedBlock)
+
+	lock   sync.Mutex
+	queue  []*driver.CommittedBlock
+	err    error
+	signal chan struct{}
+
+	done       chan struct{}
+	removeOnce sync.Once
+}
+
+// Remove unregisters the listener, the queued blocks are not notified
+func (l *queuedListener) Remove() {
+	l.removeOnce.Do(func() {
+		l.owner.remove(l)
+		close(l.done)
+	})
+}
+
+// Err returns the error that made the listener be dropped, if any
+func (l *queuedListener) Err() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.err
+}
+
+// push queues the passed block. It returns false if the queue is full, the listener must be dropped then.
+func (l *queuedListener) push(block *driver.CommittedBlock) bool {
+	l.lock.Lock()
+	if len(l.queue) >= maxQueuedBlocks {
+		if l.err == nil {
+			l.err = errors.Errorf("commit listener dropped at block [%d], more than [%d] blocks not notified", block.Number, maxQueuedBlocks)
+			logger.Warnf("%s", l.err)
+		}
+		l.queue = nil
+		l.lock.Unlock()
+		return false
+	}
+	l.queue = append(l.queue, block)
+	l.lock.Unlock()
+
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// pop returns the first block queued, if any
+func (l *queuedListener) pop() (*driver.CommittedBlock, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.queue) == 0 {
+		return nil, false
+	}
+	block := l.queue[0]
+	l.queue[0] = nil
+	l.queue = l.queue[1:]
+	return block, true
+}
+
+func (l *queuedListener) run() {
+	for {
+		block, ok := l.pop()
+		if !ok {
+			select {
+			case <-l.signal:
+				continue
+			case <-l.done:
+				return
+			}
+		}
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+		l.safeNotify(block)
+	}
+}
+
+// safeNotify notifies the passed block, a panic of the listener is logged and does not stop the notifications
+func (l *queuedListener) safeNotify(block *driver.CommittedBlock) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("commit listener panicked on block [%d]: [%v]\n%s", block.Number, r, debug.Stack())
+		}
+	}()
+	l.notify(block)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+type blockRecorder struct {
+	blocks  chan *driver.CommittedBlock
+	release chan struct{}
+}
+
+func newBlockRecorder() *blockRecorder {
+	return &blockRecorder{blocks: make(chan *driver.CommittedBlock, 10)}
+}
+
+func (r *blockRecorder) OnBlock(block *driver.CommittedBlock) {
+	if r.release != nil {
+		<-r.release
+	}
+	r.blocks <- block
+}
+
+func (r *blockRecorder) next(t *testing.T) *driver.CommittedBlock {
+	select {
+	case block := <-r.blocks:
+		return block
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no block notified")
+		return nil
+	}
+}
+
+type txRecorder struct {
+	txs chan *driver.CommittedTransaction
+}
+
+func (r *txRecorder) OnTransaction(tx *driver.CommittedTransaction) {
+	if tx.TxID == "panic" {
+		panic("listener failure")
+	}
+	r.txs <- tx
+}
+
+func (r *txRecorder) next(t *testing.T) *driver.CommittedTransaction {
+	select {
+	case tx := <-r.txs:
+		return tx
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no transaction notified")
+		return nil
+	}
+}
+
+func dispatch(t *testing.T, c *committer, block *common.Block) {
+	filteredBlock, err := filterBlock("channel", block)
+	assert.NoError(t, err)
+	c.commitListeners.dispatch(committedBlock(&fullBlock{Block: block}, filteredBlock))
+}
+
+func TestCommitListeners(t *testing.T) {
+	c, err := New("channel", nil, nil, time.Second, true)
+	assert.NoError(t, err)
+	assert.True(t, c.commitListeners.empty())
+
+	// a slow listener does not block the others
+	slow := newBlockRecorder()
+	slow.release = make(chan struct{})
+	c.AddBlockListener(slow)
+	blocks := newBlockRecorder()
+	blocksRegistration := c.AddBlockListener(blocks)
+	configs := newBlockRecorder()
+	c.AddConfigListener(configs)
+	assets := &txRecorder{txs: make(chan *driver.CommittedTransaction, 10)}
+	c.AddTransactionListener(assets, "asset")
+	assert.False(t, c.commitListeners.empty())
+
+	dispatch(t, c, newBlock(t, 0, tx{id: "config", config: true}))
+	dispatch(t, c, newBlock(t, 1,
+		tx{id: "tx1", valid: true, namespaces: []string{"asset", "other"}},
+		tx{id: "tx2", valid: false, namespaces: []string{"asset"}},
+		tx{id: "tx3", valid: true, namespaces: []string{"other"}},
+	))
+	dispatch(t, c, newBlock(t, 2, tx{id: "panic", valid: true, namespaces: []string{"asset"}}))
+	dispatch(t, c, newBlock(t, 3, tx{id: "tx4", valid: true, namespaces: []string{"asset"}}))
+
+	assert.Equal(t, uint64(0), blocks.next(t).Number)
+	block := blocks.next(t)
+	assert.Equal(t, uint64(1), block.Number)
+	assert.False(t, block.Config)
+	assert.Len(t, block.Transactions, 3)
+	assert.Equal(t, uint64(2), blocks.next(t).Number)
+	assert.Equal(t, uint64(3), blocks.next(t).Number)
+
+	block = configs.next(t)
+	assert.Equal(t, uint64(0), block.Number)
+	assert.True(t, block.Config)
+	assert.Equal(t, int32(common.HeaderType_CONFIG), block.Transactions[0].Type)
+
+	// transactions are filtered by namespace, the panic of the listener is recovered
+	tx := assets.next(t)
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, uint64(1), tx.BlockNumber)
+	assert.Equal(t, 0, tx.IndexInBlock)
+	assert.Equal(t, int32(pb.TxValidationCode_VALID), tx.ValidationCode)
+	assert.Equal(t, []string{"asset", "other"}, tx.Namespaces)
+	assert.NotEmpty(t, tx.Results)
+	tx = assets.next(t)
+	assert.Equal(t, "tx2", tx.TxID)
+	assert.Equal(t, 1, tx.IndexInBlock)
+	assert.Equal(t, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), tx.ValidationCode)
+	assert.Equal(t, "tx4", assets.next(t).TxID)
+
+	close(slow.release)
+	for i := uint64(0); i < 4; i++ {
+		assert.Equal(t, i, slow.next(t).Number)
+	}
+
+	// removed listeners are not notified anymore
+	blocksRegistration.Remove()
+	blocksRegistration.Remove()
+	dispatch(t, c, newBlock(t, 4))
+	assert.Equal(t, uint64(4), slow.next(t).Number)
+	select {
+	case <-blocks.blocks:
+		assert.Fail(t, "removed listener notified")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+type blockCounter struct {
+	count chan uint64
+}
+
+func (c *blockCounter) OnBlock(block *driver.CommittedBlock) {
+	c.count <- block.Number
+}
+
+func TestCommitListenersSlowListener(t *testing.T) {
+	c, err := New("channel", nil, nil, time.Second, true)
+	assert.NoError(t, err)
+
+	slow := newBlockRecorder()
+	slow.release = make(chan struct{})
+	defer close(slow.release)
+	slowRegistration := c.AddBlockListener(slow)
+	fast := &blockCounter{count: make(chan uint64, maxQueuedBlocks+10)}
+	fastRegistration := c.AddBlockListener(fast)
+
+	// the slow listener is busy with the first block while the others queue up, until it is dropped
+	for i := uint64(0); i <= maxQueuedBlocks+1; i++ {
+		c.commitListeners.dispatch(&driver.CommittedBlock{Number: i})
+		assert.Equal(t, i, <-fast.count)
+	}
+	assert.Error(t, slowRegistration.Err())
+	assert.Contains(t, slowRegistration.Err().Error(), "more than [1000] blocks not notified")
+	assert.NoError(t, fastRegistration.Err())
+	assert.Eventually(t, func() bool {
+		c.commitListeners.lock.RLock()
+		defer c.commitListeners.lock.RUnlock()
+		return len(c.commitListeners.listeners) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the fast listener keeps being notified
+	c.commitListeners.dispatch(&driver.CommittedBlock{Number: maxQueuedBlocks + 2})
+	assert.Equal(t, uint64(maxQueuedBlocks+2), <-fast.count)
+}
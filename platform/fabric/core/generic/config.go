@@ -59,6 +59,11 @@ func NewConfig(configService ConfigService, name string, defaultConfig bool) (*C
 	return nil, errors.Errorf("configuration for [%s] not found", name)
 }
 
+// Name returns the name of the network this configuration refers to
+func (c *Config) Name() string {
+	return c.name
+}
+
 func (c *Config) TLSEnabled() bool {
 	return c.configService.GetBool("fabric." + c.prefix + "tls.enabled")
 }
@@ -95,6 +100,18 @@ func (c *Config) Orderers() ([]*grpc.ConnectionConfig, error) {
 	return res, nil
 }
 
+// Ordering returns the configuration of the ordering service client
+func (c *Config) Ordering() (*config.Ordering, error) {
+	res := &config.Ordering{}
+	if !c.configService.IsSet("fabric." + c.prefix + "ordering") {
+		return res, nil
+	}
+	if err := c.configService.UnmarshalKey("fabric."+c.prefix+"ordering", res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (c *Config) Peers() ([]*grpc.ConnectionConfig, error) {
 	var res []*grpc.ConnectionConfig
 	if err := c.configService.UnmarshalKey("fabric."+c.prefix+"peers", &res); err != nil {
@@ -119,6 +136,31 @@ func (c *Config) VaultPersistenceOpts(opts interface{}) error {
 	return c.configService.UnmarshalKey("fabric."+c.prefix+"vault.persistence.opts", opts)
 }
 
+// DeliveryMode returns the type of blocks the delivery service subscribes to, either full or filtered
+func (c *Config) DeliveryMode() string {
+	return c.configService.GetString("fabric." + c.prefix + "delivery.mode")
+}
+
+// FinalityCacheSize returns the number of recently finalized transactions the finality listener remembers.
+// It returns zero, meaning the default size, if not set.
+func (c *Config) FinalityCacheSize() int {
+	var size int
+	if !c.configService.IsSet("fabric." + c.prefix + "finality.cacheSize") {
+		return 0
+	}
+	if err := c.configService.UnmarshalKey("fabric."+c.prefix+"finality.cacheSize", &size); err != nil {
+		logger.Warnf("invalid finality cache size, using the default one: [%s]", err)
+		return 0
+	}
+	return size
+}
+
+// FinalityPartyTimeout returns how long a remote party is given to notify the finality of a transaction.
+// It returns zero, meaning the default timeout, if not set.
+func (c *Config) FinalityPartyTimeout() time.Duration {
+	return c.configService.GetDuration("fabric." + c.prefix + "finality.partyTimeout")
+}
+
 func (c *Config) MSPConfigPath() string {
 	return c.configService.GetPath("fabric." + c.prefix + "mspConfigPath")
 }
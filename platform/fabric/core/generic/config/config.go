@@ -49,6 +49,25 @@ type Channel struct {
 	Default bool   `yaml:"default,omitempty"`
 }
 
+type Delivery struct {
+	// Mode is the type of blocks the delivery service subscribes to, either full (default) or filtered
+	Mode string `yaml:"mode,omitempty"`
+}
+
+type Ordering struct {
+	// Selection is the strategy used to pick the orderers to broadcast to, either roundrobin (default) or random
+	Selection string `yaml:"selection,omitempty"`
+	// NumRetries is the number of times a broadcast is retried on SERVICE_UNAVAILABLE or connection errors
+	NumRetries int `yaml:"numRetries,omitempty"`
+	// RetryInterval is the time to wait before the first retry. It doubles at each subsequent retry.
+	RetryInterval time.Duration `yaml:"retryInterval,omitempty"`
+	// BroadcastTo is the number of orderers an envelope is sent to at once. Default is 1.
+	BroadcastTo int `yaml:"broadcastTo,omitempty"`
+	// Quorum is the number of successful acks required when broadcasting to more than one orderer.
+	// Default is BroadcastTo.
+	Quorum int `yaml:"quorum,omitempty"`
+}
+
 type Network struct {
 	Default       bool                `yaml:"default,omitempty"`
 	BCCSP         *BCCSP              `yaml:"BCCSP,omitempty"`
@@ -57,8 +76,10 @@ type Network struct {
 	MSPs          []*MSP              `yaml:"msps"`
 	TLS           TLS                 `yaml:"tls"`
 	Orderers      []*ConnectionConfig `yaml:"orderers"`
+	Ordering      *Ordering           `yaml:"ordering,omitempty"`
 	Peers         []*ConnectionConfig `yaml:"peers"`
 	Channels      []*Channel          `yaml:"channels"`
+	Delivery      *Delivery           `yaml:"delivery,omitempty"`
 	Vault         Vault               `yaml:"vault"`
 	Endpoint      *Endpoint           `yaml:"endpoint,omitempty"`
 }
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 type VaultOpts struct {
-	Path string `yaml:"path"`
+	Path       string `yaml:"path"`
+	Driver     string `yaml:"driver,omitempty"`
+	DataSource string `yaml:"dataSource,omitempty"`
 }
 
 type VaultPersistence struct {
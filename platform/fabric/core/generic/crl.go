@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generic
+
+import (
+	"crypto/x509"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+
+	x5092 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/x509"
+	x509v "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
+)
+
+// updateCRLs passes the revocation lists of the organizations in the passed channel configuration
+// to the certificate validator, replacing those of the previous configuration
+func (c *channel) updateCRLs(config *common.Config) {
+	validator := x509v.GetValidator(c.sp)
+	if validator == nil || config == nil {
+		return
+	}
+	var crls [][]byte
+	var issuers []*x509.Certificate
+	if err := collectCRLs(config.ChannelGroup, &crls, &issuers); err != nil {
+		logger.Warnf("failed reading crls of channel [%s]: [%s]", c.name, err)
+		return
+	}
+	source := "fabric:" + c.network.Name() + ":" + c.name
+	if err := validator.SetCRLs(source, crls, issuers); err != nil {
+		logger.Warnf("failed setting crls of channel [%s]: [%s]", c.name, err)
+	}
+}
+
+func collectCRLs(group *common.ConfigGroup, crls *[][]byte, issuers *[]*x509.Certificate) error {
+	if group == nil {
+		return nil
+	}
+	if value, ok := group.Values[channelconfig.MSPKey]; ok {
+		mspConfig := &msp.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return errors.Wrap(err, "failed unmarshalling msp config")
+		}
+		fabricConfig := &msp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return errors.Wrap(err, "failed unmarshalling fabric msp config")
+		}
+		if len(fabricConfig.RevocationList) != 0 {
+			*crls = append(*crls, fabricConfig.RevocationList...)
+			for _, raw := range append(fabricConfig.RootCerts, fabricConfig.IntermediateCerts...) {
+				cert, err := x5092.PemDecodeCert(raw)
+				if err != nil {
+					return errors.WithMessagef(err, "failed parsing ca certificate of [%s]", fabricConfig.Name)
+				}
+				*issuers = append(*issuers, cert)
+			}
+		}
+	}
+	for _, sub := range group.Groups {
+		if err := collectCRLs(sub, crls, issuers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -55,8 +55,14 @@ type DeliverClient interface {
 	// NewDeliverFilterd returns a DeliverFiltered
 	NewDeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error)
 
+	// NewDeliver returns a DeliverFiltered that receives full blocks
+	NewDeliver(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error)
+
 	// Certificate returns tls certificate for the deliver client to peer
 	Certificate() *tls.Certificate
+
+	// Close closes the connection to the peer
+	Close()
 }
 
 // deliverClient implements DeliverClient interface
@@ -88,6 +94,35 @@ func NewDeliverClient(config *grpc2.ConnectionConfig) (DeliverClient, error) {
 
 // NewDeliverFilterd creates a DeliverFiltered client
 func (d *deliverClient) NewDeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error) {
+	if err := d.reconnect(); err != nil {
+		return nil, err
+	}
+
+	// create a new DeliverFiltered
+	df, err := pb.NewDeliverClient(d.conn).DeliverFiltered(ctx, opts...)
+	if err != nil {
+		rpcStatus, _ := status.FromError(err)
+		return nil, errors.Wrapf(err, "failed to new a deliver filtered, rpcStatus=%+v", rpcStatus)
+	}
+	return df, nil
+}
+
+// NewDeliver creates a client that receives full blocks
+func (d *deliverClient) NewDeliver(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error) {
+	if err := d.reconnect(); err != nil {
+		return nil, err
+	}
+
+	// create a new Deliver
+	df, err := pb.NewDeliverClient(d.conn).Deliver(ctx, opts...)
+	if err != nil {
+		rpcStatus, _ := status.FromError(err)
+		return nil, errors.Wrapf(err, "failed to new a deliver, rpcStatus=%+v", rpcStatus)
+	}
+	return df, nil
+}
+
+func (d *deliverClient) reconnect() error {
 	if d.conn != nil {
 		// close the old connection because new connection will restart its timeout
 		d.conn.Close()
@@ -97,16 +132,15 @@ func (d *deliverClient) NewDeliverFiltered(ctx context.Context, opts ...grpc.Cal
 	var err error
 	d.conn, err = d.grpcClient.NewConnection(d.peerAddr)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "failed to connect to peer %s", d.peerAddr)
+		return errors.WithMessagef(err, "failed to connect to peer %s", d.peerAddr)
 	}
+	return nil
+}
 
-	// create a new DeliverFiltered
-	df, err := pb.NewDeliverClient(d.conn).DeliverFiltered(ctx)
-	if err != nil {
-		rpcStatus, _ := status.FromError(err)
-		return nil, errors.Wrapf(err, "failed to new a deliver filtered, rpcStatus=%+v", rpcStatus)
+func (d *deliverClient) Close() {
+	if d.conn != nil {
+		d.conn.Close()
 	}
-	return df, nil
 }
 
 func (d *deliverClient) Certificate() *tls.Certificate {
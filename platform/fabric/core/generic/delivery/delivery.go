@@ -9,11 +9,13 @@ package delivery
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/committer"
@@ -23,14 +25,26 @@ import (
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 )
 
 var logger = flogging.MustGetLogger("fabric-sdk.delivery")
 
-// Committer models a filtered block committer
+const (
+	// FullBlocks makes the delivery service subscribe to full blocks with the Deliver API.
+	// Blocks are passed to the committer as they are received.
+	FullBlocks = "full"
+	// FilteredBlocks makes the delivery service subscribe to filtered blocks with the DeliverFiltered API.
+	// This is suited for light clients, the committer must fetch the full blocks on its own.
+	FilteredBlocks = "filtered"
+)
+
+// Committer models a block committer
 type Committer interface {
-	// Commit commits the transaction in the passed block
+	// Commit commits the transaction in the passed filtered block
 	Commit(block *pb.FilteredBlock)
+	// CommitBlock commits the transaction in the passed block, it returns an error if the block is malformed
+	CommitBlock(block *common.Block) error
 }
 
 // Vault models a key-value store that can be updated by committing rwsets
@@ -40,40 +54,74 @@ type Vault interface {
 }
 
 type Network interface {
+	Name() string
 	Channel(name string) (driver.Channel, error)
-	Peers() []*grpc.ConnectionConfig
 	LocalMembership() driver.LocalMembership
 }
 
+// PeerSelector selects the peer to connect to and keeps track of the peers' health
+type PeerSelector interface {
+	// Current returns the peer currently selected
+	Current() *grpc.ConnectionConfig
+	// Failed marks the peer at the passed address as unhealthy, moving the selection to another peer
+	Failed(address string)
+	// Succeeded marks the peer at the passed address as healthy
+	Succeeded(address string)
+	// Healthy returns the peers that are currently considered healthy
+	Healthy() []*grpc.ConnectionConfig
+}
+
 type delivery struct {
-	channel              string
-	sp                   view2.ServiceProvider
-	network              Network
-	waitForEventTimeout  time.Duration
-	peerConnectionConfig *grpc.ConnectionConfig
-	committer            Committer
-	vault                Vault
+	channel             string
+	sp                  view2.ServiceProvider
+	network             Network
+	peers               PeerSelector
+	waitForEventTimeout time.Duration
+	committer           Committer
+	vault               Vault
+	mode                string
+
+	// lastBlock is the number of the last block committed, valid if hasLastBlock is true
+	lastBlock    uint64
+	hasLastBlock bool
+
+	metrics *Metrics
+	// stateLock guards the state of the stream reported by the health check
+	stateLock sync.RWMutex
+	connected bool
+	lastErr   error
 }
 
 func New(
 	channel string,
 	sp view2.ServiceProvider,
 	network Network,
+	peers PeerSelector,
 	committer Committer,
 	vault Vault,
 	waitForEventTimeout time.Duration,
+	mode string,
 ) (*delivery, error) {
 	if len(channel) == 0 {
 		panic("expected a channel, got empty string")
 	}
+	switch mode {
+	case "":
+		mode = FullBlocks
+	case FullBlocks, FilteredBlocks:
+	default:
+		return nil, errors.Errorf("invalid delivery mode [%s], expected [%s] or [%s]", mode, FullBlocks, FilteredBlocks)
+	}
 	d := &delivery{
-		channel:              channel,
-		sp:                   sp,
-		network:              network,
-		waitForEventTimeout:  waitForEventTimeout,
-		peerConnectionConfig: network.Peers()[0],
-		committer:            committer,
-		vault:                vault,
+		channel:             channel,
+		sp:                  sp,
+		network:             network,
+		peers:               peers,
+		waitForEventTimeout: waitForEventTimeout,
+		committer:           committer,
+		vault:               vault,
+		mode:                mode,
+		metrics:             NewMetrics(operations.GetMetricsProvider(sp), network.Name(), channel),
 	}
 	return d, nil
 }
@@ -83,26 +131,38 @@ func (d *delivery) Start() {
 }
 
 func (d *delivery) run() {
+	var client DeliverClient
 	var df DeliverFiltered
 	var err error
+	var address string
 	for {
-		address := d.peerConnectionConfig.Address
-		logger.Debugf("deliver service [%s:%s], next event...", address, d.channel)
 		if df == nil {
+			// the connection to the previous peer is not used anymore
+			if client != nil {
+				client.Close()
+				client = nil
+			}
+			peer := d.peers.Current()
+			address = peer.Address
 			logger.Debugf("deliver service [%s:%s], connecting...", address, d.channel)
-			df, err = d.connect()
+			client, df, err = d.connect(peer)
 			if err != nil {
-				logger.Errorf("failed connecting to delivery service [%s:%s] [%s]. Wait 10 sec before reconnecting", address, d.channel, err)
-				time.Sleep(10 * time.Second)
-				logger.Debugf("reconnecting to delivery service [%s:%s]", address, d.channel)
+				logger.Errorf("failed connecting to delivery service [%s:%s] [%s]", address, d.channel, err)
+				d.setState(false, err)
+				d.failover(address)
 				continue
 			}
+			d.peers.Succeeded(address)
+			d.setState(true, nil)
 		}
+		logger.Debugf("deliver service [%s:%s], next event...", address, d.channel)
 
 		resp, err := df.Recv()
 		if err != nil {
 			df = nil
+			d.setState(false, err)
 			logger.Errorf("delivery service [%s:%s], failed receiving response [%s]", address, d.channel, errors.WithMessagef(err, "error receiving deliver response from peer %s", address))
+			d.failover(address)
 			continue
 		}
 
@@ -111,39 +171,158 @@ func (d *delivery) run() {
 			logger.Debugf("delivery service [%s:%s], commit block [%d]", address, d.channel, r.FilteredBlock.Number)
 
 			d.committer.Commit(r.FilteredBlock)
+			d.lastBlock, d.hasLastBlock = r.FilteredBlock.Number, true
+			d.metrics.BlocksReceived.Add(1)
+			d.metrics.BlockHeight.Set(float64(r.FilteredBlock.Number))
+		case *pb.DeliverResponse_Block:
+			if err := d.committer.CommitBlock(r.Block); err != nil {
+				// the block is requested again from another peer
+				df = nil
+				d.setState(false, err)
+				logger.Errorf("delivery service [%s:%s], failed committing block [%s]", address, d.channel, err)
+				d.failover(address)
+				continue
+			}
+			logger.Debugf("delivery service [%s:%s], committed block [%d]", address, d.channel, r.Block.Header.Number)
+			d.lastBlock, d.hasLastBlock = r.Block.Header.Number, true
+			d.metrics.BlocksReceived.Add(1)
+			d.metrics.BlockHeight.Set(float64(r.Block.Header.Number))
+			if lag, ok := blockLag(r.Block); ok {
+				d.metrics.Lag.Set(lag.Seconds())
+			}
 		case *pb.DeliverResponse_Status:
 			if r.Status == common.Status_NOT_FOUND {
 				df = nil
-				logger.Warnf("delivery service [%s:%s] status [%s], wait a few seconds before retrying", address, d.channel, r.Status)
-				time.Sleep(10 * time.Second)
+				d.setState(false, errors.Errorf("status [%s]", r.Status))
+				logger.Warnf("delivery service [%s:%s] status [%s], try another peer", address, d.channel, r.Status)
+				d.failover(address)
 			} else {
 				logger.Warnf("delivery service [%s:%s] status [%s]", address, d.channel, r.Status)
 			}
 		default:
 			df = nil
+			d.setState(false, errors.Errorf("unexpected response [%s]", r))
 			logger.Errorf("delivery service [%s:%s], got [%s]", address, d.channel, r)
+			d.failover(address)
 		}
 	}
 }
 
-func (d *delivery) connect() (DeliverFiltered, error) {
-	address := d.peerConnectionConfig.Address
+// failover marks the passed peer as failed so that the next connection goes to another peer.
+// If no healthy peer is left, it waits a few seconds before returning.
+func (d *delivery) failover(address string) {
+	d.metrics.Reconnects.Add(1)
+	d.peers.Failed(address)
+	if len(d.peers.Healthy()) == 0 {
+		logger.Warnf("delivery service [%s], no healthy peer available. Wait 10 sec before reconnecting", d.channel)
+		time.Sleep(10 * time.Second)
+	}
+	logger.Debugf("reconnecting to delivery service [%s] using [%s]", d.channel, d.peers.Current().Address)
+}
+
+// HealthCheck returns an error if the delivery stream is not connected to a peer
+func (d *delivery) HealthCheck(context.Context) error {
+	d.stateLock.RLock()
+	defer d.stateLock.RUnlock()
+	if d.connected {
+		return nil
+	}
+	if d.lastErr != nil {
+		return errors.WithMessagef(d.lastErr, "delivery service of [%s] not connected", d.channel)
+	}
+	return errors.Errorf("delivery service of [%s] not connected", d.channel)
+}
+
+func (d *delivery) setState(connected bool, err error) {
+	d.stateLock.Lock()
+	defer d.stateLock.Unlock()
+	d.connected, d.lastErr = connected, err
+}
+
+// blockLag returns how long ago the first transaction of the passed block has been created
+func blockLag(block *common.Block) (time.Duration, bool) {
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return 0, false
+	}
+	env, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[0])
+	if err != nil {
+		return 0, false
+	}
+	chdr, err := protoutil.ChannelHeader(env)
+	if err != nil || chdr.Timestamp == nil {
+		return 0, false
+	}
+	return time.Since(time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))), true
+}
+
+// connect opens a deliver stream to the passed peer.
+// The returned client must be closed once the stream is not used anymore, it is closed already if connect fails.
+func (d *delivery) connect(peer *grpc.ConnectionConfig) (DeliverClient, DeliverFiltered, error) {
+	address := peer.Address
 	logger.Debugf("connecting to deliver service at [%s] for channel [%s]", address, d.channel)
 
 	var ctx context.Context
 	//var cancelFunc context.CancelFunc
 
-	deliverClient, err := NewDeliverClient(d.peerConnectionConfig)
+	deliverClient, err := NewDeliverClient(peer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	//ctx, cancelFunc = context.WithTimeout(context.Background(), d.waitForEventTimeout)
 	//defer cancelFunc()
 	ctx = context.Background()
-	deliverFiltered, err := deliverClient.NewDeliverFiltered(ctx)
+	var deliverFiltered DeliverFiltered
+	if d.mode == FilteredBlocks {
+		deliverFiltered, err = deliverClient.NewDeliverFiltered(ctx)
+	} else {
+		deliverFiltered, err = deliverClient.NewDeliver(ctx)
+	}
 	if err != nil {
-		return nil, err
+		deliverClient.Close()
+		return nil, nil, err
+	}
+
+	start, err := d.startPosition()
+	if err != nil {
+		deliverClient.Close()
+		return nil, nil, err
+	}
+
+	blockEnvelope, err := CreateDeliverEnvelope(
+		d.channel,
+		d.network.LocalMembership().DefaultSigningIdentity(),
+		deliverClient.Certificate(),
+		hash.GetHasher(d.sp),
+		start,
+	)
+	if err != nil {
+		deliverClient.Close()
+		return nil, nil, err
+	}
+	err = DeliverSend(deliverFiltered, address, blockEnvelope)
+	if err != nil {
+		deliverClient.Close()
+		return nil, nil, err
+	}
+
+	logger.Debugf("connected to deliver service at [%s] with mode [%s]", address, d.mode)
+	return deliverClient, deliverFiltered, nil
+}
+
+// startPosition returns the position from which the delivery must start.
+// If a block has already been committed, the delivery resumes from the next block.
+// Otherwise, it starts from the block containing the last transaction stored in the vault, if any.
+func (d *delivery) startPosition() (*ab.SeekPosition, error) {
+	start := &ab.SeekPosition{}
+	if d.hasLastBlock {
+		start.Type = &ab.SeekPosition_Specified{
+			Specified: &ab.SeekSpecified{
+				Number: d.lastBlock + 1,
+			},
+		}
+		logger.Debugf("resuming from block [%d]", d.lastBlock+1)
+		return start, nil
 	}
 
 	lastTxID, err := d.vault.GetLastTxID()
@@ -151,7 +330,6 @@ func (d *delivery) connect() (DeliverFiltered, error) {
 		return nil, errors.WithMessagef(err, "failed getting last transaction committed/discarted from the vault")
 	}
 
-	start := &ab.SeekPosition{}
 	if len(lastTxID) != 0 && !strings.HasPrefix(lastTxID, committer.ConfigTXPrefix) {
 		// Retrieve block from Fabric
 		ch, err := d.network.Channel(d.channel)
@@ -174,22 +352,5 @@ func (d *delivery) connect() (DeliverFiltered, error) {
 		}
 		logger.Debugf("starting from the beginning, no last transaction found")
 	}
-
-	blockEnvelope, err := CreateDeliverEnvelope(
-		d.channel,
-		d.network.LocalMembership().DefaultSigningIdentity(),
-		deliverClient.Certificate(),
-		hash.GetHasher(d.sp),
-		start,
-	)
-	if err != nil {
-		return nil, err
-	}
-	err = DeliverSend(deliverFiltered, d.peerConnectionConfig.Address, blockEnvelope)
-	if err != nil {
-		return nil, err
-	}
-
-	logger.Debugf("connected to deliver service at [%s]", address)
-	return deliverFiltered, nil
+	return start, nil
 }
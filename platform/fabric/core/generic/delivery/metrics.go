@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package delivery
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	blocksReceivedCounterOpts = metrics.CounterOpts{
+		Namespace:    "fabric",
+		Subsystem:    "delivery",
+		Name:         "blocks_received",
+		Help:         "Blocks received from the delivery service of the peers.",
+		LabelNames:   []string{"network", "channel"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}",
+	}
+
+	blockHeightGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "fabric",
+		Subsystem:    "delivery",
+		Name:         "block_height",
+		Help:         "Number of the last block received from the delivery service.",
+		LabelNames:   []string{"network", "channel"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}",
+	}
+
+	lagGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "fabric",
+		Subsystem:    "delivery",
+		Name:         "lag",
+		Help:         "Seconds between the creation of the first transaction of the last block received and its delivery. Full blocks only.",
+		LabelNames:   []string{"network", "channel"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}",
+	}
+
+	reconnectsCounterOpts = metrics.CounterOpts{
+		Namespace:    "fabric",
+		Subsystem:    "delivery",
+		Name:         "reconnects",
+		Help:         "Times the delivery stream has been reconnected, after a failure.",
+		LabelNames:   []string{"network", "channel"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}",
+	}
+)
+
+// Metrics are the metrics of the delivery stream of a channel
+type Metrics struct {
+	BlocksReceived metrics.Counter
+	BlockHeight    metrics.Gauge
+	Lag            metrics.Gauge
+	Reconnects     metrics.Counter
+}
+
+// NewMetrics returns the metrics of the delivery stream of the passed channel
+func NewMetrics(p metrics.Provider, network, channel string) *Metrics {
+	return &Metrics{
+		BlocksReceived: p.NewCounter(blocksReceivedCounterOpts).With("network", network, "channel", channel),
+		BlockHeight:    p.NewGauge(blockHeightGaugeOpts).With("network", network, "channel", channel),
+		Lag:            p.NewGauge(lagGaugeOpts).With("network", network, "channel", channel),
+		Reconnects:     p.NewCounter(reconnectsCounterOpts).With("network", network, "channel", channel),
+	}
+}
@@ -0,0 +1,15 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generic
+
+import (
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+func (c *channel) SubscribeChaincodeEvents(request *driver.ChaincodeEventsRequest) (driver.ChaincodeEventStream, error) {
+	return c.chaincodeEvents.SubscribeChaincodeEvents(request)
+}
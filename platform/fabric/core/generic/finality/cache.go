@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package finality
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/delivery"
+)
+
+// DefaultCacheSize is the number of finalized transactions remembered by default
+const DefaultCacheSize = 1000
+
+// eventCache remembers the events of the most recently finalized transactions.
+// When full, the oldest event is evicted.
+type eventCache struct {
+	lock   sync.RWMutex
+	events map[string]delivery.TxEvent
+	// ring contains the cached transaction ids in insertion order, next is the slot to overwrite
+	ring []string
+	next int
+}
+
+func newEventCache(size int) *eventCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &eventCache{
+		events: make(map[string]delivery.TxEvent, size),
+		ring:   make([]string, size),
+	}
+}
+
+func (c *eventCache) get(txID string) (delivery.TxEvent, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	event, ok := c.events[txID]
+	return event, ok
+}
+
+func (c *eventCache) add(event delivery.TxEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.events[event.Txid]; ok {
+		c.events[event.Txid] = event
+		return
+	}
+	if evicted := c.ring[c.next]; len(evicted) != 0 {
+		delete(c.events, evicted)
+	}
+	c.ring[c.next] = event.Txid
+	c.next = (c.next + 1) % len(c.ring)
+	c.events[event.Txid] = event
+}
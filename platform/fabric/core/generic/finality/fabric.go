@@ -8,9 +8,11 @@ package finality
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 
 	"github.com/pkg/errors"
 
@@ -19,7 +21,15 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 )
 
+const (
+	// minReconnectBackoff is the wait before reconnecting after the first failure
+	minReconnectBackoff = 100 * time.Millisecond
+	// maxReconnectBackoff bounds the wait before reconnecting after repeated failures
+	maxReconnectBackoff = 10 * time.Second
+)
+
 type Network interface {
+	Name() string
 	Peers() []*grpc.ConnectionConfig
 	LocalMembership() driver.LocalMembership
 	Comm(channel string) (driver.Comm, error)
@@ -31,45 +41,230 @@ type Hasher interface {
 	Hash(msg []byte) (hash []byte, err error)
 }
 
+// PeerSelector selects the peer to connect to and keeps track of the peers' health
+type PeerSelector interface {
+	// Len returns the number of peers available
+	Len() int
+	// Current returns the peer currently selected
+	Current() *grpc.ConnectionConfig
+	// Failed marks the peer at the passed address as unhealthy, moving the selection to another peer
+	Failed(address string)
+	// Succeeded marks the peer at the passed address as healthy
+	Succeeded(address string)
+}
+
+// fabricFinality keeps a single deliver stream open to the peers of a channel and fans out the status of the
+// committed transactions to the callers of IsFinal.
+// The stream is opened with the first call to IsFinal and it is kept open for the lifetime of the node.
 type fabricFinality struct {
-	channel              string
-	network              Network
-	hasher               Hasher
-	waitForEventTimeout  time.Duration
-	peerConnectionConfig *grpc.ConnectionConfig
+	channel             string
+	network             Network
+	peers               PeerSelector
+	hasher              Hasher
+	waitForEventTimeout time.Duration
+
+	lock        sync.Mutex
+	started     bool
+	subscribers map[string][]chan delivery.TxEvent
+	cache       *eventCache
+
+	// lastBlock is the number of the last block received, valid if hasLastBlock is true
+	lastBlock    uint64
+	hasLastBlock bool
+
+	newClient  func(*grpc.ConnectionConfig) (delivery.DeliverClient, error)
+	minBackoff time.Duration
+	maxBackoff time.Duration
 }
 
-func NewFabricFinality(channel string, network Network, hasher Hasher, waitForEventTimeout time.Duration) (*fabricFinality, error) {
+func NewFabricFinality(channel string, network Network, peers PeerSelector, hasher Hasher, waitForEventTimeout time.Duration, cacheSize int) (*fabricFinality, error) {
 	if len(channel) == 0 {
 		panic("expected a channel, got empty string")
 	}
 
 	d := &fabricFinality{
-		channel:              channel,
-		network:              network,
-		hasher:               hasher,
-		waitForEventTimeout:  waitForEventTimeout,
-		peerConnectionConfig: network.Peers()[0],
+		channel:             channel,
+		network:             network,
+		peers:               peers,
+		hasher:              hasher,
+		waitForEventTimeout: waitForEventTimeout,
+		subscribers:         map[string][]chan delivery.TxEvent{},
+		cache:               newEventCache(cacheSize),
+		newClient:           delivery.NewDeliverClient,
+		minBackoff:          minReconnectBackoff,
+		maxBackoff:          maxReconnectBackoff,
 	}
 
 	return d, nil
 }
 
-func (d *fabricFinality) IsFinal(txID string, address string) error {
-	var eventCh chan delivery.TxEvent
-	var ctx context.Context
-	var cancelFunc context.CancelFunc
+// IsFinal waits for the passed transaction to be committed.
+// Transactions finalized recently are answered from the cache without waiting.
+func (d *fabricFinality) IsFinal(txID string) error {
+	ch := make(chan delivery.TxEvent, 1)
+	if event, ok := d.subscribe(txID, ch); ok {
+		logger.Debugf("finality of [%s] found in cache", txID)
+		return eventToError(event)
+	}
+	defer d.unsubscribe(txID, ch)
+
+	select {
+	case event := <-ch:
+		return eventToError(event)
+	case <-time.After(d.waitForEventTimeout):
+		return errors.Errorf("timed out waiting for committing txid %s", txID)
+	}
+}
+
+// subscribe registers the passed channel to receive the event of the passed transaction.
+// If the event is already cached, it is returned and no subscription takes place.
+func (d *fabricFinality) subscribe(txID string, ch chan delivery.TxEvent) (delivery.TxEvent, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if event, ok := d.cache.get(txID); ok {
+		return event, true
+	}
+	d.subscribers[txID] = append(d.subscribers[txID], ch)
+	if !d.started {
+		d.started = true
+		go d.run()
+	}
+	return delivery.TxEvent{}, false
+}
+
+func (d *fabricFinality) unsubscribe(txID string, ch chan delivery.TxEvent) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	subscribers := d.subscribers[txID]
+	for i, subscriber := range subscribers {
+		if subscriber == ch {
+			subscribers = append(subscribers[:i], subscribers[i+1:]...)
+			break
+		}
+	}
+	if len(subscribers) == 0 {
+		delete(d.subscribers, txID)
+		return
+	}
+	d.subscribers[txID] = subscribers
+}
+
+// run receives the filtered blocks from the currently selected peer, failing over to the other peers
+// when the stream breaks
+func (d *fabricFinality) run() {
+	var client delivery.DeliverClient
+	var df delivery.DeliverFiltered
+	var address string
+	backoff := d.minBackoff
+	for {
+		if df == nil {
+			if client != nil {
+				// release the connection to the previous peer before opening a new one
+				client.Close()
+				client = nil
+			}
+			peer := d.peers.Current()
+			address = peer.Address
+			var err error
+			client, df, err = d.connect(peer)
+			if err != nil {
+				logger.Warnf("failed connecting to [%s] to listen to finality on channel [%s]: [%s]", address, d.channel, err)
+				d.failover(address, &backoff)
+				continue
+			}
+			d.peers.Succeeded(address)
+		}
+
+		resp, err := df.Recv()
+		if err != nil {
+			logger.Warnf("finality listener [%s:%s], failed receiving response [%s]", address, d.channel, err)
+			df = nil
+			d.failover(address, &backoff)
+			continue
+		}
+		switch r := resp.Type.(type) {
+		case *pb.DeliverResponse_FilteredBlock:
+			d.dispatch(address, r.FilteredBlock)
+			backoff = d.minBackoff
+		case *pb.DeliverResponse_Status:
+			logger.Warnf("finality listener [%s:%s] got status [%s], try another peer", address, d.channel, r.Status)
+			df = nil
+			d.failover(address, &backoff)
+		default:
+			logger.Errorf("finality listener [%s:%s] received unexpected response type [%T]", address, d.channel, r)
+			df = nil
+			d.failover(address, &backoff)
+		}
+	}
+}
+
+// dispatch caches the status of the transactions in the passed block and notifies the subscribers
+func (d *fabricFinality) dispatch(address string, block *pb.FilteredBlock) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.lastBlock, d.hasLastBlock = block.Number, true
+	for i, tx := range block.FilteredTransactions {
+		event := delivery.TxEvent{
+			Txid:         tx.Txid,
+			Block:        block.Number,
+			IndexInBlock: i,
+			CommitPeer:   address,
+		}
+		if tx.TxValidationCode == pb.TxValidationCode_VALID {
+			event.Committed = true
+		} else {
+			event.Err = errors.Errorf("transaction [%s] status is not valid: %s", tx.Txid, tx.TxValidationCode)
+		}
+		d.cache.add(event)
 
-	deliverClient, err := delivery.NewDeliverClient(d.peerConnectionConfig)
+		subscribers := d.subscribers[tx.Txid]
+		logger.Debugf("transaction [%s] in block [%d], notify [%d] subscribers", tx.Txid, block.Number, len(subscribers))
+		for _, subscriber := range subscribers {
+			select {
+			case subscriber <- event:
+			default:
+			}
+		}
+		delete(d.subscribers, tx.Txid)
+	}
+}
+
+// failover marks the passed peer as failed so that the next connection goes to another peer.
+// It waits for the passed backoff before returning, and doubles it up to the maximum backoff, so that
+// a peer failing right away is not reconnected in a tight loop.
+func (d *fabricFinality) failover(address string, backoff *time.Duration) {
+	d.peers.Failed(address)
+	logger.Debugf("finality listener [%s], wait [%s] before reconnecting", d.channel, *backoff)
+	time.Sleep(*backoff)
+	*backoff *= 2
+	if *backoff > d.maxBackoff {
+		*backoff = d.maxBackoff
+	}
+}
+
+// connect opens a deliver stream to the passed peer, the returned client must be closed once the stream is
+// no longer used
+func (d *fabricFinality) connect(peer *grpc.ConnectionConfig) (delivery.DeliverClient, delivery.DeliverFiltered, error) {
+	deliverClient, err := d.newClient(peer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deliverFiltered, err := d.openStream(deliverClient, peer)
 	if err != nil {
-		return err
+		deliverClient.Close()
+		return nil, nil, err
 	}
+	return deliverClient, deliverFiltered, nil
+}
 
-	ctx, cancelFunc = context.WithTimeout(context.Background(), d.waitForEventTimeout)
-	defer cancelFunc()
-	deliverFiltered, err := deliverClient.NewDeliverFiltered(ctx)
+func (d *fabricFinality) openStream(deliverClient delivery.DeliverClient, peer *grpc.ConnectionConfig) (delivery.DeliverFiltered, error) {
+	deliverFiltered, err := deliverClient.NewDeliverFiltered(context.Background())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	blockEnvelope, err := delivery.CreateDeliverEnvelope(
@@ -77,26 +272,42 @@ func (d *fabricFinality) IsFinal(txID string, address string) error {
 		d.network.LocalMembership().DefaultSigningIdentity(),
 		deliverClient.Certificate(),
 		d.hasher,
-		&ab.SeekPosition{
-			Type: &ab.SeekPosition_Newest{
-				Newest: &ab.SeekNewest{},
-			},
-		},
+		d.startPosition(),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = delivery.DeliverSend(deliverFiltered, address, blockEnvelope)
-	if err != nil {
-		return err
+	if err := delivery.DeliverSend(deliverFiltered, peer.Address, blockEnvelope); err != nil {
+		return nil, err
 	}
-	eventCh = make(chan delivery.TxEvent, 1)
-	go delivery.DeliverReceive(deliverFiltered, address, txID, eventCh)
-	committed, _, _, err := delivery.DeliverWaitForResponse(ctx, eventCh, txID)
-	if err != nil {
-		return err
+	return deliverFiltered, nil
+}
+
+// startPosition returns the newest block, unless a block has already been received.
+// In that case, the listener resumes from the next block so that no transaction is missed.
+func (d *fabricFinality) startPosition() *ab.SeekPosition {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.hasLastBlock {
+		return &ab.SeekPosition{
+			Type: &ab.SeekPosition_Specified{
+				Specified: &ab.SeekSpecified{Number: d.lastBlock + 1},
+			},
+		}
+	}
+	return &ab.SeekPosition{
+		Type: &ab.SeekPosition_Newest{
+			Newest: &ab.SeekNewest{},
+		},
+	}
+}
+
+func eventToError(event delivery.TxEvent) error {
+	if event.Err != nil {
+		return event.Err
 	}
-	if !committed {
+	if !event.Committed {
 		return errors.New("not committed")
 	}
 	return nil
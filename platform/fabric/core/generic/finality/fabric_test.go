@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package finality
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpc2 "google.golang.org/grpc"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/delivery"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+func TestFanOut(t *testing.T) {
+	d, err := NewFabricFinality("ch", nil, nil, nil, time.Second, 2)
+	assert.NoError(t, err)
+	// do not connect to any peer, blocks are dispatched by the test
+	d.started = true
+
+	results := make(chan error, 3)
+	for i := 0; i < 2; i++ {
+		go func() { results <- d.IsFinal("tx1") }()
+	}
+	go func() { results <- d.IsFinal("tx2") }()
+	assert.Eventually(t, func() bool {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		return len(d.subscribers["tx1"]) == 2 && len(d.subscribers["tx2"]) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	d.dispatch("peer0", &pb.FilteredBlock{Number: 5, FilteredTransactions: []*pb.FilteredTransaction{
+		{Txid: "tx1", TxValidationCode: pb.TxValidationCode_VALID},
+		{Txid: "tx2", TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT},
+	}})
+	errs := []error{<-results, <-results, <-results}
+	valid := 0
+	for _, err := range errs {
+		if err == nil {
+			valid++
+		}
+	}
+	assert.Equal(t, 2, valid)
+	assert.Len(t, d.subscribers, 0)
+
+	// late callers are answered from the cache
+	assert.NoError(t, d.IsFinal("tx1"))
+	assert.Error(t, d.IsFinal("tx2"))
+
+	// the cache is bounded, the oldest transactions are evicted
+	d.dispatch("peer0", &pb.FilteredBlock{Number: 6, FilteredTransactions: []*pb.FilteredTransaction{
+		{Txid: "tx3", TxValidationCode: pb.TxValidationCode_VALID},
+	}})
+	_, ok := d.cache.get("tx1")
+	assert.False(t, ok)
+	_, ok = d.cache.get("tx3")
+	assert.True(t, ok)
+
+	// the listener resumes after the last block received
+	assert.Equal(t, uint64(7), d.startPosition().GetSpecified().Number)
+}
+
+func (l *localMembership) DefaultSigningIdentity() driver.SigningIdentity { return &signer{} }
+
+type signer struct{}
+
+func (s *signer) Serialize() ([]byte, error) { return []byte("me"), nil }
+
+func (s *signer) Sign(msg []byte) ([]byte, error) { return []byte("signature"), nil }
+
+// peers selects the peers in turn, moving to the next one on failure
+type peers struct {
+	lock      sync.Mutex
+	addresses []string
+	current   int
+	failed    []string
+}
+
+func (p *peers) Len() int { return len(p.addresses) }
+
+func (p *peers) Current() *grpc.ConnectionConfig {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return &grpc.ConnectionConfig{Address: p.addresses[p.current]}
+}
+
+func (p *peers) Failed(address string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.failed = append(p.failed, address)
+	p.current = (p.current + 1) % len(p.addresses)
+}
+
+func (p *peers) Succeeded(address string) {}
+
+// deliverClient opens the passed stream, or fails if there is none
+type deliverClient struct {
+	stream *stream
+	closed chan struct{}
+}
+
+func (c *deliverClient) NewDeliverFiltered(ctx context.Context, opts ...grpc2.CallOption) (delivery.DeliverFiltered, error) {
+	if c.stream == nil {
+		return nil, errors.New("connection refused")
+	}
+	return c.stream, nil
+}
+
+func (c *deliverClient) NewDeliver(ctx context.Context, opts ...grpc2.CallOption) (delivery.DeliverFiltered, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *deliverClient) Certificate() *tls.Certificate { return nil }
+
+func (c *deliverClient) Close() { close(c.closed) }
+
+// stream returns its responses, then fails. If it has no response, it blocks until the test ends.
+type stream struct {
+	responses []*pb.DeliverResponse
+	done      chan struct{}
+}
+
+func (s *stream) Send(*common.Envelope) error { return nil }
+
+func (s *stream) Recv() (*pb.DeliverResponse, error) {
+	if len(s.responses) == 0 {
+		<-s.done
+		return nil, errors.New("stream closed")
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	if response == nil {
+		return nil, errors.New("connection reset")
+	}
+	return response, nil
+}
+
+func (s *stream) CloseSend() error { return nil }
+
+func TestReconnect(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	block := &pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: &pb.FilteredBlock{
+		Number:               5,
+		FilteredTransactions: []*pb.FilteredTransaction{{Txid: "tx1", TxValidationCode: pb.TxValidationCode_VALID}},
+	}}}
+	// peer0 refuses the stream, peer1 delivers a block then breaks, peer0 delivers the next blocks
+	clients := []*deliverClient{
+		{closed: make(chan struct{})},
+		{stream: &stream{responses: []*pb.DeliverResponse{block, nil}}, closed: make(chan struct{})},
+		{stream: &stream{done: done}, closed: make(chan struct{})},
+	}
+	p := &peers{addresses: []string{"peer0", "peer1"}}
+	d, err := NewFabricFinality("ch", &network{}, p, nil, 5*time.Second, 10)
+	assert.NoError(t, err)
+	// once the test ends, the peers are gone and the listener backs off
+	d.minBackoff, d.maxBackoff = time.Millisecond, time.Hour
+	var lock sync.Mutex
+	var connected []string
+	d.newClient = func(config *grpc.ConnectionConfig) (delivery.DeliverClient, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		connected = append(connected, config.Address)
+		if len(clients) == 0 {
+			return nil, errors.New("connection refused")
+		}
+		client := clients[0]
+		clients = clients[1:]
+		return client, nil
+	}
+	refused, broken, current := clients[0], clients[1], clients[2]
+
+	assert.NoError(t, d.IsFinal("tx1"))
+
+	// the connections of the failed peers are closed before reconnecting
+	for _, client := range []*deliverClient{refused, broken} {
+		select {
+		case <-client.closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("connection not closed")
+		}
+	}
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(connected) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	lock.Lock()
+	assert.Equal(t, []string{"peer0", "peer1", "peer0"}, connected)
+	lock.Unlock()
+	p.lock.Lock()
+	assert.Equal(t, []string{"peer0", "peer1"}, p.failed)
+	p.lock.Unlock()
+	select {
+	case <-current.closed:
+		t.Fatal("connection in use closed")
+	default:
+	}
+	// the stream resumes after the last block received
+	assert.Equal(t, uint64(6), d.startPosition().GetSpecified().Number)
+}
+
+func TestFailoverBackoff(t *testing.T) {
+	p := &peers{addresses: []string{"peer0", "peer1"}}
+	d, err := NewFabricFinality("ch", nil, p, nil, time.Second, 10)
+	assert.NoError(t, err)
+	d.maxBackoff = 4 * time.Millisecond
+
+	// the wait doubles at each failure, up to the maximum
+	backoff := time.Millisecond
+	for _, expected := range []time.Duration{2, 4, 4} {
+		start := time.Now()
+		waited := backoff
+		d.failover("peer0", &backoff)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(waited))
+		assert.Equal(t, expected*time.Millisecond, backoff)
+	}
+	assert.Len(t, p.failed, 3)
+}
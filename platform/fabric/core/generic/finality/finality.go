@@ -9,14 +9,9 @@ package finality
 import (
 	"time"
 
-	view3 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/client/view"
-	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
-
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
-
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
-
-	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -32,20 +27,20 @@ type Committer interface {
 }
 
 type finality struct {
-	channel    string
-	network    Network
-	sp         view2.ServiceProvider
-	committer  Committer
-	TLSEnabled bool
+	channel      string
+	network      Network
+	sp           view2.ServiceProvider
+	committer    Committer
+	partyTimeout time.Duration
 }
 
-func NewService(sp view2.ServiceProvider, network Network, channel string, committer Committer) (*finality, error) {
+func NewService(sp view2.ServiceProvider, network Network, channel string, committer Committer, partyTimeout time.Duration) (*finality, error) {
 	return &finality{
-		sp:         sp,
-		network:    network,
-		committer:  committer,
-		channel:    channel,
-		TLSEnabled: true,
+		sp:           sp,
+		network:      network,
+		committer:    committer,
+		channel:      channel,
+		partyTimeout: partyTimeout,
 	}, nil
 }
 
@@ -53,59 +48,50 @@ func (f *finality) IsFinal(txID string) error {
 	return f.committer.IsFinal(txID)
 }
 
+// IsFinalForParties subscribes to the finality of the passed transaction at each party, in parallel.
+// Each party is given partyTimeout to notify that the transaction is final.
+// If some party does not confirm, a *driver.PartiesFinalityError reporting the confirmed parties is returned.
 func (f *finality) IsFinalForParties(txID string, parties ...view.Identity) error {
 	logger.Debugf("Is [%s] final for parties [%v]?", txID, parties)
 
-	var err error
-	comm, err := f.network.Comm(f.channel)
-	if err != nil {
-		return err
+	type result struct {
+		party view.Identity
+		err   error
 	}
-
+	results := make(chan result, len(parties))
 	for _, party := range parties {
-		logger.Debugf("Asking [%s] if [%s] is final...", party, txID)
 		if f.network.LocalMembership().IsMe(party) {
-			logger.Debugf("[%s] is me, skipping.", party, txID)
+			logger.Debugf("[%s] is me, skipping.", party)
+			results <- result{party: party}
 			continue
 		}
-
-		endpoints, err := view2.GetEndpointService(f.sp).Endpoint(party)
-		if err != nil {
-			return err
-		}
-		logger.Debugf("Asking [%s] resolved from [%s] if [%s] is final...", endpoints[view2.ViewPort], party, txID)
-
-		var certs [][]byte
-		if f.TLSEnabled {
-			certs, err = comm.GetTLSRootCert(party)
-			if err != nil {
-				return err
-			}
-		}
-
-		c, err := view3.New(
-			&view3.Config{
-				ID: "",
-				FSCNode: &grpc.ConnectionConfig{
-					Address:           endpoints[view2.ViewPort],
-					ConnectionTimeout: 300 * time.Second,
-					TLSEnabled:        f.TLSEnabled,
-					TLSRootCertBytes:  certs,
+		go func(party view.Identity) {
+			logger.Debugf("Asking [%s] if [%s] is final...", party, txID)
+			_, err := view2.GetManager(f.sp).InitiateView(&subscribeView{
+				subscription: &Subscription{
+					Network: f.network.Name(),
+					Channel: f.channel,
+					TxID:    txID,
 				},
-			},
-			f.network.LocalMembership().DefaultSigningIdentity(),
-			hash.GetHasher(f.sp),
-		)
-		if err != nil {
-			logger.Errorf("Failed connecting to [%s] resolved from [%s] to as if [%s] is final...", endpoints[view2.ViewPort], party, txID)
-			return err
-		}
-		err = c.IsTxFinal(txID)
-		logger.Debugf("Is [%s] final on [%s]: [%s]?", txID, party, err)
-		if err != nil {
-			return err
-		}
+				party:   party,
+				timeout: f.partyTimeout,
+			})
+			logger.Debugf("Is [%s] final on [%s]: [%s]?", txID, party, err)
+			results <- result{party: party, err: err}
+		}(party)
 	}
 
+	report := &driver.PartiesFinalityError{TxID: txID}
+	for range parties {
+		r := <-results
+		if r.err != nil {
+			report.Failures = append(report.Failures, driver.PartyFailure{Party: r.party, Err: r.err})
+			continue
+		}
+		report.Confirmed = append(report.Confirmed, r.party)
+	}
+	if len(report.Failures) != 0 {
+		return report
+	}
 	return nil
 }
@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package finality
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/session"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// Subscription is sent to a remote party to register interest in the finality of a transaction
+type Subscription struct {
+	Network string
+	Channel string
+	TxID    string
+}
+
+// Notification is sent back to the subscriber once the transaction is final, or its finality cannot be established
+type Notification struct {
+	TxID string
+	// Error is empty if the transaction is final and valid
+	Error string
+}
+
+// DefaultMaxSubscriptions is the default number of finality subscriptions of remote parties served concurrently
+const DefaultMaxSubscriptions = 1000
+
+// Registry binds responders to initiators
+type Registry interface {
+	RegisterResponder(responder view.View, initiatedBy view.View)
+}
+
+// InstallResponder makes this node answer the finality subscriptions of remote parties,
+// at most maxSubscriptions at a time. The subscriptions beyond that are answered with an error.
+func InstallResponder(registry Registry, maxSubscriptions int) {
+	registry.RegisterResponder(newSubscriptionResponderView(maxSubscriptions), &subscribeView{})
+}
+
+// subscribeView subscribes to the finality of a transaction at a remote party, and waits for the notification
+type subscribeView struct {
+	subscription *Subscription
+	party        view.Identity
+	timeout      time.Duration
+}
+
+func (s *subscribeView) Call(context view.Context) (interface{}, error) {
+	js, err := session.NewJSON(context, context.Initiator(), s.party)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed opening session to [%s]", s.party)
+	}
+	if err := js.Send(s.subscription); err != nil {
+		return nil, errors.WithMessagef(err, "failed subscribing to finality of [%s] at [%s]", s.subscription.TxID, s.party)
+	}
+	notification := &Notification{}
+	if err := js.ReceiveWithTimeout(notification, s.timeout); err != nil {
+		return nil, errors.WithMessagef(err, "failed receiving finality of [%s] from [%s]", s.subscription.TxID, s.party)
+	}
+	if notification.TxID != s.subscription.TxID {
+		return nil, errors.Errorf("received finality of [%s] from [%s], expected [%s]", notification.TxID, s.party, s.subscription.TxID)
+	}
+	if len(notification.Error) != 0 {
+		return nil, errors.Errorf("transaction [%s] is not final at [%s]: %s", notification.TxID, s.party, notification.Error)
+	}
+	return nil, nil
+}
+
+// subscriptionResponderView waits for the local finality of the transaction a remote party subscribed to,
+// and notifies the party. Only the nodes known to the endpoint service can subscribe.
+type subscriptionResponderView struct {
+	// slots bounds the subscriptions waiting for finality
+	slots chan struct{}
+}
+
+func newSubscriptionResponderView(maxSubscriptions int) *subscriptionResponderView {
+	return &subscriptionResponderView{slots: make(chan struct{}, maxSubscriptions)}
+}
+
+func (s *subscriptionResponderView) Call(context view.Context) (interface{}, error) {
+	if err := authorize(context); err != nil {
+		return nil, err
+	}
+	js := session.JSON(context)
+	subscription := &Subscription{}
+	if err := js.Receive(subscription); err != nil {
+		return nil, errors.WithMessage(err, "failed receiving finality subscription")
+	}
+	logger.Debugf("Answering: Is [%s] final?", subscription.TxID)
+
+	notification := &Notification{TxID: subscription.TxID}
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		logger.Warnf("too many finality subscriptions, rejecting subscription to [%s] from [%s]", subscription.TxID, context.Session().Info().Caller)
+		notification.Error = "too many finality subscriptions, retry later"
+		if err := js.Send(notification); err != nil {
+			return nil, errors.WithMessagef(err, "failed rejecting finality subscription to [%s]", subscription.TxID)
+		}
+		return nil, nil
+	}
+	if err := isFinal(context, subscription); err != nil {
+		logger.Debugf("Answering: Is [%s] final? No [%s]", subscription.TxID, err)
+		notification.Error = err.Error()
+	} else {
+		logger.Debugf("Answering: Is [%s] final? Yes", subscription.TxID)
+	}
+	if err := js.Send(notification); err != nil {
+		return nil, errors.WithMessagef(err, "failed notifying finality of [%s]", subscription.TxID)
+	}
+	return nil, nil
+}
+
+// authorize checks that the caller of the session is a node known to the endpoint service,
+// and that it is bound to the endpoint the session comes from
+func authorize(context view.Context) error {
+	info := context.Session().Info()
+	if info.Caller.IsNone() {
+		return errors.New("finality subscription from unknown caller")
+	}
+	_, _, pkid, err := view2.GetEndpointService(context).Resolve(info.Caller)
+	if err != nil {
+		return errors.WithMessagef(err, "finality subscription from unknown caller [%s]", info.Caller)
+	}
+	if !bytes.Equal(pkid, info.EndpointPKID) {
+		return errors.Errorf("finality subscription from [%s] not coming from its endpoint", info.Caller)
+	}
+	return nil
+}
+
+func isFinal(context view.Context, subscription *Subscription) error {
+	fns, err := driver.GetFabricManagementService(context).FabricNetworkService(subscription.Network)
+	if err != nil {
+		return errors.WithMessagef(err, "network [%s] not found", subscription.Network)
+	}
+	ch, err := fns.Channel(subscription.Channel)
+	if err != nil {
+		return errors.WithMessagef(err, "channel [%s:%s] not found", subscription.Network, subscription.Channel)
+	}
+	return ch.IsFinal(subscription.TxID)
+}
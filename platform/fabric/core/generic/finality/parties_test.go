@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package finality
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	driver2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type fakeSession struct {
+	info view.SessionInfo
+	in   chan *view.Message
+	out  chan *view.Message
+}
+
+// newSessionPair returns the two ends of a session, the responder end carries the passed info
+func newSessionPair(info view.SessionInfo) (*fakeSession, *fakeSession) {
+	a, b := make(chan *view.Message, 10), make(chan *view.Message, 10)
+	return &fakeSession{in: a, out: b}, &fakeSession{info: info, in: b, out: a}
+}
+
+func (f *fakeSession) Info() view.SessionInfo { return f.info }
+
+func (f *fakeSession) Send(payload []byte) error {
+	f.out <- &view.Message{Status: view.OK, Payload: payload}
+	return nil
+}
+
+func (f *fakeSession) SendError(payload []byte) error {
+	f.out <- &view.Message{Status: view.ERROR, Payload: payload}
+	return nil
+}
+
+func (f *fakeSession) Receive() <-chan *view.Message { return f.in }
+
+func (f *fakeSession) Close() {}
+
+// viewContext names the embedded context, whose Context method would be shadowed by a field named Context
+type viewContext = view.Context
+
+type fakeContext struct {
+	viewContext
+	sp        view2.ServiceProvider
+	initiator view.View
+	session   view.Session
+	open      func(party view.Identity) (view.Session, error)
+}
+
+func (c *fakeContext) GetService(v interface{}) (interface{}, error) { return c.sp.GetService(v) }
+
+func (c *fakeContext) ID() string { return "ctx" }
+
+func (c *fakeContext) Initiator() view.View { return c.initiator }
+
+func (c *fakeContext) GetSession(caller view.View, party view.Identity) (view.Session, error) {
+	return c.open(party)
+}
+
+func (c *fakeContext) Session() view.Session { return c.session }
+
+func (c *fakeContext) Context() context.Context { return context.Background() }
+
+type endpointService struct {
+	driver2.EndpointService
+	pkids map[string][]byte
+}
+
+func (e *endpointService) Resolve(party view.Identity) (view.Identity, map[driver2.PortName]string, []byte, error) {
+	pkid, ok := e.pkids[party.UniqueID()]
+	if !ok {
+		return nil, nil, nil, errors.New("not found")
+	}
+	return party, nil, pkid, nil
+}
+
+type fnsProvider struct {
+	driver.FabricNetworkServiceProvider
+	fns *fns
+}
+
+func (f *fnsProvider) FabricNetworkService(id string) (driver.FabricNetworkService, error) {
+	if id != "network" {
+		return nil, errors.New("not found")
+	}
+	return f.fns, nil
+}
+
+type fns struct {
+	driver.FabricNetworkService
+	channel *channel
+}
+
+func (f *fns) Channel(name string) (driver.Channel, error) {
+	if name != "channel" {
+		return nil, errors.New("not found")
+	}
+	return f.channel, nil
+}
+
+type channel struct {
+	driver.Channel
+	isFinal func(txID string) error
+}
+
+func (c *channel) IsFinal(txID string) error { return c.isFinal(txID) }
+
+// node is a remote party, it answers the finality subscriptions unless silent
+type node struct {
+	responder *subscriptionResponderView
+	sp        view2.ServiceProvider
+	silent    bool
+}
+
+func newNode(t *testing.T, caller view.Identity, isFinal func(txID string) error) *node {
+	sp := registry2.New()
+	assert.NoError(t, sp.RegisterService(&endpointService{pkids: map[string][]byte{caller.UniqueID(): []byte("caller")}}))
+	assert.NoError(t, sp.RegisterService(&fnsProvider{fns: &fns{channel: &channel{isFinal: isFinal}}}))
+	return &node{responder: newSubscriptionResponderView(DefaultMaxSubscriptions), sp: sp}
+}
+
+// viewManager runs the subscriptions of the caller against the nodes of the parties
+type viewManager struct {
+	driver2.ViewManager
+	sp     view2.ServiceProvider
+	caller view.Identity
+	nodes  map[string]*node
+}
+
+func (m *viewManager) InitiateView(v view.View) (interface{}, error) {
+	return v.Call(&fakeContext{sp: m.sp, initiator: v, open: m.open})
+}
+
+func (m *viewManager) open(party view.Identity) (view.Session, error) {
+	n, ok := m.nodes[party.UniqueID()]
+	if !ok {
+		return nil, errors.New("unknown party")
+	}
+	initiator, responder := newSessionPair(view.SessionInfo{Caller: m.caller, EndpointPKID: []byte("caller")})
+	if !n.silent {
+		go n.responder.Call(&fakeContext{sp: n.sp, session: responder})
+	}
+	return initiator, nil
+}
+
+type network struct {
+	Network
+	me view.Identity
+}
+
+func (n *network) Name() string { return "network" }
+
+func (n *network) LocalMembership() driver.LocalMembership { return &localMembership{me: n.me} }
+
+type localMembership struct {
+	driver.LocalMembership
+	me view.Identity
+}
+
+func (l *localMembership) IsMe(id view.Identity) bool { return l.me.Equal(id) }
+
+func TestIsFinalForParties(t *testing.T) {
+	caller := view.Identity("caller")
+	me, alice, bob, charlie, dave := view.Identity("me"), view.Identity("alice"), view.Identity("bob"), view.Identity("charlie"), view.Identity("dave")
+	final := func(txID string) error { return nil }
+	invalid := func(txID string) error { return errors.New("invalid transaction") }
+
+	sp := registry2.New()
+	manager := &viewManager{sp: sp, caller: caller, nodes: map[string]*node{
+		alice.UniqueID():   newNode(t, caller, final),
+		bob.UniqueID():     newNode(t, caller, final),
+		charlie.UniqueID(): newNode(t, caller, invalid),
+		dave.UniqueID():    {silent: true},
+	}}
+	assert.NoError(t, sp.RegisterService(manager))
+	f, err := NewService(sp, &network{me: me}, "channel", nil, 200*time.Millisecond)
+	assert.NoError(t, err)
+
+	// all parties final, this node included
+	assert.NoError(t, f.IsFinalForParties("tx1", me, alice, bob))
+
+	// the failures of the parties are aggregated, the silent ones time out
+	err = f.IsFinalForParties("tx1", me, alice, charlie, dave)
+	assert.Error(t, err)
+	report, ok := err.(*driver.PartiesFinalityError)
+	assert.True(t, ok)
+	assert.Equal(t, "tx1", report.TxID)
+	assert.ElementsMatch(t, []view.Identity{me, alice}, report.Confirmed)
+	assert.Len(t, report.Failures, 2)
+	for _, failure := range report.Failures {
+		switch {
+		case failure.Party.Equal(charlie):
+			assert.Contains(t, failure.Err.Error(), "invalid transaction")
+		case failure.Party.Equal(dave):
+			assert.Contains(t, failure.Err.Error(), "failed receiving finality")
+		default:
+			t.Fatalf("unexpected failure of [%s]", failure.Party)
+		}
+	}
+}
+
+func TestSubscriptionResponder(t *testing.T) {
+	caller := view.Identity("caller")
+	release := make(chan struct{})
+	n := newNode(t, caller, func(txID string) error {
+		<-release
+		return nil
+	})
+	n.responder = newSubscriptionResponderView(1)
+
+	subscribe := func(info view.SessionInfo) (*fakeSession, chan error) {
+		initiator, responder := newSessionPair(info)
+		done := make(chan error, 1)
+		go func() {
+			_, err := n.responder.Call(&fakeContext{sp: n.sp, session: responder})
+			done <- err
+		}()
+		assert.NoError(t, initiator.Send([]byte(`{"Network":"network","Channel":"channel","TxID":"tx1"}`)))
+		return initiator, done
+	}
+	receive := func(s *fakeSession) *view.Message {
+		select {
+		case msg := <-s.in:
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("no notification received")
+			return nil
+		}
+	}
+
+	// unknown callers, and callers not coming from their endpoint, are rejected
+	_, done := subscribe(view.SessionInfo{Caller: view.Identity("eve"), EndpointPKID: []byte("caller")})
+	assert.Error(t, <-done)
+	_, done = subscribe(view.SessionInfo{Caller: caller, EndpointPKID: []byte("eve")})
+	assert.Error(t, <-done)
+	_, done = subscribe(view.SessionInfo{EndpointPKID: []byte("caller")})
+	assert.Error(t, <-done)
+
+	// the subscriptions beyond the bound are rejected
+	first, firstDone := subscribe(view.SessionInfo{Caller: caller, EndpointPKID: []byte("caller")})
+	assert.Eventually(t, func() bool { return len(n.responder.slots) == 1 }, 5*time.Second, 10*time.Millisecond)
+	second, secondDone := subscribe(view.SessionInfo{Caller: caller, EndpointPKID: []byte("caller")})
+	assert.Contains(t, string(receive(second).Payload), "too many finality subscriptions")
+	assert.NoError(t, <-secondDone)
+
+	close(release)
+	msg := receive(first)
+	assert.Equal(t, `{"TxID":"tx1","Error":""}`, string(msg.Payload))
+	assert.NoError(t, <-firstDone)
+	assert.Len(t, n.responder.slots, 0)
+}
+
+type registry struct {
+	responder, initiator view.View
+}
+
+func (r *registry) RegisterResponder(responder view.View, initiatedBy view.View) {
+	r.responder, r.initiator = responder, initiatedBy
+}
+
+func TestInstallResponder(t *testing.T) {
+	r := &registry{}
+	InstallResponder(r, 10)
+	assert.IsType(t, &subscribeView{}, r.initiator)
+	assert.IsType(t, &subscriptionResponderView{}, r.responder)
+	assert.Equal(t, 10, cap(r.responder.(*subscriptionResponderView).slots))
+}
@@ -95,7 +95,7 @@ func TestInfoIdemix(t *testing.T) {
 }
 
 func TestInfoX509(t *testing.T) {
-	p, err := x5092.NewProvider("./testdata/x509", "apple", nil)
+	p, err := x5092.NewProvider("./testdata/x509", "apple", nil, nil, nil)
 	assert.NoError(t, err)
 	id, _, err := p.Identity()
 	assert.NoError(t, err)
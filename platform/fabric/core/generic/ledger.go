@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric/protoutil"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	driver2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
 )
 
 // NewRWSet returns a RWSet for this ledger.
@@ -43,11 +44,16 @@ func (c *channel) NewQueryExecutor() (driver.QueryExecutor, error) {
 	return c.vault.NewQueryExecutor()
 }
 
+// CreateIndex declares a secondary index on the values of the passed namespace
+func (c *channel) CreateIndex(namespace string, index *driver2.Index) error {
+	return c.vault.CreateIndex(namespace, index)
+}
+
 // GetBlockByNumber fetches a block by number
 func (c *channel) GetBlockByNumber(number uint64) (driver.Block, error) {
 	res, err := c.Chaincode("qscc").NewInvocation(driver.ChaincodeQuery, GetBlockByNumber, c.name, number).WithSignerIdentity(
 		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	).WithEndorsersByConnConfig(c.peerSelector.Current()).Call()
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +65,22 @@ func (c *channel) GetBlockByNumber(number uint64) (driver.Block, error) {
 	return &Block{Block: b}, nil
 }
 
+// GetLedgerHeight returns the number of blocks in the ledger
+func (c *channel) GetLedgerHeight() (uint64, error) {
+	res, err := c.Chaincode("qscc").NewInvocation(driver.ChaincodeQuery, GetChainInfo, c.name).WithSignerIdentity(
+		c.network.LocalMembership().DefaultIdentity(),
+	).WithEndorsersByConnConfig(c.peerSelector.Current()).Call()
+	if err != nil {
+		return 0, err
+	}
+
+	info := &common.BlockchainInfo{}
+	if err := proto.Unmarshal(res.([]byte), info); err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
 // Block wraps a Fabric block
 type Block struct {
 	*common.Block
@@ -19,12 +19,15 @@ import (
 	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
 
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
 	sig2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
 	api3 "github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
@@ -40,6 +43,7 @@ const (
 var logger = flogging.MustGetLogger("fabric-sdk.msp")
 
 type Config interface {
+	Name() string
 	MSPConfigPath() string
 	MSPs() ([]config.MSP, error)
 	LocalMSPID() string
@@ -49,10 +53,12 @@ type Config interface {
 
 type SignerService interface {
 	RegisterSigner(identity view.Identity, signer api2.Signer, verifier api2.Verifier) error
+	UnregisterSigner(identity view.Identity) error
 }
 
 type BinderService interface {
 	Bind(longTerm view.Identity, ephemeral view.Identity) error
+	Unbind(ephemeral view.Identity) error
 }
 
 type ConfigProvider interface {
@@ -92,6 +98,7 @@ type service struct {
 	resolversByEnrollmentID  map[string]*Resolver
 	resolversByTypeAndName   map[string]*Resolver
 	bccspResolversByIdentity map[string]*Resolver
+	walletEntries            map[string]*api2.WalletEntry
 }
 
 func NewLocalMSPManager(sp view2.ServiceProvider, config Config, signerService SignerService, binderService BinderService, defaultViewIdentity view.Identity) *service {
@@ -105,6 +112,7 @@ func NewLocalMSPManager(sp view2.ServiceProvider, config Config, signerService S
 		bccspResolversByIdentity: map[string]*Resolver{},
 		resolversByEnrollmentID:  map[string]*Resolver{},
 		resolversByName:          map[string]*Resolver{},
+		walletEntries:            map[string]*api2.WalletEntry{},
 	}
 }
 
@@ -119,6 +127,11 @@ func (s *service) Load() error {
 		return err
 	}
 
+	// Register the identities in the wallet
+	if err := s.loadWallet(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -141,8 +154,17 @@ func (s *service) AnonymousIdentity() view.Identity {
 	return id
 }
 
+// Identity returns the identity bound to the passed label, the wallet is checked first
 func (s *service) Identity(label string) view.Identity {
-	id, err := view2.GetEndpointService(s.sp).GetIdentity(label, nil)
+	id, err := s.walletIdentity(label)
+	if err != nil {
+		panic(err)
+	}
+	if id != nil {
+		return id
+	}
+
+	id, err = view2.GetEndpointService(s.sp).GetIdentity(label, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -239,6 +261,17 @@ func (s *service) RegisterIdemixMSP(id string, path string, mspID string) error
 	s.resolversMutex.Lock()
 	defer s.resolversMutex.Unlock()
 
+	return s.registerIdemixMSP(id, path, mspID)
+}
+
+func (s *service) RegisterX509MSP(id string, path string, mspID string) error {
+	s.resolversMutex.Lock()
+	defer s.resolversMutex.Unlock()
+
+	return s.registerX509MSP(id, path, mspID)
+}
+
+func (s *service) registerIdemixMSP(id string, path string, mspID string) error {
 	conf, err := msp.GetLocalMspConfigWithType(path, nil, mspID, IdemixMSP)
 	if err != nil {
 		return errors.Wrapf(err, "failed reading idemix msp configuration from [%s]", path)
@@ -254,13 +287,14 @@ func (s *service) RegisterIdemixMSP(id string, path string, mspID string) error
 	return nil
 }
 
-func (s *service) RegisterX509MSP(id string, path string, mspID string) error {
-	s.resolversMutex.Lock()
-	defer s.resolversMutex.Unlock()
-
-	provider, err := x5092.NewProvider(path, mspID, s.signerService)
+func (s *service) registerX509MSP(id string, path string, mspID string) error {
+	csp, err := s.csp(id, path)
 	if err != nil {
-		return errors.Wrapf(err, "failed instantiating idemix msp provider from [%s]", path)
+		return err
+	}
+	provider, err := x5092.NewProvider(path, mspID, s.signerService, csp, s.certValidator())
+	if err != nil {
+		return errors.Wrapf(err, "failed instantiating x509 msp provider from [%s]", path)
 	}
 
 	s.deserializerManager().AddDeserializer(provider)
@@ -279,6 +313,7 @@ func (s *service) Refresh() error {
 	s.bccspResolversByIdentity = map[string]*Resolver{}
 	s.resolversByEnrollmentID = map[string]*Resolver{}
 	s.resolversByName = map[string]*Resolver{}
+	s.walletEntries = map[string]*api2.WalletEntry{}
 
 	// reload
 	return s.Load()
@@ -318,6 +353,7 @@ func (s *service) addResolver(Name string, Type string, EnrollmentID string, Ide
 			panic(fmt.Sprintf("cannot get identity for [%s,%s,%s][%s]", Name, Type, EnrollmentID, err))
 		}
 		s.bccspResolversByIdentity[id.String()] = resolver
+		s.trackExpiry(Name, id)
 	}
 	s.resolversByTypeAndName[Type+Name] = resolver
 	s.resolversByName[Name] = resolver
@@ -327,6 +363,71 @@ func (s *service) addResolver(Name string, Type string, EnrollmentID string, Ide
 	s.resolvers = append(s.resolvers, resolver)
 }
 
+// removeResolver removes the resolver with the passed name from the indices, and returns it.
+// It returns nil if no resolver is found.
+func (s *service) removeResolver(name string) *Resolver {
+	resolver, ok := s.resolversByName[name]
+	if !ok {
+		return nil
+	}
+	delete(s.resolversByName, name)
+	delete(s.resolversByTypeAndName, resolver.Type+name)
+	if r, ok := s.resolversByEnrollmentID[resolver.EnrollmentID]; ok && r == resolver {
+		delete(s.resolversByEnrollmentID, resolver.EnrollmentID)
+	}
+	for k, r := range s.bccspResolversByIdentity {
+		if r == resolver {
+			delete(s.bccspResolversByIdentity, k)
+		}
+	}
+	for i, r := range s.resolvers {
+		if r == resolver {
+			s.resolvers = append(s.resolvers[:i], s.resolvers[i+1:]...)
+			break
+		}
+	}
+	return resolver
+}
+
+// certValidator returns the validator of the certificates of the deserialized identities, nil if not available
+func (s *service) certValidator() x5092.CertValidator {
+	validator := x509.GetValidator(s.sp)
+	if validator == nil {
+		return nil
+	}
+	return validator
+}
+
+// trackExpiry reports the certificate of the passed identity when it gets close to its expiration
+func (s *service) trackExpiry(name string, id view.Identity) {
+	validator := x509.GetValidator(s.sp)
+	if validator == nil {
+		return
+	}
+	cert, err := x5092.CertFromIdentity(id)
+	if err != nil {
+		logger.Warnf("cannot track expiration of [%s]: [%s]", name, err)
+		return
+	}
+	validator.Track(s.config.Name()+":"+name, cert)
+}
+
+func (s *service) untrackExpiry(name string) {
+	if validator := x509.GetValidator(s.sp); validator != nil {
+		validator.Untrack(s.config.Name() + ":" + name)
+	}
+}
+
+// csp returns the BCCSP holding the signing key of the x509 msp at the passed path, selected by label.
+// It returns nil if the keys are not managed, the key is then read from the msp keystore.
+func (s *service) csp(label string, mspPath string) (bccsp.BCCSP, error) {
+	kmsService := kms.GetService(s.sp)
+	if kmsService == nil {
+		return nil, nil
+	}
+	return kmsService.NewCSP(label, filepath.Join(mspPath, "keystore"))
+}
+
 func (s *service) deserializerManager() DeserializerManager {
 	dm, err := s.sp.GetService(reflect.TypeOf((*DeserializerManager)(nil)))
 	if err != nil {
@@ -350,7 +451,11 @@ func (s *service) loadDefaultResolver() error {
 	case mspType != msp.ProviderTypeToString(msp.FABRIC):
 		return errors.Errorf("default identity must by of type [%s]", msp.ProviderTypeToString(msp.FABRIC))
 	}
-	provider, err := x5092.NewProvider(mspConfigDir, mspID, s.signerService)
+	csp, err := s.csp("default", mspConfigDir)
+	if err != nil {
+		return err
+	}
+	provider, err := x5092.NewProvider(mspConfigDir, mspID, s.signerService, csp, s.certValidator())
 	if err != nil {
 		return err
 	}
@@ -401,7 +506,11 @@ func (s *service) loadExtraResolvers() error {
 			dm.AddDeserializer(provider)
 			s.addResolver(config.ID, config.MSPType, provider.EnrollmentID(), provider.Identity)
 		case BccspMSP:
-			provider, err = x5092.NewProvider(s.config.TranslatePath(config.Path), config.MSPID, s.signerService)
+			csp, err := s.csp(config.ID, s.config.TranslatePath(config.Path))
+			if err != nil {
+				return errors.WithMessagef(err, "failed opening key store of [%s]", config.ID)
+			}
+			provider, err = x5092.NewProvider(s.config.TranslatePath(config.Path), config.MSPID, s.signerService, csp, s.certValidator())
 			if err != nil {
 				return errors.Wrapf(err, "failed instantiating x509 msp provider from [%s]", s.config.TranslatePath(config.Path))
 			}
@@ -450,18 +559,32 @@ func (s *service) loadExtraResolvers() error {
 				id := entry.Name()
 
 				// Try without "msp"
+				csp, err := s.csp(id, filepath.Join(s.config.TranslatePath(config.Path), id))
+				if err != nil {
+					logger.Warnf("failed opening key store of [%s]: [%s]", id, err)
+					continue
+				}
 				provider, err = x5092.NewProvider(
 					filepath.Join(s.config.TranslatePath(config.Path), id),
 					config.MSPID,
 					s.signerService,
+					csp,
+					s.certValidator(),
 				)
 				if err != nil {
 					logger.Debugf("failed reading bccsp msp configuration from [%s]: [%s]", filepath.Join(s.config.TranslatePath(config.Path), id), err)
 					// Try with "msp"
+					csp, err = s.csp(id, filepath.Join(s.config.TranslatePath(config.Path), id, "msp"))
+					if err != nil {
+						logger.Warnf("failed opening key store of [%s]: [%s]", id, err)
+						continue
+					}
 					provider, err = x5092.NewProvider(
 						filepath.Join(s.config.TranslatePath(config.Path), id, "msp"),
 						config.MSPID,
 						s.signerService,
+						csp,
+						s.certValidator(),
 					)
 					if err != nil {
 						logger.Warnf("failed reading bccsp msp configuration from [%s and %s]: [%s]",
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic"
 	msp2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp"
 	mock2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/mock"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/config"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
 	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
@@ -193,3 +194,99 @@ func TestRefresh(t *testing.T) {
 		assert.NotNil(t, mspService.GetIdentityInfoByLabel(msp2.BccspMSP, s))
 	}
 }
+
+func TestWallet(t *testing.T) {
+	registry := registry2.New()
+
+	cp := &mock2.ConfigProvider{}
+	cp.IsSetReturns(false)
+	assert.NoError(t, registry.RegisterService(cp))
+	kvss, err := kvs.New("memory", "", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+	des, err := sig.NewMultiplexDeserializer(registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(des))
+	config, err := generic.NewConfig(cp, "default", true)
+	assert.NoError(t, err)
+	mspService := msp2.NewLocalMSPManager(registry, config, nil, nil, nil)
+	assert.NoError(t, registry.RegisterService(mspService))
+	sigService := sig.NewSignService(registry, nil)
+	assert.NoError(t, registry.RegisterService(sigService))
+	assert.NoError(t, mspService.Load())
+
+	wallet := mspService.Wallet()
+	assert.NoError(t, wallet.Add(&driver.WalletEntry{
+		ID:       "alice",
+		MSPType:  msp2.BccspMSP,
+		MSPID:    "x509",
+		Path:     "./x509/testdata/msp",
+		Labels:   []string{"user", "org1"},
+		Metadata: map[string]string{"email": "alice@org1.example.com"},
+	}))
+	assert.NoError(t, wallet.Add(&driver.WalletEntry{
+		ID:      "bob",
+		MSPType: msp2.IdemixMSP,
+		MSPID:   "idemix",
+		Path:    "./idemix/testdata/idemix",
+		Labels:  []string{"user"},
+	}))
+	assert.Error(t, wallet.Add(&driver.WalletEntry{ID: "alice", MSPType: msp2.BccspMSP, MSPID: "x509", Path: "./x509/testdata/msp"}))
+	assert.Error(t, wallet.Add(&driver.WalletEntry{ID: "charlie", MSPType: "unknown", Path: "./x509/testdata/msp"}))
+	assert.Error(t, wallet.Add(&driver.WalletEntry{ID: "charlie", MSPType: msp2.BccspMSP, Path: "./testdata/missing"}))
+
+	e, err := wallet.Get("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "auditor.org1.example.com", e.EnrollmentID)
+	assert.Equal(t, "alice@org1.example.com", e.Metadata["email"])
+	assert.False(t, e.Created.IsZero())
+	e, err = wallet.Get("charlie")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	entries, err := wallet.List("user")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "alice", entries[0].ID)
+	entries, err = wallet.List("user", "org1")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// the wallet identities are resolved by label
+	ii := mspService.GetIdentityInfoByLabel(msp2.BccspMSP, "alice")
+	assert.NotNil(t, ii)
+	id, _, err := ii.GetIdentity()
+	assert.NoError(t, err)
+	assert.Equal(t, id, mspService.Identity("alice"))
+	assert.NotNil(t, mspService.GetIdentityInfoByLabel(msp2.IdemixMSP, "bob"))
+
+	assert.NoError(t, wallet.Update("bob", []string{"auditor"}, map[string]string{"team": "audit"}))
+	entries, err = wallet.List("auditor")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "audit", entries[0].Metadata["team"])
+
+	// rotate the credentials of alice
+	assert.Error(t, wallet.Rotate("alice", "./testdata/missing"))
+	assert.Equal(t, id, mspService.Identity("alice"))
+	assert.NoError(t, wallet.Rotate("alice", "./testdata/x509typefolder/msps/Admin@org1.example.com"))
+	e, err = wallet.Get("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "Admin@org1.example.com", e.EnrollmentID)
+	assert.NotEqual(t, id, mspService.Identity("alice"))
+	assert.Nil(t, mspService.GetIdentityInfoByIdentity(msp2.BccspMSP, id))
+
+	// the entries survive a restart
+	mspService = msp2.NewLocalMSPManager(registry, config, nil, nil, nil)
+	assert.NoError(t, mspService.Load())
+	assert.Equal(t, []string{"alice", "bob"}, mspService.Resolvers())
+	e, err = mspService.Wallet().Get("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "./testdata/x509typefolder/msps/Admin@org1.example.com", e.Path)
+
+	assert.NoError(t, mspService.Wallet().Remove("alice"))
+	assert.Error(t, mspService.Wallet().Remove("alice"))
+	assert.Nil(t, mspService.GetIdentityInfoByLabel(msp2.BccspMSP, "alice"))
+	assert.NoError(t, mspService.Refresh())
+	assert.Equal(t, []string{"bob"}, mspService.Resolvers())
+}
@@ -0,0 +1,274 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const walletPrefix = "fabric.msp.wallet"
+
+// wallet manages the identities registered at runtime, the entries are stored in the KVS
+type wallet struct {
+	s *service
+}
+
+// Wallet returns the wallet of the identities managed at runtime
+func (s *service) Wallet() api2.Wallet {
+	return &wallet{s: s}
+}
+
+func (w *wallet) Add(entry *api2.WalletEntry) error {
+	if entry == nil || len(entry.ID) == 0 {
+		return errors.New("invalid wallet entry, id not set")
+	}
+	if len(entry.Path) == 0 {
+		return errors.Errorf("invalid wallet entry [%s], path not set", entry.ID)
+	}
+
+	s := w.s
+	s.resolversMutex.Lock()
+	defer s.resolversMutex.Unlock()
+
+	if _, ok := s.resolversByName[entry.ID]; ok {
+		return errors.Errorf("identity [%s] already registered", entry.ID)
+	}
+	e := copyEntry(entry)
+	if err := s.registerWalletEntry(e); err != nil {
+		return err
+	}
+	e.Created = time.Now()
+	e.Updated = e.Created
+	if err := s.putWalletEntry(e); err != nil {
+		s.forget(s.removeResolver(e.ID))
+		s.untrackExpiry(e.ID)
+		return err
+	}
+	s.walletEntries[e.ID] = e
+	logger.Debugf("identity [%s:%s] added to the wallet", e.MSPType, e.ID)
+	return nil
+}
+
+func (w *wallet) Get(id string) (*api2.WalletEntry, error) {
+	w.s.resolversMutex.RLock()
+	defer w.s.resolversMutex.RUnlock()
+
+	e, ok := w.s.walletEntries[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyEntry(e), nil
+}
+
+func (w *wallet) List(labels ...string) ([]*api2.WalletEntry, error) {
+	w.s.resolversMutex.RLock()
+	defer w.s.resolversMutex.RUnlock()
+
+	var res []*api2.WalletEntry
+	for _, e := range w.s.walletEntries {
+		if e.HasLabels(labels...) {
+			res = append(res, copyEntry(e))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (w *wallet) Update(id string, labels []string, metadata map[string]string) error {
+	s := w.s
+	s.resolversMutex.Lock()
+	defer s.resolversMutex.Unlock()
+
+	e, ok := s.walletEntries[id]
+	if !ok {
+		return errors.Errorf("identity [%s] not found in the wallet", id)
+	}
+	e = copyEntry(e)
+	e.Labels = labels
+	e.Metadata = metadata
+	e.Updated = time.Now()
+	if err := s.putWalletEntry(e); err != nil {
+		return err
+	}
+	s.walletEntries[id] = e
+	return nil
+}
+
+func (w *wallet) Rotate(id string, path string) error {
+	s := w.s
+	s.resolversMutex.Lock()
+	defer s.resolversMutex.Unlock()
+
+	e, ok := s.walletEntries[id]
+	if !ok {
+		return errors.Errorf("identity [%s] not found in the wallet", id)
+	}
+	old := s.removeResolver(id)
+	if old == nil {
+		return errors.Errorf("identity [%s] not registered", id)
+	}
+
+	rotated := copyEntry(e)
+	rotated.Path = path
+	if err := s.registerWalletEntry(rotated); err != nil {
+		// keep the current credentials
+		s.addResolver(old.Name, old.Type, old.EnrollmentID, old.GetIdentity)
+		return errors.WithMessagef(err, "failed rotating identity [%s]", id)
+	}
+	rotated.Updated = time.Now()
+	if err := s.putWalletEntry(rotated); err != nil {
+		return err
+	}
+	s.walletEntries[id] = rotated
+
+	// forget the previous identity, unless the credentials have not changed
+	oldID, _, err1 := old.GetIdentity()
+	newID, _, err2 := s.resolversByName[id].GetIdentity()
+	if old.Type != BccspMSP || err1 != nil || err2 != nil || !oldID.Equal(newID) {
+		s.forget(old)
+	}
+	logger.Debugf("identity [%s:%s] rotated", rotated.MSPType, id)
+	return nil
+}
+
+func (w *wallet) Remove(id string) error {
+	s := w.s
+	s.resolversMutex.Lock()
+	defer s.resolversMutex.Unlock()
+
+	if _, ok := s.walletEntries[id]; !ok {
+		return errors.Errorf("identity [%s] not found in the wallet", id)
+	}
+	if err := kvs.GetService(s.sp).Delete(walletKey(s.config.Name(), id)); err != nil {
+		return errors.WithMessagef(err, "failed deleting wallet entry [%s]", id)
+	}
+	delete(s.walletEntries, id)
+	s.forget(s.removeResolver(id))
+	s.untrackExpiry(id)
+	logger.Debugf("identity [%s] removed from the wallet", id)
+	return nil
+}
+
+// registerWalletEntry registers the msp described by the passed entry, and sets its enrollment id
+func (s *service) registerWalletEntry(e *api2.WalletEntry) error {
+	var err error
+	switch e.MSPType {
+	case BccspMSP:
+		err = s.registerX509MSP(e.ID, e.Path, e.MSPID)
+	case IdemixMSP:
+		err = s.registerIdemixMSP(e.ID, e.Path, e.MSPID)
+	default:
+		return errors.Errorf("msp type [%s] not supported by the wallet, expected [%s] or [%s]", e.MSPType, BccspMSP, IdemixMSP)
+	}
+	if err != nil {
+		return err
+	}
+	e.EnrollmentID = s.resolversByName[e.ID].EnrollmentID
+	return nil
+}
+
+// forget unregisters the signer of the identity of the passed resolver and removes its binding
+func (s *service) forget(r *Resolver) {
+	if r == nil || r.Type != BccspMSP {
+		return
+	}
+	id, _, err := r.GetIdentity()
+	if err != nil {
+		logger.Warnf("cannot get identity for [%s]: [%s]", r.Name, err)
+		return
+	}
+	if s.signerService != nil {
+		if err := s.signerService.UnregisterSigner(id); err != nil {
+			logger.Warnf("failed unregistering signer of [%s]: [%s]", r.Name, err)
+		}
+	}
+	if s.binderService != nil {
+		if err := s.binderService.Unbind(id); err != nil {
+			logger.Debugf("failed unbinding [%s]: [%s]", r.Name, err)
+		}
+	}
+}
+
+func (s *service) putWalletEntry(e *api2.WalletEntry) error {
+	if err := kvs.GetService(s.sp).Put(walletKey(s.config.Name(), e.ID), e); err != nil {
+		return errors.WithMessagef(err, "failed storing wallet entry [%s]", e.ID)
+	}
+	return nil
+}
+
+// loadWallet registers the identities stored in the wallet, the entries that cannot be loaded are skipped
+func (s *service) loadWallet() error {
+	kvss, err := s.sp.GetService(&kvs.KVS{})
+	if err != nil {
+		logger.Debugf("kvs not available, wallet not loaded")
+		return nil
+	}
+	it, err := kvss.(*kvs.KVS).GetByPartialCompositeID(walletPrefix, []string{s.config.Name()})
+	if err != nil {
+		return errors.WithMessage(err, "failed iterating over the wallet")
+	}
+	defer it.Close()
+
+	for it.HasNext() {
+		e := &api2.WalletEntry{}
+		if err := it.Next(e); err != nil {
+			return errors.WithMessage(err, "failed reading wallet entry")
+		}
+		if _, ok := s.resolversByName[e.ID]; ok {
+			logger.Warnf("wallet entry [%s] shadowed by a configured identity, skipping", e.ID)
+			continue
+		}
+		if err := s.registerWalletEntry(e); err != nil {
+			logger.Warnf("failed loading wallet entry [%s:%s]: [%s]", e.MSPType, e.ID, err)
+			continue
+		}
+		s.walletEntries[e.ID] = e
+	}
+	logger.Debugf("loaded [%d] identities from the wallet", len(s.walletEntries))
+	return nil
+}
+
+func walletKey(network, id string) string {
+	return kvs.CreateCompositeKeyOrPanic(walletPrefix, []string{network, id})
+}
+
+func copyEntry(e *api2.WalletEntry) *api2.WalletEntry {
+	c := *e
+	c.Labels = append([]string(nil), e.Labels...)
+	if e.Metadata != nil {
+		c.Metadata = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return &c
+}
+
+// walletIdentity returns the identity of the wallet entry with the passed id, nil if not in the wallet
+func (s *service) walletIdentity(id string) (view.Identity, error) {
+	s.resolversMutex.RLock()
+	defer s.resolversMutex.RUnlock()
+
+	if _, ok := s.walletEntries[id]; !ok {
+		return nil, nil
+	}
+	r, ok := s.resolversByName[id]
+	if !ok {
+		return nil, errors.Errorf("identity [%s] not registered", id)
+	}
+	identity, _, err := r.GetIdentity()
+	return identity, err
+}
@@ -10,6 +10,8 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/pkg/errors"
 )
 
@@ -30,3 +32,38 @@ func PemDecodeCert(pemBytes []byte) (*x509.Certificate, error) {
 		return nil, errors.Errorf("bad type %s, expected 'CERTIFICATE", block.Type)
 	}
 }
+
+// CertFromIdentity returns the certificate of the passed serialized identity
+func CertFromIdentity(raw []byte) (*x509.Certificate, error) {
+	si := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(raw, si); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal to msp.SerializedIdentity{}")
+	}
+	return PemDecodeCert(si.IdBytes)
+}
+
+// CertValidator checks that certificates are within their validity window and have not been revoked
+type CertValidator interface {
+	Validate(cert *x509.Certificate) error
+}
+
+// ValidateIdentity validates the certificate of the passed serialized identity.
+// Identities that do not carry a PEM encoded certificate, such as the idemix ones, are accepted.
+func ValidateIdentity(validator CertValidator, raw []byte) error {
+	si := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(raw, si); err != nil {
+		return errors.Wrap(err, "failed to unmarshal to msp.SerializedIdentity{}")
+	}
+	block, _ := pem.Decode(si.IdBytes)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.WithMessage(err, "pem bytes are not cert encoded")
+	}
+	if err := validator.Validate(cert); err != nil {
+		return errors.WithMessagef(err, "invalid identity of [%s]", si.Mspid)
+	}
+	return nil
+}
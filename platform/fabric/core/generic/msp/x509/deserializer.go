@@ -14,11 +14,16 @@ import (
 	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/pkg/errors"
 
+	x5092 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
-type Deserializer struct{}
+// Deserializer deserializes x509 identities.
+// If Validator is set, the certificates must be within their validity window and not revoked.
+type Deserializer struct {
+	Validator CertValidator
+}
 
 func (i *Deserializer) DeserializeVerifier(raw []byte) (driver.Verifier, error) {
 	si := &msp.SerializedIdentity{}
@@ -26,6 +31,11 @@ func (i *Deserializer) DeserializeVerifier(raw []byte) (driver.Verifier, error)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal to msp.SerializedIdentity{}")
 	}
+	if i.Validator != nil {
+		if err := ValidateIdentity(i.Validator, raw); err != nil {
+			return nil, err
+		}
+	}
 	genericPublicKey, err := PemDecodeKey(si.IdBytes)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed parsing received public key")
@@ -35,6 +45,11 @@ func (i *Deserializer) DeserializeVerifier(raw []byte) (driver.Verifier, error)
 		return nil, errors.New("expected *ecdsa.PublicKey")
 	}
 
+	if i.Validator != nil {
+		if cert, err := PemDecodeCert(si.IdBytes); err == nil {
+			return &x5092.ValidatingVerifier{Verifier: NewVerifier(publicKey), Validator: i.Validator, Cert: cert}, nil
+		}
+	}
 	return NewVerifier(publicKey), nil
 }
 
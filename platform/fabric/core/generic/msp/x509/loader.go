@@ -13,6 +13,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	msp2 "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
@@ -34,9 +35,16 @@ type SigningIdentity interface {
 	Sign(msg []byte) ([]byte, error)
 }
 
-// GetSigningIdentity retrieves a signing identity from the passed arguments
-func GetSigningIdentity(mspConfigPath, mspID string) (SigningIdentity, error) {
-	mspInstance, err := LoadLocalMSPAt(mspConfigPath, mspID, "bccsp")
+// GetSigningIdentity retrieves a signing identity from the passed arguments.
+// If csp is not nil, it holds the signing key, otherwise the key is read from the msp keystore.
+func GetSigningIdentity(mspConfigPath, mspID string, csp bccsp.BCCSP) (SigningIdentity, error) {
+	var mspInstance msp.MSP
+	var err error
+	if csp != nil {
+		mspInstance, err = LoadLocalMSPWithCSP(mspConfigPath, mspID, csp)
+	} else {
+		mspInstance, err = LoadLocalMSPAt(mspConfigPath, mspID, "bccsp")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +86,24 @@ func LoadLocalMSPAt(dir, id, mspType string) (msp.MSP, error) {
 	return thisMSP, nil
 }
 
+// LoadLocalMSPWithCSP loads an MSP whose configuration is stored at 'dir', and whose
+// signing key is held by the passed BCCSP.
+func LoadLocalMSPWithCSP(dir, id string, csp bccsp.BCCSP) (msp.MSP, error) {
+	conf, err := msp.GetLocalMspConfig(dir, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	thisMSP, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}}, csp)
+	if err != nil {
+		return nil, err
+	}
+	err = thisMSP.Setup(conf)
+	if err != nil {
+		return nil, err
+	}
+	return thisMSP, nil
+}
+
 func GetEnrollmentID(id []byte) (string, error) {
 	si := &msp2.SerializedIdentity{}
 	err := proto.Unmarshal(id, si)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/pkg/errors"
 
 	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
@@ -27,10 +28,14 @@ type provider struct {
 	sID          SigningIdentity
 	id           []byte
 	enrollmentID string
+	validator    CertValidator
 }
 
-func NewProvider(mspConfigPath, mspID string, signerService SignerService) (*provider, error) {
-	sID, err := GetSigningIdentity(mspConfigPath, mspID)
+// NewProvider returns a provider for the x509 MSP at the passed path. If csp is not nil, it holds the signing key,
+// otherwise the key is read from the msp keystore. If validator is not nil, the deserialized identities must be
+// within their validity window and not revoked.
+func NewProvider(mspConfigPath, mspID string, signerService SignerService, csp bccsp.BCCSP, validator CertValidator) (*provider, error) {
+	sID, err := GetSigningIdentity(mspConfigPath, mspID, csp)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +54,7 @@ func NewProvider(mspConfigPath, mspID string, signerService SignerService) (*pro
 		return nil, errors.Wrapf(err, "failed getting enrollment id for [%s:%s]", mspConfigPath, mspID)
 	}
 
-	return &provider{sID: sID, id: idRaw, enrollmentID: enrollmentID}, nil
+	return &provider{sID: sID, id: idRaw, enrollmentID: enrollmentID, validator: validator}, nil
 }
 
 func (p *provider) Identity() (view.Identity, []byte, error) {
@@ -66,6 +71,11 @@ func (p *provider) DeserializeVerifier(raw []byte) (driver.Verifier, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal to msp.SerializedIdentity{}")
 	}
+	if p.validator != nil {
+		if err := ValidateIdentity(p.validator, raw); err != nil {
+			return nil, err
+		}
+	}
 	genericPublicKey, err := PemDecodeKey(si.IdBytes)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed parsing received public key")
@@ -75,8 +85,6 @@ func (p *provider) DeserializeVerifier(raw []byte) (driver.Verifier, error) {
 		return nil, errors.New("expected *ecdsa.PublicKey")
 	}
 
-	// TODO: check the validity of the identity against the msp
-
 	return NewVerifier(publicKey), nil
 }
 
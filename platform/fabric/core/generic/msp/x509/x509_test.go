@@ -7,13 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package x509
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver/file"
 )
 
 func TestDeserializer(t *testing.T) {
-	p, err := NewProvider("./testdata/msp", "apple", nil)
+	p, err := NewProvider("./testdata/msp", "apple", nil, nil, nil)
 	assert.NoError(t, err)
 	id, auditInfo, err := p.Identity()
 	assert.NoError(t, err)
@@ -26,3 +33,34 @@ func TestDeserializer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "MSP.x509: [f+hVlmGaPejN2G0XDcESSMX2ol29WPcPQ+Fp3lOARBQ=][apple][auditor.org1.example.com]", info)
 }
+
+func TestProviderWithEncryptedKeystore(t *testing.T) {
+	// encrypt the key of the msp in a separate keystore
+	dir, err := ioutil.TempDir("", "x509")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	entries, err := ioutil.ReadDir("./testdata/msp/keystore")
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		keyPEM, err := ioutil.ReadFile(filepath.Join("./testdata/msp/keystore", entry.Name()))
+		assert.NoError(t, err)
+		encrypted, err := file.Encrypt(keyPEM, []byte("password"), nil)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, entry.Name()), encrypted, 0600))
+	}
+
+	csp, err := kms.NewCSP(&driver.Opts{File: dir, Password: "password", RejectPlaintext: true})
+	assert.NoError(t, err)
+	p, err := NewProvider("./testdata/msp", "apple", nil, csp, nil)
+	assert.NoError(t, err)
+	sID, err := p.SerializedIdentity()
+	assert.NoError(t, err)
+	sigma, err := sID.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, sID.Verify([]byte("hello"), sigma))
+
+	csp, err = kms.NewCSP(&driver.Opts{File: dir, Password: "wrong"})
+	assert.NoError(t, err)
+	_, err = NewProvider("./testdata/msp", "apple", nil, csp, nil)
+	assert.Error(t, err)
+}
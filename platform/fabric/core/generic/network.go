@@ -9,12 +9,14 @@ package generic
 import (
 	"io/ioutil"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/ordering"
+	peer2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/peer"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/rwset"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
@@ -25,6 +27,11 @@ import (
 
 var logger = flogging.MustGetLogger("fabric-sdk.core")
 
+var (
+	// peerRetryInterval is the time a peer that failed is not selected for new connections
+	peerRetryInterval = 30 * time.Second
+)
+
 type Channel struct {
 	Name    string `yaml:"Name,omitempty"`
 	Default bool   `yaml:"Default,omitempty"`
@@ -45,6 +52,7 @@ type network struct {
 	tlsRootCerts   [][]byte
 	orderers       []*grpc.ConnectionConfig
 	peers          []*grpc.ConnectionConfig
+	peerSelector   *peer2.Selector
 	defaultChannel string
 	channelDefs    []*Channel
 
@@ -200,6 +208,10 @@ func (f *network) init() error {
 		return errors.Wrap(err, "failed loading peers")
 	}
 	logger.Debugf("Peers [%v]", f.peers)
+	if len(f.peers) == 0 {
+		return errors.New("no peers configured")
+	}
+	f.peerSelector = peer2.NewSelector(f.peers, peerRetryInterval)
 
 	f.channelDefs, err = f.config.Channels()
 	if err != nil {
@@ -213,7 +225,14 @@ func (f *network) init() error {
 		}
 	}
 
-	f.ordering = ordering.NewService(f.sp, f)
+	orderingConfig, err := f.config.Ordering()
+	if err != nil {
+		return errors.Wrap(err, "failed loading ordering configuration")
+	}
+	f.ordering, err = ordering.NewService(f.sp, f, orderingConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed creating ordering service")
+	}
 	return nil
 }
 
@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ordering
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	common2 "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+const (
+	// RoundRobin selects the orderers to broadcast to in round-robin order
+	RoundRobin = "roundrobin"
+	// Random selects the orderers to broadcast to at random
+	Random = "random"
+
+	defaultRetryInterval = 500 * time.Millisecond
+)
+
+type broadcastResult struct {
+	address string
+	status  common2.Status
+	err     error
+}
+
+// broadcastEnvelope sends the passed envelope to the ordering service.
+// Failed attempts are retried, with exponential backoff, as long as the failure is transient.
+func (o *service) broadcastEnvelope(env *common2.Envelope) error {
+	interval := o.config.RetryInterval
+	var err error
+	for attempt := 0; attempt <= o.config.NumRetries; attempt++ {
+		if attempt > 0 {
+			logger.Debugf("retrying broadcast in [%s], attempt [%d] of [%d]", interval, attempt, o.config.NumRetries)
+			time.Sleep(interval)
+			interval *= 2
+		}
+
+		var retry bool
+		retry, err = o.broadcastToQuorum(env)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return err
+		}
+		logger.Warnf("broadcast attempt [%d] failed [%s]", attempt, err)
+	}
+	return errors.WithMessagef(err, "failed broadcasting after [%d] retries", o.config.NumRetries)
+}
+
+// broadcastToQuorum sends the passed envelope, in parallel, to the configured number of orderers and
+// succeeds as soon as a quorum of them accepted it.
+// On failure, it also returns true if all the failures were transient and the broadcast can be retried.
+func (o *service) broadcastToQuorum(env *common2.Envelope) (bool, error) {
+	orderers := o.selectOrderers(o.config.BroadcastTo)
+	if len(orderers) == 0 {
+		return false, errors.New("no orderers available")
+	}
+	quorum := o.config.Quorum
+	if quorum > len(orderers) {
+		quorum = len(orderers)
+	}
+
+	results := make(chan *broadcastResult, len(orderers))
+	for _, orderer := range orderers {
+		go func(orderer *grpc.ConnectionConfig) {
+			status, err := o.broadcastTo(orderer, env)
+			results <- &broadcastResult{address: orderer.Address, status: status, err: err}
+		}(orderer)
+	}
+
+	acks := 0
+	retry := true
+	var errs []error
+	for i := 0; i < len(orderers); i++ {
+		res := <-results
+		if res.err == nil {
+			logger.Debugf("broadcast accepted by orderer [%s]", res.address)
+			acks++
+			if acks >= quorum {
+				return false, nil
+			}
+			continue
+		}
+		logger.Debugf("broadcast to orderer [%s] failed with status [%s] [%s]", res.address, res.status, res.err)
+		errs = append(errs, res.err)
+		retry = retry && isRetriable(res.status)
+		if len(errs) > len(orderers)-quorum {
+			// the quorum cannot be reached anymore
+			break
+		}
+	}
+	return retry, errors.WithMessagef(toError(errs), "failed broadcasting, got [%d] acks out of a quorum of [%d]", acks, quorum)
+}
+
+// broadcastTo sends the passed envelope to the passed orderer and waits for its response
+func (o *service) broadcastTo(orderer *grpc.ConnectionConfig, env *common2.Envelope) (common2.Status, error) {
+	client, err := o.getClient(orderer)
+	if err != nil {
+		return common2.Status_UNKNOWN, err
+	}
+	broadcastClient, err := client.NewBroadcast(context.Background())
+	if err != nil {
+		o.discardClient(orderer.Address, client)
+		return common2.Status_UNKNOWN, err
+	}
+	if err := BroadcastSend(broadcastClient, orderer.Address, env); err != nil {
+		o.discardClient(orderer.Address, client)
+		return common2.Status_UNKNOWN, err
+	}
+
+	responses := make(chan common2.Status)
+	errs := make(chan error, 1)
+	go BroadcastReceive(broadcastClient, orderer.Address, responses, errs)
+	status, err := BroadcastWaitForResponse(responses, errs)
+	if status == common2.Status_SUCCESS {
+		return status, nil
+	}
+	if err == nil {
+		err = errors.Errorf("no response from orderer %s", orderer.Address)
+	}
+	if status == common2.Status_UNKNOWN {
+		// the stream broke, do not reuse the connection
+		o.discardClient(orderer.Address, client)
+	}
+	return status, errors.Wrapf(err, "failed broadcasting, status %s", common2.Status_name[int32(status)])
+}
+
+// selectOrderers returns up to n distinct orderers following the configured selection strategy
+func (o *service) selectOrderers(n int) []*grpc.ConnectionConfig {
+	orderers := o.network.Orderers()
+	if len(orderers) == 0 {
+		return nil
+	}
+	if n > len(orderers) {
+		n = len(orderers)
+	}
+	res := make([]*grpc.ConnectionConfig, n)
+	switch o.config.Selection {
+	case Random:
+		for i, j := range rand.Perm(len(orderers))[:n] {
+			res[i] = orderers[j]
+		}
+	default:
+		start := int((atomic.AddUint64(&o.next, 1) - 1) % uint64(len(orderers)))
+		for i := 0; i < n; i++ {
+			res[i] = orderers[(start+i)%len(orderers)]
+		}
+	}
+	return res
+}
+
+// getClient returns the pooled client for the passed orderer, creating it if needed
+func (o *service) getClient(orderer *grpc.ConnectionConfig) (OrdererClient, error) {
+	o.clientsLock.Lock()
+	defer o.clientsLock.Unlock()
+
+	if client, ok := o.clients[orderer.Address]; ok {
+		return client, nil
+	}
+	client, err := o.newClient(orderer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating orderer client for %s", orderer.Address)
+	}
+	o.clients[orderer.Address] = client
+	return client, nil
+}
+
+// discardClient closes and removes the passed client from the pool
+func (o *service) discardClient(address string, client OrdererClient) {
+	o.clientsLock.Lock()
+	defer o.clientsLock.Unlock()
+
+	if o.clients[address] == client {
+		delete(o.clients, address)
+		client.Close()
+	}
+}
+
+// isRetriable returns true if a broadcast that failed with the passed status might succeed if retried.
+// An unknown status means that no response was received from the orderer, for instance due to a connection error.
+func isRetriable(status common2.Status) bool {
+	return status == common2.Status_UNKNOWN || status == common2.Status_SERVICE_UNAVAILABLE
+}
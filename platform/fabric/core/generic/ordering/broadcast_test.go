@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ordering
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	common2 "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpc2 "google.golang.org/grpc"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/config"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+// orderer answers each broadcast with the next of its statuses, UNKNOWN stands for a connection failure
+type orderer struct {
+	lock      sync.Mutex
+	statuses  []common2.Status
+	envelopes int
+	clients   int
+	closed    int
+}
+
+func (o *orderer) next() common2.Status {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	status := o.statuses[0]
+	if len(o.statuses) > 1 {
+		o.statuses = o.statuses[1:]
+	}
+	return status
+}
+
+type fakeClient struct {
+	OrdererClient
+	orderer *orderer
+}
+
+func (c *fakeClient) NewBroadcast(ctx context.Context, opts ...grpc2.CallOption) (Broadcast, error) {
+	status := c.orderer.next()
+	if status == common2.Status_UNKNOWN {
+		return nil, errors.New("connection refused")
+	}
+	return &broadcast{orderer: c.orderer, status: status}, nil
+}
+
+func (c *fakeClient) Close() {
+	c.orderer.lock.Lock()
+	defer c.orderer.lock.Unlock()
+	c.orderer.closed++
+}
+
+type broadcast struct {
+	orderer *orderer
+	status  common2.Status
+	done    bool
+}
+
+func (b *broadcast) Send(m *common2.Envelope) error {
+	b.orderer.lock.Lock()
+	defer b.orderer.lock.Unlock()
+	b.orderer.envelopes++
+	return nil
+}
+
+func (b *broadcast) Recv() (*ab.BroadcastResponse, error) {
+	if b.done {
+		return nil, io.EOF
+	}
+	b.done = true
+	return &ab.BroadcastResponse{Status: b.status}, nil
+}
+
+func (b *broadcast) CloseSend() error { return nil }
+
+type network struct {
+	Network
+	orderers []*grpc.ConnectionConfig
+}
+
+func (n *network) Orderers() []*grpc.ConnectionConfig { return n.orderers }
+
+func newBroadcastService(config *config.Ordering, orderers map[string]*orderer) *service {
+	n := &network{}
+	for _, address := range []string{"orderer0", "orderer1", "orderer2"} {
+		if _, ok := orderers[address]; ok {
+			n.orderers = append(n.orderers, &grpc.ConnectionConfig{Address: address})
+		}
+	}
+	return &service{
+		network: n,
+		config:  config,
+		clients: map[string]OrdererClient{},
+		newClient: func(config *grpc.ConnectionConfig) (OrdererClient, error) {
+			o := orderers[config.Address]
+			o.lock.Lock()
+			defer o.lock.Unlock()
+			o.clients++
+			return &fakeClient{orderer: o}, nil
+		},
+	}
+}
+
+func TestBroadcastQuorum(t *testing.T) {
+	ok := func() *orderer { return &orderer{statuses: []common2.Status{common2.Status_SUCCESS}} }
+	bad := func() *orderer { return &orderer{statuses: []common2.Status{common2.Status_BAD_REQUEST}} }
+
+	// a quorum of acks is enough
+	orderers := map[string]*orderer{"orderer0": ok(), "orderer1": bad(), "orderer2": ok()}
+	s := newBroadcastService(&config.Ordering{BroadcastTo: 3, Quorum: 2, NumRetries: 3, RetryInterval: time.Millisecond}, orderers)
+	assert.NoError(t, s.broadcastEnvelope(&common2.Envelope{}))
+
+	// a quorum that cannot be reached fails, the rejections are not retried
+	orderers = map[string]*orderer{"orderer0": ok(), "orderer1": bad(), "orderer2": bad()}
+	s = newBroadcastService(&config.Ordering{BroadcastTo: 3, Quorum: 2, NumRetries: 3, RetryInterval: time.Millisecond}, orderers)
+	err := s.broadcastEnvelope(&common2.Envelope{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "out of a quorum of [2]")
+	assert.Contains(t, err.Error(), "broadcast response error")
+	for _, o := range []string{"orderer1", "orderer2"} {
+		assert.LessOrEqual(t, orderers[o].envelopes, 1)
+	}
+}
+
+func TestBroadcastRetry(t *testing.T) {
+	// the connection to the orderer fails, then the orderer is unavailable, then it accepts the envelope
+	o := &orderer{statuses: []common2.Status{common2.Status_UNKNOWN, common2.Status_SERVICE_UNAVAILABLE, common2.Status_SUCCESS}}
+	s := newBroadcastService(&config.Ordering{BroadcastTo: 1, NumRetries: 2, RetryInterval: time.Millisecond}, map[string]*orderer{"orderer0": o})
+	assert.NoError(t, s.broadcastEnvelope(&common2.Envelope{}))
+	assert.Equal(t, 2, o.envelopes)
+	// the broken client is discarded and replaced, the unavailable orderer keeps its client
+	assert.Equal(t, 2, o.clients)
+	assert.Equal(t, 1, o.closed)
+
+	// retries are bounded
+	o = &orderer{statuses: []common2.Status{common2.Status_SERVICE_UNAVAILABLE}}
+	s = newBroadcastService(&config.Ordering{BroadcastTo: 1, NumRetries: 2, RetryInterval: time.Millisecond}, map[string]*orderer{"orderer0": o})
+	err := s.broadcastEnvelope(&common2.Envelope{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed broadcasting after [2] retries")
+	assert.Equal(t, 3, o.envelopes)
+}
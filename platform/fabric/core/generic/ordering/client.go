@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	grpc2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 
@@ -49,7 +50,9 @@ type ordererClient struct {
 	ordererAddr        string
 	serverNameOverride string
 	grpcClient         *grpc2.Client
-	conn               *grpc.ClientConn
+
+	connLock sync.RWMutex
+	conn     *grpc.ClientConn
 }
 
 func NewOrdererClient(config *grpc2.ConnectionConfig) (OrdererClient, error) {
@@ -71,7 +74,6 @@ func NewOrdererClient(config *grpc2.ConnectionConfig) (OrdererClient, error) {
 	}, nil
 }
 
-// TODO: improve by providing grpc connection pool
 func (oc *ordererClient) Close() {
 	go oc.grpcClient.Close()
 }
@@ -79,15 +81,27 @@ func (oc *ordererClient) Close() {
 // NewBroadcast creates a Broadcast
 func (oc *ordererClient) NewBroadcast(ctx context.Context, opts ...grpc.CallOption) (Broadcast, error) {
 	// reuse the existing connection to create Broadcast client
-	broadcast, err := ab.NewAtomicBroadcastClient(oc.conn).Broadcast(ctx)
+	oc.connLock.RLock()
+	conn := oc.conn
+	oc.connLock.RUnlock()
+	broadcast, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
 	if err == nil {
 		return broadcast, nil
 	}
 
 	// error occurred with the existing connection, so create a new connection to orderer
-	oc.conn, err = oc.grpcClient.NewConnection(oc.ordererAddr)
-	if err != nil {
-		return nil, errors.WithMessagef(err, "failed to connect to orderer %s", oc.ordererAddr)
+	oc.connLock.Lock()
+	defer oc.connLock.Unlock()
+	if oc.conn == conn {
+		// the broken connection is closed before being replaced, unless another caller already replaced it
+		if err := conn.Close(); err != nil {
+			logger.Debugf("failed closing connection to orderer %s [%s]", oc.ordererAddr, err)
+		}
+		newConn, err := oc.grpcClient.NewConnection(oc.ordererAddr)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to connect to orderer %s", oc.ordererAddr)
+		}
+		oc.conn = newConn
 	}
 
 	// create a new Broadcast
@@ -135,6 +149,7 @@ func BroadcastReceive(broadcast Broadcast, addr string, responses chan common.St
 			responses <- broadcastResponse.Status
 		} else {
 			errs <- errors.Errorf("broadcast response error %d from orderer %s", int32(broadcastResponse.Status), addr)
+			responses <- broadcastResponse.Status
 		}
 	}
 }
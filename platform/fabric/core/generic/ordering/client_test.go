@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ordering
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	common2 "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/stretchr/testify/assert"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+type broadcastServer struct {
+	ab.UnimplementedAtomicBroadcastServer
+}
+
+func (b *broadcastServer) Broadcast(srv ab.AtomicBroadcast_BroadcastServer) error {
+	if _, err := srv.Recv(); err != nil {
+		return err
+	}
+	return srv.Send(&ab.BroadcastResponse{Status: common2.Status_SUCCESS})
+}
+
+func TestNewBroadcastReconnect(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	gs := grpc2.NewServer()
+	ab.RegisterAtomicBroadcastServer(gs, &broadcastServer{})
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	client, err := NewOrdererClient(&grpc.ConnectionConfig{Address: lis.Addr().String(), ConnectionTimeout: 5 * time.Second})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	// the connection breaks, it is closed and replaced by a new one
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	assert.NoError(t, dead.Close())
+	broken, err := grpc2.Dial(dead.Addr().String(), grpc2.WithInsecure())
+	assert.NoError(t, err)
+	oc := client.(*ordererClient)
+	oc.conn = broken
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	b, err := client.NewBroadcast(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, connectivity.Shutdown, broken.GetState())
+	assert.NotEqual(t, broken, oc.conn)
+
+	assert.NoError(t, BroadcastSend(b, lis.Addr().String(), &common2.Envelope{}))
+	response, err := b.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, common2.Status_SUCCESS, response.Status)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ordering
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	broadcastsCounterOpts = metrics.CounterOpts{
+		Namespace:    "fabric",
+		Subsystem:    "ordering",
+		Name:         "broadcasts",
+		Help:         "Envelopes broadcast to the ordering service.",
+		LabelNames:   []string{"network", "success"},
+		StatsdFormat: "%{#fqname}.%{network}.%{success}",
+	}
+
+	broadcastDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:    "fabric",
+		Subsystem:    "ordering",
+		Name:         "broadcast_duration",
+		Help:         "Time taken to broadcast an envelope to the ordering service, retries included, in seconds.",
+		LabelNames:   []string{"network"},
+		StatsdFormat: "%{#fqname}.%{network}",
+	}
+)
+
+// Metrics are the metrics of the broadcasts to the ordering service of a network
+type Metrics struct {
+	Broadcasts        metrics.Counter
+	BroadcastDuration metrics.Histogram
+}
+
+// NewMetrics returns the metrics of the broadcasts of the passed network
+func NewMetrics(p metrics.Provider, network string) *Metrics {
+	return &Metrics{
+		Broadcasts:        p.NewCounter(broadcastsCounterOpts).With("network", network),
+		BroadcastDuration: p.NewHistogram(broadcastDurationHistogramOpts).With("network", network),
+	}
+}
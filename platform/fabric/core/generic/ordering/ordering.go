@@ -8,14 +8,18 @@ package ordering
 
 import (
 	"bytes"
-	"context"
 	"encoding/base64"
 	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/config"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 
 	"github.com/golang/protobuf/proto"
 	common2 "github.com/hyperledger/fabric-protos-go/common"
@@ -50,6 +54,7 @@ type Configuration interface {
 
 type Network interface {
 	Configuration
+	Name() string
 	Peers() []*grpc.ConnectionConfig
 	LocalMembership() driver.LocalMembership
 	// Broadcast sends the passed blob to the ordering service to be ordered
@@ -70,13 +75,45 @@ type Transaction interface {
 type service struct {
 	sp      view2.ServiceProvider
 	network Network
+	config  *config.Ordering
+
+	clientsLock sync.Mutex
+	clients     map[string]OrdererClient
+	newClient   func(config *grpc.ConnectionConfig) (OrdererClient, error)
+	next        uint64
+
+	metrics *Metrics
 }
 
-func NewService(sp view2.ServiceProvider, network Network) *service {
-	return &service{
-		sp:      sp,
-		network: network,
+func NewService(sp view2.ServiceProvider, network Network, orderingConfig *config.Ordering) (*service, error) {
+	if orderingConfig == nil {
+		orderingConfig = &config.Ordering{}
+	}
+	switch orderingConfig.Selection {
+	case "":
+		orderingConfig.Selection = RoundRobin
+	case RoundRobin, Random:
+	default:
+		return nil, errors.Errorf("invalid orderer selection [%s], expected [%s] or [%s]", orderingConfig.Selection, RoundRobin, Random)
+	}
+	if orderingConfig.BroadcastTo <= 0 {
+		orderingConfig.BroadcastTo = 1
+	}
+	if orderingConfig.Quorum <= 0 || orderingConfig.Quorum > orderingConfig.BroadcastTo {
+		orderingConfig.Quorum = orderingConfig.BroadcastTo
 	}
+	if orderingConfig.RetryInterval <= 0 {
+		orderingConfig.RetryInterval = defaultRetryInterval
+	}
+
+	return &service{
+		sp:        sp,
+		network:   network,
+		config:    orderingConfig,
+		clients:   map[string]OrdererClient{},
+		newClient: NewOrdererClient,
+		metrics:   NewMetrics(operations.GetMetricsProvider(sp), network.Name()),
+	}, nil
 }
 
 func (o *service) Broadcast(blob interface{}) error {
@@ -99,7 +136,11 @@ func (o *service) Broadcast(blob interface{}) error {
 		return errors.Errorf("invalid blob's type, got [%T]", blob)
 	}
 
-	return o.broadcastEnvelope(env)
+	start := time.Now()
+	err = o.broadcastEnvelope(env)
+	o.metrics.Broadcasts.With("success", strconv.FormatBool(err == nil)).Add(1)
+	o.metrics.BroadcastDuration.Observe(time.Since(start).Seconds())
+	return err
 }
 
 func (o *service) createFabricEndorseTransactionEnvelope(tx Transaction) (*common2.Envelope, error) {
@@ -144,40 +185,6 @@ func (o *service) castFabricEndorseTransactionEnvelope(blob []byte) (*common2.En
 	return env, nil
 }
 
-func (o *service) broadcastEnvelope(env *common2.Envelope) error {
-	OrdererConfig := o.network.Orderers()[0]
-
-	ordererClient, err := NewOrdererClient(OrdererConfig)
-	if err != nil {
-		return err
-	}
-	broadcastClient, err := ordererClient.NewBroadcast(context.Background())
-	if err != nil {
-		ordererClient.Close()
-		return err
-	}
-	defer func() {
-		broadcastClient.CloseSend()
-		ordererClient.Close()
-	}()
-
-	// send the envelope for ordering
-	err = BroadcastSend(broadcastClient, OrdererConfig.Address, env)
-	if err != nil {
-		return err
-	}
-
-	responses := make(chan common2.Status)
-	errs := make(chan error, 1)
-	go BroadcastReceive(broadcastClient, OrdererConfig.Address, responses, errs)
-	status, err := BroadcastWaitForResponse(responses, errs)
-	if status != common2.Status_SUCCESS {
-		err = errors.Wrapf(err, "failed broadcasting, status %s", common2.Status_name[int32(status)])
-	}
-
-	return err
-}
-
 // createSignedTx assembles an Envelope message from proposal, endorsements,
 // and a signer. This function should be called by a client when it has
 // collected enough endorsements for a proposal to create a transaction and
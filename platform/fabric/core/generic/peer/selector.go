@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+var logger = flogging.MustGetLogger("fabric-sdk.peer")
+
+// Selector keeps track of the health of the peers of a network and selects the peer to connect to.
+// The selected peer is kept as long as it works. Only when it fails, the selector fails over to the next
+// healthy peer in the list. A peer that failed is skipped until the retry interval elapses or a connection
+// to it succeeds again.
+type Selector struct {
+	peers      []*grpc.ConnectionConfig
+	retryAfter time.Duration
+
+	lock     sync.RWMutex
+	current  int
+	failures map[string]time.Time
+}
+
+// NewSelector returns a new Selector for the passed peers
+func NewSelector(peers []*grpc.ConnectionConfig, retryAfter time.Duration) *Selector {
+	return &Selector{
+		peers:      peers,
+		retryAfter: retryAfter,
+		failures:   map[string]time.Time{},
+	}
+}
+
+// Len returns the number of peers known by this selector
+func (s *Selector) Len() int {
+	return len(s.peers)
+}
+
+// Current returns the peer currently selected, nil if no peer is available
+func (s *Selector) Current() *grpc.ConnectionConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.peers) == 0 {
+		return nil
+	}
+	return s.peers[s.current]
+}
+
+// Failed marks the peer at the passed address as unhealthy.
+// If that peer is the one currently selected, the selector moves to the next healthy peer.
+// If no peer is healthy, the selector moves to the peer that failed the longest time ago.
+func (s *Selector) Failed(address string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	s.failures[address] = now
+	if len(s.peers) == 0 || s.peers[s.current].Address != address {
+		return
+	}
+
+	oldest := s.current
+	for i := 1; i < len(s.peers); i++ {
+		next := (s.current + i) % len(s.peers)
+		if s.isHealthy(s.peers[next].Address, now) {
+			logger.Debugf("peer [%s] failed, switching to [%s]", address, s.peers[next].Address)
+			s.current = next
+			return
+		}
+		if s.failures[s.peers[next].Address].Before(s.failures[s.peers[oldest].Address]) {
+			oldest = next
+		}
+	}
+	logger.Debugf("peer [%s] failed, no healthy peer available, switching to [%s]", address, s.peers[oldest].Address)
+	s.current = oldest
+}
+
+// Succeeded marks the peer at the passed address as healthy
+func (s *Selector) Succeeded(address string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.failures, address)
+}
+
+// Healthy returns the peers that are currently considered healthy
+func (s *Selector) Healthy() []*grpc.ConnectionConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := time.Now()
+	var res []*grpc.ConnectionConfig
+	for _, peer := range s.peers {
+		if s.isHealthy(peer.Address, now) {
+			res = append(res, peer)
+		}
+	}
+	return res
+}
+
+func (s *Selector) isHealthy(address string, now time.Time) bool {
+	failedAt, ok := s.failures[address]
+	return !ok || now.Sub(failedAt) >= s.retryAfter
+}
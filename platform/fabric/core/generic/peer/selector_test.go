@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+func TestSelector(t *testing.T) {
+	s := NewSelector([]*grpc.ConnectionConfig{
+		{Address: "peer0:7051"},
+		{Address: "peer1:7051"},
+		{Address: "peer2:7051"},
+	}, time.Hour)
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, "peer0:7051", s.Current().Address)
+	assert.Len(t, s.Healthy(), 3)
+
+	// a failure of a peer that is not selected does not change the selection
+	s.Failed("peer2:7051")
+	assert.Equal(t, "peer0:7051", s.Current().Address)
+	assert.Len(t, s.Healthy(), 2)
+
+	// failing the current peer skips the unhealthy ones
+	s.Failed("peer0:7051")
+	assert.Equal(t, "peer1:7051", s.Current().Address)
+	assert.Len(t, s.Healthy(), 1)
+
+	// no healthy peer left, pick the one that failed the longest time ago
+	s.Failed("peer1:7051")
+	assert.Equal(t, "peer2:7051", s.Current().Address)
+	assert.Len(t, s.Healthy(), 0)
+
+	s.Succeeded("peer0:7051")
+	assert.Len(t, s.Healthy(), 1)
+	s.Failed("peer2:7051")
+	assert.Equal(t, "peer0:7051", s.Current().Address)
+}
+
+func TestSelectorRetryAfter(t *testing.T) {
+	s := NewSelector([]*grpc.ConnectionConfig{
+		{Address: "peer0:7051"},
+		{Address: "peer1:7051"},
+	}, 0)
+	s.Failed("peer0:7051")
+	assert.Equal(t, "peer1:7051", s.Current().Address)
+	// with no retry interval, a failed peer is immediately healthy again
+	assert.Len(t, s.Healthy(), 2)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generic
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
+)
+
+// LoadPvtRWSet returns the private rwset of the endorser transaction with the passed id, as stored by this node
+func (c *channel) LoadPvtRWSet(txid string) ([]byte, error) {
+	raw, err := c.TransactionService().LoadTransaction(txid)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "cannot load etx [%s]", txid)
+	}
+	tx := &transaction.Transaction{}
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling etx [%s]", txid)
+	}
+	return tx.PvtRWSet, nil
+}
+
+// pvtDataCommitter stores the private data of committed transactions
+type pvtDataCommitter interface {
+	// CommitPvtData stores the private writes of the passed private rwset, once checked against the passed rwset
+	CommitPvtData(txid string, block uint64, indexInBloc int, results, pvtRWSet []byte) error
+}
+
+// StorePvtRWSet stores the private writes of the passed private rwset of the committed transaction with the passed id.
+// The position of the transaction in the ledger gives the version of the private writes.
+func (c *channel) StorePvtRWSet(txid string, pvtRWSet []byte) error {
+	block, err := c.getBlockByTxID(txid)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting block of [%s]", txid)
+	}
+	return storePvtRWSet(c.vault, block, txid, pvtRWSet)
+}
+
+// storePvtRWSet commits the passed private rwset of the transaction with the passed id, found in the passed block
+func storePvtRWSet(committer pvtDataCommitter, block *common.Block, txid string, pvtRWSet []byte) error {
+	if block.Header == nil || block.Data == nil {
+		return errors.Errorf("invalid block of [%s], missing header or data", txid)
+	}
+	for i, data := range block.Data.Data {
+		env, err := protoutil.UnmarshalEnvelope(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed unmarshalling envelope [%d] of block [%d]", i, block.Header.Number)
+		}
+		chdr, err := protoutil.ChannelHeader(env)
+		if err != nil {
+			return errors.Wrapf(err, "failed getting channel header [%d] of block [%d]", i, block.Header.Number)
+		}
+		if chdr.TxId != txid {
+			continue
+		}
+
+		action, err := protoutil.GetActionFromEnvelope(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed getting chaincode action of [%s]", txid)
+		}
+		return committer.CommitPvtData(txid, block.Header.Number, i, action.Results, pvtRWSet)
+	}
+	return errors.Errorf("transaction [%s] not found in block [%d]", txid, block.Header.Number)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/vault"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/vault/txidstore"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+)
+
+func newMemVault(t *testing.T) *vault.Vault {
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	return vault.New(ddb, tidstore)
+}
+
+func marshal(t *testing.T, m proto.Message) []byte {
+	raw, err := proto.Marshal(m)
+	assert.NoError(t, err)
+	return raw
+}
+
+// envelope returns an endorser transaction envelope with the passed id and results
+func envelope(t *testing.T, txid string, results []byte) []byte {
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: marshal(t, &common.ChannelHeader{
+			Type: int32(common.HeaderType_ENDORSER_TRANSACTION),
+			TxId: txid,
+		})},
+		Data: marshal(t, &pb.Transaction{Actions: []*pb.TransactionAction{{
+			Payload: marshal(t, &pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{
+				ProposalResponsePayload: marshal(t, &pb.ProposalResponsePayload{Extension: marshal(t, &pb.ChaincodeAction{Results: results})}),
+			}}),
+		}}}),
+	}
+	return marshal(t, &common.Envelope{Payload: marshal(t, payload)})
+}
+
+func getPrivateState(t *testing.T, v *vault.Vault, ns, coll, key string) []byte {
+	qe, err := v.NewQueryExecutor()
+	assert.NoError(t, err)
+	defer qe.Done()
+	value, err := qe.GetPrivateState(ns, coll, key)
+	assert.NoError(t, err)
+	return value
+}
+
+func TestStorePvtRWSet(t *testing.T) {
+	ns, coll := "namespace", "coll"
+
+	// a member of the collection endorses the transaction
+	member := newMemVault(t)
+	rws, err := member.NewRWSet("tx1")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k1", []byte("secret")))
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k2", []byte("another secret")))
+	rws.Done()
+	results, err := rws.Bytes()
+	assert.NoError(t, err)
+	pvt, err := rws.PvtBytes()
+	assert.NoError(t, err)
+
+	// the node commits the hashes only, then k2 is overwritten by a later transaction
+	v := newMemVault(t)
+	rws, err = v.GetRWSet("tx1", results)
+	assert.NoError(t, err)
+	rws.Done()
+	assert.NoError(t, v.CommitTX("tx1", 5, 1))
+	rws, err = v.NewRWSet("tx2")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k2", []byte("newer secret")))
+	rws.Done()
+	assert.NoError(t, v.CommitTX("tx2", 6, 0))
+
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 5},
+		Data:   &common.BlockData{Data: [][]byte{envelope(t, "tx0", nil), envelope(t, "tx1", results)}},
+	}
+
+	// the transaction must be in the block, the private data must match its hashes
+	assert.Error(t, storePvtRWSet(v, &common.Block{}, "tx1", pvt))
+	err = storePvtRWSet(v, block, "unknown", pvt)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "transaction [unknown] not found in block [5]")
+	forged, err := (&rwsetutil.TxPvtRwSet{NsPvtRwSet: []*rwsetutil.NsPvtRwSet{{
+		NameSpace: ns,
+		CollPvtRwSets: []*rwsetutil.CollPvtRwSet{{
+			CollectionName: coll,
+			KvRwSet:        &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "k1", Value: []byte("forged")}}},
+		}},
+	}}}).ToProtoBytes()
+	assert.NoError(t, err)
+	assert.Error(t, storePvtRWSet(v, block, "tx1", forged))
+	assert.Nil(t, getPrivateState(t, v, ns, coll, "k1"))
+
+	// the private writes take the version of the transaction, the keys written later keep their value
+	assert.NoError(t, storePvtRWSet(v, block, "tx1", pvt))
+	assert.Equal(t, []byte("secret"), getPrivateState(t, v, ns, coll, "k1"))
+	assert.Equal(t, []byte("newer secret"), getPrivateState(t, v, ns, coll, "k2"))
+}
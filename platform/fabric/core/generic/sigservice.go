@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	x5092 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/x509"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -35,3 +37,15 @@ func (s *SigService) GetSigningIdentity(id view.Identity) (driver.SigningIdentit
 func (s *SigService) RegisterSigner(identity view.Identity, signer driver.Signer, verifier driver.Verifier) error {
 	return view2.GetSigService(s.sp).RegisterSigner(identity, signer, verifier)
 }
+
+func (s *SigService) UnregisterSigner(identity view.Identity) error {
+	return view2.GetSigService(s.sp).UnregisterSigner(identity)
+}
+
+func (s *SigService) IsValid(identity view.Identity) error {
+	validator := x509.GetValidator(s.sp)
+	if validator == nil {
+		return nil
+	}
+	return x5092.ValidateIdentity(validator, identity)
+}
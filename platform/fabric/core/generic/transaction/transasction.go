@@ -90,7 +90,9 @@ type Transaction struct {
 	TFunction         string
 	TParameters       [][]byte
 
-	RWSet      []byte
+	RWSet []byte
+	// PvtRWSet carries the private writes of RWSet, it is shared only with the members of the collections
+	PvtRWSet   []byte
 	TTransient driver.TransientMap
 
 	TProposal          *pb.Proposal
@@ -164,6 +166,7 @@ func (t *Transaction) From(tx driver.Transaction) (err error) {
 	t.TFunction = payload.TFunction
 	t.TParameters = payload.TParameters
 	t.RWSet = payload.RWSet
+	t.PvtRWSet = payload.PvtRWSet
 	t.TProposal = payload.TProposal
 	t.TSignedProposal = payload.TSignedProposal
 	if payload.TSignedProposal != nil {
@@ -324,6 +327,14 @@ func (t *Transaction) SetRWSet() error {
 			return err
 		}
 	}
+	if len(t.PvtRWSet) != 0 {
+		logger.Debugf("populate private writes")
+		if err := t.rwset.AppendPvtRWSet(t.PvtRWSet); err != nil {
+			t.rwset.Done()
+			t.rwset = nil
+			return errors.WithMessagef(err, "invalid private rwset for [%s]", t.ID())
+		}
+	}
 	logger.Debugf("rws set [%s]", t.rwset.String())
 	return nil
 }
@@ -343,6 +354,10 @@ func (t *Transaction) Done() error {
 		if err != nil {
 			return errors.Wrapf(err, "failed marshalling rws")
 		}
+		t.PvtRWSet, err = t.rwset.PvtBytes()
+		if err != nil {
+			return errors.Wrapf(err, "failed marshalling private rws")
+		}
 		logger.Debugf("terminated simulation with [%s][len:%d]", t.rwset.Namespaces(), len(t.RWSet))
 	}
 	return nil
@@ -362,6 +377,10 @@ func (t *Transaction) Raw() ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+		t.PvtRWSet, err = t.rwset.PvtBytes()
+		if err != nil {
+			return nil, err
+		}
 	}
 	return json.Marshal(t)
 }
@@ -384,6 +403,8 @@ func (t *Transaction) Bytes() ([]byte, error) {
 	return json.Marshal(t)
 }
 
+// BytesNoTransient marshals the transaction without the transient and the private rwset,
+// the parties receiving it see the hashes of the private data only
 func (t *Transaction) BytesNoTransient() ([]byte, error) {
 	if err := t.Done(); err != nil {
 		return nil, err
@@ -393,10 +414,31 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 		return nil, err
 	}
 	temp.ResetTransient()
+	temp.PvtRWSet = nil
 	return json.Marshal(temp)
 
 }
 
+// BytesNoPvtData marshals the transaction without the private rwset and with the transient entries
+// the passed function keeps, the parties receiving it see the hashes of the private data only
+func (t *Transaction) BytesNoPvtData(keep func(key string) bool) ([]byte, error) {
+	if err := t.Done(); err != nil {
+		return nil, err
+	}
+	temp := &Transaction{}
+	if err := temp.From(t); err != nil {
+		return nil, err
+	}
+	temp.ResetTransient()
+	for k, v := range t.TTransient {
+		if keep(k) {
+			temp.TTransient[k] = v
+		}
+	}
+	temp.PvtRWSet = nil
+	return json.Marshal(temp)
+}
+
 func (t *Transaction) Endorse() error {
 	return t.EndorseWithIdentity(t.Creator())
 }
@@ -103,6 +103,7 @@ func (c *channel) CommitConfig(blockNumber uint64, envelope []byte) error {
 	c.lock.Lock()
 	c.resources = bundle
 	c.lock.Unlock()
+	c.updateCRLs(ctx.Config)
 
 	return nil
 }
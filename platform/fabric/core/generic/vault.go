@@ -17,12 +17,21 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 )
 
 type Badger struct {
 	Path string
 }
 
+// SQL are the options of the sql vault persistence
+type SQL struct {
+	// Driver is the sql driver, postgres or sqlite
+	Driver string
+	// DataSource identifies the database, the state of each channel is stored in its own tables
+	DataSource string
+}
+
 func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.Vault, *txidstore.TXIDStore, error) {
 	var persistence driver.VersionedPersistence
 	pType := config.VaultPersistenceType()
@@ -48,8 +57,18 @@ func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.V
 		if err != nil {
 			return nil, nil, err
 		}
+	case "sql":
+		opts := &SQL{}
+		err := config.VaultPersistenceOpts(opts)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed getting opts for vault")
+		}
+		persistence, err = db.OpenVersioned(opts.Driver, driver.DataSourceName(opts.DataSource, channel))
+		if err != nil {
+			return nil, nil, err
+		}
 	default:
-		return nil, nil, errors.Errorf("invalid persistence type, expected one of [file,memory], got [%s]", pType)
+		return nil, nil, errors.Errorf("invalid persistence type, expected one of [file,memory,sql], got [%s]", pType)
 	}
 
 	txidstore, err := txidstore.NewTXIDStore(db.Unversioned(persistence))
@@ -57,5 +76,9 @@ func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.V
 		return nil, nil, err
 	}
 
-	return vault.New(persistence, txidstore), txidstore, nil
+	v := vault.New(persistence, txidstore)
+	v.SetMetrics(vault.NewMetrics(operations.GetMetricsProvider(sp), config.Name(), channel))
+	operations.RegisterChecker(sp, "fabric.vault."+config.Name()+"."+channel, &db.VersionedHealthChecker{Persistence: persistence})
+
+	return v, txidstore, nil
 }
@@ -29,6 +29,7 @@ func newInspector() *Inspector {
 			metaWriteSet: metaWriteSet{
 				metawrites: namespaceKeyedMetaWrites{},
 			},
+			collections: collections{},
 		},
 	}
 }
@@ -57,6 +58,31 @@ func (i *Inspector) SetStateMetadata(namespace, key string, metadata map[string]
 	panic("programming error: the rwset inspector is read-only")
 }
 
+func (i *Inspector) SetPrivateState(namespace, collection, key string, value []byte) error {
+	panic("programming error: the rwset inspector is read-only")
+}
+
+func (i *Inspector) DeletePrivateState(namespace, collection, key string) error {
+	panic("programming error: the rwset inspector is read-only")
+}
+
+// GetPrivateState returns the value written for the given private key, nil if it is not known
+func (i *Inspector) GetPrivateState(namespace, collection, key string, opts ...driver.GetStateOpt) ([]byte, error) {
+	crws, in := i.rws.collections.get(namespace, collection)
+	if !in {
+		return nil, nil
+	}
+	w, in := crws.getWrite(key)
+	if !in || !w.known {
+		return nil, nil
+	}
+	return w.value, nil
+}
+
+func (i *Inspector) Collections(ns string) []string {
+	return i.rws.collections.names(ns)
+}
+
 func (i *Inspector) GetReadKeyAt(ns string, pos int) (string, error) {
 	key, in := i.rws.readSet.getAt(ns, pos)
 	if !in {
@@ -106,6 +132,9 @@ func (i *Inspector) Namespaces() []string {
 	for ns := range i.rws.writes {
 		mergedMaps[ns] = struct{}{}
 	}
+	for ns := range i.rws.collections {
+		mergedMaps[ns] = struct{}{}
+	}
 
 	namespaces := make([]string, 0, len(mergedMaps))
 	for ns := range mergedMaps {
@@ -119,6 +148,14 @@ func (i *Inspector) AppendRWSet(raw []byte, nss ...string) error {
 	panic("programming error: the rwset inspector is read-only")
 }
 
+func (i *Inspector) AppendPvtRWSet(raw []byte) error {
+	panic("programming error: the rwset inspector is read-only")
+}
+
+func (i *Inspector) PvtBytes() ([]byte, error) {
+	panic("programming error: unexpected call")
+}
+
 func (i *Inspector) Bytes() ([]byte, error) {
 	panic("programming error: unexpected call")
 }
@@ -16,12 +16,16 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/pkg/errors"
 )
 
+// QueryExecutor serves the reads of an Interceptor from a snapshot of the vault
 type QueryExecutor interface {
 	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
 	GetState(namespace, key string) ([]byte, uint64, uint64, error)
+	// GetCommittedState returns the value and version currently committed in the vault, bypassing the snapshot
+	GetCommittedState(namespace, key string) ([]byte, uint64, uint64, error)
 	Done()
 }
 
@@ -52,6 +56,7 @@ func newInterceptor(qe QueryExecutor, txidStore TXIDStoreReader, txid string) *I
 			metaWriteSet: metaWriteSet{
 				metawrites: namespaceKeyedMetaWrites{},
 			},
+			collections: collections{},
 		},
 	}
 }
@@ -65,9 +70,10 @@ func (i *Interceptor) IsValid() error {
 		return errors.Errorf("duplicate txid %s", i.txid)
 	}
 
+	// reads are checked against the latest commits, not against the snapshot
 	for ns, nsMap := range i.rws.reads {
 		for k, v := range nsMap {
-			_, b, t, err := i.qe.GetState(ns, k)
+			_, b, t, err := i.qe.GetCommittedState(ns, k)
 			if err != nil {
 				return err
 			}
@@ -78,6 +84,22 @@ func (i *Interceptor) IsValid() error {
 		}
 	}
 
+	// private reads are checked against the versions of the hashes
+	for ns, colls := range i.rws.collections {
+		for coll, crws := range colls {
+			for _, r := range crws.reads {
+				_, b, t, err := i.qe.GetCommittedState(hashedNamespace(ns, coll), hashedKey(r.keyHash))
+				if err != nil {
+					return err
+				}
+
+				if b != r.block || t != r.txnum {
+					return errors.Errorf("invalid private read: vault at version %s:%s:%x %d:%d, read-write set at version %d:%d", ns, coll, r.keyHash, b, t, r.block, r.txnum)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -89,6 +111,7 @@ func (i *Interceptor) Clear(ns string) error {
 	i.rws.readSet.clear(ns)
 	i.rws.writeSet.clear(ns)
 	i.rws.metaWriteSet.clear(ns)
+	i.rws.collections.clear(ns)
 
 	return nil
 }
@@ -154,6 +177,9 @@ func (i *Interceptor) Namespaces() []string {
 	for ns := range i.rws.writes {
 		mergedMaps[ns] = struct{}{}
 	}
+	for ns := range i.rws.collections {
+		mergedMaps[ns] = struct{}{}
+	}
 
 	namespaces := make([]string, 0, len(mergedMaps))
 	for ns := range mergedMaps {
@@ -284,6 +310,125 @@ func (i *Interceptor) GetState(namespace string, key string, opts ...driver.GetS
 	}
 }
 
+// Collections returns the private data collections of the passed namespace this rwset touches
+func (i *Interceptor) Collections(ns string) []string {
+	return i.rws.collections.names(ns)
+}
+
+// DeletePrivateState deletes the given key of the given collection of the given namespace
+func (i *Interceptor) DeletePrivateState(namespace, collection, key string) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+
+	return i.SetPrivateState(namespace, collection, key, nil)
+}
+
+// SetPrivateState sets the given value for the given key of the given collection of the given namespace.
+// Only the hashes of the key and the value appear in the rwset, the value is carried by the private rwset.
+func (i *Interceptor) SetPrivateState(namespace, collection, key string, value []byte) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+	logger.Debugf("SetPrivateState [%s,%s,%s,%s]", namespace, collection, key, hash.Hashable(value).String())
+
+	crws, err := i.rws.collections.getOrCreate(namespace, collection)
+	if err != nil {
+		return err
+	}
+	return crws.addWrite(key, value)
+}
+
+// GetPrivateState returns the value of the given key of the given collection of the given namespace.
+// Reads from the storage are recorded as hashed reads, the value is nil if this node does not have the private data.
+func (i *Interceptor) GetPrivateState(namespace, collection, key string, opts ...driver.GetStateOpt) ([]byte, error) {
+	if i.closed {
+		return nil, errors.New("this instance was closed")
+	}
+
+	if len(opts) > 1 {
+		return nil, errors.Errorf("a single getoption is supported, %d provided", len(opts))
+	}
+
+	opt := driver.FromStorage
+	if len(opts) == 1 {
+		opt = opts[0]
+	}
+
+	switch opt {
+	case driver.FromStorage:
+		val, _, _, err := i.qe.GetState(privateNamespace(namespace, collection), key)
+		if err != nil {
+			return nil, err
+		}
+		// the version is the one of the hash, the private data might be missing or stored later
+		_, block, txnum, err := i.qe.GetState(hashedNamespace(namespace, collection), hashedKey(util.ComputeStringHash(key)))
+		if err != nil {
+			return nil, err
+		}
+
+		crws, err := i.rws.collections.getOrCreate(namespace, collection)
+		if err != nil {
+			return nil, err
+		}
+		r, in := crws.getRead(key)
+		if in {
+			if r.block != block || r.txnum != txnum {
+				return nil, errors.Errorf("invalid private read [%s:%s:%s]: previous value returned at version %d:%d, current value at version %d:%d", namespace, collection, key, r.block, r.txnum, block, txnum)
+			}
+		} else {
+			crws.addRead(key, block, txnum)
+		}
+
+		return val, nil
+
+	case driver.FromIntermediate:
+		crws, in := i.rws.collections.get(namespace, collection)
+		if !in {
+			return nil, nil
+		}
+		w, in := crws.getWrite(key)
+		if !in {
+			return nil, nil
+		}
+		if !w.known {
+			return nil, errors.Errorf("value of private write [%s:%s:%s] not available", namespace, collection, key)
+		}
+		return w.value, nil
+
+	case driver.FromBoth:
+		val, err := i.GetPrivateState(namespace, collection, key, driver.FromIntermediate)
+		if err != nil || val != nil {
+			return val, err
+		}
+		if crws, in := i.rws.collections.get(namespace, collection); in {
+			if _, in := crws.getWrite(key); in {
+				return nil, nil
+			}
+		}
+
+		return i.GetPrivateState(namespace, collection, key, driver.FromStorage)
+
+	default:
+		return nil, errors.Errorf("invalid get option %+v", opts)
+	}
+}
+
+// AppendPvtRWSet fills in the private writes of this rwset from the passed marshalled private rwset.
+// The keys and values are checked against the hashes in the rwset.
+func (i *Interceptor) AppendPvtRWSet(raw []byte) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+
+	return i.rws.collections.appendPvtRWSet(raw)
+}
+
+// PvtBytes returns the marshalled private rwset carrying the private writes of this rwset, nil if there is none
+func (i *Interceptor) PvtBytes() ([]byte, error) {
+	return i.rws.collections.pvtBytes()
+}
+
 func (i *Interceptor) AppendRWSet(raw []byte, nss ...string) error {
 	if i.closed {
 		return errors.New("this instance was closed")
@@ -355,6 +500,10 @@ func (i *Interceptor) AppendRWSet(raw []byte, nss ...string) error {
 				return err
 			}
 		}
+
+		if err := i.rws.collections.appendHashed(nsrws); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -388,7 +537,10 @@ func (i *Interceptor) Bytes() ([]byte, error) {
 		return nil, err
 	}
 
-	return simRes.GetPubSimulationBytes()
+	if len(i.rws.collections) == 0 {
+		return simRes.GetPubSimulationBytes()
+	}
+	return i.rws.collections.addTo(simRes.PubSimulationResults)
 }
 
 func (i *Interceptor) Equals(other interface{}, nss ...string) error {
@@ -406,6 +558,9 @@ func (i *Interceptor) Equals(other interface{}, nss ...string) error {
 	if err := i.rws.metawrites.equals(o.rws.metawrites, nss...); err != nil {
 		return errors.Wrap(err, "meta writes do not match")
 	}
+	if err := i.rws.collections.equals(o.rws.collections, nss...); err != nil {
+		return errors.Wrap(err, "private data collections do not match")
+	}
 
 	return nil
 }
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	commitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "fabric",
+		Subsystem:    "vault",
+		Name:         "commits",
+		Help:         "Transactions committed to the vault.",
+		LabelNames:   []string{"network", "channel", "success"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}.%{success}",
+	}
+
+	commitDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:    "fabric",
+		Subsystem:    "vault",
+		Name:         "commit_duration",
+		Help:         "Time taken to commit a transaction to the vault, in seconds.",
+		LabelNames:   []string{"network", "channel"},
+		StatsdFormat: "%{#fqname}.%{network}.%{channel}",
+	}
+)
+
+// Metrics are the metrics of the commits of a vault
+type Metrics struct {
+	Commits        metrics.Counter
+	CommitDuration metrics.Histogram
+}
+
+// NewMetrics returns the metrics of the vault of the passed channel
+func NewMetrics(p metrics.Provider, network, channel string) *Metrics {
+	return &Metrics{
+		Commits:        p.NewCounter(commitsCounterOpts).With("network", network, "channel", channel),
+		CommitDuration: p.NewHistogram(commitDurationHistogramOpts).With("network", network, "channel", channel),
+	}
+}
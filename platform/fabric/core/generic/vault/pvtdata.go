@@ -0,0 +1,432 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/keys"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/pkg/errors"
+)
+
+// privateNamespace returns the namespace under which the vault stores the private data of the passed collection
+func privateNamespace(ns, collection string) string {
+	return ns + "$$p" + collection
+}
+
+// hashedNamespace returns the namespace under which the vault stores the hashes of the private data of the passed
+// collection. The hashes are stored by all the nodes, they give the versions of the private keys as Fabric sees them.
+func hashedNamespace(ns, collection string) string {
+	return ns + "$$h" + collection
+}
+
+// hashedKey returns the key under which the vault stores the hash of a private key
+func hashedKey(keyHash []byte) string {
+	return hex.EncodeToString(keyHash)
+}
+
+// hashedRead is a read of a private key, identified by its hash as the versions are those of the hashes
+type hashedRead struct {
+	keyHash []byte
+	block   uint64
+	txnum   uint64
+}
+
+// hashedWrite is a write of a private key.
+// Only the members of the collection know the key and the value, the others know their hashes.
+type hashedWrite struct {
+	key       string
+	keyHash   []byte
+	value     []byte
+	valueHash []byte
+	isDelete  bool
+	// known is true if the key and the value of the write are known
+	known bool
+}
+
+// collectionRWSet holds the reads and writes of a private data collection, indexed by key hash
+type collectionRWSet struct {
+	reads  map[string]*hashedRead
+	writes map[string]*hashedWrite
+	// pvtRWSetHash is the hash of the private rwset of the collection, as found in a received rwset
+	pvtRWSetHash []byte
+}
+
+func newCollectionRWSet() *collectionRWSet {
+	return &collectionRWSet{
+		reads:  map[string]*hashedRead{},
+		writes: map[string]*hashedWrite{},
+	}
+}
+
+func (c *collectionRWSet) getRead(key string) (*hashedRead, bool) {
+	r, in := c.reads[string(util.ComputeStringHash(key))]
+	return r, in
+}
+
+func (c *collectionRWSet) addRead(key string, block, txnum uint64) {
+	keyHash := util.ComputeStringHash(key)
+	c.reads[string(keyHash)] = &hashedRead{keyHash: keyHash, block: block, txnum: txnum}
+}
+
+func (c *collectionRWSet) getWrite(key string) (*hashedWrite, bool) {
+	w, in := c.writes[string(util.ComputeStringHash(key))]
+	return w, in
+}
+
+func (c *collectionRWSet) addWrite(key string, value []byte) error {
+	if err := keys.ValidateKey(key); err != nil {
+		return err
+	}
+
+	keyHash := util.ComputeStringHash(key)
+	w := &hashedWrite{key: key, keyHash: keyHash, isDelete: len(value) == 0, known: true}
+	if !w.isDelete {
+		w.value = append([]byte(nil), value...)
+		w.valueHash = util.ComputeHash(value)
+	}
+	c.writes[string(keyHash)] = w
+	// the private rwset changed, its hash has to be computed again
+	c.pvtRWSetHash = nil
+
+	return nil
+}
+
+// appendHashed adds the passed hashed reads and writes
+func (c *collectionRWSet) appendHashed(hashed *kvrwset.HashedRWSet, pvtRWSetHash []byte) error {
+	if hashed == nil {
+		return nil
+	}
+
+	for _, read := range hashed.HashedReads {
+		bnum := uint64(0)
+		txnum := uint64(0)
+		if read.Version != nil {
+			bnum = read.Version.BlockNum
+			txnum = read.Version.TxNum
+		}
+
+		r, in := c.reads[string(read.KeyHash)]
+		if in {
+			if r.block != bnum || r.txnum != txnum {
+				return errors.Errorf("invalid private read [%x]: previous value returned at version %d:%d, current value at version %d:%d", read.KeyHash, r.block, r.txnum, bnum, txnum)
+			}
+			continue
+		}
+		c.reads[string(read.KeyHash)] = &hashedRead{keyHash: read.KeyHash, block: bnum, txnum: txnum}
+	}
+
+	for _, write := range hashed.HashedWrites {
+		if _, in := c.writes[string(write.KeyHash)]; in {
+			return errors.Errorf("duplicate private write entry for key hash %x", write.KeyHash)
+		}
+		c.writes[string(write.KeyHash)] = &hashedWrite{keyHash: write.KeyHash, valueHash: write.ValueHash, isDelete: write.IsDelete}
+	}
+
+	if len(pvtRWSetHash) != 0 {
+		c.pvtRWSetHash = pvtRWSetHash
+	}
+
+	return nil
+}
+
+// setPvtRWSet fills in the keys and values of the writes from the passed marshalled private rwset,
+// after checking them against their hashes
+func (c *collectionRWSet) setPvtRWSet(raw []byte) error {
+	if len(c.pvtRWSetHash) != 0 && !bytes.Equal(util.ComputeHash(raw), c.pvtRWSetHash) {
+		return errors.New("private rwset does not match its hash")
+	}
+
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(raw, kvRWSet); err != nil {
+		return errors.Wrap(err, "failed unmarshalling private rwset")
+	}
+
+	for _, write := range kvRWSet.Writes {
+		w, in := c.getWrite(write.Key)
+		if !in {
+			return errors.Errorf("private write [%s] not found in the rwset", write.Key)
+		}
+		if w.isDelete != write.IsDelete || (!write.IsDelete && !bytes.Equal(w.valueHash, util.ComputeHash(write.Value))) {
+			return errors.Errorf("private write [%s] does not match its hash", write.Key)
+		}
+		w.key = write.Key
+		w.value = write.Value
+		w.known = true
+	}
+
+	return nil
+}
+
+// pvtRWSet returns the private rwset of the collection, with the writes sorted by key as Fabric does.
+// It returns false if some of the writes are not known.
+func (c *collectionRWSet) pvtRWSet() (*kvrwset.KVRWSet, bool) {
+	kvRWSet := &kvrwset.KVRWSet{}
+	for _, w := range c.writes {
+		if !w.known {
+			return nil, false
+		}
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: w.key, IsDelete: w.isDelete, Value: w.value})
+	}
+	sort.Slice(kvRWSet.Writes, func(i, j int) bool {
+		return kvRWSet.Writes[i].Key < kvRWSet.Writes[j].Key
+	})
+	return kvRWSet, true
+}
+
+// hashedRWSet returns the hashed rwset of the collection, sorted by key hash, and the hash of its private rwset
+func (c *collectionRWSet) hashedRWSet() (*kvrwset.HashedRWSet, []byte, error) {
+	hashed := &kvrwset.HashedRWSet{}
+	for _, r := range c.reads {
+		read := &kvrwset.KVReadHash{KeyHash: r.keyHash}
+		if r.block != 0 || r.txnum != 0 {
+			read.Version = &kvrwset.Version{BlockNum: r.block, TxNum: r.txnum}
+		}
+		hashed.HashedReads = append(hashed.HashedReads, read)
+	}
+	sort.Slice(hashed.HashedReads, func(i, j int) bool {
+		return bytes.Compare(hashed.HashedReads[i].KeyHash, hashed.HashedReads[j].KeyHash) < 0
+	})
+	for _, w := range c.writes {
+		hashed.HashedWrites = append(hashed.HashedWrites, &kvrwset.KVWriteHash{KeyHash: w.keyHash, IsDelete: w.isDelete, ValueHash: w.valueHash})
+	}
+	sort.Slice(hashed.HashedWrites, func(i, j int) bool {
+		return bytes.Compare(hashed.HashedWrites[i].KeyHash, hashed.HashedWrites[j].KeyHash) < 0
+	})
+
+	if len(c.pvtRWSetHash) != 0 || len(c.writes) == 0 {
+		return hashed, c.pvtRWSetHash, nil
+	}
+	kvRWSet, ok := c.pvtRWSet()
+	if !ok {
+		return nil, nil, errors.New("private rwset hash not available")
+	}
+	raw, err := proto.Marshal(kvRWSet)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling private rwset")
+	}
+	return hashed, util.ComputeHash(raw), nil
+}
+
+func (c *collectionRWSet) equals(o *collectionRWSet) error {
+	hashed, pvtHash, err := c.hashedRWSet()
+	if err != nil {
+		return err
+	}
+	oHashed, oPvtHash, err := o.hashedRWSet()
+	if err != nil {
+		return err
+	}
+	if !proto.Equal(hashed, oHashed) {
+		return errors.New("hashed rwsets do not match")
+	}
+	if !bytes.Equal(pvtHash, oPvtHash) {
+		return errors.Errorf("private rwset hashes do not match [%x]!=[%x]", pvtHash, oPvtHash)
+	}
+	return nil
+}
+
+// collections holds the rwsets of the private data collections, indexed by namespace and collection name
+type collections map[string]map[string]*collectionRWSet
+
+func (c collections) get(ns, collection string) (*collectionRWSet, bool) {
+	crws, in := c[ns][collection]
+	return crws, in
+}
+
+func (c collections) getOrCreate(ns, collection string) (*collectionRWSet, error) {
+	if err := keys.ValidateNs(ns); err != nil {
+		return nil, err
+	}
+	if len(collection) == 0 {
+		return nil, errors.Errorf("no collection specified for namespace [%s]", ns)
+	}
+
+	nsMap, in := c[ns]
+	if !in {
+		nsMap = map[string]*collectionRWSet{}
+		c[ns] = nsMap
+	}
+	crws, in := nsMap[collection]
+	if !in {
+		crws = newCollectionRWSet()
+		nsMap[collection] = crws
+	}
+	return crws, nil
+}
+
+func (c collections) clear(ns string) {
+	delete(c, ns)
+}
+
+// names returns the sorted names of the collections of the passed namespace
+func (c collections) names(ns string) []string {
+	names := make([]string, 0, len(c[ns]))
+	for name := range c[ns] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appendHashed adds the hashed rwsets of the collections of the passed namespace
+func (c collections) appendHashed(nsRWSet *rwsetutil.NsRwSet) error {
+	for _, coll := range nsRWSet.CollHashedRwSets {
+		crws, err := c.getOrCreate(nsRWSet.NameSpace, coll.CollectionName)
+		if err != nil {
+			return err
+		}
+		if err := crws.appendHashed(coll.HashedRwSet, coll.PvtRwSetHash); err != nil {
+			return errors.WithMessagef(err, "invalid rwset for collection [%s:%s]", nsRWSet.NameSpace, coll.CollectionName)
+		}
+	}
+	return nil
+}
+
+// appendPvtRWSet fills in the private writes from the passed marshalled rwset.TxPvtReadWriteSet
+func (c collections) appendPvtRWSet(raw []byte) error {
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(raw, txPvtRWSet); err != nil {
+		return errors.Wrap(err, "provided invalid private read-write set bytes, unmarshal failed")
+	}
+
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwset {
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			crws, in := c.get(nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			if !in {
+				return errors.Errorf("collection [%s:%s] not found in the rwset", nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			}
+			if err := crws.setPvtRWSet(collPvtRWSet.Rwset); err != nil {
+				return errors.WithMessagef(err, "invalid private rwset for collection [%s:%s]", nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			}
+		}
+	}
+	return nil
+}
+
+// pvtBytes returns the marshalled rwset.TxPvtReadWriteSet of the collections whose writes are all known,
+// nil if there is none
+func (c collections) pvtBytes() ([]byte, error) {
+	txPvtRWSet := &rwsetutil.TxPvtRwSet{}
+	for _, ns := range c.namespaces() {
+		nsPvtRWSet := &rwsetutil.NsPvtRwSet{NameSpace: ns}
+		for _, name := range c.names(ns) {
+			crws := c[ns][name]
+			if len(crws.writes) == 0 {
+				continue
+			}
+			kvRWSet, ok := crws.pvtRWSet()
+			if !ok {
+				continue
+			}
+			nsPvtRWSet.CollPvtRwSets = append(nsPvtRWSet.CollPvtRwSets, &rwsetutil.CollPvtRwSet{CollectionName: name, KvRwSet: kvRWSet})
+		}
+		if len(nsPvtRWSet.CollPvtRwSets) != 0 {
+			txPvtRWSet.NsPvtRwSet = append(txPvtRWSet.NsPvtRwSet, nsPvtRWSet)
+		}
+	}
+	if len(txPvtRWSet.NsPvtRwSet) == 0 {
+		return nil, nil
+	}
+	return txPvtRWSet.ToProtoBytes()
+}
+
+// addTo adds the hashed rwsets of the collections to the passed public rwset and returns it marshalled
+func (c collections) addTo(pub *rwset.TxReadWriteSet) ([]byte, error) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if pub != nil {
+		var err error
+		txRWSet, err = rwsetutil.TxRwSetFromProtoMsg(pub)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling public rwset")
+		}
+	}
+
+	nsRWSets := map[string]*rwsetutil.NsRwSet{}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		nsRWSets[nsRWSet.NameSpace] = nsRWSet
+	}
+	for _, ns := range c.namespaces() {
+		nsRWSet, in := nsRWSets[ns]
+		if !in {
+			nsRWSet = &rwsetutil.NsRwSet{NameSpace: ns, KvRwSet: &kvrwset.KVRWSet{}}
+			txRWSet.NsRwSets = append(txRWSet.NsRwSets, nsRWSet)
+		}
+		for _, name := range c.names(ns) {
+			hashed, pvtRWSetHash, err := c[ns][name].hashedRWSet()
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed building hashed rwset for collection [%s:%s]", ns, name)
+			}
+			nsRWSet.CollHashedRwSets = append(nsRWSet.CollHashedRwSets, &rwsetutil.CollHashedRwSet{
+				CollectionName: name,
+				HashedRwSet:    hashed,
+				PvtRwSetHash:   pvtRWSetHash,
+			})
+		}
+	}
+	sort.Slice(txRWSet.NsRwSets, func(i, j int) bool {
+		return txRWSet.NsRwSets[i].NameSpace < txRWSet.NsRwSets[j].NameSpace
+	})
+
+	return txRWSet.ToProtoBytes()
+}
+
+// namespaces returns the sorted namespaces having collections
+func (c collections) namespaces() []string {
+	namespaces := make([]string, 0, len(c))
+	for ns := range c {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (c collections) equals(o collections, nss ...string) error {
+	selected := func(ns string) bool {
+		if len(nss) == 0 {
+			return true
+		}
+		for _, ref := range nss {
+			if ns == ref {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range []struct{ a, b collections }{{c, o}, {o, c}} {
+		for ns, colls := range m.a {
+			if !selected(ns) {
+				continue
+			}
+			for name := range colls {
+				if _, in := m.b.get(ns, name); !in {
+					return errors.Errorf("collection [%s:%s] not found", ns, name)
+				}
+			}
+		}
+	}
+
+	for ns, colls := range c {
+		if !selected(ns) {
+			continue
+		}
+		for name, crws := range colls {
+			if err := crws.equals(o[ns][name]); err != nil {
+				return errors.WithMessagef(err, "collection [%s:%s] does not match", ns, name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/vault/txidstore"
+	fdriver "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
+)
+
+func newMemVault(t *testing.T) *Vault {
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	return New(ddb, tidstore)
+}
+
+func getPrivateState(t *testing.T, vault *Vault, ns, coll, key string) []byte {
+	qe, err := vault.NewQueryExecutor()
+	assert.NoError(t, err)
+	defer qe.Done()
+	v, err := qe.GetPrivateState(ns, coll, key)
+	assert.NoError(t, err)
+	return v
+}
+
+func TestPrivateData(t *testing.T) {
+	ns := "namespace"
+	coll := "coll"
+	txid := "tx1"
+
+	member := newMemVault(t)
+	other := newMemVault(t)
+
+	// the member writes public and private data
+	rws, err := member.NewRWSet(txid)
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetState(ns, "pub", []byte("public")))
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k1", []byte("secret")))
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k2", []byte("another secret")))
+	assert.NoError(t, rws.DeletePrivateState(ns, coll, "k2"))
+	v, err := rws.GetPrivateState(ns, coll, "k1", fdriver.FromIntermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), v)
+	v, err = rws.GetPrivateState(ns, coll, "k3", fdriver.FromBoth)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.Equal(t, []string{coll}, rws.Collections(ns))
+	assert.Equal(t, []string{ns}, rws.Namespaces())
+	_, err = rws.GetPrivateState(ns, "", "k1")
+	assert.Error(t, err)
+	rws.Done()
+
+	results, err := rws.Bytes()
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(results, []byte("secret")))
+	pvt, err := rws.PvtBytes()
+	assert.NoError(t, err)
+	assert.True(t, bytes.Contains(pvt, []byte("secret")))
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	assert.NoError(t, txRWSet.FromProtoBytes(results))
+	assert.Len(t, txRWSet.NsRwSets, 1)
+	assert.Len(t, txRWSet.NsRwSets[0].CollHashedRwSets, 1)
+	hashed := txRWSet.NsRwSets[0].CollHashedRwSets[0]
+	assert.Equal(t, coll, hashed.CollectionName)
+	assert.NotEmpty(t, hashed.PvtRwSetHash)
+	assert.Len(t, hashed.HashedRwSet.HashedReads, 1)
+	assert.Len(t, hashed.HashedRwSet.HashedWrites, 2)
+
+	// a node that only knows the hashes reproduces the same rwset
+	hashesOnly, err := other.GetRWSet(txid, results)
+	assert.NoError(t, err)
+	raw, err := hashesOnly.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, results, raw)
+	raw, err = hashesOnly.PvtBytes()
+	assert.NoError(t, err)
+	assert.Nil(t, raw)
+	_, err = hashesOnly.GetPrivateState(ns, coll, "k1", fdriver.FromIntermediate)
+	assert.Error(t, err)
+	assert.NoError(t, hashesOnly.Equals(rws))
+
+	// private data that does not match the hashes is rejected
+	tampered := &rwsetutil.TxPvtRwSet{NsPvtRwSet: []*rwsetutil.NsPvtRwSet{{
+		NameSpace: ns,
+		CollPvtRwSets: []*rwsetutil.CollPvtRwSet{{
+			CollectionName: coll,
+			KvRwSet:        &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "k1", Value: []byte("forged")}}},
+		}},
+	}}}
+	tamperedRaw, err := tampered.ToProtoBytes()
+	assert.NoError(t, err)
+	assert.Error(t, hashesOnly.AppendPvtRWSet(tamperedRaw))
+	hashesOnly.Done()
+
+	// a member receiving the private data reproduces both rwsets
+	other2 := newMemVault(t)
+	received, err := other2.GetRWSet(txid, results)
+	assert.NoError(t, err)
+	assert.NoError(t, received.AppendPvtRWSet(pvt))
+	raw, err = received.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, results, raw)
+	raw, err = received.PvtBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, pvt, raw)
+	v, err = received.GetPrivateState(ns, coll, "k1", fdriver.FromIntermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), v)
+	received.Done()
+
+	// on commit, the member stores its private writes, the other node the hashes only
+	assert.NoError(t, member.CommitTX(txid, 2, 3))
+	assert.Equal(t, []byte("secret"), getPrivateState(t, member, ns, coll, "k1"))
+	assert.Nil(t, getPrivateState(t, member, ns, coll, "k2"))
+
+	assert.NoError(t, other.CommitTX(txid, 2, 3))
+	assert.Nil(t, getPrivateState(t, other, ns, coll, "k1"))
+
+	// the versions of the private reads come from the hashes, also without the private data
+	rws, err = other.NewRWSet("tx2")
+	assert.NoError(t, err)
+	v, err = rws.GetPrivateState(ns, coll, "k1")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, rws.IsValid())
+	rws.Done()
+	raw, err = rws.Bytes()
+	assert.NoError(t, err)
+	txRWSet = &rwsetutil.TxRwSet{}
+	assert.NoError(t, txRWSet.FromProtoBytes(raw))
+	read := txRWSet.NsRwSets[0].CollHashedRwSets[0].HashedRwSet.HashedReads[0]
+	assert.Equal(t, &kvrwset.Version{BlockNum: 2, TxNum: 3}, read.Version)
+
+	// the private data fetched later is stored, once checked against the hashes
+	assert.Error(t, other.CommitPvtData("unknown", 2, 3, results, pvt))
+	assert.Error(t, other.CommitPvtData(txid, 2, 3, results, tamperedRaw))
+	assert.NoError(t, other.CommitPvtData(txid, 2, 3, results, pvt))
+	assert.Equal(t, []byte("secret"), getPrivateState(t, other, ns, coll, "k1"))
+
+	// the private data of an older transaction does not overwrite newer values
+	rws, err = other.NewRWSet("tx3")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k1", []byte("newer secret")))
+	rws.Done()
+	assert.NoError(t, other.CommitTX("tx3", 4, 0))
+	assert.NoError(t, other.CommitPvtData(txid, 2, 3, results, pvt))
+	assert.Equal(t, []byte("newer secret"), getPrivateState(t, other, ns, coll, "k1"))
+
+	// a private read is invalidated by a later write of the key
+	assert.EqualError(t, rwsIsValid(t, other, raw), "invalid private read: vault at version namespace:coll:"+hashedKey(util.ComputeStringHash("k1"))+" 4:0, read-write set at version 2:3")
+}
+
+func rwsIsValid(t *testing.T, vault *Vault, results []byte) error {
+	rws, err := vault.GetRWSet("check", results)
+	assert.NoError(t, err)
+	defer rws.Done()
+	return rws.IsValid()
+}
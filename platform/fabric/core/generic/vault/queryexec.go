@@ -13,46 +13,84 @@ import (
 
 // this file contains all structs that perform DB access. They
 // differ in terms of the results that they return. They are both
-// created with the assumption that a read lock on the vault is held.
-// The lock is released when Done is called.
+// created with a snapshot of the store that is released when
+// Done is called.
 
 type directQueryExecutor struct {
-	vault *Vault
+	vault    *Vault
+	snapshot driver.VersionedSnapshot
 }
 
 func (q *directQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
 	logger.Debugf("Get State [%s,%s]", namespace, key)
-	v, _, _, err := q.vault.store.GetState(namespace, key)
+	v, _, _, err := q.snapshot.GetState(namespace, key)
 	logger.Debugf("Got State [%s,%s] -> [%v]", namespace, key, hash.Hashable(v).String())
 	return v, err
 }
 
+func (q *directQueryExecutor) GetPrivateState(namespace, collection, key string) ([]byte, error) {
+	return q.GetState(privateNamespace(namespace, collection), key)
+}
+
 func (q *directQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
-	return q.vault.store.GetStateRangeScanIterator(namespace, startKey, endKey)
+	return q.snapshot.GetStateRangeScanIterator(namespace, startKey, endKey)
+}
+
+func (q *directQueryExecutor) GetStateByQuery(namespace string, query string) (driver.VersionedResultsIterator, error) {
+	return q.snapshot.GetStateByQuery(namespace, query)
 }
 
 func (q *directQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
-	return q.vault.store.GetStateMetadata(namespace, key)
+	return q.snapshot.GetStateMetadata(namespace, key)
 }
 
 func (q *directQueryExecutor) Done() {
 	q.vault.counter.Dec()
-	q.vault.storeLock.RUnlock()
+	q.snapshot.Close()
 }
 
 type interceptorQueryExecutor struct {
-	*Vault
+	vault    *Vault
+	snapshot driver.VersionedSnapshot
 }
 
 func (i *interceptorQueryExecutor) Done() {
-	i.counter.Dec()
-	i.storeLock.RUnlock()
+	i.vault.counter.Dec()
+	i.snapshot.Close()
 }
 
 func (i *interceptorQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
-	return i.store.GetStateMetadata(namespace, key)
+	return i.snapshot.GetStateMetadata(namespace, key)
 }
 
 func (i *interceptorQueryExecutor) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
-	return i.store.GetState(namespace, key)
+	return i.snapshot.GetState(namespace, key)
 }
+
+func (i *interceptorQueryExecutor) GetCommittedState(namespace, key string) ([]byte, uint64, uint64, error) {
+	return i.vault.store.GetState(namespace, key)
+}
+
+// liveSnapshot is used for the stores that do not support snapshots.
+// It reads directly from the store, therefore commits are visible as soon as they happen.
+type liveSnapshot struct {
+	store driver.VersionedPersistence
+}
+
+func (s *liveSnapshot) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
+	return s.store.GetState(namespace, key)
+}
+
+func (s *liveSnapshot) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	return s.store.GetStateMetadata(namespace, key)
+}
+
+func (s *liveSnapshot) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	return s.store.GetStateRangeScanIterator(namespace, startKey, endKey)
+}
+
+func (s *liveSnapshot) GetStateByQuery(namespace string, query string) (driver.VersionedResultsIterator, error) {
+	return s.store.GetStateByQuery(namespace, query)
+}
+
+func (s *liveSnapshot) Close() {}
@@ -22,6 +22,7 @@ type readWriteSet struct {
 	readSet
 	writeSet
 	metaWriteSet
+	collections
 }
 
 func (rws *readWriteSet) populate(rwsetBytes []byte, txid string) error {
@@ -65,6 +66,10 @@ func (rws *readWriteSet) populate(rwsetBytes []byte, txid string) error {
 				return err
 			}
 		}
+
+		if err := rws.collections.appendHashed(nsrws); err != nil {
+			return err
+		}
 	}
 
 	return nil
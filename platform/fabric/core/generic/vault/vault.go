@@ -9,7 +9,9 @@ package vault
 import (
 	"bytes"
 	"encoding/json"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/atomic"
@@ -39,17 +41,18 @@ type Vault struct {
 	interceptors     map[string]*Interceptor
 	counter          atomic.Int32
 
-	// the vault handles access concurrency to the store using storeLock.
+	// readers never block commits, and commits never block readers.
 	// In particular:
-	// * when a directQueryExecutor is returned, it holds a read-lock;
-	//   when Done is called on it, the lock is released.
-	// * when an interceptor is returned (using NewRWSet (in case the
-	//   transaction context is generated from nothing) or GetRWSet
+	// * when a directQueryExecutor or an interceptor is returned (using NewRWSet
+	//   (in case the transaction context is generated from nothing) or GetRWSet
 	//   (in case the transaction context is received from another node)),
-	//   it holds a read-lock; when Done is called on it, the lock is released.
-	// * an exclusive lock is held when Commit is called.
-	store     driver.VersionedPersistence
-	storeLock sync.RWMutex
+	//   it reads from a snapshot of the store taken at creation time, if the
+	//   store supports snapshots; when Done is called on it, the snapshot is released.
+	// * commitLock serializes the updates to the store.
+	store      driver.VersionedPersistence
+	commitLock sync.Mutex
+
+	metrics *Metrics
 }
 
 // New returns a new instance of Vault
@@ -61,17 +64,40 @@ func New(store driver.VersionedPersistence, txIDStore TXIDStore) *Vault {
 	}
 }
 
+// SetMetrics sets the metrics the commits are reported to
+func (db *Vault) SetMetrics(m *Metrics) {
+	db.metrics = m
+}
+
 func (db *Vault) NewQueryExecutor() (fdriver.QueryExecutor, error) {
-	logger.Debugf("getting lock for query executor")
+	logger.Debugf("getting snapshot for query executor")
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return nil, err
+	}
 	db.counter.Inc()
-	db.storeLock.RLock()
 
 	logger.Debugf("return new query executor")
 	return &directQueryExecutor{
-		vault: db,
+		vault:    db,
+		snapshot: snapshot,
 	}, nil
 }
 
+// newSnapshot returns a snapshot of the store, if supported.
+// Otherwise, it returns a reader that accesses the store directly and sees the commits as they happen.
+func (db *Vault) newSnapshot() (driver.VersionedSnapshot, error) {
+	snapshotter, ok := db.store.(driver.Snapshotter)
+	if !ok {
+		return &liveSnapshot{store: db.store}, nil
+	}
+	snapshot, err := snapshotter.NewSnapshot()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting snapshot of the store")
+	}
+	return snapshot, nil
+}
+
 func (db *Vault) unmapInterceptor(txid string) (*Interceptor, error) {
 	db.interceptorsLock.Lock()
 	defer db.interceptorsLock.Unlock()
@@ -117,6 +143,9 @@ func (db *Vault) DiscardTx(txid string) error {
 		return err
 	}
 
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
 	err = db.store.BeginUpdate()
 	if err != nil {
 		return errors.WithMessagef(err, "begin update for txid '%s' failed", txid)
@@ -135,7 +164,15 @@ func (db *Vault) DiscardTx(txid string) error {
 	return nil
 }
 
-func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
+func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) (err error) {
+	if db.metrics != nil {
+		start := time.Now()
+		defer func() {
+			db.metrics.Commits.With("success", strconv.FormatBool(err == nil)).Add(1)
+			db.metrics.CommitDuration.Observe(time.Since(start).Seconds())
+		}()
+	}
+
 	logger.Debugf("unmapInterceptor [%s]", txid)
 	i, err := db.unmapInterceptor(txid)
 	if err != nil {
@@ -143,8 +180,8 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 	}
 
 	logger.Debugf("get lock [%s][%d]", txid, db.counter.Load())
-	db.storeLock.Lock()
-	defer db.storeLock.Unlock()
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
 
 	m, _ := json.Marshal(i.rws)
 	logger.Debugf("committing \n[%s]\n", string(m))
@@ -191,6 +228,15 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 		}
 	}
 
+	logger.Debugf("parse private writes [%s]", txid)
+	if err := db.storePrivateWrites(i.rws.collections, block, indexInBloc); err != nil {
+		if err1 := db.store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+
+		return err
+	}
+
 	logger.Debugf("set state to valid [%s]", txid)
 	err = db.txidStore.Set(txid, fdriver.Valid)
 	if err != nil {
@@ -209,45 +255,183 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 	return nil
 }
 
+// CommitPvtData stores the private writes carried by the passed private rwset of a committed transaction.
+// This is used when the private data of a transaction is received after the transaction has been committed.
+// The private rwset is checked against the hashes found in the passed rwset, the transaction results;
+// the keys written by later transactions are left untouched.
+func (db *Vault) CommitPvtData(txid string, block uint64, indexInBloc int, results, pvtRWSet []byte) error {
+	code, err := db.txidStore.Get(txid)
+	if err != nil {
+		return err
+	}
+	if code != fdriver.Valid {
+		return errors.Errorf("transaction [%s] is not valid, cannot store its private data", txid)
+	}
+
+	rws := newInspector().rws
+	if err := rws.populate(results, txid); err != nil {
+		return err
+	}
+	if err := rws.collections.appendPvtRWSet(pvtRWSet); err != nil {
+		return errors.WithMessagef(err, "invalid private data for [%s]", txid)
+	}
+
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	if err := db.store.BeginUpdate(); err != nil {
+		return errors.WithMessagef(err, "begin update for txid '%s' failed", txid)
+	}
+	if err := db.storePvtData(rws.collections, block, indexInBloc); err != nil {
+		if err1 := db.store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+
+		return err
+	}
+	if err := db.store.Commit(); err != nil {
+		return errors.WithMessagef(err, "committing private data for txid '%s' failed", txid)
+	}
+
+	return nil
+}
+
+// storePrivateWrites stores the hashes of the private writes of the passed collections, and the values of the known ones
+func (db *Vault) storePrivateWrites(colls collections, block uint64, indexInBloc int) error {
+	for ns, nsMap := range colls {
+		for coll, crws := range nsMap {
+			hns, pns := hashedNamespace(ns, coll), privateNamespace(ns, coll)
+			for _, w := range crws.writes {
+				logger.Debugf("store private write [%s,%s,%x,%v]", ns, coll, w.keyHash, w.known)
+				var err error
+				switch {
+				case w.isDelete:
+					err = db.store.DeleteState(hns, hashedKey(w.keyHash))
+					if err == nil && w.known {
+						err = db.store.DeleteState(pns, w.key)
+					}
+				default:
+					err = db.store.SetState(hns, hashedKey(w.keyHash), w.valueHash, block, uint64(indexInBloc))
+					// the value is not known if we are not a member of the collection, or the private data is missing
+					if err == nil && w.known {
+						err = db.store.SetState(pns, w.key, w.value, block, uint64(indexInBloc))
+					}
+				}
+				if err != nil {
+					return errors.Errorf("failed to commit private operation on %s:%s:%x at height %d:%d", ns, coll, w.keyHash, block, indexInBloc)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// storePvtData stores the values of the known private writes of the passed collections,
+// for the keys whose hashes are still at the passed version
+func (db *Vault) storePvtData(colls collections, block uint64, indexInBloc int) error {
+	for ns, nsMap := range colls {
+		for coll, crws := range nsMap {
+			hns, pns := hashedNamespace(ns, coll), privateNamespace(ns, coll)
+			for _, w := range crws.writes {
+				if !w.known || w.isDelete {
+					continue
+				}
+
+				_, b, t, err := db.store.GetState(hns, hashedKey(w.keyHash))
+				if err != nil {
+					return errors.WithMessagef(err, "failed getting version of %s:%s:%s", ns, coll, w.key)
+				}
+				if b != block || t != uint64(indexInBloc) {
+					logger.Debugf("skip private write [%s,%s,%s], hash at version %d:%d", ns, coll, w.key, b, t)
+					continue
+				}
+
+				logger.Debugf("store private write [%s,%s,%s,%v]", ns, coll, w.key, hash.Hashable(w.value).String())
+				if err := db.store.SetState(pns, w.key, w.value, block, uint64(indexInBloc)); err != nil {
+					return errors.Errorf("failed to commit private operation on %s:%s:%s at height %d:%d", ns, coll, w.key, block, indexInBloc)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *Vault) newInterceptorQueryExecutor() (*interceptorQueryExecutor, error) {
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	db.counter.Inc()
+
+	return &interceptorQueryExecutor{vault: db, snapshot: snapshot}, nil
+}
+
+// CreateIndex declares the passed index on the passed namespace of the store
+func (db *Vault) CreateIndex(namespace string, index *driver.Index) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	if err := db.store.BeginUpdate(); err != nil {
+		return errors.WithMessagef(err, "begin update for index [%s:%s] failed", namespace, index.Name)
+	}
+	if err := db.store.CreateIndex(namespace, index); err != nil {
+		if err1 := db.store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+		return errors.WithMessagef(err, "failed creating index [%s:%s]", namespace, index.Name)
+	}
+	if err := db.store.Commit(); err != nil {
+		return errors.WithMessagef(err, "committing index [%s:%s] failed", namespace, index.Name)
+	}
+	return nil
+}
+
 func (db *Vault) NewRWSet(txid string) (*Interceptor, error) {
 	logger.Debugf("NewRWSet[%s][%d]", txid, db.counter.Load())
-	i := newInterceptor(&interceptorQueryExecutor{db}, db.txidStore, txid)
+	qe, err := db.newInterceptorQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	i := newInterceptor(qe, db.txidStore, txid)
 
 	db.interceptorsLock.Lock()
 	if _, in := db.interceptors[txid]; in {
 		db.interceptorsLock.Unlock()
+		i.Done()
 		return nil, errors.Errorf("duplicate read-write set for txid %s", txid)
 	}
 	db.interceptors[txid] = i
 	db.interceptorsLock.Unlock()
 
-	db.counter.Inc()
-	db.storeLock.RLock()
-
 	return i, nil
 }
 
 func (db *Vault) GetRWSet(txid string, rwsetBytes []byte) (*Interceptor, error) {
 	logger.Debugf("GetRWSet[%s][%d]", txid, db.counter.Load())
-	i := newInterceptor(&interceptorQueryExecutor{db}, db.txidStore, txid)
+	qe, err := db.newInterceptorQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	i := newInterceptor(qe, db.txidStore, txid)
 
 	if err := i.rws.populate(rwsetBytes, txid); err != nil {
+		i.Done()
 		return nil, err
 	}
 
 	db.interceptorsLock.Lock()
-	if i, in := db.interceptors[txid]; in {
-		if !i.closed {
+	if prev, in := db.interceptors[txid]; in {
+		if !prev.closed {
 			db.interceptorsLock.Unlock()
+			i.Done()
 			return nil, errors.Errorf("programming error: previous read-write set for %s has not been closed", txid)
 		}
 	}
 	db.interceptors[txid] = i
 	db.interceptorsLock.Unlock()
 
-	db.counter.Inc()
-	db.storeLock.RLock()
-
 	return i, nil
 }
 
@@ -277,10 +461,6 @@ func (db *Vault) Match(txid string, rwsRaw []byte) error {
 		return errors.Errorf("attempted to retrieve read-write set for %s when done has not been called", txid)
 	}
 
-	logger.Debugf("get lock [%s][%d]", txid, db.counter.Load())
-	db.storeLock.Lock()
-	defer db.storeLock.Unlock()
-
 	rwsRaw2, err := i.Bytes()
 	if err != nil {
 		return err
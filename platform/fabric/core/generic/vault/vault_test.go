@@ -11,7 +11,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
@@ -734,11 +736,18 @@ func TestInterceptorConcurrency(t *testing.T) {
 	err = ddb.Commit()
 	assert.NoError(t, err)
 
-	_, _, err = rws.GetReadAt(ns, 0)
-	assert.EqualError(t, err, "invalid read [namespace:key1]: previous value returned at version 0:0, current value at version 35:1")
+	// the interceptor keeps reading from its snapshot
+	_, v, err = rws.GetReadAt(ns, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
 
-	_, err = rws.GetState(ns, k)
-	assert.EqualError(t, err, "invalid read [namespace:key1]: previous value returned at version 0:0, current value at version 35:1")
+	v, err = rws.GetState(ns, k)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	// while the validation is done against the latest commits
+	err = rws.IsValid()
+	assert.EqualError(t, err, "invalid read: vault at version namespace:key1 35:1, read-write set at version 0:0")
 
 	mv, err := rws.GetStateMetadata(ns, mk)
 	assert.NoError(t, err)
@@ -751,8 +760,61 @@ func TestInterceptorConcurrency(t *testing.T) {
 	err = ddb.Commit()
 	assert.NoError(t, err)
 
-	_, err = rws.GetStateMetadata(ns, mk)
-	assert.EqualError(t, err, "invalid metadata read: previous value returned at version 0:0, current value at version 36:2")
+	mv, err = rws.GetStateMetadata(ns, mk)
+	assert.NoError(t, err)
+	assert.Nil(t, mv)
+	rws.Done()
+
+	// a new read-write set sees the latest commits
+	rws, err = vault1.NewRWSet("txid2")
+	assert.NoError(t, err)
+	v, err = rws.GetState(ns, k)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+	mv, err = rws.GetStateMetadata(ns, mk)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"k": []byte("v")}, mv)
+	rws.Done()
+}
+
+func TestCommitWithOpenReaders(t *testing.T) {
+	ns := "namespace"
+	k := "key1"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault1 := New(ddb, tidstore)
+
+	// readers that are never closed must not block commits
+	qe, err := vault1.NewQueryExecutor()
+	assert.NoError(t, err)
+	open, err := vault1.NewRWSet("open")
+	assert.NoError(t, err)
+
+	rws, err := vault1.NewRWSet("txid")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetState(ns, k, []byte("val")))
+	rws.Done()
+	assert.NoError(t, vault1.CommitTX("txid", 35, 1))
+
+	v, err := qe.GetState(ns, k)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	v, err = open.GetState(ns, k)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	qe2, err := vault1.NewQueryExecutor()
+	assert.NoError(t, err)
+	v, err = qe2.GetState(ns, k)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+
+	qe.Done()
+	qe2.Done()
+	open.Done()
 }
 
 func TestQueryExecutor(t *testing.T) {
@@ -933,6 +995,68 @@ func TestShardLikeCommit(t *testing.T) {
 	assert.Len(t, vault.interceptors, 0)
 }
 
+// BenchmarkCommitWithConcurrentReaders measures the commit latency while readers keep query executors open.
+// Readers work on snapshots, therefore the latency should not depend on the number of readers.
+func BenchmarkCommitWithConcurrentReaders(b *testing.B) {
+	for _, readers := range []int{0, 8, 64} {
+		b.Run(fmt.Sprintf("readers-%d", readers), func(b *testing.B) {
+			benchmarkCommit(b, readers)
+		})
+	}
+}
+
+func benchmarkCommit(b *testing.B, readers int) {
+	ns := "namespace"
+	k := "key1"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(b, err)
+	tidDB, err := db.OpenVersioned("memory", "")
+	assert.NoError(b, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(tidDB))
+	assert.NoError(b, err)
+	vault := New(ddb, tidstore)
+
+	// each reader holds its query executor for a while, as a long-running view would do
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				qe, err := vault.NewQueryExecutor()
+				if err != nil {
+					b.Error(err)
+					return
+				}
+				_, _ = qe.GetState(ns, k)
+				time.Sleep(time.Millisecond)
+				qe.Done()
+			}
+		}()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		txid := fmt.Sprintf("txid-%d", i)
+		rws, err := vault.NewRWSet(txid)
+		assert.NoError(b, err)
+		assert.NoError(b, rws.SetState(ns, k, []byte(txid)))
+		rws.Done()
+		assert.NoError(b, vault.CommitTX(txid, uint64(i), 0))
+	}
+	b.StopTimer()
+
+	close(stop)
+	wg.Wait()
+}
+
 func TestMain(m *testing.M) {
 	var err error
 	tempDir, err = ioutil.TempDir("", "vault-test")
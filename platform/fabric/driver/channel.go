@@ -9,6 +9,7 @@ package driver
 // Channel gives access to Fabric channel related information
 type Channel interface {
 	Committer
+	CommitListeners
 	Vault
 
 	Ledger
@@ -17,6 +18,7 @@ type Channel interface {
 	ChannelMembership
 	TXIDStore
 	ChaincodeManager
+	ChaincodeEvents
 
 	// Name returns the name of the channel this instance is bound to
 	Name() string
@@ -44,3 +44,63 @@ type Committer interface {
 	// CommitConfig commits the passed configuration envelope.
 	CommitConfig(blockNumber uint64, envelope []byte) error
 }
+
+// CommittedTransaction describes a transaction of a block processed by the committer
+type CommittedTransaction struct {
+	TxID string
+	// Type is the Fabric header type of the transaction
+	Type int32
+	// BlockNumber is the number of the block containing the transaction
+	BlockNumber uint64
+	// IndexInBlock is the position of the transaction in its block
+	IndexInBlock int
+	// ValidationCode is the code Fabric assigned to the transaction when validating it
+	ValidationCode int32
+	// Envelope is the marshalled envelope of the transaction
+	Envelope []byte
+	// Results is the marshalled rwset of the transaction, empty for config transactions
+	Results []byte
+	// Namespaces are the namespaces the rwset of the transaction touches
+	Namespaces []string
+}
+
+// CommittedBlock describes a block processed by the committer
+type CommittedBlock struct {
+	Number       uint64
+	Transactions []*CommittedTransaction
+	// Config is true if the block carries a configuration transaction
+	Config bool
+}
+
+// BlockListener is notified of the blocks processed by the committer
+type BlockListener interface {
+	OnBlock(block *CommittedBlock)
+}
+
+// TransactionListener is notified of the transactions processed by the committer
+type TransactionListener interface {
+	OnTransaction(tx *CommittedTransaction)
+}
+
+// ListenerRegistration is returned when a listener is added, it removes the listener
+type ListenerRegistration interface {
+	Remove()
+	// Err returns the error that made the committer drop the listener, nil if the listener has not been dropped
+	Err() error
+}
+
+// CommitListeners lets applications observe the blocks and transactions processed by the committer.
+// Each listener is called from a goroutine of its own, in commit order. A slow listener does not block the commit
+// of the following blocks, the notifications queue up until the listener catches up.
+// A listener with too many notifications queued up is dropped, its registration reports the error.
+type CommitListeners interface {
+	// AddBlockListener registers a listener of all the blocks
+	AddBlockListener(listener BlockListener) ListenerRegistration
+
+	// AddConfigListener registers a listener of the blocks carrying a configuration transaction
+	AddConfigListener(listener BlockListener) ListenerRegistration
+
+	// AddTransactionListener registers a listener of the transactions whose rwset touches any of the passed namespaces.
+	// If no namespace is passed, the listener is notified of all the transactions.
+	AddTransactionListener(listener TransactionListener, namespaces ...string) ListenerRegistration
+}
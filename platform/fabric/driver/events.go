@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package driver
+
+// ChaincodeEvent is an event set by a chaincode with SetEvent in a valid transaction
+type ChaincodeEvent struct {
+	// BlockNumber is the number of the block containing the transaction
+	BlockNumber uint64
+	// TxID is the id of the transaction that set the event
+	TxID string
+	// ChaincodeName is the name of the chaincode that set the event
+	ChaincodeName string
+	// EventName is the name the chaincode gave to the event
+	EventName string
+	// Payload is the payload of the event
+	Payload []byte
+}
+
+// ChaincodeEventsRequest selects the chaincode events a subscription delivers
+type ChaincodeEventsRequest struct {
+	// ChaincodeName is the name of the chaincode whose events are delivered
+	ChaincodeName string
+	// EventNames are the names of the events delivered, all the events of the chaincode if empty
+	EventNames []string
+	// StartBlock is the number of the first block whose events are delivered, unless FromNewest is set
+	StartBlock uint64
+	// FromNewest makes the subscription deliver only the events of the blocks committed after the subscription
+	FromNewest bool
+}
+
+// ChaincodeEventStream delivers the events selected by a subscription
+type ChaincodeEventStream interface {
+	// Events returns the channel the events are delivered on, in the order they have been committed.
+	// The channel is closed when the stream is closed.
+	Events() <-chan *ChaincodeEvent
+
+	// Err returns the error that terminated the stream, nil if the stream has been closed by Close.
+	// It is meaningful once the events channel is closed.
+	Err() error
+
+	// Close terminates the subscription
+	Close()
+}
+
+// ChaincodeEvents gives access to the events set by the chaincodes of a channel.
+// The events are taken from the blocks the committer receives, no additional connection is opened to the peers.
+type ChaincodeEvents interface {
+	// SubscribeChaincodeEvents returns a stream of the chaincode events selected by the passed request.
+	// Blocks committed before the subscription are fetched from the ledger.
+	SubscribeChaincodeEvents(request *ChaincodeEventsRequest) (ChaincodeEventStream, error)
+}
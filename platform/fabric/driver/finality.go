@@ -6,14 +6,44 @@ SPDX-License-Identifier: Apache-2.0
 
 package driver
 
-import "github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
 
 type Finality interface {
 	// IsFinal takes in input a transaction id and waits for its confirmation.
 	IsFinal(txID string) error
 
 	// IsFinalForParties takes in input a transaction id and an array of identities.
-	// The identities are contacted to gather information about the finality of the
-	// passed transaction
+	// The identities are contacted in parallel to gather information about the finality of the
+	// passed transaction. If some of them do not confirm the finality, a *PartiesFinalityError is returned.
 	IsFinalForParties(txID string, parties ...view.Identity) error
 }
+
+// PartyFailure describes why a party did not confirm the finality of a transaction
+type PartyFailure struct {
+	Party view.Identity
+	Err   error
+}
+
+// PartiesFinalityError is returned by IsFinalForParties when some of the parties did not confirm
+// the finality of the transaction
+type PartiesFinalityError struct {
+	TxID string
+	// Confirmed are the parties that confirmed the finality of the transaction
+	Confirmed []view.Identity
+	// Failures are the parties that did not confirm the finality of the transaction
+	Failures []PartyFailure
+}
+
+func (p *PartiesFinalityError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "transaction [%s] not confirmed final by [%d] of [%d] parties:", p.TxID, len(p.Failures), len(p.Failures)+len(p.Confirmed))
+	for _, failure := range p.Failures {
+		fmt.Fprintf(&b, " [%s: %s]", failure.Party, failure.Err)
+	}
+	return b.String()
+}
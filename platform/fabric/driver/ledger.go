@@ -34,4 +34,7 @@ type Ledger interface {
 
 	// GetBlockByNumber fetches a block by number
 	GetBlockByNumber(number uint64) (Block, error)
+
+	// GetLedgerHeight returns the number of blocks in the ledger
+	GetLedgerHeight() (uint64, error)
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package driver
 
 import (
+	"time"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
@@ -35,6 +37,57 @@ type LocalMembership interface {
 	GetIdentityInfoByLabel(mspType string, label string) *IdentityInfo
 	GetIdentityInfoByIdentity(mspType string, id view.Identity) *IdentityInfo
 	Refresh() error
+	Wallet() Wallet
+}
+
+// WalletEntry describes an identity managed at runtime by the wallet of the local membership
+type WalletEntry struct {
+	// ID is the label the identity is looked up with
+	ID string
+	// MSPType is the type of the msp, either bccsp or idemix
+	MSPType string
+	MSPID   string
+	// Path is the folder of the msp holding the credentials
+	Path         string
+	EnrollmentID string
+	Labels       []string
+	Metadata     map[string]string
+	Created      time.Time
+	Updated      time.Time
+}
+
+// HasLabels returns true if the entry carries all the passed labels
+func (e *WalletEntry) HasLabels(labels ...string) bool {
+	for _, label := range labels {
+		found := false
+		for _, l := range e.Labels {
+			if l == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Wallet manages the identities of the local membership at runtime. The entries are stored
+// in the KVS and registered again when the node restarts.
+type Wallet interface {
+	// Add registers the identity described by the passed entry and stores the entry
+	Add(entry *WalletEntry) error
+	// Get returns the entry with the passed id, nil if not found
+	Get(id string) (*WalletEntry, error)
+	// List returns the entries carrying all the passed labels, all the entries if no label is passed
+	List(labels ...string) ([]*WalletEntry, error)
+	// Update replaces the labels and the metadata of the entry with the passed id
+	Update(id string, labels []string, metadata map[string]string) error
+	// Rotate replaces the credentials of the entry with the passed id with those in the msp at the passed path
+	Rotate(id string, path string) error
+	// Remove unregisters the identity with the passed id and deletes its entry
+	Remove(id string) error
 }
 
 type MSPIdentity interface {
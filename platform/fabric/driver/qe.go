@@ -11,6 +11,9 @@ import "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/d
 type QueryExecutor interface {
 	GetState(namespace string, key string) ([]byte, error)
 	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
+	// GetPrivateState returns the value of the given key of the given private data collection of the given namespace
+	GetPrivateState(namespace, collection, key string) ([]byte, error)
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error)
+	GetStateByQuery(namespace string, query string) (driver.VersionedResultsIterator, error)
 	Done()
 }
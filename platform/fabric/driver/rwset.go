@@ -32,6 +32,19 @@ type RWSet interface {
 	// SetStateMetadata sets the metadata associated with an existing key-tuple <namespace, key>
 	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
 
+	// SetPrivateState sets the given value for the given key of the given private data collection of the given namespace.
+	// Only the hashes of the key and the value appear in the rwset.
+	SetPrivateState(namespace, collection, key string, value []byte) error
+
+	// GetPrivateState returns the value of the given key of the given private data collection of the given namespace
+	GetPrivateState(namespace, collection, key string, opts ...GetStateOpt) ([]byte, error)
+
+	// DeletePrivateState deletes the given key of the given private data collection of the given namespace
+	DeletePrivateState(namespace, collection, key string) error
+
+	// Collections returns the private data collections of the namespace ns this rwset touches
+	Collections(ns string) []string
+
 	GetReadKeyAt(ns string, i int) (string, error)
 
 	// GetReadAt returns the i-th read (key, value) in the namespace ns  of this rwset.
@@ -53,6 +66,13 @@ type RWSet interface {
 
 	AppendRWSet(raw []byte, nss ...string) error
 
+	// AppendPvtRWSet fills in the private writes of this rwset from the passed marshalled private rwset,
+	// checking them against the hashes in the rwset
+	AppendPvtRWSet(raw []byte) error
+
+	// PvtBytes returns the marshalled private rwset carrying the private writes of this rwset, nil if there is none
+	PvtBytes() ([]byte, error)
+
 	Bytes() ([]byte, error)
 
 	String() string
@@ -15,4 +15,6 @@ type SigService interface {
 	GetSigner(id view.Identity) (Signer, error)
 	GetSigningIdentity(id view.Identity) (SigningIdentity, error)
 	RegisterSigner(identity view.Identity, signer Signer, verifier Verifier) error
+	// IsValid returns an error if the certificate of the passed identity has expired or has been revoked
+	IsValid(identity view.Identity) error
 }
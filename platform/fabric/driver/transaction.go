@@ -113,6 +113,7 @@ type Transaction interface {
 	ProposalResponses() []ProposalResponse
 	ProposalResponse() ([]byte, error)
 	BytesNoTransient() ([]byte, error)
+	BytesNoPvtData(keep func(key string) bool) ([]byte, error)
 }
 
 type SignedProposal interface {
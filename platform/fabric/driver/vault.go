@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package driver
 
+import "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+
 // Vault models a key value store that can be updated by committing rwsets
 type Vault interface {
 	// NewQueryExecutor gives handle to a query executor.
@@ -27,4 +29,16 @@ type Vault interface {
 	// GetEphemeralRWSet returns an ephemeral RWSet for this ledger whose content is unmarshalled
 	// from the passed bytes.
 	GetEphemeralRWSet(rwset []byte) (RWSet, error)
+
+	// LoadPvtRWSet returns the private rwset of the endorser transaction with the passed id, as stored by this node.
+	// It returns nil if this node does not have the private data of the transaction.
+	LoadPvtRWSet(txid string) ([]byte, error)
+
+	// StorePvtRWSet stores the private writes of the passed private rwset of the committed transaction with the passed id.
+	// The private rwset is checked against the hashes found in the rwset of the committed transaction.
+	StorePvtRWSet(txid string, pvtRWSet []byte) error
+
+	// CreateIndex declares a secondary index on the values of the passed namespace.
+	// Rich queries on the namespace use the index when it constrains the first indexed field.
+	CreateIndex(namespace string, index *driver.Index) error
 }
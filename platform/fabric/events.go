@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabric
+
+import (
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+)
+
+// ChaincodeEvent is an event set by a chaincode with SetEvent in a valid transaction
+type ChaincodeEvent = driver.ChaincodeEvent
+
+type EventsOptions struct {
+	EventNames []string
+	StartBlock uint64
+	FromNewest bool
+}
+
+type EventsOption func(*EventsOptions) error
+
+func compileEventsOptions(opts ...EventsOption) (*EventsOptions, error) {
+	options := &EventsOptions{FromNewest: true}
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// WithEventNames selects the events with the passed names only
+func WithEventNames(names ...string) EventsOption {
+	return func(o *EventsOptions) error {
+		o.EventNames = names
+		return nil
+	}
+}
+
+// WithStartBlock makes the stream start from the block with the passed number,
+// instead of the next block committed
+func WithStartBlock(number uint64) EventsOption {
+	return func(o *EventsOptions) error {
+		o.StartBlock = number
+		o.FromNewest = false
+		return nil
+	}
+}
+
+// Events gives access to the events set by the chaincodes of the channel.
+// The events come from the blocks the committer receives, no additional connection to the peers is opened.
+type Events struct {
+	ch driver.Channel
+}
+
+// Subscribe returns a stream of the events set by the passed chaincode in the valid transactions.
+// By default, the stream delivers the events of the blocks committed from now on, use WithStartBlock to
+// receive the events of the past blocks too. The stream follows the committer across reconnections to the peers.
+// To resume after a restart, subscribe again starting from the block after the one of the last event processed.
+func (e *Events) Subscribe(chaincode string, opts ...EventsOption) (*EventStream, error) {
+	options, err := compileEventsOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	s, err := e.ch.SubscribeChaincodeEvents(&driver.ChaincodeEventsRequest{
+		ChaincodeName: chaincode,
+		EventNames:    options.EventNames,
+		StartBlock:    options.StartBlock,
+		FromNewest:    options.FromNewest,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{s: s}, nil
+}
+
+// EventStream delivers the events of a subscription
+type EventStream struct {
+	s driver.ChaincodeEventStream
+}
+
+// Events returns the channel the events are delivered on, in commit order. The channel is closed by Close.
+func (s *EventStream) Events() <-chan *ChaincodeEvent {
+	return s.s.Events()
+}
+
+// Err returns the error that terminated the stream, for instance because the consumer fell too far behind.
+// It is nil if the stream has been closed by Close.
+func (s *EventStream) Err() error {
+	return s.s.Err()
+}
+
+// Close terminates the subscription
+func (s *EventStream) Close() {
+	s.s.Close()
+}
@@ -49,6 +49,11 @@ func (l *Ledger) GetTransactionByID(txID string) (*ProcessedTransaction, error)
 	return &ProcessedTransaction{pt: pt}, nil
 }
 
+// GetLedgerHeight returns the number of blocks in the ledger
+func (l *Ledger) GetLedgerHeight() (uint64, error) {
+	return l.ch.ch.GetLedgerHeight()
+}
+
 // GetBlockByNumber fetches a block by number
 func (l *Ledger) GetBlockByNumber(number uint64) (*Block, error) {
 	b, err := l.ch.ch.GetBlockByNumber(number)
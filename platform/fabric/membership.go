@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fabric
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
@@ -90,6 +92,95 @@ func (s *LocalMembership) Refresh() error {
 	return s.network.LocalMembership().Refresh()
 }
 
+// Wallet returns the wallet of the identities managed at runtime
+func (s *LocalMembership) Wallet() *Wallet {
+	return &Wallet{wallet: s.network.LocalMembership().Wallet()}
+}
+
+// WalletEntry describes an identity managed by the wallet
+type WalletEntry struct {
+	// ID is the label the identity is looked up with
+	ID string
+	// MSPType is the type of the msp, either bccsp or idemix
+	MSPType string
+	MSPID   string
+	// Path is the folder of the msp holding the credentials
+	Path         string
+	EnrollmentID string
+	Labels       []string
+	Metadata     map[string]string
+	Created      time.Time
+	Updated      time.Time
+}
+
+// Wallet manages the identities of the local membership at runtime, they survive the restarts of the node
+type Wallet struct {
+	wallet driver.Wallet
+}
+
+// Add registers the identity described by the passed entry and stores the entry
+func (w *Wallet) Add(entry *WalletEntry) error {
+	return w.wallet.Add(&driver.WalletEntry{
+		ID:       entry.ID,
+		MSPType:  entry.MSPType,
+		MSPID:    entry.MSPID,
+		Path:     entry.Path,
+		Labels:   entry.Labels,
+		Metadata: entry.Metadata,
+	})
+}
+
+// Get returns the entry with the passed id, nil if not found
+func (w *Wallet) Get(id string) (*WalletEntry, error) {
+	e, err := w.wallet.Get(id)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	return newWalletEntry(e), nil
+}
+
+// List returns the entries carrying all the passed labels, all the entries if no label is passed
+func (w *Wallet) List(labels ...string) ([]*WalletEntry, error) {
+	entries, err := w.wallet.List(labels...)
+	if err != nil {
+		return nil, err
+	}
+	var res []*WalletEntry
+	for _, e := range entries {
+		res = append(res, newWalletEntry(e))
+	}
+	return res, nil
+}
+
+// Update replaces the labels and the metadata of the entry with the passed id
+func (w *Wallet) Update(id string, labels []string, metadata map[string]string) error {
+	return w.wallet.Update(id, labels, metadata)
+}
+
+// Rotate replaces the credentials of the entry with the passed id with those in the msp at the passed path
+func (w *Wallet) Rotate(id string, path string) error {
+	return w.wallet.Rotate(id, path)
+}
+
+// Remove unregisters the identity with the passed id and deletes its entry
+func (w *Wallet) Remove(id string) error {
+	return w.wallet.Remove(id)
+}
+
+func newWalletEntry(e *driver.WalletEntry) *WalletEntry {
+	return &WalletEntry{
+		ID:           e.ID,
+		MSPType:      e.MSPType,
+		MSPID:        e.MSPID,
+		Path:         e.Path,
+		EnrollmentID: e.EnrollmentID,
+		Labels:       e.Labels,
+		Metadata:     e.Metadata,
+		Created:      e.Created,
+		Updated:      e.Updated,
+	}
+}
+
 // Verifier is an interface which wraps the Verify method.
 type Verifier interface {
 	// Verify verifies the signature over the passed message.
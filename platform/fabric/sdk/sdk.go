@@ -13,13 +13,16 @@ import (
 
 	fabric2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/finality"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/crypto"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/state"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/state/vault"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/wallet"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
-	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracker"
 )
 
 var logger = flogging.MustGetLogger("fabric-sdk")
@@ -65,8 +68,17 @@ func (p *p) Install() error {
 		state.NewRWSetProcessor(fabric2.GetDefaultFNS(p.registry)),
 	))
 
-	// TODO: remove this
-	assert.NoError(p.registry.RegisterService(tracker.NewTracker()))
+	// Answer the finality subscriptions of remote parties
+	finality.InstallResponder(driver.GetRegistry(p.registry), finality.DefaultMaxSubscriptions)
+
+	// Let the view admins manage the identity wallets
+	assert.NoError(wallet.InstallViews(driver.GetRegistry(p.registry)), "failed installing wallet views")
+
+	// Reload the fabric identities together with the node identity
+	if reloadService := id.GetReloadService(p.registry); reloadService != nil {
+		reloadService.Add(&membershipReloader{sp: p.registry})
+	}
+
 	// TODO: change this
 	assert.NoError(p.registry.RegisterService(vault.NewService(p.registry)))
 
@@ -85,3 +97,17 @@ func (p *p) Start(ctx context.Context) error {
 
 	return nil
 }
+
+// membershipReloader refreshes the local membership of the fabric networks, to pick up renewed credentials
+type membershipReloader struct {
+	sp Registry
+}
+
+func (m *membershipReloader) Reload() error {
+	for _, name := range fabric2.GetFabricNetworkNames(m.sp) {
+		if err := fabric2.GetLocalMembership(m.sp, name).Refresh(); err != nil {
+			return errors.WithMessagef(err, "failed refreshing local membership of [%s]", name)
+		}
+	}
+	return nil
+}
@@ -8,11 +8,22 @@ package chaincode
 
 import (
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+const (
+	// attributes of the spans of the chaincode calls
+	networkAttribute   = "fabric.network"
+	channelAttribute   = "fabric.channel"
+	chaincodeAttribute = "fabric.chaincode"
+	functionAttribute  = "fabric.chaincode.function"
+)
+
 type Invoke struct {
 	InvokerIdentity    view.Identity
 	Network            string
@@ -28,6 +39,15 @@ type Invoke struct {
 	Args               []interface{}
 }
 
+// startSpan starts the span of a chaincode call of the passed kind, as a child of the span of the calling view
+func (i *Invoke) startSpan(context view.Context, kind string) trace.Span {
+	_, span := tracing.Start(context.Context(), context, "chaincode "+kind, trace.WithAttributes(
+		attribute.String(chaincodeAttribute, i.ChaincodeName),
+		attribute.String(functionAttribute, i.Function),
+	))
+	return span
+}
+
 type invokeChaincodeView struct {
 	*Invoke
 }
@@ -42,16 +62,19 @@ func NewInvokeView(chaincode, function string, args ...interface{}) *invokeChain
 	}
 }
 
-func (i *invokeChaincodeView) Call(context view.Context) (interface{}, error) {
+func (i *invokeChaincodeView) Call(context view.Context) (res interface{}, err error) {
 	if len(i.ChaincodeName) == 0 {
 		return nil, errors.Errorf("no chaincode specified")
 	}
+	span := i.startSpan(context, "invoke")
+	defer func() { tracing.End(span, err) }()
 
 	fNetwork := fabric.GetFabricNetworkService(context, i.Network)
 	channel, err := fNetwork.Channel(i.Channel)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", i.Network, i.Channel)
 	}
+	span.SetAttributes(attribute.String(networkAttribute, fNetwork.Name()), attribute.String(channelAttribute, channel.Name()))
 	if i.InvokerIdentity.IsNone() {
 		i.InvokerIdentity = fNetwork.IdentityProvider().DefaultIdentity()
 	}
@@ -10,7 +10,9 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -28,16 +30,19 @@ func NewQueryView(chaincode, function string, args ...interface{}) *queryChainco
 	}
 }
 
-func (i *queryChaincodeView) Call(context view.Context) (interface{}, error) {
+func (i *queryChaincodeView) Call(context view.Context) (res interface{}, err error) {
 	if len(i.ChaincodeName) == 0 {
 		return nil, errors.Errorf("no chaincode specified")
 	}
+	span := i.startSpan(context, "query")
+	defer func() { tracing.End(span, err) }()
 
 	fNetwork := fabric.GetFabricNetworkService(context, i.Network)
 	channel, err := fNetwork.Channel(i.Channel)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", i.Network, i.Channel)
 	}
+	span.SetAttributes(attribute.String(networkAttribute, fNetwork.Name()), attribute.String(channelAttribute, channel.Name()))
 	if i.InvokerIdentity.IsNone() {
 		i.InvokerIdentity = fNetwork.IdentityProvider().DefaultIdentity()
 	}
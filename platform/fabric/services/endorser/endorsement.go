@@ -24,6 +24,7 @@ type collectEndorsementsView struct {
 	tx              *Transaction
 	parties         []view.Identity
 	deleteTransient bool
+	keepTransient   func(key string) bool
 }
 
 func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error) {
@@ -54,7 +55,12 @@ func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error
 		}
 
 		var txRaw []byte
-		if c.deleteTransient {
+		if c.keepTransient != nil {
+			txRaw, err = c.tx.BytesNoPvtData(c.keepTransient)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed marshalling transaction content")
+			}
+		} else if c.deleteTransient {
 			txRaw, err = c.tx.BytesNoTransient()
 			if err != nil {
 				return nil, errors.Wrap(err, "failed marshalling transaction content")
@@ -114,6 +120,10 @@ func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error
 				found = true
 			}
 
+			if err := signService.IsValid(endorser); err != nil {
+				return nil, errors.WithMessagef(err, "invalid endorser for party %s", endorser.String())
+			}
+
 			// Verify signatures
 			verifier, err := signService.GetVerifier(endorser)
 			if err != nil {
@@ -153,6 +163,12 @@ func NewCollectApprovesView(tx *Transaction, parties ...view.Identity) *collectE
 	return &collectEndorsementsView{tx: tx, parties: parties, deleteTransient: true}
 }
 
+// NewCollectApprovesWithTransientView returns a view that collects the endorsements of the passed parties
+// sending them the transaction without the private data, and with the transient entries the passed function keeps
+func NewCollectApprovesWithTransientView(tx *Transaction, keep func(key string) bool, parties ...view.Identity) *collectEndorsementsView {
+	return &collectEndorsementsView{tx: tx, parties: parties, keepTransient: keep}
+}
+
 type endorseView struct {
 	tx         *Transaction
 	identities []view.Identity
@@ -8,8 +8,11 @@ package endorser
 
 import (
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -24,10 +27,7 @@ func (f *finalityView) Call(context view.Context) (interface{}, error) {
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", f.tx.Network(), f.tx.Channel())
 	}
-	if len(f.endpoints) != 0 {
-		return nil, ch.Finality().IsFinalForParties(f.tx.ID(), f.endpoints...)
-	}
-	return nil, ch.Finality().IsFinal(f.tx.ID())
+	return nil, isFinal(context, ch, f.tx, f.endpoints...)
 }
 
 func NewFinalityView(tx *Transaction) *finalityView {
@@ -37,3 +37,26 @@ func NewFinalityView(tx *Transaction) *finalityView {
 func NewFinalityFromView(tx *Transaction, endpoints ...view.Identity) *finalityView {
 	return &finalityView{tx: tx, endpoints: endpoints}
 }
+
+// isFinal waits for the finality of the passed transaction, asking the passed endpoints if any.
+// The wait gets its own span, child of the span of the calling view.
+func isFinal(context view.Context, ch *fabric.Channel, tx *Transaction, endpoints ...view.Identity) error {
+	_, span := tracing.Start(context.Context(), context, "finality", trace.WithAttributes(spanAttributes(tx)...))
+	var err error
+	if len(endpoints) != 0 {
+		err = ch.Finality().IsFinalForParties(tx.ID(), endpoints...)
+	} else {
+		err = ch.Finality().IsFinal(tx.ID())
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// spanAttributes returns the attributes identifying the passed transaction in a span
+func spanAttributes(tx *Transaction) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("fabric.network", tx.Network()),
+		attribute.String("fabric.channel", tx.Channel()),
+		attribute.String("fabric.txid", tx.ID()),
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -24,7 +26,10 @@ type orderingView struct {
 func (o *orderingView) Call(context view.Context) (interface{}, error) {
 	fns := fabric.GetFabricNetworkService(context, o.tx.Network())
 	tx := o.tx
-	if err := fns.Ordering().Broadcast(tx.Transaction); err != nil {
+	_, span := tracing.Start(context.Context(), context, "broadcast", trace.WithAttributes(spanAttributes(tx)...))
+	err := fns.Ordering().Broadcast(tx.Transaction)
+	tracing.End(span, err)
+	if err != nil {
 		return nil, errors.WithMessagef(err, "failed broadcasting to [%s:%s]", o.tx.Network(), o.tx.Channel())
 	}
 	if o.finality {
@@ -32,7 +37,7 @@ func (o *orderingView) Call(context view.Context) (interface{}, error) {
 		if err != nil {
 			return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", o.tx.Network(), o.tx.Channel())
 		}
-		if err := ch.Finality().IsFinal(tx.ID()); err != nil {
+		if err := isFinal(context, ch, tx); err != nil {
 			return nil, errors.WithMessagef(err, "failed asking finality of [%s] to [%s:%s]", tx.ID(), o.tx.Network(), o.tx.Channel())
 		}
 	}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// CollectionMembership tells which parties can access the private data of a collection
+type CollectionMembership interface {
+	// IsMember returns true if the passed party is a member of the passed collection of the passed namespace
+	IsMember(party view.Identity, namespace, collection string) bool
+}
+
+// PvtDataRequest asks a party for the private data of a committed transaction
+type PvtDataRequest struct {
+	Network string
+	Channel string
+	TxID    string
+}
+
+// DefaultPvtDataTimeout is the time NewFetchPvtDataView waits for the answer of the party, unless set with WithTimeout
+const DefaultPvtDataTimeout = 60 * time.Second
+
+type fetchPvtDataView struct {
+	request *PvtDataRequest
+	party   view.Identity
+	timeout time.Duration
+}
+
+// NewFetchPvtDataView returns a view that fetches from the passed party the private data of the committed
+// transaction with the passed id, and stores it in the vault.
+// The private data is checked against the hashes found in the committed transaction.
+func NewFetchPvtDataView(network, channel, txID string, party view.Identity) *fetchPvtDataView {
+	return &fetchPvtDataView{
+		request: &PvtDataRequest{Network: network, Channel: channel, TxID: txID},
+		party:   party,
+		timeout: DefaultPvtDataTimeout,
+	}
+}
+
+// WithTimeout sets the time to wait for the answer of the party
+func (f *fetchPvtDataView) WithTimeout(timeout time.Duration) *fetchPvtDataView {
+	f.timeout = timeout
+	return f
+}
+
+func (f *fetchPvtDataView) Call(context view.Context) (interface{}, error) {
+	fns := fabric.GetFabricNetworkService(context, f.request.Network)
+	if fns == nil {
+		return nil, errors.Errorf("fabric network service [%s] not found", f.request.Network)
+	}
+	ch, err := fns.Channel(f.request.Channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", f.request.Network, f.request.Channel)
+	}
+
+	session, err := context.GetSession(context.Initiator(), f.party)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting session")
+	}
+	raw, err := json.Marshal(f.request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling request")
+	}
+	sessionCh := session.Receive()
+	if err := session.Send(raw); err != nil {
+		return nil, errors.Wrap(err, "failed sending request")
+	}
+
+	var msg *view.Message
+	select {
+	case msg = <-sessionCh:
+	case <-time.After(f.timeout):
+		return nil, errors.Errorf("Timeout from party %s", f.party)
+	}
+	if msg.Status == view.ERROR {
+		return nil, errors.New(string(msg.Payload))
+	}
+	if len(msg.Payload) == 0 {
+		return nil, errors.Errorf("party %s has no private data for [%s]", f.party, f.request.TxID)
+	}
+
+	if err := ch.Vault().StorePvtRWSet(f.request.TxID, msg.Payload); err != nil {
+		return nil, errors.WithMessagef(err, "failed storing private data of [%s]", f.request.TxID)
+	}
+	return nil, nil
+}
+
+type pvtDataResponderView struct {
+	membership CollectionMembership
+}
+
+// NewPvtDataResponderView returns a view that answers a private data request sent by NewFetchPvtDataView.
+// The caller gets the private data of the collections the passed membership says it is a member of.
+func NewPvtDataResponderView(membership CollectionMembership) *pvtDataResponderView {
+	return &pvtDataResponderView{membership: membership}
+}
+
+func (p *pvtDataResponderView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+	raw, err := context.RunView(&receiveView{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed receiving request")
+	}
+	request := &PvtDataRequest{}
+	if err := json.Unmarshal(raw.([]byte), request); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling request")
+	}
+
+	caller := session.Info().Caller
+	if caller.IsNone() {
+		return nil, errors.Errorf("unknown caller for private data of [%s]", request.TxID)
+	}
+
+	fns := fabric.GetFabricNetworkService(context, request.Network)
+	if fns == nil {
+		return nil, errors.Errorf("fabric network service [%s] not found", request.Network)
+	}
+	ch, err := fns.Channel(request.Channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", request.Network, request.Channel)
+	}
+	pvtRWSet, err := ch.Vault().LoadPvtRWSet(request.TxID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed loading private data of [%s]", request.TxID)
+	}
+
+	filtered, err := p.filter(caller, pvtRWSet)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed filtering private data of [%s]", request.TxID)
+	}
+	if err := session.Send(filtered); err != nil {
+		return nil, errors.Wrap(err, "failed sending private data")
+	}
+	return nil, nil
+}
+
+// filter keeps the collections of the passed private rwset the passed party is a member of
+func (p *pvtDataResponderView) filter(party view.Identity, pvtRWSet []byte) ([]byte, error) {
+	if len(pvtRWSet) == 0 {
+		return nil, nil
+	}
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(pvtRWSet, txPvtRWSet); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling private rwset")
+	}
+
+	filtered := &rwset.TxPvtReadWriteSet{DataModel: txPvtRWSet.DataModel}
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwset {
+		var colls []*rwset.CollectionPvtReadWriteSet
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			if p.membership.IsMember(party, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName) {
+				colls = append(colls, collPvtRWSet)
+				continue
+			}
+			logger.Debugf("party [%s] is not a member of [%s:%s]", party, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+		}
+		if len(colls) != 0 {
+			filtered.NsPvtRwset = append(filtered.NsPvtRwset, &rwset.NsPvtReadWriteSet{Namespace: nsPvtRWSet.Namespace, CollectionPvtRwset: colls})
+		}
+	}
+	if len(filtered.NsPvtRwset) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(filtered)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/driver"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type fakeSession struct {
+	info view.SessionInfo
+	in   chan *view.Message
+	out  chan *view.Message
+}
+
+// newSessionPair returns the two ends of a session, the responder end carries the passed info
+func newSessionPair(info view.SessionInfo) (*fakeSession, *fakeSession) {
+	a, b := make(chan *view.Message, 10), make(chan *view.Message, 10)
+	return &fakeSession{in: a, out: b}, &fakeSession{info: info, in: b, out: a}
+}
+
+func (f *fakeSession) Info() view.SessionInfo { return f.info }
+
+func (f *fakeSession) Send(payload []byte) error {
+	f.out <- &view.Message{Status: view.OK, Payload: payload}
+	return nil
+}
+
+func (f *fakeSession) SendError(payload []byte) error {
+	f.out <- &view.Message{Status: view.ERROR, Payload: payload}
+	return nil
+}
+
+func (f *fakeSession) Receive() <-chan *view.Message { return f.in }
+
+func (f *fakeSession) Close() {}
+
+// viewContext names the embedded context, whose Context method would be shadowed by a field named Context
+type viewContext = view.Context
+
+type fakeContext struct {
+	viewContext
+	sp      view2.ServiceProvider
+	session view.Session
+}
+
+func (c *fakeContext) GetService(v interface{}) (interface{}, error) { return c.sp.GetService(v) }
+
+func (c *fakeContext) Initiator() view.View { return nil }
+
+func (c *fakeContext) GetSession(caller view.View, party view.Identity) (view.Session, error) {
+	return c.session, nil
+}
+
+func (c *fakeContext) Session() view.Session { return c.session }
+
+func (c *fakeContext) RunView(v view.View) (interface{}, error) { return v.Call(c) }
+
+func (c *fakeContext) Context() context.Context { return context.Background() }
+
+type fnsProvider struct {
+	driver.FabricNetworkServiceProvider
+	fns *fns
+}
+
+func (f *fnsProvider) FabricNetworkService(id string) (driver.FabricNetworkService, error) {
+	if id != "network" {
+		return nil, errors.New("not found")
+	}
+	return f.fns, nil
+}
+
+type fns struct {
+	driver.FabricNetworkService
+	channel *channel
+}
+
+func (f *fns) Name() string { return "network" }
+
+func (f *fns) Channel(name string) (driver.Channel, error) {
+	if name != "channel" {
+		return nil, errors.New("not found")
+	}
+	return f.channel, nil
+}
+
+// channel keeps the private data of tx1, and records the private data stored
+type channel struct {
+	driver.Channel
+	pvtRWSet []byte
+	stored   []byte
+	storeErr error
+}
+
+func (c *channel) LoadPvtRWSet(txid string) ([]byte, error) {
+	if txid != "tx1" {
+		return nil, errors.Errorf("transaction [%s] not found", txid)
+	}
+	return c.pvtRWSet, nil
+}
+
+func (c *channel) StorePvtRWSet(txid string, pvtRWSet []byte) error {
+	if c.storeErr != nil {
+		return c.storeErr
+	}
+	c.stored = pvtRWSet
+	return nil
+}
+
+type membership struct {
+	members map[string]string
+}
+
+func (m *membership) IsMember(party view.Identity, namespace, collection string) bool {
+	return m.members[party.UniqueID()] == namespace+":"+collection
+}
+
+func newPvtDataContext(t *testing.T, ch *channel, session view.Session) *fakeContext {
+	sp := registry2.New()
+	assert.NoError(t, sp.RegisterService(&fnsProvider{fns: &fns{channel: ch}}))
+	return &fakeContext{sp: sp, session: session}
+}
+
+func TestFetchPvtData(t *testing.T) {
+	alice, bob, charlie := view.Identity("alice"), view.Identity("bob"), view.Identity("charlie")
+	pvtRWSet, err := proto.Marshal(&rwset.TxPvtReadWriteSet{NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+		{Namespace: "ns1", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+			{CollectionName: "coll1", Rwset: []byte("coll1 writes")},
+			{CollectionName: "coll2", Rwset: []byte("coll2 writes")},
+		}},
+		{Namespace: "ns2", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+			{CollectionName: "coll1", Rwset: []byte("ns2 writes")},
+		}},
+	}})
+	assert.NoError(t, err)
+	responder := NewPvtDataResponderView(&membership{members: map[string]string{
+		alice.UniqueID(): "ns1:coll1",
+		bob.UniqueID():   "ns3:coll1",
+	}})
+
+	// fetch runs the fetch view of the caller against the responder view of bob
+	fetch := func(caller view.Identity, local *channel, timeout time.Duration) (chan error, error) {
+		initiator, remote := newSessionPair(view.SessionInfo{Caller: caller})
+		responded := make(chan error, 1)
+		go func() {
+			_, err := responder.Call(newPvtDataContext(t, &channel{pvtRWSet: pvtRWSet}, remote))
+			responded <- err
+		}()
+		_, err := NewFetchPvtDataView("network", "channel", "tx1", bob).WithTimeout(timeout).Call(newPvtDataContext(t, local, initiator))
+		return responded, err
+	}
+
+	// a member gets the collections it is a member of only
+	local := &channel{}
+	responded, err := fetch(alice, local, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, <-responded)
+	received := &rwset.TxPvtReadWriteSet{}
+	assert.NoError(t, proto.Unmarshal(local.stored, received))
+	assert.Len(t, received.NsPvtRwset, 1)
+	assert.Equal(t, "ns1", received.NsPvtRwset[0].Namespace)
+	assert.Len(t, received.NsPvtRwset[0].CollectionPvtRwset, 1)
+	assert.Equal(t, "coll1", received.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+	assert.Equal(t, []byte("coll1 writes"), received.NsPvtRwset[0].CollectionPvtRwset[0].Rwset)
+
+	// a party member of none of the collections gets nothing
+	local = &channel{}
+	responded, err = fetch(charlie, local, time.Second)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no private data for [tx1]")
+	assert.NoError(t, <-responded)
+	assert.Nil(t, local.stored)
+
+	// the private data not matching the hashes of the transaction is rejected by the vault
+	local = &channel{storeErr: errors.New("private rwset hash mismatch")}
+	responded, err = fetch(alice, local, time.Second)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed storing private data of [tx1]: private rwset hash mismatch")
+	assert.NoError(t, <-responded)
+
+	// anonymous callers get no answer, the fetch times out
+	responded, err = fetch(nil, &channel{}, 100*time.Millisecond)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Timeout from party "+bob.String())
+	assert.Error(t, <-responded)
+}
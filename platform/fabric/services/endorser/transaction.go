@@ -101,6 +101,10 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 	return t.Transaction.BytesNoTransient()
 }
 
+func (t *Transaction) BytesNoPvtData(keep func(key string) bool) ([]byte, error) {
+	return t.Transaction.BytesNoPvtData(keep)
+}
+
 func (t *Transaction) Raw() ([]byte, error) {
 	return t.Transaction.Raw()
 }
@@ -18,8 +18,12 @@ func NewCollectEndorsementsView(tx *Transaction, parties ...view.Identity) view.
 	return endorser.NewCollectEndorsementsView(tx.tx, parties...)
 }
 
+// NewCollectApprovesView returns a view that collects the endorsements of the passed approvers.
+// The approvers do not receive the private data of the transaction, they see its hashes only.
 func NewCollectApprovesView(tx *Transaction, parties ...view.Identity) view.View {
-	return endorser.NewCollectEndorsementsView(tx.tx, parties...)
+	return endorser.NewCollectApprovesWithTransientView(tx.tx, func(key string) bool {
+		return !isPrivateFieldMappingKey(key)
+	}, parties...)
 }
 
 // NewEndorseView returns a view that does the following:
@@ -154,6 +154,9 @@ func (n *Namespace) AddInputByLinearID(id string, state interface{}, opts ...Add
 	if err != nil {
 		return errors.Wrapf(err, "failed getting mapping [%s, %s]", n.namespace(), id)
 	}
+	if err := n.loadPrivateFields(rwSet, state, id, mapping); err != nil {
+		return errors.Wrapf(err, "failed loading private fields [%s, %s]", n.namespace(), id)
+	}
 
 	if len(mapping) != 0 && len(mapping["_root_"]) != 0 {
 		raw = mapping["_root_"]
@@ -185,6 +188,13 @@ func (n *Namespace) AddInputByLinearID(id string, state interface{}, opts ...Add
 	}
 
 	// add state info
+	fields, err := privateFields(state)
+	if err != nil {
+		return errors.Wrapf(err, "failed getting private fields [%s, %s]", n.namespace(), id)
+	}
+	if err := n.setPrivateFieldMapping(n.namespace(), id, fields, mapping); err != nil {
+		return errors.Wrap(err, "failed setting private field mapping")
+	}
 	if err := n.setFieldMapping(n.namespace(), id, mapping); err != nil {
 		return errors.Wrap(err, "failed setting meta mapping")
 	}
@@ -250,6 +260,11 @@ func (n *Namespace) AddOutput(st interface{}, opts ...AddOutputOption) error {
 		}
 	}
 
+	// Store private fields
+	if err := n.setPrivateFields(rwSet, st, id, mapping); err != nil {
+		return errors.Wrap(err, "failed setting private fields")
+	}
+
 	// Store mapping
 	if err := n.setFieldMapping(n.namespace(), id, mapping); err != nil {
 		return errors.Wrap(err, "failed setting meta mapping")
@@ -366,6 +381,9 @@ func (n *Namespace) Delete(state interface{}) error {
 	if err != nil {
 		return err
 	}
+	if err := n.deletePrivateFields(rwSet, state, id); err != nil {
+		return errors.Wrap(err, "failed deleting private fields")
+	}
 	return nil
 }
 
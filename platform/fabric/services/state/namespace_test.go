@@ -73,3 +73,41 @@ func TestMarshalTagsPointerToStruct2(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, h, h2)
 }
+
+type Agreement struct {
+	ID    string `json:"id"`
+	Price int    `state:"private" collection:"prices" json:"price"`
+}
+
+func TestMarshalTagsPrivate(t *testing.T) {
+	n := &Namespace{}
+	a := &Agreement{ID: "1234", Price: 100}
+	a2, mapping, err := n.marshalTags(nil, a)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, a2.(*Agreement).Price)
+	assert.Equal(t, []byte("100"), mapping["Price"])
+
+	// without the private data, the field keeps the zero value
+	err = n.unmarshalTags(nil, a2, map[string][]byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, &Agreement{ID: "1234"}, a2)
+
+	err = n.unmarshalTags(nil, a2, mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, a, a2)
+
+	fields, err := privateFields(a)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Price": "prices"}, fields)
+	_, err = privateFields(&struct {
+		Price int `state:"private"`
+	}{})
+	assert.Error(t, err)
+
+	k, err := privateFieldMappingKey("ns", "1234")
+	assert.NoError(t, err)
+	assert.True(t, isPrivateFieldMappingKey(k))
+	k, err = fieldMappingKey("ns", "1234")
+	assert.NoError(t, err)
+	assert.False(t, isPrivateFieldMappingKey(k))
+}
@@ -19,6 +19,9 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/rwset"
 )
 
+// privateTag marks the fields whose values are kept in the private data collection named by the collection tag
+const privateTag = "private"
+
 func (n *Namespace) setFieldMapping(namespace string, key string, mapping map[string][]byte) error {
 	logger.Debugf("setting field mapping for [%s:%s]", namespace, key)
 	if len(mapping) == 0 {
@@ -60,7 +63,11 @@ func (n *Namespace) getFieldMapping(namespace string, key string, flag bool) (ma
 		raw = t
 	} else {
 		if !flag {
-			return nil, nil
+			mapping := map[string][]byte{}
+			if err := n.getPrivateFieldMapping(namespace, key, mapping); err != nil {
+				return nil, err
+			}
+			return mapping, nil
 		}
 		logger.Debugf("getting field mapping for [%s:%s], not found in transient, looking into the rws", namespace, key)
 		rws, err := n.tx.RWSet()
@@ -73,7 +80,11 @@ func (n *Namespace) getFieldMapping(namespace string, key string, flag bool) (ma
 		}
 		if len(meta) == 0 || len(meta[k]) == 0 {
 			logger.Debugf("getting field mapping for [%s:%s], not found in rws", namespace, key)
-			return map[string][]byte{}, nil
+			mapping := map[string][]byte{}
+			if err := n.getPrivateFieldMapping(namespace, key, mapping); err != nil {
+				return nil, err
+			}
+			return mapping, nil
 		}
 		raw = meta[k]
 		logger.Debugf("getting field mapping for [%s:%s], found in rws", namespace, key)
@@ -84,6 +95,9 @@ func (n *Namespace) getFieldMapping(namespace string, key string, flag bool) (ma
 	if err != nil {
 		return nil, errors.Wrap(err, "filed unmarshalling mapping")
 	}
+	if err := n.getPrivateFieldMapping(namespace, key, mapping); err != nil {
+		return nil, err
+	}
 	for k, v := range mapping {
 		logger.Debugf("getting field mapping for [%s:%s], entry [%s:%s]", namespace, key, k, string(v))
 	}
@@ -133,6 +147,16 @@ func (n *Namespace) marshalTags(set *fabric.RWSet, source interface{}) (interfac
 						base64.StdEncoding.EncodeToString(h), t.Field(i).Name, string(field.Bytes()))
 					field.Set(reflect.ValueOf(h))
 				}
+			case privateTag:
+				// the value goes to the mapping, and from there to the private data collection,
+				// the state carries the zero value
+				field := v.Field(i)
+				raw, err := json.Marshal(field.Interface())
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "failed marshalling private field [%s]", t.Field(i).Name)
+				}
+				mapping[t.Field(i).Name] = raw
+				field.Set(reflect.Zero(field.Type()))
 			}
 		}
 	}
@@ -177,12 +201,175 @@ func (n *Namespace) unmarshalTags(set *fabric.RWSet, source interface{}, mapping
 
 					field.Set(reflect.ValueOf(original))
 				}
+			case privateTag:
+				raw, ok := mapping[t.Field(i).Name]
+				if !ok {
+					// the private data is not available, the field keeps the zero value
+					continue
+				}
+				if err := json.Unmarshal(raw, v.Field(i).Addr().Interface()); err != nil {
+					return errors.Wrapf(err, "failed unmarshalling private field [%s]", t.Field(i).Name)
+				}
 			}
 		}
 	}
 	return nil
 }
 
+// privateFields returns the private data collections of the fields of the passed state tagged as private,
+// indexed by field name
+func privateFields(state interface{}) (map[string]string, error) {
+	t := reflect.TypeOf(state).Elem()
+	fields := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		if tag, ok := t.Field(i).Tag.Lookup("state"); !ok || tag != privateTag {
+			continue
+		}
+		collection := t.Field(i).Tag.Get("collection")
+		if len(collection) == 0 {
+			return nil, errors.Errorf("no collection specified for private field [%s]", t.Field(i).Name)
+		}
+		fields[t.Field(i).Name] = collection
+	}
+	return fields, nil
+}
+
+// setPrivateFields writes the values of the private fields of the passed state, found in the mapping,
+// to their private data collections, and moves them to the private field mapping
+func (n *Namespace) setPrivateFields(set *fabric.RWSet, state interface{}, id string, mapping map[string][]byte) error {
+	fields, err := privateFields(state)
+	if err != nil {
+		return err
+	}
+	for name, collection := range fields {
+		k, err := privateFieldKey(id, name)
+		if err != nil {
+			return errors.Wrap(err, "failed creating private field key")
+		}
+		if err := set.SetPrivateState(n.namespace(), collection, k, mapping[name]); err != nil {
+			return errors.Wrapf(err, "failed setting private field [%s]", name)
+		}
+	}
+	return n.setPrivateFieldMapping(n.namespace(), id, fields, mapping)
+}
+
+// loadPrivateFields adds to the mapping the values of the private fields of the passed state not already there,
+// loaded from the vault. The values this node does not have are left out.
+func (n *Namespace) loadPrivateFields(set *fabric.RWSet, state interface{}, id string, mapping map[string][]byte) error {
+	fields, err := privateFields(state)
+	if err != nil {
+		return err
+	}
+	for name, collection := range fields {
+		if _, ok := mapping[name]; ok {
+			continue
+		}
+		k, err := privateFieldKey(id, name)
+		if err != nil {
+			return errors.Wrap(err, "failed creating private field key")
+		}
+		raw, err := set.GetPrivateState(n.namespace(), collection, k)
+		if err != nil {
+			return errors.Wrapf(err, "failed getting private field [%s]", name)
+		}
+		if len(raw) != 0 {
+			mapping[name] = raw
+		}
+	}
+	return nil
+}
+
+// setPrivateFieldMapping moves the values of the passed private fields from the mapping to the transient,
+// under a key of their own. This way, they can be kept from the parties that are not members of the collections.
+func (n *Namespace) setPrivateFieldMapping(namespace string, key string, fields map[string]string, mapping map[string][]byte) error {
+	private := map[string][]byte{}
+	for name := range fields {
+		if v, ok := mapping[name]; ok {
+			private[name] = v
+			delete(mapping, name)
+		}
+	}
+	if len(private) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(private)
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling private field mapping")
+	}
+	k, err := privateFieldMappingKey(namespace, key)
+	if err != nil {
+		return errors.Wrap(err, "failed creating private field mapping key")
+	}
+	if err := n.tx.SetTransient(k, raw); err != nil {
+		return errors.Wrap(err, "failed setting private field mapping")
+	}
+	return nil
+}
+
+// getPrivateFieldMapping adds to the passed mapping the values of the private fields found in the transient
+func (n *Namespace) getPrivateFieldMapping(namespace string, key string, mapping map[string][]byte) error {
+	k, err := privateFieldMappingKey(namespace, key)
+	if err != nil {
+		return errors.Wrap(err, "failed creating private field mapping key")
+	}
+	raw := n.tx.GetTransient(k)
+	if len(raw) == 0 {
+		return nil
+	}
+	private := map[string][]byte{}
+	if err := json.Unmarshal(raw, &private); err != nil {
+		return errors.Wrap(err, "failed unmarshalling private field mapping")
+	}
+	for name, v := range private {
+		mapping[name] = v
+	}
+	return nil
+}
+
+// deletePrivateFields deletes the values of the private fields of the passed state from their private data collections
+func (n *Namespace) deletePrivateFields(set *fabric.RWSet, state interface{}, id string) error {
+	fields, err := privateFields(state)
+	if err != nil {
+		return err
+	}
+	for name, collection := range fields {
+		k, err := privateFieldKey(id, name)
+		if err != nil {
+			return errors.Wrap(err, "failed creating private field key")
+		}
+		if err := set.DeletePrivateState(n.namespace(), collection, k); err != nil {
+			return errors.Wrapf(err, "failed deleting private field [%s]", name)
+		}
+	}
+	return nil
+}
+
+func privateFieldKey(id, field string) (string, error) {
+	prefix, attrs, err := rwset.SplitCompositeKey(id)
+	if err != nil {
+		return "", err
+	}
+	elems := append([]string{prefix}, attrs...)
+	elems = append(elems, field)
+	return rwset.CreateCompositeKey("private_field", elems)
+}
+
+func privateFieldMappingKey(ns, key string) (string, error) {
+	prefix, attrs, err := rwset.SplitCompositeKey(key)
+	if err != nil {
+		return "", err
+	}
+	elems := append([]string{ns, prefix}, attrs...)
+	return rwset.CreateCompositeKey("private_field_mapping", elems)
+}
+
+// isPrivateFieldMappingKey returns true if the passed transient key holds the values of private fields
+func isPrivateFieldMappingKey(key string) bool {
+	prefix, _, err := rwset.SplitCompositeKey(key)
+	return err == nil && prefix == "private_field_mapping"
+}
+
 func fieldMappingKey(ns, key string) (string, error) {
 	prefix, attrs, err := rwset.SplitCompositeKey(key)
 	if err != nil {
@@ -31,6 +31,15 @@ type Vault interface {
 	GetStateCertification(namespace string, key string) ([]byte, error)
 
 	GetStateByPartialCompositeID(ns string, prefix string, attrs []string) (QueryIteratorInterface, error)
+
+	// GetStateByQuery returns an iterator over the states of the passed namespace that satisfy the passed query.
+	// The query follows the syntax of CouchDB Mango queries, for instance:
+	// {"selector": {"Owner": "alice", "Amount": {"$gt": 10}}, "sort": [{"Amount": "desc"}], "limit": 10}
+	GetStateByQuery(ns string, query string) (QueryIteratorInterface, error)
+
+	// CreateIndex declares an index on the passed fields of the states of the passed namespace.
+	// Queries constraining the first field of the index do not need to scan the whole namespace.
+	CreateIndex(ns string, name string, fields ...string) error
 }
 
 // VaultService models a vault instance provider
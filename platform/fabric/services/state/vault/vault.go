@@ -18,6 +18,7 @@ import (
 )
 
 type ListStateQueryIteratorInterface struct {
+	qe   *fabric.QueryExecutor
 	it   *fabric.ResultsIterator
 	next *fabric.Read
 }
@@ -33,6 +34,7 @@ func (l *ListStateQueryIteratorInterface) HasNext() bool {
 
 func (l *ListStateQueryIteratorInterface) Close() error {
 	l.it.Close()
+	l.qe.Done()
 	return nil
 }
 
@@ -92,13 +94,35 @@ func (f *vault) GetStateByPartialCompositeID(ns string, prefix string, attrs []s
 	if err != nil {
 		return nil, errors.Wrap(err, "failed getting query executor")
 	}
-	defer q.Done()
 
 	it, err := q.GetStateRangeScanIterator(ns, startKey, endKey)
 	if err != nil {
+		q.Done()
 		return nil, errors.Wrap(err, "failed getting state iterator")
 	}
-	return &ListStateQueryIteratorInterface{it: it}, nil
+	return &ListStateQueryIteratorInterface{qe: q, it: it}, nil
+}
+
+func (f *vault) GetStateByQuery(ns string, query string) (state.QueryIteratorInterface, error) {
+	q, err := f.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting query executor")
+	}
+
+	it, err := q.GetStateByQuery(ns, query)
+	if err != nil {
+		q.Done()
+		return nil, errors.Wrapf(err, "failed querying states of [%s]", ns)
+	}
+	return &ListStateQueryIteratorInterface{qe: q, it: it}, nil
+}
+
+func (f *vault) CreateIndex(ns string, name string, fields ...string) error {
+	ch, err := fabric.GetFabricNetworkService(f.sp, f.network).Channel(f.channel)
+	if err != nil {
+		return errors.Wrapf(err, "failed getting channel [%s:%s]", f.network, f.channel)
+	}
+	return ch.Vault().CreateIndex(ns, name, fields...)
 }
 
 func (f *vault) GetStateCertification(namespace string, key string) ([]byte, error) {
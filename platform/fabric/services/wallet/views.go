@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// The identifiers of the view factories driving the wallet through the view service.
+// They are reserved to the default identity of the node and its admins, the view policies cannot grant them.
+const (
+	AddView    = "fabric.wallet.add"
+	GetView    = "fabric.wallet.get"
+	ListView   = "fabric.wallet.list"
+	UpdateView = "fabric.wallet.update"
+	RotateView = "fabric.wallet.rotate"
+	RemoveView = "fabric.wallet.remove"
+)
+
+// Request is the input of the wallet views, each view reads the fields it needs
+type Request struct {
+	// Network is the fabric network whose wallet is used, the default one if empty
+	Network  string
+	ID       string
+	MSPType  string
+	MSPID    string
+	Path     string
+	Labels   []string
+	Metadata map[string]string
+}
+
+// Registry binds ids to view factories
+type Registry interface {
+	RegisterFactory(id string, factory driver.Factory) error
+}
+
+// InstallViews registers the factories of the wallet views
+func InstallViews(registry Registry) error {
+	for _, op := range []string{AddView, GetView, ListView, UpdateView, RotateView, RemoveView} {
+		if err := registry.RegisterFactory(op, &ViewFactory{op: op}); err != nil {
+			return errors.WithMessagef(err, "failed registering factory [%s]", op)
+		}
+	}
+	return nil
+}
+
+type walletView struct {
+	op      string
+	request *Request
+}
+
+func (w *walletView) Call(context view.Context) (interface{}, error) {
+	fns := fabric.GetFabricNetworkService(context, w.request.Network)
+	if fns == nil {
+		return nil, errors.Errorf("fabric network [%s] not found", w.request.Network)
+	}
+	wallet := fns.LocalMembership().Wallet()
+
+	switch w.op {
+	case AddView:
+		err := wallet.Add(&fabric.WalletEntry{
+			ID:       w.request.ID,
+			MSPType:  w.request.MSPType,
+			MSPID:    w.request.MSPID,
+			Path:     w.request.Path,
+			Labels:   w.request.Labels,
+			Metadata: w.request.Metadata,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return wallet.Get(w.request.ID)
+	case GetView:
+		e, err := wallet.Get(w.request.ID)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return nil, errors.Errorf("identity [%s] not found in the wallet", w.request.ID)
+		}
+		return e, nil
+	case ListView:
+		return wallet.List(w.request.Labels...)
+	case UpdateView:
+		if err := wallet.Update(w.request.ID, w.request.Labels, w.request.Metadata); err != nil {
+			return nil, err
+		}
+		return wallet.Get(w.request.ID)
+	case RotateView:
+		if err := wallet.Rotate(w.request.ID, w.request.Path); err != nil {
+			return nil, err
+		}
+		return wallet.Get(w.request.ID)
+	case RemoveView:
+		return nil, wallet.Remove(w.request.ID)
+	default:
+		return nil, errors.Errorf("wallet operation [%s] not recognized", w.op)
+	}
+}
+
+type ViewFactory struct {
+	op string
+}
+
+// AdminOnly reserves the wallet views to the admins of the node
+func (f *ViewFactory) AdminOnly() {}
+
+func (f *ViewFactory) NewView(in []byte) (view.View, error) {
+	v := &walletView{op: f.op, request: &Request{}}
+	if len(in) != 0 {
+		if err := json.Unmarshal(in, v.request); err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling input of [%s]", f.op)
+		}
+	}
+	return v, nil
+}
@@ -22,3 +22,8 @@ func (s *SigService) GetVerifier(id view.Identity) (Verifier, error) {
 func (s *SigService) GetSigner(id view.Identity) (Signer, error) {
 	return s.sigService.GetSigner(id)
 }
+
+// IsValid returns an error if the certificate of the passed identity has expired or has been revoked
+func (s *SigService) IsValid(identity view.Identity) error {
+	return s.sigService.IsValid(identity)
+}
@@ -311,6 +311,12 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 	return t.tx.BytesNoTransient()
 }
 
+// BytesNoPvtData marshals the transaction without the private data, keeping only the transient entries
+// the passed function selects
+func (t *Transaction) BytesNoPvtData(keep func(key string) bool) ([]byte, error) {
+	return t.tx.BytesNoPvtData(keep)
+}
+
 func (t *Transaction) FabricNetworkService() *NetworkService {
 	return t.fns
 }
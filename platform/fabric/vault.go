@@ -109,6 +109,31 @@ func (r *RWSet) SetStateMetadata(namespace, key string, metadata map[string][]by
 	return r.rws.SetStateMetadata(namespace, key, metadata)
 }
 
+// SetPrivateState sets the given value for the given key of the given private data collection of the given namespace.
+// Only the hashes of the key and the value appear in the rwset.
+func (r *RWSet) SetPrivateState(namespace, collection, key string, value []byte) error {
+	return r.rws.SetPrivateState(namespace, collection, key, value)
+}
+
+// GetPrivateState returns the value of the given key of the given private data collection of the given namespace
+func (r *RWSet) GetPrivateState(namespace, collection, key string, opts ...GetStateOpt) ([]byte, error) {
+	var o []fdriver.GetStateOpt
+	for _, opt := range opts {
+		o = append(o, fdriver.GetStateOpt(opt))
+	}
+	return r.rws.GetPrivateState(namespace, collection, key, o...)
+}
+
+// DeletePrivateState deletes the given key of the given private data collection of the given namespace
+func (r *RWSet) DeletePrivateState(namespace, collection, key string) error {
+	return r.rws.DeletePrivateState(namespace, collection, key)
+}
+
+// Collections returns the private data collections of the namespace ns this rwset touches
+func (r *RWSet) Collections(ns string) []string {
+	return r.rws.Collections(ns)
+}
+
 func (r *RWSet) GetReadKeyAt(ns string, i int) (string, error) {
 	return r.rws.GetReadKeyAt(ns, i)
 }
@@ -144,6 +169,16 @@ func (r *RWSet) AppendRWSet(raw []byte, nss ...string) error {
 	return r.rws.AppendRWSet(raw, nss...)
 }
 
+// AppendPvtRWSet fills in the private writes of this rwset from the passed marshalled private rwset
+func (r *RWSet) AppendPvtRWSet(raw []byte) error {
+	return r.rws.AppendPvtRWSet(raw)
+}
+
+// PvtBytes returns the marshalled private rwset carrying the private writes of this rwset, nil if there is none
+func (r *RWSet) PvtBytes() ([]byte, error) {
+	return r.rws.PvtBytes()
+}
+
 func (r *RWSet) Bytes() ([]byte, error) {
 	return r.rws.Bytes()
 }
@@ -216,6 +251,12 @@ func (qe *QueryExecutor) GetStateMetadata(namespace, key string) (map[string][]b
 	return qe.qe.GetStateMetadata(namespace, key)
 }
 
+// GetPrivateState returns the value of the given key of the given private data collection of the given namespace,
+// nil if this node does not have it
+func (qe *QueryExecutor) GetPrivateState(namespace, collection, key string) ([]byte, error) {
+	return qe.qe.GetPrivateState(namespace, collection, key)
+}
+
 func (qe *QueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (*ResultsIterator, error) {
 	ri, err := qe.qe.GetStateRangeScanIterator(namespace, startKey, endKey)
 	if err != nil {
@@ -224,6 +265,16 @@ func (qe *QueryExecutor) GetStateRangeScanIterator(namespace string, startKey st
 	return &ResultsIterator{ri: ri}, nil
 }
 
+// GetStateByQuery returns an iterator over the states of the passed namespace whose values, JSON documents,
+// satisfy the passed query. The query follows the syntax of CouchDB Mango queries.
+func (qe *QueryExecutor) GetStateByQuery(namespace string, query string) (*ResultsIterator, error) {
+	ri, err := qe.qe.GetStateByQuery(namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultsIterator{ri: ri}, nil
+}
+
 func (qe *QueryExecutor) Done() {
 	qe.qe.Done()
 }
@@ -330,6 +381,15 @@ func (c *Vault) NewQueryExecutor() (*QueryExecutor, error) {
 	return &QueryExecutor{qe: qe}, nil
 }
 
+// CreateIndex declares a secondary index, named as passed, on the passed fields of the values of the passed namespace.
+// Nested fields are expressed in dot notation.
+func (c *Vault) CreateIndex(namespace string, name string, fields ...string) error {
+	if len(fields) == 0 {
+		return errors.Errorf("index [%s] has no fields", name)
+	}
+	return c.ch.CreateIndex(namespace, &driver.Index{Name: name, Fields: fields})
+}
+
 // NewRWSet returns a RWSet for this ledger.
 // A client may obtain more than one such simulator; they are made unique
 // by way of the supplied txid
@@ -374,3 +434,15 @@ func (c *Vault) StoreTransaction(id string, raw []byte) error {
 func (c *Vault) StoreTransient(id string, tm TransientMap) error {
 	return c.ch.MetadataService().StoreTransient(id, fdriver.TransientMap(tm))
 }
+
+// LoadPvtRWSet returns the private rwset of the endorser transaction with the passed id, as stored by this node.
+// It returns nil if this node does not have the private data of the transaction.
+func (c *Vault) LoadPvtRWSet(txid string) ([]byte, error) {
+	return c.ch.LoadPvtRWSet(txid)
+}
+
+// StorePvtRWSet stores the private writes of the passed private rwset of the committed transaction with the passed id.
+// The private rwset is checked against the hashes found in the rwset of the committed transaction.
+func (c *Vault) StorePvtRWSet(txid string, pvtRWSet []byte) error {
+	return c.ch.StorePvtRWSet(txid, pvtRWSet)
+}
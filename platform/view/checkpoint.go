@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// ErrAbort makes the failure of a resumable view final, the view is not resumed after a restart.
+// Wrap it to give the reason of the failure, e.g. errors.WithMessage(ErrAbort, "invalid input").
+var ErrAbort = driver.ErrAbort
+
+// Checkpoint records the passed step and state as the latest progress of the resumable view
+// running in the passed context. The state is marshalled to json.
+func Checkpoint(context view.Context, step string, state interface{}) error {
+	c, ok := context.(driver.Checkpointer)
+	if !ok {
+		return errors.Errorf("context [%s] does not support checkpoints", context.ID())
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "failed marshalling state for step [%s]", step)
+	}
+	return c.Checkpoint(step, raw)
+}
+
+// LastCheckpoint returns the last step recorded by the resumable view running in the passed context,
+// and unmarshals the associated state into the passed one.
+// The returned step is empty if the view did not record any checkpoint yet, in this case state is left untouched.
+func LastCheckpoint(context view.Context, state interface{}) (string, error) {
+	c, ok := context.(driver.Checkpointer)
+	if !ok {
+		return "", errors.Errorf("context [%s] does not support checkpoints", context.ID())
+	}
+	step, raw, err := c.LastCheckpoint()
+	if err != nil {
+		return "", err
+	}
+	if len(step) == 0 || len(raw) == 0 {
+		return step, nil
+	}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return "", errors.Wrapf(err, "failed unmarshalling state for step [%s]", step)
+	}
+	return step, nil
+}
@@ -9,6 +9,8 @@ package endpoint
 import (
 	"bytes"
 	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -39,22 +41,27 @@ func (r *resolver) GetIdentity() (view.Identity, error) {
 	return r.Id, nil
 }
 
-// NetworkMember is a peer's representation
-type NetworkMember struct {
-	Endpoint         string
-	Metadata         []byte
-	PKIid            []byte
-	InternalEndpoint string
-}
-
+// Discovery finds at runtime the parties that are not among the static resolvers
 type Discovery interface {
-	Peers() []NetworkMember
+	// Endpoints returns the addresses published by the passed party
+	Endpoints(party view.Identity) (map[driver.PortName]string, error)
+	// Identity returns the identity of the party known by the passed label or PKI ID
+	Identity(label string, pkid []byte) (view.Identity, error)
 }
 
+const (
+	bindingPrefix    = "platform.fsc.endpoint.binding"
+	resolutionPrefix = "platform.fsc.endpoint.resolved"
+)
+
 type endpointEntry struct {
 	Endpoints map[driver.PortName]string
 	Ephemeral view.Identity
 	Identity  view.Identity
+	// Origin tells why the binding was created
+	Origin string
+	// Time is when the binding was created
+	Time time.Time
 }
 
 type service struct {
@@ -62,6 +69,11 @@ type service struct {
 	resolvers    []*resolver
 	Discovery    Discovery
 	pkiResolvers []driver.PKIResolver
+
+	// bindingsLock serializes the updates of the bindings and of the resolutions
+	bindingsLock sync.RWMutex
+	// resolved caches the identities the parties resolved to, they are persisted in the kvs when available
+	resolved map[string]view.Identity
 }
 
 // NewService returns a new instance of the view-sdk endpoint service
@@ -70,6 +82,7 @@ func NewService(sp view2.ServiceProvider, discovery Discovery) (*service, error)
 		sp:           sp,
 		Discovery:    discovery,
 		pkiResolvers: []driver.PKIResolver{},
+		resolved:     map[string]view.Identity{},
 	}
 	return er, nil
 }
@@ -84,7 +97,12 @@ func (r *service) Endpoint(party view.Identity) (map[driver.PortName]string, err
 			logger.Debugf("resolving via binding for %s", cursor)
 			ee, err := r.getBinding(cursor.UniqueID())
 			if err != nil {
-				return nil, errors.Wrapf(err, "endpoint not found for identity [%s,%s]", string(cursor), cursor.UniqueID())
+				e, derr := r.discoverEndpoint(cursor)
+				if derr != nil {
+					return nil, errors.Wrapf(err, "endpoint not found for identity [%s,%s]", string(cursor), cursor.UniqueID())
+				}
+				logger.Debugf("endpoint for [%s] discovered at [%s] with ports [%v]", party, cursor, e)
+				return e, nil
 			}
 
 			cursor = ee.Identity
@@ -97,10 +115,6 @@ func (r *service) Endpoint(party view.Identity) (map[driver.PortName]string, err
 	}
 }
 
-var (
-	resolved = make(map[string]view.Identity)
-)
-
 func (r *service) Resolve(party view.Identity) (view.Identity, map[driver.PortName]string, []byte, error) {
 	cursor := party
 	for {
@@ -111,7 +125,15 @@ func (r *service) Resolve(party view.Identity) (view.Identity, map[driver.PortNa
 			logger.Debugf("resolving via binding for %s", cursor)
 			ee, err := r.getBinding(cursor.UniqueID())
 			if err != nil {
-				return nil, nil, nil, errors.Wrapf(err, "endpoint not found for identity [%s,%s]", string(cursor), cursor.UniqueID())
+				e, derr := r.discoverEndpoint(cursor)
+				if derr != nil {
+					return nil, nil, nil, errors.Wrapf(err, "endpoint not found for identity [%s,%s]", string(cursor), cursor.UniqueID())
+				}
+				logger.Debugf("resolved [%s] via discovery to [%s] with ports [%v]", party, cursor, e)
+				if err := r.checkResolution(party, cursor); err != nil {
+					return nil, nil, nil, err
+				}
+				return cursor, e, r.pkiResolve(cursor), nil
 			}
 
 			cursor = ee.Identity
@@ -120,28 +142,96 @@ func (r *service) Resolve(party view.Identity) (view.Identity, map[driver.PortNa
 		}
 
 		logger.Debugf("resolved [%s] to [%s] with ports [%v]", party, cursor, e)
-		alreadyResolved, ok := resolved[party.UniqueID()]
-		if ok && !alreadyResolved.Equal(cursor) {
-			return nil, nil, nil, errors.Errorf("[%s] already resolved to [%s], resolved to [%s] this time", party, alreadyResolved, cursor)
+		if err := r.checkResolution(party, cursor); err != nil {
+			return nil, nil, nil, err
 		}
-		resolved[party.UniqueID()] = cursor
 		return cursor, e, r.pkiResolve(cursor), nil
 	}
 }
 
 func (r *service) Bind(longTerm view.Identity, ephemeral view.Identity) error {
+	return r.BindWithOrigin(longTerm, ephemeral, "")
+}
+
+// BindWithOrigin binds ephemeral to longTerm, recording the passed origin
+func (r *service) BindWithOrigin(longTerm view.Identity, ephemeral view.Identity, origin string) error {
 	e, err := r.Endpoint(longTerm)
 	if err != nil {
 		return errors.Errorf("long term identity not found for identity [%s]", longTerm.UniqueID())
 	}
-	logger.Debugf("bind [%s] to [%s]", ephemeral.String(), longTerm.String())
-	if err := r.putBinding(ephemeral.UniqueID(), &endpointEntry{Endpoints: e, Identity: longTerm, Ephemeral: ephemeral}); err != nil {
+	logger.Debugf("bind [%s] to [%s] with origin [%s]", ephemeral.String(), longTerm.String(), origin)
+	r.bindingsLock.Lock()
+	defer r.bindingsLock.Unlock()
+	if err := r.putBinding(ephemeral.UniqueID(), &endpointEntry{
+		Endpoints: e,
+		Identity:  longTerm,
+		Ephemeral: ephemeral,
+		Origin:    origin,
+		Time:      time.Now(),
+	}); err != nil {
 		return errors.WithMessagef(err, "failed storing binding of [%s]  to [%s]", ephemeral.UniqueID(), longTerm.UniqueID())
 	}
 
 	return nil
 }
 
+// Bindings returns the bindings to the passed identity, all the bindings if the identity is empty
+func (r *service) Bindings(longTerm view.Identity) ([]*driver.Binding, error) {
+	r.bindingsLock.RLock()
+	defer r.bindingsLock.RUnlock()
+
+	it, err := kvs.GetService(r.sp).GetByPartialCompositeID(bindingPrefix, []string{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed scanning bindings")
+	}
+	defer it.Close()
+	var bindings []*driver.Binding
+	for it.HasNext() {
+		entry := &endpointEntry{}
+		if err := it.Next(entry); err != nil {
+			return nil, errors.WithMessage(err, "failed reading binding")
+		}
+		// long term identities are stored as bindings without ephemeral identity
+		if len(entry.Ephemeral) == 0 {
+			continue
+		}
+		if len(longTerm) != 0 && !entry.Identity.Equal(longTerm) {
+			continue
+		}
+		bindings = append(bindings, &driver.Binding{
+			Ephemeral: entry.Ephemeral,
+			LongTerm:  entry.Identity,
+			Origin:    entry.Origin,
+			Time:      entry.Time,
+		})
+	}
+	return bindings, nil
+}
+
+// Unbind revokes the binding of the passed ephemeral identity, and forgets what it resolved to
+func (r *service) Unbind(ephemeral view.Identity) error {
+	r.bindingsLock.Lock()
+	defer r.bindingsLock.Unlock()
+
+	kvss := kvs.GetService(r.sp)
+	k := kvs.CreateCompositeKeyOrPanic(bindingPrefix, []string{ephemeral.UniqueID()})
+	if !kvss.Exists(k) {
+		return errors.Errorf("binding not found for [%s]", ephemeral.UniqueID())
+	}
+	if err := kvss.Delete(k); err != nil {
+		return errors.WithMessagef(err, "failed deleting binding of [%s]", ephemeral.UniqueID())
+	}
+	delete(r.resolved, ephemeral.UniqueID())
+	k = kvs.CreateCompositeKeyOrPanic(resolutionPrefix, []string{ephemeral.UniqueID()})
+	if kvss.Exists(k) {
+		if err := kvss.Delete(k); err != nil {
+			return errors.WithMessagef(err, "failed deleting resolution of [%s]", ephemeral.UniqueID())
+		}
+	}
+	logger.Debugf("unbound [%s]", ephemeral.String())
+	return nil
+}
+
 func (r *service) IsBoundTo(a view.Identity, b view.Identity) bool {
 	for {
 		if a.Equal(b) {
@@ -183,6 +273,15 @@ func (r *service) GetIdentity(endpoint string, pkid []byte) (view.Identity, erro
 			return id, nil
 		}
 	}
+	// ask the discovery service
+	if r.Discovery != nil {
+		id, err := r.Discovery.Identity(endpoint, pkid)
+		if err == nil {
+			logger.Infof("discovered [%s,%s] as %s", endpoint, view.Identity(pkid), id)
+			return id, nil
+		}
+		logger.Debugf("discovery failed for [%s,%s]: [%s]", endpoint, view.Identity(pkid), err)
+	}
 	// ask the msp service
 	id, err := fabric.GetDefaultFNS(r.sp).LocalMembership().GetIdentityByID(endpoint)
 	if err != nil {
@@ -223,6 +322,8 @@ func (r *service) AddPKIResolver(pkiResolver driver.PKIResolver) error {
 }
 
 func (r *service) AddLongTermIdentity(identity view.Identity) error {
+	r.bindingsLock.Lock()
+	defer r.bindingsLock.Unlock()
 	return r.putBinding(identity.String(), &endpointEntry{
 		Identity: identity,
 	})
@@ -248,9 +349,56 @@ func (r *service) rootEndpoint(party view.Identity) (map[driver.PortName]string,
 	return nil, errors.Errorf("endpoint not found for identity %s", party.UniqueID())
 }
 
+func (r *service) discoverEndpoint(party view.Identity) (map[driver.PortName]string, error) {
+	if r.Discovery == nil {
+		return nil, errors.New("discovery not enabled")
+	}
+	return r.Discovery.Endpoints(party)
+}
+
+// checkResolution checks that party always resolves to the same identity
+func (r *service) checkResolution(party view.Identity, resolvedTo view.Identity) error {
+	r.bindingsLock.Lock()
+	defer r.bindingsLock.Unlock()
+
+	kvss := r.kvs()
+	alreadyResolved, ok := r.resolved[party.UniqueID()]
+	k := kvs.CreateCompositeKeyOrPanic(resolutionPrefix, []string{party.UniqueID()})
+	if !ok && kvss != nil && kvss.Exists(k) {
+		if err := kvss.Get(k, &alreadyResolved); err != nil {
+			return errors.WithMessagef(err, "failed loading resolution of [%s]", party)
+		}
+		ok = true
+		r.resolved[party.UniqueID()] = alreadyResolved
+	}
+	if ok {
+		if !alreadyResolved.Equal(resolvedTo) {
+			return errors.Errorf("[%s] already resolved to [%s], resolved to [%s] this time", party, alreadyResolved, resolvedTo)
+		}
+		return nil
+	}
+
+	r.resolved[party.UniqueID()] = resolvedTo
+	if kvss != nil {
+		if err := kvss.Put(k, resolvedTo); err != nil {
+			return errors.WithMessagef(err, "failed storing resolution of [%s]", party)
+		}
+	}
+	return nil
+}
+
+// kvs returns the kvs, if already available
+func (r *service) kvs() *kvs.KVS {
+	kvss, err := r.sp.GetService(&kvs.KVS{})
+	if err != nil {
+		return nil
+	}
+	return kvss.(*kvs.KVS)
+}
+
 func (r *service) putBinding(key string, entry *endpointEntry) error {
 	k := kvs.CreateCompositeKeyOrPanic(
-		"platform.fsc.endpoint.binding",
+		bindingPrefix,
 		[]string{key},
 	)
 	kvss := kvs.GetService(r.sp)
@@ -261,8 +409,11 @@ func (r *service) putBinding(key string, entry *endpointEntry) error {
 }
 
 func (r *service) getBinding(key string) (*endpointEntry, error) {
+	r.bindingsLock.RLock()
+	defer r.bindingsLock.RUnlock()
+
 	k := kvs.CreateCompositeKeyOrPanic(
-		"platform.fsc.endpoint.binding",
+		bindingPrefix,
 		[]string{key},
 	)
 	kvss := kvs.GetService(r.sp)
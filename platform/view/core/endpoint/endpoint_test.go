@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type kvsConfig struct{}
+
+func (f *kvsConfig) GetString(key string) string                       { return "" }
+func (f *kvsConfig) GetDuration(key string) time.Duration              { return 0 }
+func (f *kvsConfig) GetBool(key string) bool                           { return false }
+func (f *kvsConfig) GetStringSlice(key string) []string                { return nil }
+func (f *kvsConfig) IsSet(key string) bool                             { return false }
+func (f *kvsConfig) UnmarshalKey(key string, rawVal interface{}) error { return nil }
+func (f *kvsConfig) ConfigFileUsed() string                            { return "" }
+func (f *kvsConfig) GetPath(key string) string                         { return "" }
+func (f *kvsConfig) TranslatePath(path string) string                  { return "" }
+
+func newService(t *testing.T, sp view2.ServiceProvider) *service {
+	s, err := NewService(sp, nil)
+	assert.NoError(t, err)
+	_, err = s.AddResolver("alice", "", map[string]string{"P2P": "127.0.0.1:2000"}, nil, []byte("alice"))
+	assert.NoError(t, err)
+	_, err = s.AddResolver("bob", "", map[string]string{"P2P": "127.0.0.1:2001"}, nil, []byte("bob"))
+	assert.NoError(t, err)
+	return s
+}
+
+func TestBindings(t *testing.T) {
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+	kvss, err := kvs.New("memory", "_default", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+
+	alice, bob := view.Identity("alice"), view.Identity("bob")
+	s := newService(t, registry)
+	assert.NoError(t, s.Bind(alice, view.Identity("alice-1")))
+	assert.NoError(t, s.BindWithOrigin(alice, view.Identity("alice-2"), "tx1"))
+	assert.NoError(t, s.BindWithOrigin(bob, view.Identity("bob-1"), "tx2"))
+	assert.Error(t, s.Bind(view.Identity("charlie"), view.Identity("charlie-1")))
+
+	bindings, err := s.Bindings(alice)
+	assert.NoError(t, err)
+	assert.Len(t, bindings, 2)
+	origins := map[string]string{}
+	for _, b := range bindings {
+		assert.Equal(t, alice, b.LongTerm)
+		assert.False(t, b.Time.IsZero())
+		origins[string(b.Ephemeral)] = b.Origin
+	}
+	assert.Equal(t, map[string]string{"alice-1": "", "alice-2": "tx1"}, origins)
+	bindings, err = s.Bindings(nil)
+	assert.NoError(t, err)
+	assert.Len(t, bindings, 3)
+
+	// concurrent resolutions agree
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, _, _, err := s.Resolve(view.Identity("alice-2"))
+			assert.NoError(t, err)
+			assert.Equal(t, alice, id)
+		}()
+	}
+	wg.Wait()
+
+	// bindings and resolutions survive a restart
+	s = newService(t, registry)
+	assert.True(t, s.IsBoundTo(view.Identity("bob-1"), bob))
+	assert.NoError(t, s.Bind(bob, view.Identity("alice-2")))
+	_, _, _, err = s.Resolve(view.Identity("alice-2"))
+	assert.Error(t, err)
+
+	// revoked bindings can be bound again
+	assert.NoError(t, s.Unbind(view.Identity("alice-2")))
+	assert.Error(t, s.Unbind(view.Identity("alice-2")))
+	assert.False(t, s.IsBoundTo(view.Identity("alice-2"), alice))
+	assert.NoError(t, s.Bind(bob, view.Identity("alice-2")))
+	id, _, _, err := s.Resolve(view.Identity("alice-2"))
+	assert.NoError(t, err)
+	assert.Equal(t, bob, id)
+	bindings, err = s.Bindings(alice)
+	assert.NoError(t, err)
+	assert.Len(t, bindings, 1)
+}
@@ -7,13 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package id
 
 import (
+	ecdsa2 "crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
+	"sync"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -35,21 +40,46 @@ type SigService interface {
 
 type EndpointService interface {
 	GetIdentity(label string, pkid []byte) (view.Identity, error)
+	Bind(longTerm view.Identity, ephemeral view.Identity) error
+}
+
+// CertValidator checks the validity of certificates, and tracks the expiration of the ones of this node
+type CertValidator interface {
+	// ValidatePEM returns an error if the passed certificate has expired or has been revoked
+	ValidatePEM(raw []byte) error
+	// TrackPEM reports the passed certificate when it gets close to its expiration
+	TrackPEM(label string, raw []byte) error
+}
+
+// KMS gives access to the managed private keys
+type KMS interface {
+	// NewSigner returns a signer for the private key of the passed public key, held in the key store of the
+	// passed identity label
+	NewSigner(label string, keyFile string, pk *ecdsa2.PublicKey) (driver.Signer, error)
 }
 
 type provider struct {
 	configProvider  ConfigProvider
 	sigService      SigService
 	endpointService EndpointService
-	defaultID       view.Identity
-	admins          []view.Identity
+	kms             KMS
+	validator       CertValidator
+
+	lock      sync.RWMutex
+	defaultID view.Identity
+	admins    []view.Identity
 }
 
-func NewProvider(configProvider ConfigProvider, sigService SigService, endpointService EndpointService) *provider {
+// NewProvider returns a new identity provider. If kms is nil, the private key of the default identity is read
+// in plaintext from fsc.identity.key.file. If validator is not nil, the certificate of the default identity is
+// tracked till its expiration, and the renewed certificates are validated before being reloaded.
+func NewProvider(configProvider ConfigProvider, sigService SigService, endpointService EndpointService, kmsService KMS, validator CertValidator) *provider {
 	return &provider{
 		configProvider:  configProvider,
 		sigService:      sigService,
 		endpointService: endpointService,
+		kms:             kmsService,
+		validator:       validator,
 	}
 }
 
@@ -65,7 +95,30 @@ func (p *provider) Load() error {
 	return nil
 }
 
+// Reload loads again the default identity and the admins, once their certificates have been renewed.
+// If the default identity changes, the previous one is bound to it, so that it still resolves to this node.
+// The remote nodes must be given the renewed certificate to authenticate this node with it.
+func (p *provider) Reload() error {
+	previous := p.DefaultIdentity()
+	if err := p.Load(); err != nil {
+		return err
+	}
+	current := p.DefaultIdentity()
+	if previous.Equal(current) {
+		return nil
+	}
+	logger.Infof("default identity renewed")
+	if p.endpointService != nil {
+		if err := p.endpointService.Bind(current, previous); err != nil {
+			return errors.WithMessagef(err, "failed binding previous default identity")
+		}
+	}
+	return nil
+}
+
 func (p *provider) DefaultIdentity() view.Identity {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 	return p.defaultID
 }
 
@@ -78,6 +131,8 @@ func (p *provider) Identity(label string) view.Identity {
 }
 
 func (p *provider) Admins() []view.Identity {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 	return p.admins
 }
 
@@ -86,25 +141,59 @@ func (p *provider) loadDefaultIdentity() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed loading SFC Node Identity")
 	}
+	if p.validator != nil && p.DefaultIdentity() != nil {
+		// do not replace the current identity with an invalid one
+		if err := p.validator.ValidatePEM(defaultID); err != nil {
+			return errors.WithMessagef(err, "invalid renewed default identity")
+		}
+	}
 	id, verifier, err := ecdsa.NewIdentityFromPEMCert(defaultID)
 	if err != nil {
 		return errors.Wrap(err, "failed loading default verifier")
 	}
-	fileCont, err := ioutil.ReadFile(p.configProvider.GetPath("fsc.identity.key.file"))
-	if err != nil {
-		return errors.Wrapf(err, "failed reading file [%s]", fileCont)
-	}
-	signer, err := ecdsa.NewSignerFromPEM(fileCont)
+	signer, err := p.loadDefaultSigner(defaultID)
 	if err != nil {
 		return errors.Wrapf(err, "failed loading default signer")
 	}
 	if err := p.sigService.RegisterSigner(id, signer, verifier); err != nil {
 		return errors.Wrapf(err, "failed registering default identity signer")
 	}
+	if p.validator != nil {
+		if err := p.validator.TrackPEM(kms.NodeIdentityLabel, defaultID); err != nil {
+			logger.Warnf("cannot track expiration of default identity: [%s]", err)
+		}
+	}
+	p.lock.Lock()
 	p.defaultID = defaultID
+	p.lock.Unlock()
 	return nil
 }
 
+func (p *provider) loadDefaultSigner(cert []byte) (driver.Signer, error) {
+	keyFile := p.configProvider.GetPath("fsc.identity.key.file")
+	if p.kms == nil {
+		fileCont, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading file [%s]", keyFile)
+		}
+		return ecdsa.NewSignerFromPEM(fileCont)
+	}
+
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, errors.New("failed decoding default identity certificate")
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing default identity certificate")
+	}
+	pk, ok := c.PublicKey.(*ecdsa2.PublicKey)
+	if !ok {
+		return nil, errors.New("expected *ecdsa.PublicKey")
+	}
+	return p.kms.NewSigner(kms.NodeIdentityLabel, keyFile, pk)
+}
+
 func (p *provider) loadAdminIdentities() error {
 	certs := p.configProvider.GetStringSlice("fsc.admin.certs")
 	var admins []view.Identity
@@ -120,6 +209,8 @@ func (p *provider) loadAdminIdentities() error {
 		admins = append(admins, admin)
 	}
 	logger.Infof("loaded [%d] admin identities", len(admins))
+	p.lock.Lock()
 	p.admins = admins
+	p.lock.Unlock()
 	return nil
 }
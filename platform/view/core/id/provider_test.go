@@ -7,12 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package id_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/mock"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver/file"
 )
 
 func TestLoad(t *testing.T) {
@@ -25,7 +31,7 @@ func TestLoad(t *testing.T) {
 	cp.TranslatePathReturnsOnCall(0, "./testdata/admin/admin.pem")
 	sigService := &mock.SigService{}
 
-	idProvider := id.NewProvider(cp, sigService, nil)
+	idProvider := id.NewProvider(cp, sigService, nil, nil, nil)
 	assert.NoError(t, idProvider.Load(), "failed loading identities")
 
 	raw, err := id.LoadIdentity("./testdata/default/signcerts/default.pem")
@@ -37,3 +43,41 @@ func TestLoad(t *testing.T) {
 	assert.Len(t, idProvider.Admins(), 1)
 	assert.Equal(t, raw, []byte(idProvider.Admins()[0]))
 }
+
+func TestLoadWithKMS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "id")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyPEM, err := ioutil.ReadFile("./testdata/default/keystore/priv_sk")
+	assert.NoError(t, err)
+	encrypted, err := file.Encrypt(keyPEM, []byte("password"), nil)
+	assert.NoError(t, err)
+	keyFile := filepath.Join(dir, "priv_sk")
+	assert.NoError(t, ioutil.WriteFile(keyFile, encrypted, 0600))
+
+	cp := &mock.ConfigProvider{}
+	cp.GetPathReturnsOnCall(0, "./testdata/default/signcerts/default.pem")
+	cp.GetPathReturnsOnCall(1, keyFile)
+	sigService := &mock.SigService{}
+
+	// wrong password
+	kmsService := kms.New(&kms.Config{Default: &driver.Opts{Password: "wrong"}})
+	assert.Error(t, id.NewProvider(cp, sigService, nil, kmsService, nil).Load())
+
+	cp.GetPathReturnsOnCall(2, "./testdata/default/signcerts/default.pem")
+	cp.GetPathReturnsOnCall(3, keyFile)
+	kmsService = kms.New(&kms.Config{
+		Default: &driver.Opts{Password: "wrong"},
+		Identities: map[string]*driver.Opts{
+			kms.NodeIdentityLabel: {Password: "password", RejectPlaintext: true},
+		},
+	})
+	idProvider := id.NewProvider(cp, sigService, nil, kmsService, nil)
+	assert.NoError(t, idProvider.Load())
+	assert.Equal(t, 1, sigService.RegisterSignerCallCount())
+	identity, signer, verifier := sigService.RegisterSignerArgsForCall(0)
+	assert.Equal(t, idProvider.DefaultIdentity(), identity)
+	sigma, err := signer.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify([]byte("hello"), sigma))
+}
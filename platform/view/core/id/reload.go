@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package id
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// ReloadViewID identifies the view factory that reloads the renewed credentials through the view service
+const ReloadViewID = "fsc.identity.reload"
+
+// Reloader loads again credentials that have been renewed
+type Reloader interface {
+	Reload() error
+}
+
+// ReloaderFunc adapts a function to the Reloader interface
+type ReloaderFunc func() error
+
+func (f ReloaderFunc) Reload() error {
+	return f()
+}
+
+// ReloadService reloads the renewed certificates and keys of this node, without restarting it.
+// The platforms add the reloaders of the identities they manage.
+type ReloadService struct {
+	lock      sync.Mutex
+	reloaders []Reloader
+}
+
+func NewReloadService() *ReloadService {
+	return &ReloadService{}
+}
+
+// Add appends the passed reloader, the reloaders run in the order they have been added
+func (r *ReloadService) Add(reloader Reloader) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.reloaders = append(r.reloaders, reloader)
+}
+
+// Reload runs all the reloaders, it stops at the first failure
+func (r *ReloadService) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, reloader := range r.reloaders {
+		if err := reloader.Reload(); err != nil {
+			return errors.WithMessage(err, "failed reloading credentials")
+		}
+	}
+	logger.Infof("reloaded credentials")
+	return nil
+}
+
+// GetReloadService returns the reload service registered in the passed service provider, nil if not found
+func GetReloadService(sp driver.ServiceProvider) *ReloadService {
+	s, err := sp.GetService(&ReloadService{})
+	if err != nil {
+		return nil
+	}
+	return s.(*ReloadService)
+}
+
+type reloadView struct {
+	service *ReloadService
+}
+
+func (r *reloadView) Call(context view.Context) (interface{}, error) {
+	return nil, r.service.Reload()
+}
+
+// ReloadViewFactory creates the views reloading the renewed credentials
+type ReloadViewFactory struct {
+	Service *ReloadService
+}
+
+func (f *ReloadViewFactory) NewView(in []byte) (view.View, error) {
+	return &reloadView{service: f.Service}, nil
+}
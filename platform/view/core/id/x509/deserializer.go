@@ -16,10 +16,18 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+// Deserializer deserializes PEM encoded x509 identities.
+// If Validator is set, the certificates must be within their validity window and not revoked.
 type Deserializer struct {
+	Validator *Validator
 }
 
 func (x *Deserializer) DeserializeVerifier(raw []byte) (driver.Verifier, error) {
+	if x.Validator != nil {
+		if err := x.Validator.ValidatePEM(raw); err != nil {
+			return nil, errors.WithMessage(err, "invalid identity")
+		}
+	}
 	genericPublicKey, err := PemDecodeKey(raw)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed parsing received public key")
@@ -29,6 +37,11 @@ func (x *Deserializer) DeserializeVerifier(raw []byte) (driver.Verifier, error)
 		return nil, errors.New("expected *ecdsa.PublicKey")
 	}
 
+	if x.Validator != nil {
+		if cert, err := PemDecodeCert(raw); err == nil {
+			return &ValidatingVerifier{Verifier: NewVerifier(publicKey), Validator: x.Validator, Cert: cert}, nil
+		}
+	}
 	return NewVerifier(publicKey), nil
 }
 
@@ -0,0 +1,298 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x509
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.id.x509")
+
+const (
+	// DefaultExpiryWarning is how long before their expiration the tracked certificates are reported
+	DefaultExpiryWarning = 30 * 24 * time.Hour
+
+	expiryCheckInterval = time.Hour
+)
+
+// Validator checks that certificates are within their validity window and have not been revoked.
+// The revoked serial numbers come from CRLs grouped by source, such as a local file or a channel
+// configuration, so that the CRLs of a source are replaced when the source changes.
+// The Validator also tracks the certificates of this node, and reports them when they are about to expire.
+type Validator struct {
+	// WarnBefore is how long before their expiration the tracked certificates are reported
+	WarnBefore time.Duration
+	// Now returns the current time
+	Now func() time.Time
+
+	lock sync.RWMutex
+	// revoked maps each source to the revoked serial numbers, indexed by issuer
+	revoked map[string]map[string]map[string]struct{}
+	tracked map[string]*x509.Certificate
+}
+
+func NewValidator() *Validator {
+	return &Validator{
+		WarnBefore: DefaultExpiryWarning,
+		Now:        time.Now,
+		revoked:    map[string]map[string]map[string]struct{}{},
+		tracked:    map[string]*x509.Certificate{},
+	}
+}
+
+// Validate returns an error if the passed certificate is outside its validity window or has been revoked
+func (v *Validator) Validate(cert *x509.Certificate) error {
+	now := v.Now()
+	if now.Before(cert.NotBefore) {
+		return errors.Errorf("certificate [%s] not valid before [%s]", cert.Subject, cert.NotBefore)
+	}
+	if now.After(cert.NotAfter) {
+		return errors.Errorf("certificate [%s] expired at [%s]", cert.Subject, cert.NotAfter)
+	}
+
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	issuer := cert.Issuer.String()
+	serial := cert.SerialNumber.String()
+	for source, revoked := range v.revoked {
+		if _, ok := revoked[issuer][serial]; ok {
+			return errors.Errorf("certificate [%s] revoked by [%s], according to [%s]", cert.Subject, issuer, source)
+		}
+	}
+	return nil
+}
+
+// ValidatePEM validates the PEM encoded certificate in the passed bytes.
+// Other PEM blocks, such as public keys, carry no validity information and are accepted.
+func (v *Validator) ValidatePEM(raw []byte) error {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return errors.New("bytes are not PEM encoded")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.WithMessage(err, "pem bytes are not cert encoded")
+	}
+	return v.Validate(cert)
+}
+
+// SetCRLs replaces the CRLs of the passed source with the passed ones, PEM or DER encoded.
+// If issuers are passed, each CRL must be signed by one of them.
+func (v *Validator) SetCRLs(source string, crls [][]byte, issuers []*x509.Certificate) error {
+	revoked := map[string]map[string]struct{}{}
+	for _, raw := range crls {
+		crl, err := parseCRL(raw)
+		if err != nil {
+			return errors.WithMessagef(err, "failed parsing crl from [%s]", source)
+		}
+		var name pkix.Name
+		name.FillFromRDNSequence(&crl.TBSCertList.Issuer)
+		issuer := name.String()
+		if len(issuers) != 0 && !signedByOneOf(crl, issuer, issuers) {
+			return errors.Errorf("crl of [%s] from [%s] not signed by a known issuer", issuer, source)
+		}
+		if crl.HasExpired(v.Now()) {
+			logger.Warnf("crl of [%s] from [%s] is past its next update [%s]", issuer, source, crl.TBSCertList.NextUpdate)
+		}
+		if revoked[issuer] == nil {
+			revoked[issuer] = map[string]struct{}{}
+		}
+		for _, rc := range crl.TBSCertList.RevokedCertificates {
+			revoked[issuer][rc.SerialNumber.String()] = struct{}{}
+		}
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if len(revoked) == 0 {
+		delete(v.revoked, source)
+		return nil
+	}
+	v.revoked[source] = revoked
+	logger.Debugf("loaded crls of [%d] issuers from [%s]", len(revoked), source)
+	return nil
+}
+
+// LoadCRLs reads the CRLs in the passed file, or in the files of the passed folder.
+// The path is the source of the CRLs, loading the same path again replaces them.
+func (v *Validator) LoadCRLs(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading crls at [%s]", path)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed reading crls at [%s]", path)
+		}
+		files = nil
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var crls [][]byte
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed reading crl [%s]", file)
+		}
+		crls = append(crls, raw)
+	}
+	return v.SetCRLs(path, crls, nil)
+}
+
+// Track reports the passed certificate, that belongs to this node, when it gets close to its expiration.
+// Tracking again the same label replaces the certificate, after it has been renewed for instance.
+func (v *Validator) Track(label string, cert *x509.Certificate) {
+	v.lock.Lock()
+	v.tracked[label] = cert
+	v.lock.Unlock()
+
+	v.checkExpiry(label, cert)
+}
+
+// Untrack stops tracking the certificate with the passed label
+func (v *Validator) Untrack(label string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	delete(v.tracked, label)
+}
+
+// TrackPEM tracks the PEM encoded certificate in the passed bytes
+func (v *Validator) TrackPEM(label string, raw []byte) error {
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.Errorf("no certificate found for [%s]", label)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.WithMessagef(err, "failed parsing certificate of [%s]", label)
+	}
+	v.Track(label, cert)
+	return nil
+}
+
+// Expiring returns the labels of the tracked certificates that expire within WarnBefore, or have expired
+func (v *Validator) Expiring() []string {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	var res []string
+	for label, cert := range v.tracked {
+		if v.expiresSoon(cert) {
+			res = append(res, label)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Start checks the tracked certificates periodically, until the passed context is done
+func (v *Validator) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(expiryCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				v.lock.RLock()
+				tracked := make(map[string]*x509.Certificate, len(v.tracked))
+				for label, cert := range v.tracked {
+					tracked[label] = cert
+				}
+				v.lock.RUnlock()
+				for label, cert := range tracked {
+					v.checkExpiry(label, cert)
+				}
+			}
+		}
+	}()
+}
+
+func (v *Validator) checkExpiry(label string, cert *x509.Certificate) {
+	switch {
+	case v.Now().After(cert.NotAfter):
+		logger.Errorf("certificate of [%s] expired at [%s], it must be renewed", label, cert.NotAfter)
+	case v.expiresSoon(cert):
+		logger.Warnf("certificate of [%s] expires at [%s], it should be renewed", label, cert.NotAfter)
+	}
+}
+
+func (v *Validator) expiresSoon(cert *x509.Certificate) bool {
+	return v.Now().Add(v.WarnBefore).After(cert.NotAfter)
+}
+
+func signedByOneOf(crl *pkix.CertificateList, issuer string, issuers []*x509.Certificate) bool {
+	for _, cert := range issuers {
+		if cert.Subject.String() == issuer && cert.CheckCRLSignature(crl) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCRL(raw []byte) (*pkix.CertificateList, error) {
+	if block, _ := pem.Decode(raw); block != nil {
+		if block.Type != "X509 CRL" {
+			return nil, errors.Errorf("bad type %s, expected 'X509 CRL'", block.Type)
+		}
+		raw = block.Bytes
+	}
+	return x509.ParseDERCRL(raw)
+}
+
+// CertValidator validates certificates, Validator is its default implementation
+type CertValidator interface {
+	Validate(cert *x509.Certificate) error
+}
+
+// ValidatingVerifier validates the certificate of the signer before each verification.
+// Verifiers are cached once deserialized, this keeps them from outliving the expiration or revocation of the certificate.
+type ValidatingVerifier struct {
+	driver.Verifier
+	Validator CertValidator
+	Cert      *x509.Certificate
+}
+
+func (v *ValidatingVerifier) Verify(message, sigma []byte) error {
+	if err := v.Validator.Validate(v.Cert); err != nil {
+		return errors.WithMessage(err, "invalid identity")
+	}
+	return v.Verifier.Verify(message, sigma)
+}
+
+// GetValidator returns the certificate validator registered in the passed service provider, nil if not found
+func GetValidator(sp driver.ServiceProvider) *Validator {
+	s, err := sp.GetService(&Validator{})
+	if err != nil {
+		return nil
+	}
+	return s.(*Validator)
+}
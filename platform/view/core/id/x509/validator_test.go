@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x509_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/stretchr/testify/assert"
+
+	x5092 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+)
+
+type ca struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCA(t *testing.T, name string) *ca {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	assert.NoError(t, err)
+	return &ca{cert: cert, key: key}
+}
+
+func (c *ca) issue(t *testing.T, serial int64, notBefore, notAfter time.Time) *x509.Certificate {
+	cert, _ := c.issueWithKey(t, serial, notBefore, notAfter)
+	return cert
+}
+
+func (c *ca) issueWithKey(t *testing.T, serial int64, notBefore, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func (c *ca) crl(t *testing.T, serials ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+	raw, err := c.cert.CreateCRL(rand.Reader, c.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: raw})
+}
+
+func TestValidityWindow(t *testing.T) {
+	ca := newCA(t, "ca")
+	v := x5092.NewValidator()
+
+	assert.NoError(t, v.Validate(ca.issue(t, 2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))))
+	assert.Error(t, v.Validate(ca.issue(t, 3, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))))
+	assert.Error(t, v.Validate(ca.issue(t, 4, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))))
+
+	cert := ca.issue(t, 5, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	assert.NoError(t, v.ValidatePEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	assert.NoError(t, v.ValidatePEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("key")})))
+	assert.Error(t, v.ValidatePEM([]byte("not pem")))
+}
+
+func TestRevocation(t *testing.T) {
+	ca := newCA(t, "ca")
+	other := newCA(t, "other")
+	v := x5092.NewValidator()
+
+	revoked := ca.issue(t, 2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	valid := ca.issue(t, 3, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	// the crl must be signed by one of the passed issuers
+	assert.Error(t, v.SetCRLs("channel", [][]byte{ca.crl(t, 2)}, []*x509.Certificate{other.cert}))
+	assert.NoError(t, v.Validate(revoked))
+
+	assert.NoError(t, v.SetCRLs("channel", [][]byte{ca.crl(t, 2)}, []*x509.Certificate{ca.cert}))
+	assert.Error(t, v.Validate(revoked))
+	assert.NoError(t, v.Validate(valid))
+
+	// the same serial number from another issuer is not revoked
+	assert.NoError(t, v.Validate(other.issue(t, 2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))))
+
+	// an update of the source replaces its crls
+	assert.NoError(t, v.SetCRLs("channel", nil, nil))
+	assert.NoError(t, v.Validate(revoked))
+
+	// crls from a folder
+	dir, err := ioutil.TempDir("", "crls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca.crl"), ca.crl(t, 3), 0600))
+	assert.NoError(t, v.LoadCRLs(dir))
+	assert.NoError(t, v.Validate(revoked))
+	assert.Error(t, v.Validate(valid))
+	assert.Error(t, v.LoadCRLs(filepath.Join(dir, "missing")))
+}
+
+func TestExpiring(t *testing.T) {
+	ca := newCA(t, "ca")
+	v := x5092.NewValidator()
+	v.WarnBefore = 24 * time.Hour
+
+	v.Track("fresh", ca.issue(t, 2, time.Now().Add(-time.Hour), time.Now().Add(48*time.Hour)))
+	v.Track("expiring", ca.issue(t, 3, time.Now().Add(-time.Hour), time.Now().Add(time.Hour)))
+	v.Track("expired", ca.issue(t, 4, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)))
+	assert.Equal(t, []string{"expired", "expiring"}, v.Expiring())
+
+	// a renewed certificate replaces the previous one
+	v.Track("expiring", ca.issue(t, 5, time.Now().Add(-time.Hour), time.Now().Add(48*time.Hour)))
+	v.Untrack("expired")
+	assert.Empty(t, v.Expiring())
+}
+
+func TestCachedVerifierRevocation(t *testing.T) {
+	ca := newCA(t, "ca")
+	v := x5092.NewValidator()
+	des, err := sig.NewMultiplexDeserializer(nil)
+	assert.NoError(t, err)
+	des.AddDeserializer(&x5092.Deserializer{Validator: v})
+	sigService := sig.NewSignService(registry2.New(), des)
+
+	cert, key := ca.issueWithKey(t, 2, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	identity := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	msg := []byte("hello")
+	dgst := sha256.Sum256(msg)
+	r, sv, err := ecdsa.Sign(rand.Reader, key, dgst[:])
+	assert.NoError(t, err)
+	sv, _, err = x5092.ToLowS(&key.PublicKey, sv)
+	assert.NoError(t, err)
+	sigma, err := utils.MarshalECDSASignature(r, sv)
+	assert.NoError(t, err)
+
+	verifier, err := sigService.GetVerifier(identity)
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify(msg, sigma))
+
+	// the certificate is revoked after its verifier has been cached
+	assert.NoError(t, v.SetCRLs("channel", [][]byte{ca.crl(t, 2)}, []*x509.Certificate{ca.cert}))
+	verifier, err = sigService.GetVerifier(identity)
+	assert.NoError(t, err)
+	assert.Error(t, verifier.Verify(msg, sigma))
+
+	// and it expires
+	assert.NoError(t, v.SetCRLs("channel", nil, nil))
+	assert.NoError(t, verifier.Verify(msg, sigma))
+	v.Now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	assert.Error(t, verifier.Verify(msg, sigma))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package manager
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const checkpointPrefix = "view-checkpoint"
+
+// Checkpoint records the progress of a resumable view execution.
+// It contains all the information needed to re-create the view and its context after a restart.
+type Checkpoint struct {
+	ContextID string
+	FactoryID string
+	Input     []byte
+	Identity  view.Identity
+	Step      string
+	State     []byte
+}
+
+func checkpointKey(contextID string) string {
+	return kvs.CreateCompositeKeyOrPanic(checkpointPrefix, []string{contextID})
+}
+
+// getKVS returns the KVS registered in the passed service provider, nil if none is available
+func getKVS(sp driver.ServiceProvider) *kvs.KVS {
+	s, err := sp.GetService(&kvs.KVS{})
+	if err != nil {
+		return nil
+	}
+	return s.(*kvs.KVS)
+}
+
+// Checkpoint stores the passed step and state as the latest progress of this context.
+// It fails if the context was not initiated as resumable.
+func (ctx *ctx) Checkpoint(step string, state []byte) error {
+	ctx.checkpointLock.Lock()
+	defer ctx.checkpointLock.Unlock()
+
+	if ctx.checkpoint == nil {
+		return errors.Errorf("context [%s] is not resumable", ctx.id)
+	}
+	kvss := getKVS(ctx.sp)
+	if kvss == nil {
+		return errors.Errorf("no kvs available to checkpoint context [%s]", ctx.id)
+	}
+
+	ctx.checkpoint.Step = step
+	ctx.checkpoint.State = state
+	if err := kvss.Put(checkpointKey(ctx.id), ctx.checkpoint); err != nil {
+		return errors.WithMessagef(err, "failed storing checkpoint for context [%s]", ctx.id)
+	}
+	logger.Debugf("context [%s] checkpointed at step [%s]", ctx.id, step)
+	return nil
+}
+
+// LastCheckpoint returns the last step and state recorded for this context.
+// The step is empty if no checkpoint has been recorded yet.
+func (ctx *ctx) LastCheckpoint() (string, []byte, error) {
+	ctx.checkpointLock.Lock()
+	defer ctx.checkpointLock.Unlock()
+
+	if ctx.checkpoint == nil {
+		return "", nil, errors.Errorf("context [%s] is not resumable", ctx.id)
+	}
+	return ctx.checkpoint.Step, ctx.checkpoint.State, nil
+}
+
+// InitiateResumableView instantiates the view bound to the passed factory id and runs it in a new context whose
+// progress is recorded in the KVS. If the node stops before the view terminates, the view is re-created
+// from the same factory and input, and run again, when the manager starts.
+func (cm *manager) InitiateResumableView(fid string, in []byte) (interface{}, error) {
+	return cm.InitiateResumableViewWithIdentity(fid, in, cm.me())
+}
+
+func (cm *manager) InitiateResumableViewWithIdentity(fid string, in []byte, id view.Identity) (interface{}, error) {
+	kvss := getKVS(cm.sp)
+	if kvss == nil {
+		return nil, errors.Errorf("no kvs available to initiate resumable view [%s]", fid)
+	}
+
+	f, err := cm.NewView(fid, in)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed instantiating view [%s]", fid)
+	}
+	ctx, span := tracing.Start(cm.getCtx(), cm.sp, getIdentifier(f), trace.WithAttributes(attribute.String(roleAttribute, initiatorRole)))
+	viewContext, err := NewContextForInitiator(ctx, cm.sp, GetCommLayer(cm.sp), driver.GetEndpointService(cm.sp), id, f)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	viewContext.checkpoint = &Checkpoint{
+		ContextID: viewContext.ID(),
+		FactoryID: fid,
+		Input:     in,
+		Identity:  id,
+	}
+	if err := kvss.Put(checkpointKey(viewContext.ID()), viewContext.checkpoint); err != nil {
+		err = errors.WithMessagef(err, "failed storing checkpoint for context [%s]", viewContext.ID())
+		tracing.End(span, err)
+		return nil, err
+	}
+
+	return cm.runResumable(viewContext, f, span)
+}
+
+// resume re-creates and runs, in the background, all the resumable views whose execution did not terminate.
+// The views whose factory is not registered yet are resumed as soon as their factory gets registered.
+func (cm *manager) resume() {
+	kvss := getKVS(cm.sp)
+	if kvss == nil {
+		logger.Debugf("no kvs available, no resumable views to restore")
+		return
+	}
+
+	it, err := kvss.GetByPartialCompositeID(checkpointPrefix, []string{})
+	if err != nil {
+		logger.Errorf("failed loading checkpoints [%s]", err)
+		return
+	}
+	var checkpoints []*Checkpoint
+	for it.HasNext() {
+		checkpoint := &Checkpoint{}
+		if err := it.Next(checkpoint); err != nil {
+			logger.Errorf("failed loading checkpoint [%s]", err)
+			continue
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := it.Close(); err != nil {
+		logger.Warningf("failed closing checkpoint iterator [%s]", err)
+	}
+
+	for _, checkpoint := range checkpoints {
+		cm.factoriesSync.Lock()
+		factory, ok := cm.factories[checkpoint.FactoryID]
+		if !ok {
+			logger.Infof("factory [%s] not registered yet, resume context [%s] once it is", checkpoint.FactoryID, checkpoint.ContextID)
+			cm.pending[checkpoint.FactoryID] = append(cm.pending[checkpoint.FactoryID], checkpoint)
+		}
+		cm.factoriesSync.Unlock()
+		if ok {
+			cm.resumeCheckpoint(checkpoint, factory)
+		}
+	}
+}
+
+// resumeCheckpoint re-creates the view of the passed checkpoint from the passed factory and runs it in the background
+func (cm *manager) resumeCheckpoint(checkpoint *Checkpoint, factory driver.Factory) {
+	logger.Infof("resuming view [%s] in context [%s] from step [%s]", checkpoint.FactoryID, checkpoint.ContextID, checkpoint.Step)
+	f, err := newView(factory, checkpoint.Input)
+	if err != nil {
+		// the factory rejects the input of the view, the view can never be resumed
+		logger.Errorf("failed re-creating view [%s] for context [%s], discarding checkpoint [%s]", checkpoint.FactoryID, checkpoint.ContextID, err)
+		if err := getKVS(cm.sp).Delete(checkpointKey(checkpoint.ContextID)); err != nil {
+			logger.Errorf("failed deleting checkpoint for context [%s] [%s]", checkpoint.ContextID, err)
+		}
+		return
+	}
+	ctx, span := tracing.Start(cm.getCtx(), cm.sp, getIdentifier(f), trace.WithAttributes(attribute.String(roleAttribute, initiatorRole)))
+	viewContext, err := NewContext(ctx, cm.sp, checkpoint.ContextID, GetCommLayer(cm.sp), driver.GetEndpointService(cm.sp), checkpoint.Identity, nil, nil)
+	if err != nil {
+		tracing.End(span, err)
+		logger.Errorf("failed re-creating context [%s] [%s]", checkpoint.ContextID, err)
+		return
+	}
+	viewContext.initiator = f
+	viewContext.checkpoint = checkpoint
+
+	go func() {
+		if _, err := cm.runResumable(viewContext, f, span); err != nil {
+			logger.Errorf("resumed view in context [%s] failed [%s]", viewContext.ID(), err)
+		}
+	}()
+}
+
+// runResumable runs the passed view, traced by the passed span.
+// The checkpoint of the view is removed once the view succeeds, or fails with driver.ErrAbort.
+// On any other failure, e.g. the node shutting down, the checkpoint is kept and the view is resumed at the next start.
+func (cm *manager) runResumable(viewContext *ctx, f view.View, span trace.Span) (interface{}, error) {
+	wrappedContext := &wrappedContext{ctx: viewContext}
+	cm.contextsSync.Lock()
+	cm.contexts[wrappedContext.ID()] = wrappedContext
+	cm.contextsSync.Unlock()
+
+	logger.Debugf("[%s] InitiateResumableView [view:%s], [ContextID:%s]", viewContext.me, getIdentifier(f), wrappedContext.ID())
+	span.SetAttributes(attribute.String(contextIDAttribute, wrappedContext.ID()))
+	start := time.Now()
+	res, err := wrappedContext.RunView(f)
+	cm.observe(f, initiatorRole, start, err)
+	tracing.End(span, err)
+	if err == nil || errors.Cause(err) == driver.ErrAbort {
+		if err2 := getKVS(cm.sp).Delete(checkpointKey(wrappedContext.ID())); err2 != nil {
+			logger.Errorf("failed deleting checkpoint for context [%s] [%s]", wrappedContext.ID(), err2)
+		}
+	}
+	if err != nil {
+		logger.Debugf("[%s] InitiateResumableView [view:%s], [ContextID:%s] failed [%s]", viewContext.me, getIdentifier(f), wrappedContext.ID(), err)
+		return nil, err
+	}
+	logger.Debugf("[%s] InitiateResumableView [view:%s], [ContextID:%s] terminated", viewContext.me, getIdentifier(f), wrappedContext.ID())
+	return res, nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/manager"
+	mock2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/manager/mock"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver/mock"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type kvsConfig struct{}
+
+func (f *kvsConfig) GetString(key string) string                       { return "" }
+func (f *kvsConfig) GetDuration(key string) time.Duration              { return 0 }
+func (f *kvsConfig) GetBool(key string) bool                           { return false }
+func (f *kvsConfig) GetStringSlice(key string) []string                { return nil }
+func (f *kvsConfig) IsSet(key string) bool                             { return false }
+func (f *kvsConfig) UnmarshalKey(key string, rawVal interface{}) error { return nil }
+func (f *kvsConfig) ConfigFileUsed() string                            { return "" }
+func (f *kvsConfig) GetPath(key string) string                         { return "" }
+func (f *kvsConfig) TranslatePath(path string) string                  { return "" }
+
+type progress struct {
+	Counter int
+}
+
+type ResumableView struct {
+	checkpointed chan struct{}
+	crash        chan struct{}
+	resumed      chan string
+}
+
+func (r *ResumableView) Call(context view.Context) (interface{}, error) {
+	state := &progress{}
+	step, err := view2.LastCheckpoint(context, state)
+	if err != nil {
+		return nil, err
+	}
+	if len(step) == 0 {
+		if err := view2.Checkpoint(context, "first", &progress{Counter: 1}); err != nil {
+			return nil, err
+		}
+		r.checkpointed <- struct{}{}
+		// simulate a crash, the view does not terminate until the node is gone
+		<-r.crash
+		return nil, errors.New("crashed")
+	}
+	r.resumed <- step
+	return state.Counter, nil
+}
+
+type ResumableFactory struct {
+	view view.View
+}
+
+func (r *ResumableFactory) NewView(in []byte) (view.View, error) {
+	return r.view, nil
+}
+
+type ResumableManager interface {
+	RegisterFactory(id string, factory driver.Factory) error
+	InitiateResumableView(fid string, in []byte) (interface{}, error)
+	Start(ctx context.Context)
+}
+
+func newResumableManager(t *testing.T, kvss *kvs.KVS) ResumableManager {
+	registry := registry2.New()
+	idProvider := &mock.IdentityProvider{}
+	idProvider.DefaultIdentityReturns([]byte("alice"))
+	assert.NoError(t, registry.RegisterService(idProvider))
+	commLayer := &mock2.CommLayer{}
+	commLayer.MasterSessionReturns(nil, errors.New("no master session"))
+	assert.NoError(t, registry.RegisterService(commLayer))
+	assert.NoError(t, registry.RegisterService(&mock.EndpointService{}))
+	assert.NoError(t, registry.RegisterService(kvss))
+	return manager.New(registry)
+}
+
+func TestResumableView(t *testing.T) {
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+	kvss, err := kvs.New("memory", "_default", registry)
+	assert.NoError(t, err)
+
+	v := &ResumableView{checkpointed: make(chan struct{}, 1), crash: make(chan struct{}), resumed: make(chan string, 1)}
+
+	// first run, the view stops after the first checkpoint
+	m := newResumableManager(t, kvss)
+	assert.NoError(t, m.RegisterFactory("resumable", &ResumableFactory{view: v}))
+	crashed := make(chan struct{})
+	go func() {
+		defer close(crashed)
+		_, _ = m.InitiateResumableView("resumable", []byte("input"))
+	}()
+	select {
+	case <-v.checkpointed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("view did not checkpoint")
+	}
+
+	// restart, the factory is registered once the node is started, as nodes do,
+	// then the view must be resumed from the first checkpoint
+	m = newResumableManager(t, kvss)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx)
+	assert.Equal(t, 1, countCheckpoints(t, kvss))
+	assert.NoError(t, m.RegisterFactory("resumable", &ResumableFactory{view: v}))
+	select {
+	case step := <-v.resumed:
+		assert.Equal(t, "first", step)
+	case <-time.After(10 * time.Second):
+		t.Fatal("view was not resumed")
+	}
+	close(v.crash)
+	<-crashed
+
+	// once terminated, no view is left to resume
+	assert.Eventually(t, func() bool { return countCheckpoints(t, kvss) == 0 }, 10*time.Second, 100*time.Millisecond)
+}
+
+type FailingView struct {
+	err error
+}
+
+func (f *FailingView) Call(context view.Context) (interface{}, error) {
+	if err := view2.Checkpoint(context, "first", &progress{Counter: 1}); err != nil {
+		return nil, err
+	}
+	return nil, f.err
+}
+
+func TestResumableViewFailure(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		err         error
+		checkpoints int
+	}{
+		{name: "success", checkpoints: 0},
+		{name: "failure", err: errors.New("node shutting down"), checkpoints: 1},
+		{name: "abort", err: errors.WithMessage(view2.ErrAbort, "invalid input"), checkpoints: 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			registry := registry2.New()
+			assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+			kvss, err := kvs.New("memory", "_default", registry)
+			assert.NoError(t, err)
+
+			m := newResumableManager(t, kvss)
+			assert.NoError(t, m.RegisterFactory("failing", &ResumableFactory{view: &FailingView{err: test.err}}))
+			_, err = m.InitiateResumableView("failing", []byte("input"))
+			assert.Equal(t, test.err, err)
+			// the checkpoint is kept unless the view succeeded or aborted
+			assert.Equal(t, test.checkpoints, countCheckpoints(t, kvss))
+		})
+	}
+}
+
+func countCheckpoints(t *testing.T, kvss *kvs.KVS) int {
+	it, err := kvss.GetByPartialCompositeID("view-checkpoint", []string{})
+	assert.NoError(t, err)
+	defer it.Close()
+	n := 0
+	for it.HasNext() {
+		assert.NoError(t, it.Next(&manager.Checkpoint{}))
+		n++
+	}
+	return n
+}
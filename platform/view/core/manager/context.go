@@ -19,6 +19,11 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+// contextualSession is implemented by the sessions that carry the span of the view using them to the remote party
+type contextualSession interface {
+	SetContext(ctx context.Context)
+}
+
 type ctx struct {
 	context        context.Context
 	sp             driver.ServiceProvider
@@ -32,6 +37,9 @@ type ctx struct {
 
 	sessionsLock sync.RWMutex
 	sessions     map[string]view.Session
+
+	checkpointLock sync.Mutex
+	checkpoint     *Checkpoint
 }
 
 func NewContextForInitiator(context context.Context, sp driver.ServiceProvider, sessionFactory SessionFactory, resolver driver.EndpointService, party view.Identity, initiator view.View) (*ctx, error) {
@@ -201,7 +209,12 @@ func (ctx *ctx) newSession(view view.View, contextID string, party view.Identity
 	if err != nil {
 		return nil, err
 	}
-	return ctx.sessionFactory.NewSession(getIdentifier(view), contextID, endpoints[driver.P2PPort], pkid)
+	s, err := ctx.sessionFactory.NewSession(getIdentifier(view), contextID, endpoints[driver.P2PPort], pkid)
+	if err != nil {
+		return nil, err
+	}
+	ctx.bindSession(s)
+	return s, nil
 }
 
 func (ctx *ctx) newSessionByID(sessionID, contextID string, party view.Identity) (view.Session, error) {
@@ -209,5 +222,17 @@ func (ctx *ctx) newSessionByID(sessionID, contextID string, party view.Identity)
 	if err != nil {
 		return nil, err
 	}
-	return ctx.sessionFactory.NewSessionWithID(sessionID, contextID, endpoints[driver.P2PPort], pkid, nil, nil)
+	s, err := ctx.sessionFactory.NewSessionWithID(sessionID, contextID, endpoints[driver.P2PPort], pkid, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx.bindSession(s)
+	return s, nil
+}
+
+// bindSession lets the passed session propagate the span of this context, if the session supports it
+func (ctx *ctx) bindSession(s view.Session) {
+	if cs, ok := s.(contextualSession); ok && ctx.context != nil {
+		cs.SetContext(ctx.context)
+	}
 }
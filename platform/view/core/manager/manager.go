@@ -10,17 +10,29 @@ import (
 	"context"
 	"reflect"
 	"runtime/debug"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
 var logger = flogging.MustGetLogger("view-sdk.manager")
 
+const (
+	// attributes of the spans of the views
+	roleAttribute      = "fsc.view.role"
+	contextIDAttribute = "fsc.view.context"
+)
+
 type viewEntry struct {
 	View      view.View
 	ID        view.Identity
@@ -40,6 +52,10 @@ type manager struct {
 	views      map[string][]*viewEntry
 	initiators map[string]string
 	factories  map[string]driver.Factory
+	// pending holds, by factory id, the checkpoints waiting for their factory to be registered
+	pending map[string][]*Checkpoint
+
+	metrics *Metrics
 }
 
 func New(serviceProvider driver.ServiceProvider) *manager {
@@ -50,6 +66,9 @@ func New(serviceProvider driver.ServiceProvider) *manager {
 		views:      map[string][]*viewEntry{},
 		initiators: map[string]string{},
 		factories:  map[string]driver.Factory{},
+		pending:    map[string][]*Checkpoint{},
+
+		metrics: NewMetrics(operations.GetMetricsProvider(serviceProvider)),
 	}
 }
 
@@ -60,25 +79,43 @@ func (cm *manager) GetService(typ reflect.Type) (interface{}, error) {
 func (cm *manager) RegisterFactory(id string, factory driver.Factory) error {
 	logger.Debugf("Register View Factory [%s,%t]", id, factory)
 	cm.factoriesSync.Lock()
-	defer cm.factoriesSync.Unlock()
 	cm.factories[id] = factory
+	pending := cm.pending[id]
+	delete(cm.pending, id)
+	cm.factoriesSync.Unlock()
+
+	// resume the views that were waiting for this factory
+	for _, checkpoint := range pending {
+		cm.resumeCheckpoint(checkpoint, factory)
+	}
 	return nil
 }
 
-func (cm *manager) NewView(id string, in []byte) (f view.View, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			logger.Errorf("new view triggered panic: %s\n%s\n", r, debug.Stack())
-			err = errors.Errorf("failed creating view [%s]", r)
-		}
-	}()
+// IsAdminView returns true if the factory bound to the passed id is reserved to the admins of the node
+func (cm *manager) IsAdminView(id string) bool {
+	cm.factoriesSync.RLock()
+	defer cm.factoriesSync.RUnlock()
+	_, ok := cm.factories[id].(driver.AdminFactory)
+	return ok
+}
 
+func (cm *manager) NewView(id string, in []byte) (view.View, error) {
 	cm.factoriesSync.RLock()
 	factory, ok := cm.factories[id]
 	cm.factoriesSync.RUnlock()
 	if !ok {
 		return nil, errors.Errorf("no factory found for id [%s]", id)
 	}
+	return newView(factory, in)
+}
+
+func newView(factory driver.Factory, in []byte) (f view.View, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("new view triggered panic: %s\n%s\n", r, debug.Stack())
+			err = errors.Errorf("failed creating view [%s]", r)
+		}
+	}()
 	return factory.NewView(in)
 }
 
@@ -127,8 +164,10 @@ func (cm *manager) InitiateViewWithIdentity(view view.View, id view.Identity) (i
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx, span := tracing.Start(ctx, cm.sp, getIdentifier(view), trace.WithAttributes(attribute.String(roleAttribute, initiatorRole)))
 	viewContext, err := NewContextForInitiator(ctx, cm.sp, GetCommLayer(cm.sp), driver.GetEndpointService(cm.sp), id, view)
 	if err != nil {
+		tracing.End(span, err)
 		return nil, err
 	}
 	wrappedContext := &wrappedContext{ctx: viewContext}
@@ -137,7 +176,11 @@ func (cm *manager) InitiateViewWithIdentity(view view.View, id view.Identity) (i
 	cm.contextsSync.Unlock()
 
 	logger.Debugf("[%s] InitiateView [view:%s], [ContextID:%s]", id, getIdentifier(view), wrappedContext.ID())
+	span.SetAttributes(attribute.String(contextIDAttribute, wrappedContext.ID()))
+	start := time.Now()
 	res, err := wrappedContext.RunView(view)
+	cm.observe(view, initiatorRole, start, err)
+	tracing.End(span, err)
 	if err != nil {
 		logger.Debugf("[%s] InitiateView [view:%s], [ContextID:%s] failed [%s]", id, getIdentifier(view), wrappedContext.ID(), err)
 		return nil, err
@@ -176,6 +219,7 @@ func (cm *manager) Start(ctx context.Context) {
 	cm.contextsSync.Lock()
 	cm.ctx = ctx
 	cm.contextsSync.Unlock()
+	cm.resume()
 	session, err := GetCommLayer(cm.sp).MasterSession()
 	if err != nil {
 		return
@@ -220,6 +264,17 @@ func (cm *manager) GetIdentifier(f view.View) string {
 	return getIdentifier(f)
 }
 
+// unknownCallersResponder is implemented by the responders that serve the parties the endpoint service
+// cannot resolve yet, like the registry of the discovery service
+type unknownCallersResponder interface {
+	AcceptsUnknownCallers() bool
+}
+
+func acceptsUnknownCallers(responder view.View) bool {
+	r, ok := responder.(unknownCallersResponder)
+	return ok && r.AcceptsUnknownCallers()
+}
+
 func (cm *manager) respond(responder view.View, id view.Identity, msg *view.Message) (ctx view.Context, res interface{}, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -230,27 +285,46 @@ func (cm *manager) respond(responder view.View, id view.Identity, msg *view.Mess
 
 	logger.Debugf("[%s] Respond [from:%s], [sessionID:%s], [contextID:%s], [view:%s]", id, msg.FromEndpoint, msg.SessionID, msg.ContextID, getIdentifier(responder))
 
+	// the responder runs as a child of the span of the remote view, if any
+	spanCtx, span := tracing.Start(
+		tracing.Extract(cm.getCtx(), msg.TraceParent, msg.TraceState),
+		cm.sp,
+		getIdentifier(responder),
+		trace.WithAttributes(attribute.String(roleAttribute, responderRole), attribute.String(contextIDAttribute, msg.ContextID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// get context
-	ctx, err = cm.newContext(id, msg)
+	ctx, err = cm.newContext(spanCtx, id, msg, acceptsUnknownCallers(responder))
 	if err != nil {
 		return nil, nil, errors.WithMessagef(err, "failed getting context for [%s,%s,%v]", msg.ContextID, id, msg)
 	}
 
 	// run view
+	start := time.Now()
 	res, err = ctx.RunView(responder)
+	cm.observe(responder, responderRole, start, err)
 	if err != nil {
 		logger.Debugf("[%s] Respond Failure [from:%s], [sessionID:%s], [contextID:%s] [%s]\n", id, msg.FromEndpoint, msg.SessionID, msg.ContextID, err)
 	}
 	return ctx, res, err
 }
 
-func (cm *manager) newContext(id view.Identity, msg *view.Message) (view.Context, error) {
+func (cm *manager) newContext(parent context.Context, id view.Identity, msg *view.Message, unknownCallers bool) (view.Context, error) {
 	cm.contextsSync.Lock()
 	defer cm.contextsSync.Unlock()
 
-	caller, err := driver.GetEndpointService(cm.sp).GetIdentity(msg.FromEndpoint, msg.FromPKID)
-	if err != nil {
-		return nil, err
+	// An authenticated message carries the identity of the caller, no need to resolve it
+	caller := msg.FromIdentity
+	if len(caller) == 0 {
+		var err error
+		caller, err = driver.GetEndpointService(cm.sp).GetIdentity(msg.FromEndpoint, msg.FromPKID)
+		if err != nil {
+			if !unknownCallers {
+				return nil, err
+			}
+			logger.Debugf("[%s] caller at [%s] unknown, continuing without caller: [%s]", id, msg.FromEndpoint, err)
+		}
 	}
 
 	contextID := msg.ContextID
@@ -272,11 +346,7 @@ func (cm *manager) newContext(id view.Identity, msg *view.Message) (view.Context
 		if err != nil {
 			return nil, err
 		}
-		ctx := cm.ctx
-		if ctx == nil {
-			ctx = context.Background()
-		}
-		newCtx, err := NewContext(ctx, cm.sp, contextID, GetCommLayer(cm.sp), driver.GetEndpointService(cm.sp), id, backend, caller)
+		newCtx, err := NewContext(parent, cm.sp, contextID, GetCommLayer(cm.sp), driver.GetEndpointService(cm.sp), id, backend, caller)
 		if err != nil {
 			return nil, err
 		}
@@ -342,6 +412,22 @@ func (cm *manager) callView(msg *view.Message) {
 	}
 }
 
+// observe reports a run of the passed view, started at the passed time
+func (cm *manager) observe(v view.View, role string, start time.Time, err error) {
+	id := getIdentifier(v)
+	cm.metrics.Calls.With("view", id, "role", role, "success", strconv.FormatBool(err == nil)).Add(1)
+	cm.metrics.CallDuration.With("view", id, "role", role).Observe(time.Since(start).Seconds())
+}
+
+func (cm *manager) getCtx() context.Context {
+	cm.contextsSync.RLock()
+	defer cm.contextsSync.RUnlock()
+	if cm.ctx == nil {
+		return context.Background()
+	}
+	return cm.ctx
+}
+
 func (cm *manager) me() view.Identity {
 	return driver.GetIdentityProvider(cm.sp).DefaultIdentity()
 }
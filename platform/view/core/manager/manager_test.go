@@ -118,3 +118,20 @@ func getContext(t *testing.T, wg *sync.WaitGroup, m Manager) {
 	wg.Done()
 	assert.Error(t, err)
 }
+
+type AdminFactory struct {
+	DummyFactory
+}
+
+func (a *AdminFactory) AdminOnly() {}
+
+func TestIsAdminView(t *testing.T) {
+	registry := registry2.New()
+	m := manager.New(registry)
+	assert.NoError(t, m.RegisterFactory("dummy", &DummyFactory{}))
+	assert.NoError(t, m.RegisterFactory("admin", &AdminFactory{}))
+
+	assert.False(t, m.IsAdminView("dummy"))
+	assert.True(t, m.IsAdminView("admin"))
+	assert.False(t, m.IsAdminView("unknown"))
+}
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package manager
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	viewsCounterOpts = metrics.CounterOpts{
+		Namespace:    "fsc",
+		Subsystem:    "view",
+		Name:         "calls",
+		Help:         "Views run by this node, as initiator or responder.",
+		LabelNames:   []string{"view", "role", "success"},
+		StatsdFormat: "%{#fqname}.%{view}.%{role}.%{success}",
+	}
+
+	viewDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:    "fsc",
+		Subsystem:    "view",
+		Name:         "call_duration",
+		Help:         "Time taken to run a view, in seconds.",
+		LabelNames:   []string{"view", "role"},
+		StatsdFormat: "%{#fqname}.%{view}.%{role}",
+	}
+)
+
+const (
+	initiatorRole = "initiator"
+	responderRole = "responder"
+)
+
+// Metrics are the metrics of the views run by the view manager
+type Metrics struct {
+	Calls        metrics.Counter
+	CallDuration metrics.Histogram
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		Calls:        p.NewCounter(viewsCounterOpts),
+		CallDuration: p.NewHistogram(viewDurationHistogramOpts),
+	}
+}
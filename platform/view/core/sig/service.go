@@ -62,6 +62,16 @@ func (o *service) RegisterSigner(identity view.Identity, signer driver.Signer, v
 	return o.RegisterVerifier(identity, verifier)
 }
 
+// UnregisterSigner removes the signer bound to the passed identity.
+// The verifier is kept to check the signatures produced so far.
+func (o *service) UnregisterSigner(identity view.Identity) error {
+	logger.Debugf("remove signer for [id:%s]", identity.UniqueID())
+	o.viewsSync.Lock()
+	delete(o.signers, identity.UniqueID())
+	o.viewsSync.Unlock()
+	return nil
+}
+
 func (o *service) RegisterVerifier(identity view.Identity, verifier driver.Verifier) error {
 	if verifier == nil {
 		return errors.New("invalid verifier, expected a valid instance")
@@ -149,6 +159,9 @@ func (o *service) GetSigner(identity view.Identity) (driver.Signer, error) {
 	return signer, nil
 }
 
+// GetVerifier returns the verifier of the passed identity, deserializing it the first time.
+// Verifiers are cached for the lifetime of the service, the deserializers that validate certificates
+// return verifiers that validate them again on each verification.
 func (o *service) GetVerifier(identity view.Identity) (driver.Verifier, error) {
 	o.viewsSync.Lock()
 	verifier, ok := o.verifiers[identity.UniqueID()]
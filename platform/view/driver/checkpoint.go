@@ -0,0 +1,22 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package driver
+
+import "github.com/pkg/errors"
+
+// ErrAbort is the cause of a final failure of a resumable view: the checkpoint of the view is discarded and
+// the view is not resumed. Any other failure keeps the checkpoint, and the view is resumed at the next start.
+var ErrAbort = errors.New("resumable view aborted")
+
+// Checkpointer is implemented by the contexts of resumable views.
+// It allows a view to record its progress and to read it back when its execution is resumed after a restart.
+type Checkpointer interface {
+	// Checkpoint records the passed step and state as the latest progress of the context
+	Checkpoint(step string, state []byte) error
+	// LastCheckpoint returns the last recorded step and state. The step is empty if no checkpoint has been recorded yet.
+	LastCheckpoint() (string, []byte, error)
+}
@@ -8,6 +8,7 @@ package driver
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
@@ -30,6 +31,18 @@ type PKIResolver interface {
 	GetPKIidOfCert(peerIdentity view.Identity) []byte
 }
 
+// Binding links an ephemeral identity to a long term identity
+type Binding struct {
+	// Ephemeral is the bound identity
+	Ephemeral view.Identity
+	// LongTerm is the identity Ephemeral is bound to
+	LongTerm view.Identity
+	// Origin tells why the binding was created, for instance the transaction an Idemix pseudonym was created for
+	Origin string
+	// Time is when the binding was created
+	Time time.Time
+}
+
 //go:generate counterfeiter -o mock/resolver.go -fake-name EndpointService . EndpointService
 
 // EndpointService models the endpoint service
@@ -47,6 +60,15 @@ type EndpointService interface {
 	// Bind binds b to identity a
 	Bind(b view.Identity, a view.Identity) error
 
+	// BindWithOrigin binds b to identity a, recording the origin of the binding
+	BindWithOrigin(b view.Identity, a view.Identity, origin string) error
+
+	// Bindings returns the bindings to the passed long term identity, all the bindings if the identity is empty
+	Bindings(longTerm view.Identity) ([]*Binding, error)
+
+	// Unbind revokes the binding of the passed ephemeral identity
+	Unbind(ephemeral view.Identity) error
+
 	// IsBoundTo returns true if b was bound to a
 	IsBoundTo(a view.Identity, b view.Identity) bool
 
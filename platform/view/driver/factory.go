@@ -13,3 +13,12 @@ type Factory interface {
 	// NewView returns an instance of the View interface build using the passed argument.
 	NewView(in []byte) (view.View, error)
 }
+
+// AdminFactory is implemented by the factories of the views that administer the node.
+// Through the view service, and its REST API, these views can be initiated only by the default identity of the node
+// and its admins, whatever the view policies.
+type AdminFactory interface {
+	Factory
+	// AdminOnly marks the factory as reserved to the admins
+	AdminOnly()
+}
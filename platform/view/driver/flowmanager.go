@@ -22,6 +22,9 @@ type ViewManager interface {
 	InitiateView(view view.View) (interface{}, error)
 	// InitiateContext initiates a new context for the passed view
 	InitiateContext(view view.View) (view.Context, error)
+	// InitiateResumableView instantiates the view bound to the passed factory id and runs it in a context
+	// whose progress is stored, so that its execution can be resumed after a restart
+	InitiateResumableView(fid string, in []byte) (interface{}, error)
 }
 
 // GetViewManager returns an instance of the view manager.
@@ -65,6 +65,43 @@ type EndpointService struct {
 	bindReturnsOnCall map[int]struct {
 		result1 error
 	}
+	BindWithOriginStub        func(b view.Identity, a view.Identity, origin string) error
+	bindWithOriginMutex       sync.RWMutex
+	bindWithOriginArgsForCall []struct {
+		b      view.Identity
+		a      view.Identity
+		origin string
+	}
+	bindWithOriginReturns struct {
+		result1 error
+	}
+	bindWithOriginReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BindingsStub        func(longTerm view.Identity) ([]*driver.Binding, error)
+	bindingsMutex       sync.RWMutex
+	bindingsArgsForCall []struct {
+		longTerm view.Identity
+	}
+	bindingsReturns struct {
+		result1 []*driver.Binding
+		result2 error
+	}
+	bindingsReturnsOnCall map[int]struct {
+		result1 []*driver.Binding
+		result2 error
+	}
+	UnbindStub        func(ephemeral view.Identity) error
+	unbindMutex       sync.RWMutex
+	unbindArgsForCall []struct {
+		ephemeral view.Identity
+	}
+	unbindReturns struct {
+		result1 error
+	}
+	unbindReturnsOnCall map[int]struct {
+		result1 error
+	}
 	IsBoundToStub        func(a view.Identity, b view.Identity) bool
 	isBoundToMutex       sync.RWMutex
 	isBoundToArgsForCall []struct {
@@ -323,6 +360,155 @@ func (fake *EndpointService) BindReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *EndpointService) BindWithOrigin(b view.Identity, a view.Identity, origin string) error {
+	fake.bindWithOriginMutex.Lock()
+	ret, specificReturn := fake.bindWithOriginReturnsOnCall[len(fake.bindWithOriginArgsForCall)]
+	fake.bindWithOriginArgsForCall = append(fake.bindWithOriginArgsForCall, struct {
+		b      view.Identity
+		a      view.Identity
+		origin string
+	}{b, a, origin})
+	fake.recordInvocation("BindWithOrigin", []interface{}{b, a, origin})
+	fake.bindWithOriginMutex.Unlock()
+	if fake.BindWithOriginStub != nil {
+		return fake.BindWithOriginStub(b, a, origin)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.bindWithOriginReturns.result1
+}
+
+func (fake *EndpointService) BindWithOriginCallCount() int {
+	fake.bindWithOriginMutex.RLock()
+	defer fake.bindWithOriginMutex.RUnlock()
+	return len(fake.bindWithOriginArgsForCall)
+}
+
+func (fake *EndpointService) BindWithOriginArgsForCall(i int) (view.Identity, view.Identity, string) {
+	fake.bindWithOriginMutex.RLock()
+	defer fake.bindWithOriginMutex.RUnlock()
+	return fake.bindWithOriginArgsForCall[i].b, fake.bindWithOriginArgsForCall[i].a, fake.bindWithOriginArgsForCall[i].origin
+}
+
+func (fake *EndpointService) BindWithOriginReturns(result1 error) {
+	fake.BindWithOriginStub = nil
+	fake.bindWithOriginReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *EndpointService) BindWithOriginReturnsOnCall(i int, result1 error) {
+	fake.BindWithOriginStub = nil
+	if fake.bindWithOriginReturnsOnCall == nil {
+		fake.bindWithOriginReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.bindWithOriginReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *EndpointService) Bindings(longTerm view.Identity) ([]*driver.Binding, error) {
+	fake.bindingsMutex.Lock()
+	ret, specificReturn := fake.bindingsReturnsOnCall[len(fake.bindingsArgsForCall)]
+	fake.bindingsArgsForCall = append(fake.bindingsArgsForCall, struct {
+		longTerm view.Identity
+	}{longTerm})
+	fake.recordInvocation("Bindings", []interface{}{longTerm})
+	fake.bindingsMutex.Unlock()
+	if fake.BindingsStub != nil {
+		return fake.BindingsStub(longTerm)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.bindingsReturns.result1, fake.bindingsReturns.result2
+}
+
+func (fake *EndpointService) BindingsCallCount() int {
+	fake.bindingsMutex.RLock()
+	defer fake.bindingsMutex.RUnlock()
+	return len(fake.bindingsArgsForCall)
+}
+
+func (fake *EndpointService) BindingsArgsForCall(i int) view.Identity {
+	fake.bindingsMutex.RLock()
+	defer fake.bindingsMutex.RUnlock()
+	return fake.bindingsArgsForCall[i].longTerm
+}
+
+func (fake *EndpointService) BindingsReturns(result1 []*driver.Binding, result2 error) {
+	fake.BindingsStub = nil
+	fake.bindingsReturns = struct {
+		result1 []*driver.Binding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointService) BindingsReturnsOnCall(i int, result1 []*driver.Binding, result2 error) {
+	fake.BindingsStub = nil
+	if fake.bindingsReturnsOnCall == nil {
+		fake.bindingsReturnsOnCall = make(map[int]struct {
+			result1 []*driver.Binding
+			result2 error
+		})
+	}
+	fake.bindingsReturnsOnCall[i] = struct {
+		result1 []*driver.Binding
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointService) Unbind(ephemeral view.Identity) error {
+	fake.unbindMutex.Lock()
+	ret, specificReturn := fake.unbindReturnsOnCall[len(fake.unbindArgsForCall)]
+	fake.unbindArgsForCall = append(fake.unbindArgsForCall, struct {
+		ephemeral view.Identity
+	}{ephemeral})
+	fake.recordInvocation("Unbind", []interface{}{ephemeral})
+	fake.unbindMutex.Unlock()
+	if fake.UnbindStub != nil {
+		return fake.UnbindStub(ephemeral)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unbindReturns.result1
+}
+
+func (fake *EndpointService) UnbindCallCount() int {
+	fake.unbindMutex.RLock()
+	defer fake.unbindMutex.RUnlock()
+	return len(fake.unbindArgsForCall)
+}
+
+func (fake *EndpointService) UnbindArgsForCall(i int) view.Identity {
+	fake.unbindMutex.RLock()
+	defer fake.unbindMutex.RUnlock()
+	return fake.unbindArgsForCall[i].ephemeral
+}
+
+func (fake *EndpointService) UnbindReturns(result1 error) {
+	fake.UnbindStub = nil
+	fake.unbindReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *EndpointService) UnbindReturnsOnCall(i int, result1 error) {
+	fake.UnbindStub = nil
+	if fake.unbindReturnsOnCall == nil {
+		fake.unbindReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unbindReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *EndpointService) IsBoundTo(a view.Identity, b view.Identity) bool {
 	fake.isBoundToMutex.Lock()
 	ret, specificReturn := fake.isBoundToReturnsOnCall[len(fake.isBoundToArgsForCall)]
@@ -496,6 +682,12 @@ func (fake *EndpointService) Invocations() map[string][][]interface{} {
 	defer fake.getIdentityMutex.RUnlock()
 	fake.bindMutex.RLock()
 	defer fake.bindMutex.RUnlock()
+	fake.bindWithOriginMutex.RLock()
+	defer fake.bindWithOriginMutex.RUnlock()
+	fake.bindingsMutex.RLock()
+	defer fake.bindingsMutex.RUnlock()
+	fake.unbindMutex.RLock()
+	defer fake.unbindMutex.RUnlock()
 	fake.isBoundToMutex.RLock()
 	defer fake.isBoundToMutex.RUnlock()
 	fake.addResolverMutex.RLock()
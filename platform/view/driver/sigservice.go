@@ -84,6 +84,9 @@ type SigRegistry interface {
 	// RegisterSigner binds the passed identity to the passed signer and verifier
 	RegisterSigner(identity view.Identity, signer Signer, verifier Verifier) error
 
+	// UnregisterSigner removes the signer bound to the passed identity
+	UnregisterSigner(identity view.Identity) error
+
 	// RegisterVerifier binds the passed identity to the passed verifier
 	RegisterVerifier(identity view.Identity, verifier Verifier) error
 }
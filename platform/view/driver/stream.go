@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package driver
+
+import (
+	"reflect"
+)
+
+// ViewStream is the bidirectional channel between a running view and the client that initiated it
+// with a streaming invocation.
+type ViewStream interface {
+	// Send delivers the passed intermediate output to the client
+	Send(output []byte) error
+	// Report delivers the passed status report to the client
+	Report(msg string) error
+	// Recv blocks until the client sends further input, or the stream is closed
+	Recv() ([]byte, error)
+}
+
+// ViewStreamProvider gives access to the streams bound to the contexts initiated with a streaming invocation
+type ViewStreamProvider interface {
+	// Stream returns the stream bound to the passed context identifier, an error if no stream is bound
+	Stream(contextID string) (ViewStream, error)
+}
+
+// GetViewStreamProvider returns an instance of the view stream provider, an error if no instance is found.
+func GetViewStreamProvider(sp ServiceProvider) (ViewStreamProvider, error) {
+	s, err := sp.GetService(reflect.TypeOf((*ViewStreamProvider)(nil)))
+	if err != nil {
+		return nil, err
+	}
+	return s.(ViewStreamProvider), nil
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package view
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
@@ -16,6 +18,18 @@ import (
 
 var logger = flogging.MustGetLogger("view-sdk")
 
+// Binding links an ephemeral identity to a long term identity
+type Binding struct {
+	// Ephemeral is the bound identity
+	Ephemeral view.Identity
+	// LongTerm is the identity Ephemeral is bound to
+	LongTerm view.Identity
+	// Origin tells why the binding was created
+	Origin string
+	// Time is when the binding was created
+	Time time.Time
+}
+
 // PortName is the type variable for the socket ports
 type PortName string
 
@@ -92,6 +106,35 @@ func (e *EndpointService) Bind(longTerm view.Identity, ephemeral view.Identity)
 	return e.es.Bind(longTerm, ephemeral)
 }
 
+// BindWithOrigin binds the ephemeral identity to the long term one, like Bind, and records the origin of the binding,
+// for instance the transaction an Idemix pseudonym was created for.
+func (e *EndpointService) BindWithOrigin(longTerm view.Identity, ephemeral view.Identity, origin string) error {
+	return e.es.BindWithOrigin(longTerm, ephemeral, origin)
+}
+
+// Bindings returns the bindings to the passed long term identity, all the bindings if the identity is empty
+func (e *EndpointService) Bindings(longTerm view.Identity) ([]*Binding, error) {
+	bindings, err := e.es.Bindings(longTerm)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Binding, len(bindings))
+	for i, b := range bindings {
+		res[i] = &Binding{
+			Ephemeral: b.Ephemeral,
+			LongTerm:  b.LongTerm,
+			Origin:    b.Origin,
+			Time:      b.Time,
+		}
+	}
+	return res, nil
+}
+
+// Unbind revokes the binding of the passed ephemeral identity
+func (e *EndpointService) Unbind(ephemeral view.Identity) error {
+	return e.es.Unbind(ephemeral)
+}
+
 // IsBoundTo returns true if b was bound to a
 func (e *EndpointService) IsBoundTo(a view.Identity, b view.Identity) bool {
 	return e.es.IsBoundTo(a, b)
@@ -60,6 +60,15 @@ func (m *Manager) InitiateContext(view View) (*Context, error) {
 	return &Context{c: context}, nil
 }
 
+// InitiateResumableView instantiates the view bound to the passed factory id and on input, and runs it
+// in a new context whose progress is stored in the KVS.
+// If the node stops before the view terminates, or the view fails, the view is re-created and run again when
+// the node restarts and the factory is registered. Return an error caused by ErrAbort to make a failure final.
+// Use Checkpoint and LastCheckpoint to record and recover the progress of the view.
+func (m *Manager) InitiateResumableView(fid string, in []byte) (interface{}, error) {
+	return m.m.InitiateResumableView(fid, in)
+}
+
 // GetManager returns an instance of the view manager.
 // It panics, if no instance is found.
 func GetManager(sp ServiceProvider) *Manager {
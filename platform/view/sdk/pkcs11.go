@@ -0,0 +1,14 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver/pkcs11"
+)
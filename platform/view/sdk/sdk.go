@@ -8,17 +8,23 @@ package view
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/tls"
+	x5092 "crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"net"
 
 	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/badger"
 	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/sql"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver/file"
 	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
 	web2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/web"
 
 	"github.com/hyperledger/fabric/common/grpclogging"
+	crypto2 "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view"
@@ -28,14 +34,21 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/manager"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
 	comm2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm/identity"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/crypto"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/discovery"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	metrics2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging/metrics"
 	grpc2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracker"
 )
 
 var logger = flogging.MustGetLogger("view-sdk")
@@ -58,11 +71,16 @@ type p struct {
 	confPath string
 	registry Registry
 
-	webServer *web2.Server
+	webServer        *web2.Server
+	operationsSystem *operations.System
+	tracingProvider  *tracing.Provider
 
-	grpcServer  *grpc2.GRPCServer
-	viewService view2.Service
-	viewManager Startable
+	grpcServer           *grpc2.GRPCServer
+	viewService          view2.Service
+	accessControlChecker *view2.AccessControlChecker
+	viewManager          Startable
+	discoveryService     *discovery.Service
+	certValidator        *x509.Validator
 
 	context context.Context
 }
@@ -80,16 +98,80 @@ func (p *p) Install() error {
 
 	assert.NoError(p.registry.RegisterService(crypto.NewProvider()))
 
+	// Operations, installed first to let the other services register their metrics and health checkers
+	operationsOptions, err := operations.LoadOptions(configProvider)
+	if err != nil {
+		return errors.WithMessage(err, "failed loading operations config")
+	}
+	if operationsOptions != nil {
+		p.operationsSystem = operations.NewSystem(*operationsOptions)
+		assert.NoError(p.registry.RegisterService(p.operationsSystem), "failed registering operations system")
+		flogging.SetObserver(metrics2.NewObserver(p.operationsSystem.Provider))
+	}
+
+	// Tracing
+	tracingOptions, err := tracing.LoadOptions(configProvider)
+	if err != nil {
+		return errors.WithMessage(err, "failed loading tracing config")
+	}
+	if tracingOptions != nil {
+		p.tracingProvider, err = tracing.NewProvider(*tracingOptions)
+		if err != nil {
+			return errors.WithMessage(err, "failed creating tracing provider")
+		}
+		assert.NoError(p.registry.RegisterService(p.tracingProvider), "failed registering tracing provider")
+	}
+
+	// Key Management
+	var keyManager id.KMS
+	kmsConfig, err := kms.LoadConfig(configProvider)
+	assert.NoError(err, "failed loading key management config")
+	if kmsConfig != nil {
+		kmsService := kms.New(kmsConfig)
+		assert.NoError(p.registry.RegisterService(kmsService), "failed registering key management service")
+		keyManager = kmsService
+	}
+
+	// Certificate Validation
+	p.certValidator = x509.NewValidator()
+	if d := configProvider.GetDuration("fsc.identity.expiryWarning"); d != 0 {
+		p.certValidator.WarnBefore = d
+	}
+	var crlPaths []string
+	for _, path := range configProvider.GetStringSlice("fsc.identity.crls") {
+		crlPaths = append(crlPaths, configProvider.TranslatePath(path))
+	}
+	loadCRLs := func() error {
+		for _, path := range crlPaths {
+			if err := p.certValidator.LoadCRLs(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	assert.NoError(loadCRLs(), "failed loading crls")
+	assert.NoError(p.registry.RegisterService(p.certValidator), "failed registering certificate validator")
+
 	// Sig Service
 	des, err := sig.NewMultiplexDeserializer(p.registry)
 	assert.NoError(err, "failed loading sig verifier deserializer service")
-	des.AddDeserializer(&x509.Deserializer{})
+	des.AddDeserializer(&x509.Deserializer{Validator: p.certValidator})
 	assert.NoError(p.registry.RegisterService(des))
 	signerService := sig.NewSignService(p.registry, des)
 	assert.NoError(p.registry.RegisterService(signerService))
 
 	// Set Endpoint Service
-	endpointService, err := endpoint.NewService(p.registry, nil)
+	var d endpoint.Discovery
+	if configProvider.GetBool("fsc.discovery.enabled") {
+		discoveryConfig, err := discovery.LoadConfig(configProvider)
+		if err != nil {
+			return errors.WithMessage(err, "failed loading discovery config")
+		}
+		p.discoveryService = discovery.NewService(p.registry, discoveryConfig, endpoint.NewPKIResolver())
+		assert.NoError(p.registry.RegisterService(p.discoveryService), "failed registering discovery service")
+		d = p.discoveryService
+	}
+	endpointService, err := endpoint.NewService(p.registry, d)
 	assert.NoError(err, "failed instantiating endpoint service")
 	assert.NoError(p.registry.RegisterService(endpointService), "failed registering endpoint service")
 	resolverService, err := endpoint.NewResolverService(configProvider, view.GetEndpointService(p.registry))
@@ -97,9 +179,13 @@ func (p *p) Install() error {
 	assert.NoError(resolverService.LoadResolvers(), "failed loading resolvers")
 
 	// Set Identity Provider
-	idProvider := id.NewProvider(configProvider, signerService, endpointService)
+	idProvider := id.NewProvider(configProvider, signerService, endpointService, keyManager, p.certValidator)
 	assert.NoError(idProvider.Load(), "failed loading identities")
 	assert.NoError(p.registry.RegisterService(idProvider))
+	reloadService := id.NewReloadService()
+	reloadService.Add(id.ReloaderFunc(loadCRLs))
+	reloadService.Add(idProvider)
+	assert.NoError(p.registry.RegisterService(reloadService), "failed registering reload service")
 
 	// View Service Server
 	marshaller, err := view2.NewResponseMarshaler(p.registry)
@@ -107,18 +193,25 @@ func (p *p) Install() error {
 		return fmt.Errorf("error creating view service response marshaller: %webServer", err)
 	}
 
-	p.viewService, err = view2.NewViewServiceServer(marshaller,
-		view2.NewAccessControlChecker(
-			idProvider,
-			view.GetSigService(p.registry),
-		),
+	p.accessControlChecker = view2.NewAccessControlChecker(
+		idProvider,
+		view.GetSigService(p.registry),
 	)
+	p.accessControlChecker.CertValidator = p.certValidator
+	p.accessControlChecker.Policies, err = view2.LoadViewPolicies(configProvider)
+	if err != nil {
+		return errors.WithMessage(err, "failed loading view policies")
+	}
+	p.viewService, err = view2.NewViewServiceServer(marshaller, p.accessControlChecker)
 	if err != nil {
 		return fmt.Errorf("error creating view service server: %webServer", err)
 	}
 	if err := p.registry.RegisterService(p.viewService); err != nil {
 		return err
 	}
+	if err := p.registry.RegisterService(view2.NewStreams()); err != nil {
+		return err
+	}
 
 	// View Manager
 	viewManager := manager.New(p.registry)
@@ -126,6 +219,8 @@ func (p *p) Install() error {
 		return err
 	}
 	p.viewManager = viewManager
+	p.accessControlChecker.AdminViews = viewManager
+	assert.NoError(viewManager.RegisterFactory(id.ReloadViewID, &id.ReloadViewFactory{Service: reloadService}), "failed registering reload view")
 
 	// KVS
 	driverName := view.GetConfigService(p.registry).GetString("fsc.kvs.persistence.type")
@@ -137,6 +232,14 @@ func (p *p) Install() error {
 		return errors.Wrap(err, "failed creating kvs")
 	}
 	assert.NoError(p.registry.RegisterService(defaultKVS))
+	operations.RegisterChecker(p.registry, "kvs", defaultKVS)
+
+	// View Tracker
+	trackerService := tracker.NewService(p.registry)
+	if d := view.GetConfigService(p.registry).GetDuration("fsc.tracker.retention"); d != 0 {
+		trackerService.Retention = d
+	}
+	assert.NoError(p.registry.RegisterService(trackerService))
 
 	return nil
 }
@@ -144,17 +247,28 @@ func (p *p) Install() error {
 func (p *p) Start(ctx context.Context) error {
 	p.context = ctx
 
+	assert.NoError(p.startOperations(), "failed starting operations system")
 	assert.NoError(p.initWEBServer(), "failed initializing web server")
 	assert.NoError(p.initGRPCServer(), "failed initializing grpc server")
 	assert.NoError(p.startCommLayer(), "failed starting comm layer")
 	assert.NoError(p.registerViewServiceServer(), "failed registering view service server")
 	assert.NoError(p.startViewManager(), "failed starting view manager")
+	assert.NoError(p.startDiscovery(), "failed starting discovery service")
+	p.certValidator.Start(ctx)
 
 	logger.Infof("Started peer with ID=[%webServer], network ID=[%webServer], address=[%webServer]", view.GetConfigService(p.registry).GetString("fsc.id"))
 
 	return p.serve()
 }
 
+func (p *p) startOperations() error {
+	if p.operationsSystem == nil {
+		logger.Info("operations endpoint not enabled")
+		return nil
+	}
+	return p.operationsSystem.Start()
+}
+
 func (p *p) initWEBServer() error {
 	configProvider := view.GetConfigService(p.registry)
 
@@ -182,9 +296,12 @@ func (p *p) initWEBServer() error {
 	h := web2.NewHttpHandler(logger)
 	p.webServer.RegisterHandler("/", h)
 
+	// the REST callers are authenticated and authorized as the callers of the view service,
+	// only the default identity and the admins are allowed if no policy is configured
 	d := &web2.Dispatcher{
-		Logger:  logger,
-		Handler: h,
+		Logger:     logger,
+		Handler:    h,
+		Authorizer: p.accessControlChecker,
 	}
 	web2.InstallViewHandler(logger, p.registry, d)
 
@@ -208,6 +325,7 @@ func (p *p) initGRPCServer() error {
 	}
 
 	serverConfig.Logger = flogging.MustGetLogger("core.comm").With("server", "PeerServer")
+	serverConfig.ServerStatsHandler = grpc2.NewServerStatsHandler(operations.GetMetricsProvider(p.registry))
 	serverConfig.UnaryInterceptors = append(
 		serverConfig.UnaryInterceptors,
 		grpclogging.UnaryServerInterceptor(flogging.MustGetLogger("comm.grpc.server").Zap()),
@@ -239,22 +357,104 @@ func (p *p) initGRPCServer() error {
 func (p *p) startCommLayer() error {
 	configProvider := view.GetConfigService(p.registry)
 
-	k, err := identity.NewCryptoPrivKeyFromMSP(configProvider.GetPath("fsc.identity.key.file"))
+	k, err := p.p2pKey()
 	assert.NoError(err, "failed loading p2p node secret key")
 
-	commService, err := comm2.NewService(
-		&comm2.PrivateKeyFromCryptoKey{Key: k},
-		view.GetEndpointService(p.registry),
-		view.GetConfigService(p.registry),
-		view.GetIdentityProvider(p.registry).DefaultIdentity(),
-	)
-	assert.NoError(err, "failed instantiating the communication service")
+	var commService *comm2.Service
+	switch transportType := configProvider.GetString("fsc.p2p.type"); transportType {
+	case comm2.GRPCTransport:
+		transport, err := p.newGRPCTransport(&comm2.PrivateKeyFromCryptoKey{Key: k})
+		assert.NoError(err, "failed instantiating the grpc transport")
+		commService, err = comm2.NewServiceWithTransport(
+			transport,
+			view.GetEndpointService(p.registry),
+			view.GetConfigService(p.registry),
+			view.GetIdentityProvider(p.registry).DefaultIdentity(),
+			driver.GetSigService(p.registry),
+		)
+		assert.NoError(err, "failed instantiating the communication service")
+	case "", comm2.LibP2PTransport:
+		commService, err = comm2.NewService(
+			&comm2.PrivateKeyFromCryptoKey{Key: k},
+			view.GetEndpointService(p.registry),
+			view.GetConfigService(p.registry),
+			view.GetIdentityProvider(p.registry).DefaultIdentity(),
+			driver.GetSigService(p.registry),
+		)
+		assert.NoError(err, "failed instantiating the communication service")
+	default:
+		return errors.Errorf("unknown p2p transport [%s]", transportType)
+	}
+	commService.Node.SetMetrics(comm2.NewMetrics(operations.GetMetricsProvider(p.registry)))
 	assert.NoError(p.registry.RegisterService(commService), "failed registering communication service")
+	operations.RegisterChecker(p.registry, "comm", commService)
 	commService.Start(p.context)
 
 	return nil
 }
 
+// p2pKey returns the secret key of the p2p node, the key of the default identity.
+// If the key is managed, the p2p node signs with the signer of the default identity.
+func (p *p) p2pKey() (crypto2.PrivKey, error) {
+	if kms.GetService(p.registry) == nil {
+		return identity.NewCryptoPrivKeyFromMSP(view.GetConfigService(p.registry).GetPath("fsc.identity.key.file"))
+	}
+	defaultID := view.GetIdentityProvider(p.registry).DefaultIdentity()
+	signer, err := driver.GetSigService(p.registry).GetSigner(defaultID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting signer of the default identity")
+	}
+	block, _ := pem.Decode(defaultID)
+	if block == nil {
+		return nil, errors.New("failed decoding default identity")
+	}
+	cert, err := x5092.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing default identity")
+	}
+	pk, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("expected *ecdsa.PublicKey")
+	}
+	return identity.NewCryptoPrivKeyFromSigner(signer, pk)
+}
+
+// newGRPCTransport returns the grpc transport for the comm layer. It shares the TLS configuration of the
+// grpc server, the client presents the client certificate when mutual TLS is required.
+func (p *p) newGRPCTransport(keyDispenser comm2.PrivateKeyDispenser) (comm2.Transport, error) {
+	configProvider := view.GetConfigService(p.registry)
+
+	serverConfig, err := p.getServerConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed loading p2p server config")
+	}
+	serverConfig.Logger = flogging.MustGetLogger("core.comm").With("server", "P2PServer")
+
+	clientConfig := grpc2.ClientConfig{
+		KaOpts:  grpc2.DefaultKeepaliveOptions,
+		Timeout: configProvider.GetDuration("fsc.connectiontimeout"),
+		SecOpts: grpc2.SecureOptions{
+			UseTLS:        serverConfig.SecOpts.UseTLS,
+			ServerRootCAs: serverConfig.SecOpts.ServerRootCAs,
+		},
+	}
+	if clientConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert {
+		clientCert, err := p.getClientCertificate()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed loading p2p client certificate")
+		}
+		key, err := x5092.MarshalPKCS8PrivateKey(clientCert.PrivateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshalling p2p client key")
+		}
+		clientConfig.SecOpts.RequireClientCert = true
+		clientConfig.SecOpts.Certificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Certificate[0]})
+		clientConfig.SecOpts.Key = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+	}
+
+	return comm2.NewGRPCTransport(configProvider.GetString("fsc.p2p.listenAddress"), keyDispenser, serverConfig, clientConfig)
+}
+
 func (p *p) startViewManager() error {
 	view2.InstallViewHandler(p.registry, p.viewService)
 	go p.viewManager.Start(p.context)
@@ -262,6 +462,13 @@ func (p *p) startViewManager() error {
 	return nil
 }
 
+func (p *p) startDiscovery() error {
+	if p.discoveryService == nil {
+		return nil
+	}
+	return p.discoveryService.Start(p.context, view.GetIdentityProvider(p.registry).DefaultIdentity())
+}
+
 func (p *p) serve() error {
 	// Start the grpc server. Done in a goroutine
 	go func() {
@@ -290,10 +497,26 @@ func (p *p) serve() error {
 			}
 			logger.Info("web server stopping...done")
 
+			if p.operationsSystem != nil {
+				logger.Info("operations system stopping...")
+				if err := p.operationsSystem.Stop(); err != nil {
+					logger.Errorf("failed stopping operations system [%s]", err)
+				}
+				logger.Info("operations system stopping...done")
+			}
+
 			logger.Info("grpc server stopping...")
 			p.grpcServer.Stop()
 			logger.Info("grpc server stopping...done")
 
+			if p.tracingProvider != nil {
+				logger.Info("tracing provider stopping...")
+				if err := p.tracingProvider.Shutdown(context.Background()); err != nil {
+					logger.Errorf("failed stopping tracing provider [%s]", err)
+				}
+				logger.Info("tracing provider stopping...done")
+			}
+
 			logger.Info("kvs stopping...")
 			kvs.GetService(p.registry).Stop()
 			logger.Info("kvs stopping...done")
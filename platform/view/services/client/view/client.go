@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 
+	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
 
@@ -123,12 +124,107 @@ func (s *client) CallView(fid string, input []byte) (interface{}, error) {
 	return commandResp.GetCallViewResponse().GetResult(), nil
 }
 
+func (s *client) StreamView(fid string, input []byte) (api.ViewStream, error) {
+	logger.Debugf("Streaming view [%s] on input [%s]", fid, string(input))
+	payload := &protos2.Command_StreamView{StreamView: &protos2.StreamView{
+		Fid:   fid,
+		Input: input,
+	}}
+	sc, err := s.CreateSignedCommand(payload, s.SigningIdentity)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating signed command for [%s,%s]", fid, string(input))
+	}
+
+	conn, client, err := s.ViewServiceClient.CreateViewClient()
+	if err != nil {
+		if conn != nil {
+			conn.Close()
+		}
+		logger.Errorf("[stream view] failed creating view client [%s]", err)
+		return nil, errors.Wrap(err, "[stream view] failed creating view client")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.StreamView(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, errors.Wrap(err, "[stream view] failed view client stream view")
+	}
+	vs := &viewStream{client: s, conn: conn, cancel: cancel, stream: stream}
+	if err := stream.Send(sc); err != nil {
+		vs.Close()
+		return nil, errors.Wrapf(err, "failed sending stream view command for [%s,%s]", fid, string(input))
+	}
+
+	// The first event announces the context in which the view runs
+	event, err := vs.Recv()
+	if err != nil {
+		vs.Close()
+		return nil, errors.Wrapf(err, "failed receiving context for [%s,%s]", fid, string(input))
+	}
+	if event.Done {
+		vs.Close()
+		return nil, errors.Errorf("expected context announcement for [%s,%s], got result", fid, string(input))
+	}
+	return vs, nil
+}
+
 func (s *client) Initiate(fid string, in []byte) (string, error) {
 	panic("implement me")
 }
 
 func (s *client) Track(cid string) string {
-	panic("implement me")
+	logger.Debugf("Tracking context [%s]", cid)
+	payload := &protos2.Command_TrackView{TrackView: &protos2.TrackView{
+		Cid: cid,
+	}}
+	sc, err := s.CreateSignedCommand(payload, s.SigningIdentity)
+	if err != nil {
+		logger.Errorf("failed creating signed command to track context [%s]: [%s]", cid, err)
+		return ""
+	}
+
+	commandResp, err := s.processCommand(context.Background(), sc)
+	if err != nil {
+		logger.Errorf("failed process command to track context [%s]: [%s]", cid, err)
+		return ""
+	}
+	if commandResp.GetTrackViewResponse() == nil {
+		logger.Errorf("expected track view response, got nothing for context [%s]", cid)
+		return ""
+	}
+	return string(commandResp.GetTrackViewResponse().GetPayload())
+}
+
+// ListViews returns the json encoded status of the recently initiated views created by the passed factory,
+// if not empty, whose status is one of the passed ones, if any, and started at or after since, if not zero.
+// At most limit statuses are returned, if limit is positive.
+func (s *client) ListViews(fid string, statuses []int32, since time.Time, limit int32) ([]byte, error) {
+	listViews := &protos2.ListViews{
+		Fid:      fid,
+		Statuses: statuses,
+		Limit:    limit,
+	}
+	if !since.IsZero() {
+		ts, err := ptypes.TimestampProto(since)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid since [%s]", since)
+		}
+		listViews.Since = ts
+	}
+	sc, err := s.CreateSignedCommand(&protos2.Command_ListViews{ListViews: listViews}, s.SigningIdentity)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating signed command to list views")
+	}
+
+	commandResp, err := s.processCommand(context.Background(), sc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed process command to list views")
+	}
+	if commandResp.GetListViewsResponse() == nil {
+		return nil, errors.New("expected list views response, got nothing")
+	}
+	return commandResp.GetListViewsResponse().GetPayload(), nil
 }
 
 func (s *client) IsTxFinal(txid string) error {
@@ -285,6 +381,12 @@ func commandFromPayload(payload interface{}) (*protos2.Command, error) {
 		return &protos2.Command{Payload: t}, nil
 	case *protos2.Command_IsTxFinal:
 		return &protos2.Command{Payload: t}, nil
+	case *protos2.Command_StreamView:
+		return &protos2.Command{Payload: t}, nil
+	case *protos2.Command_StreamViewInput:
+		return &protos2.Command{Payload: t}, nil
+	case *protos2.Command_ListViews:
+		return &protos2.Command{Payload: t}, nil
 	default:
 		return nil, errors.Errorf("command type not recognized: %T", t)
 	}
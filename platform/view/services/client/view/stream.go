@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
+	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+)
+
+// viewStream implements api.ViewStream on top of a StreamView grpc stream
+type viewStream struct {
+	client *client
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	stream protos2.ViewService_StreamViewClient
+
+	cid       string
+	closeOnce sync.Once
+}
+
+func (v *viewStream) ContextID() string {
+	return v.cid
+}
+
+func (v *viewStream) Recv() (*api.ViewStreamEvent, error) {
+	scr, err := v.stream.Recv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed receiving from view stream")
+	}
+
+	commandResp := &protos2.CommandResponse{}
+	if err := proto.Unmarshal(scr.Response, commandResp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal command response")
+	}
+	if commandResp.GetErr() != nil {
+		return nil, errors.Errorf("error from view during stream view: %s", commandResp.GetErr().GetMessage())
+	}
+	e := commandResp.GetStreamViewEvent()
+	if e == nil {
+		return nil, errors.New("expected stream view event, got nothing")
+	}
+	if len(v.cid) == 0 {
+		v.cid = e.Cid
+	}
+
+	switch t := e.Event.(type) {
+	case *protos2.StreamViewEvent_Report:
+		return &api.ViewStreamEvent{Report: t.Report}, nil
+	case *protos2.StreamViewEvent_Output:
+		return &api.ViewStreamEvent{Output: t.Output}, nil
+	case *protos2.StreamViewEvent_Result:
+		return &api.ViewStreamEvent{Result: t.Result, Done: true}, nil
+	default:
+		return &api.ViewStreamEvent{}, nil
+	}
+}
+
+func (v *viewStream) Send(in []byte) error {
+	payload := &protos2.Command_StreamViewInput{StreamViewInput: &protos2.StreamViewInput{
+		Input: in,
+	}}
+	sc, err := v.client.CreateSignedCommand(payload, v.client.SigningIdentity)
+	if err != nil {
+		return errors.Wrapf(err, "failed creating signed command for input to context [%s]", v.cid)
+	}
+	if err := v.stream.Send(sc); err != nil {
+		return errors.Wrapf(err, "failed sending input to context [%s]", v.cid)
+	}
+	return nil
+}
+
+func (v *viewStream) Close() error {
+	var err error
+	v.closeOnce.Do(func() {
+		v.cancel()
+		err = v.conn.Close()
+	})
+	return err
+}
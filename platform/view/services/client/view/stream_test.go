@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	server2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view"
+	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+)
+
+type signer struct{}
+
+func (s *signer) Serialize() ([]byte, error) { return []byte("alice"), nil }
+
+func (s *signer) Sign(msg []byte) ([]byte, error) { return []byte("signature"), nil }
+
+type marshaler struct{}
+
+func (m *marshaler) MarshalCommandResponse(command []byte, responsePayload interface{}) (*protos2.SignedCommandResponse, error) {
+	cr := &protos2.CommandResponse{}
+	switch t := responsePayload.(type) {
+	case *protos2.CommandResponse_StreamViewEvent:
+		cr.Payload = t
+	case *protos2.CommandResponse_Err:
+		cr.Payload = t
+	default:
+		return nil, errors.Errorf("unexpected response payload %T", t)
+	}
+	raw, err := proto.Marshal(cr)
+	if err != nil {
+		return nil, err
+	}
+	return &protos2.SignedCommandResponse{Response: raw}, nil
+}
+
+type viewServiceClient struct {
+	address string
+}
+
+func (v *viewServiceClient) CreateViewClient() (*grpc.ClientConn, protos2.ViewServiceClient, error) {
+	conn, err := grpc.Dial(v.address, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, protos2.NewViewServiceClient(conn), nil
+}
+
+func (v *viewServiceClient) Certificate() *tls.Certificate { return nil }
+
+func event(e *protos2.StreamViewEvent) *protos2.CommandResponse_StreamViewEvent {
+	e.Cid = "ctx"
+	return &protos2.CommandResponse_StreamViewEvent{StreamViewEvent: e}
+}
+
+// echo announces the context and echoes the input as output, until it receives done.
+// The error terminating the stream on the server side is delivered to terminated.
+func echo(terminated chan error) server2.BidiStreamer {
+	return func(command *protos2.Command, stream server2.CommandStream) error {
+		in := command.GetStreamView().Input
+		if err := stream.Send(event(&protos2.StreamViewEvent{})); err != nil {
+			return err
+		}
+		if err := stream.Send(event(&protos2.StreamViewEvent{Event: &protos2.StreamViewEvent_Report{Report: "started"}})); err != nil {
+			return err
+		}
+		for {
+			c, err := stream.Recv()
+			if err != nil {
+				terminated <- err
+				return err
+			}
+			input := c.GetStreamViewInput().Input
+			switch string(input) {
+			case "fail":
+				return errors.New("view failed")
+			case "done":
+				return stream.Send(event(&protos2.StreamViewEvent{Event: &protos2.StreamViewEvent_Result{Result: in}}))
+			default:
+				if err := stream.Send(event(&protos2.StreamViewEvent{Event: &protos2.StreamViewEvent_Output{Output: input}})); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func newStreamClient(t *testing.T, streamer server2.BidiStreamer) (*client, func()) {
+	s, err := server2.NewViewServiceServer(&marshaler{}, &server2.YesPolicyChecker{})
+	assert.NoError(t, err)
+	s.RegisterBidiStreamer(reflect.TypeOf(&protos2.Command_StreamView{}), streamer)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	gs := grpc.NewServer()
+	protos2.RegisterViewServiceServer(gs, s)
+	go gs.Serve(lis)
+
+	return &client{
+		RandomnessReader:  rand.Reader,
+		Time:              time.Now,
+		ViewServiceClient: &viewServiceClient{address: lis.Addr().String()},
+		SigningIdentity:   &signer{},
+	}, gs.Stop
+}
+
+func TestStreamViewRoundTrip(t *testing.T) {
+	c, stop := newStreamClient(t, echo(make(chan error, 1)))
+	defer stop()
+
+	vs, err := c.StreamView("echo", []byte("hello"))
+	assert.NoError(t, err)
+	defer vs.Close()
+	assert.Equal(t, "ctx", vs.ContextID())
+
+	e, err := vs.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "started", e.Report)
+
+	assert.NoError(t, vs.Send([]byte("ping")))
+	e, err = vs.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("ping"), e.Output)
+	assert.False(t, e.Done)
+
+	assert.NoError(t, vs.Send([]byte("done")))
+	e, err = vs.Recv()
+	assert.NoError(t, err)
+	assert.True(t, e.Done)
+	assert.Equal(t, []byte("hello"), e.Result)
+}
+
+func TestStreamViewFailure(t *testing.T) {
+	c, stop := newStreamClient(t, echo(make(chan error, 1)))
+	defer stop()
+
+	vs, err := c.StreamView("echo", []byte("hello"))
+	assert.NoError(t, err)
+	defer vs.Close()
+	_, err = vs.Recv()
+	assert.NoError(t, err)
+
+	assert.NoError(t, vs.Send([]byte("fail")))
+	_, err = vs.Recv()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "view failed")
+}
+
+func TestStreamViewClientDisconnect(t *testing.T) {
+	terminated := make(chan error, 1)
+	c, stop := newStreamClient(t, echo(terminated))
+	defer stop()
+
+	vs, err := c.StreamView("echo", []byte("hello"))
+	assert.NoError(t, err)
+	_, err = vs.Recv()
+	assert.NoError(t, err)
+
+	// the client goes away mid-stream, the view waiting for input is released
+	assert.NoError(t, vs.Close())
+	select {
+	case err := <-terminated:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server did not notice the client went away")
+	}
+	_, err = vs.Recv()
+	assert.Error(t, err)
+	assert.NoError(t, vs.Close())
+}
@@ -10,12 +10,21 @@ import (
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
 	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+	web2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/web"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
 // Config models the configuration for the web client
@@ -28,12 +37,23 @@ type Config struct {
 	TLSCert string
 	// TLSKey is the TLS client key path
 	TLSKey string
+	// IdentityCert is the path of the X.509 certificate of the identity signing the requests
+	IdentityCert string
+	// IdentityKey is the path of the private key of the identity signing the requests
+	IdentityKey string
+}
+
+// Signer signs the requests of the client
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
 }
 
 // Client models a client for an FSC node
 type Client struct {
-	c   *http.Client
-	url string
+	c        *http.Client
+	url      string
+	identity view.Identity
+	signer   Signer
 }
 
 // NewClient returns a new web client
@@ -57,13 +77,28 @@ func NewClient(config *Config) (*Client, error) {
 	}
 	tlsClientConfig.Certificates = []tls.Certificate{clientCert}
 
+	identity, err := id.LoadIdentity(config.IdentityCert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed loading the identity of the client")
+	}
+	key, err := ioutil.ReadFile(config.IdentityKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading the key of the client")
+	}
+	signer, err := ecdsa.NewSignerFromPEM(key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed loading the key of the client")
+	}
+
 	return &Client{
 		c: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: tlsClientConfig,
 			},
 		},
-		url: config.URL,
+		url:      config.URL,
+		identity: identity,
+		signer:   signer,
 	}, nil
 }
 
@@ -73,10 +108,13 @@ func NewClient(config *Config) (*Client, error) {
 // an error is returned.
 func (c *Client) CallView(fid string, in []byte) (interface{}, error) {
 	url := fmt.Sprintf("%s/v1/Views/%s", c.url, fid)
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer([]byte("hi")))
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(in))
 	if err != nil {
 		return nil, err
 	}
+	if err := c.sign(req, in); err != nil {
+		return nil, errors.WithMessagef(err, "failed signing request for view [%s]", fid)
+	}
 
 	resp, err := c.c.Do(req)
 	if err != nil {
@@ -95,6 +133,24 @@ func (c *Client) CallView(fid string, in []byte) (interface{}, error) {
 	return response.CallViewResponse.Result, nil
 }
 
+// sign adds to the passed request the headers authenticating the client
+func (c *Client) sign(req *http.Request, body []byte) error {
+	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	signature, err := c.signer.Sign(web2.RequestToSign(req.Method, req.URL.RequestURI(), timestamp, body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(web2.IdentityHeader, base64.StdEncoding.EncodeToString(c.identity))
+	req.Header.Set(web2.TimestampHeader, timestamp)
+	req.Header.Set(web2.SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	return nil
+}
+
+// StreamView is not supported by the REST api, use the grpc view client instead
+func (c *Client) StreamView(fid string, in []byte) (api.ViewStream, error) {
+	return nil, errors.Errorf("streaming view [%s] is not supported over REST, use the grpc view client", fid)
+}
+
 func (c *Client) Initiate(fid string, in []byte) (string, error) {
 	panic("implement me")
 }
@@ -23,6 +23,9 @@ func NewConfigFromFSC(confDir string) (*Config, error) {
 	config.CACert = configProvider.TranslatePath(configProvider.GetStringSlice("fsc.tls.clientRootCAs.files")[0])
 	config.TLSCert = configProvider.GetPath("fsc.tls.cert.file")
 	config.TLSKey = configProvider.GetPath("fsc.tls.key.file")
+	// the client signs the requests with the identity of the node
+	config.IdentityCert = configProvider.GetPath("fsc.identity.cert.file")
+	config.IdentityKey = configProvider.GetPath("fsc.identity.key.file")
 
 	return config, nil
 }
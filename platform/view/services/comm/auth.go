@@ -0,0 +1,350 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	// DefaultHandshakeTimeout is how long a sender waits for the reply to its handshake
+	DefaultHandshakeTimeout = 10 * time.Second
+	// replayWindowSize is how many of the latest packets of a session can be received out of order
+	replayWindowSize = 64
+)
+
+var auditLogger = flogging.MustGetLogger("view-sdk.comm.audit")
+
+// SigService gives access to the signers and verifiers of FSC identities
+type SigService interface {
+	GetSigner(identity view.Identity) (driver.Signer, error)
+	GetVerifier(identity view.Identity) (driver.Verifier, error)
+}
+
+// Authenticator binds the packets exchanged on the P2P sessions to FSC identities.
+//
+// Before sending the first packet of a session to a peer, the sender runs a handshake with it:
+// the sender sends a hello carrying a fresh nonce, the recipient replies with its own fresh nonce and a signature
+// over the nonce of the sender. Then, each side signs its packets together with the nonce of the other side and
+// a counter that grows with every packet sent on the session.
+// This way, both sides prove the control of their signing key, and a packet cannot be replayed on the same session
+// or on another one. The identities are checked to be bound to the peer they come from at every handshake.
+// Packets that do not pass these checks are dropped.
+type Authenticator struct {
+	identity         view.Identity
+	sigService       SigService
+	resolver         EndpointService
+	handshakeTimeout time.Duration
+
+	lock sync.RWMutex
+	// sessions holds the state of the handshakes, by session and peer
+	sessions map[authSessionKey]*authSession
+}
+
+type authSessionKey struct {
+	sessionID string
+	peer      string
+}
+
+// authSession is the state of a session with a peer
+type authSession struct {
+	// identity is the identity of the peer, known once the handshake completes
+	identity view.Identity
+	// localNonce is the nonce the peer signs its packets with, remoteNonce the one this node signs its packets with
+	localNonce  []byte
+	remoteNonce []byte
+	// established is closed once the handshake completes
+	established chan struct{}
+	ready       bool
+	// sent is the counter of the last packet sent, received tracks the counters of the packets received
+	sent     uint64
+	received replayWindow
+}
+
+func NewAuthenticator(identity view.Identity, sigService SigService, resolver EndpointService) *Authenticator {
+	return &Authenticator{
+		identity:         identity,
+		sigService:       sigService,
+		resolver:         resolver,
+		handshakeTimeout: DefaultHandshakeTimeout,
+		sessions:         map[authSessionKey]*authSession{},
+	}
+}
+
+// IsHandshake returns true if the passed packet belongs to a handshake
+func IsHandshake(packet *ViewPacket) bool {
+	return len(packet.Nonce) != 0
+}
+
+// Sign sets the identity, the counter and the signature of the passed packet, sent to the passed recipient.
+// If the handshake with the recipient did not happen yet on the session of the packet, Sign sends the hello
+// with the passed function and waits for the recipient to reply.
+func (a *Authenticator) Sign(packet *ViewPacket, recipient string, send func(*ViewPacket) error) error {
+	key := authSessionKey{sessionID: packet.SessionID, peer: recipient}
+	a.lock.Lock()
+	s, ok := a.sessions[key]
+	if !ok {
+		nonce, err := GetRandomNonce()
+		if err != nil {
+			a.lock.Unlock()
+			return err
+		}
+		s = &authSession{localNonce: nonce, established: make(chan struct{})}
+		a.sessions[key] = s
+	}
+	a.lock.Unlock()
+
+	if !ok {
+		hello := &ViewPacket{SessionID: packet.SessionID, Nonce: s.localNonce}
+		if err := a.sign(hello, recipient, nil); err != nil {
+			a.drop(key, s)
+			return err
+		}
+		if err := send(hello); err != nil {
+			a.drop(key, s)
+			return errors.WithMessagef(err, "failed sending handshake to [%s] on session [%s]", recipient, packet.SessionID)
+		}
+	}
+	select {
+	case <-s.established:
+	case <-time.After(a.handshakeTimeout):
+		a.drop(key, s)
+		return errors.Errorf("no handshake reply from [%s] on session [%s]", recipient, packet.SessionID)
+	}
+
+	a.lock.Lock()
+	s.sent++
+	packet.Counter = s.sent
+	remoteNonce := s.remoteNonce
+	a.lock.Unlock()
+	return a.sign(packet, recipient, remoteNonce)
+}
+
+// Handshake processes the passed handshake packet, received by recipient from sender.
+// It returns the reply to send back to the sender, if any.
+func (a *Authenticator) Handshake(packet *ViewPacket, sender, recipient string) (*ViewPacket, error) {
+	identity, err := a.verify(packet, sender, recipient, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkBinding(identity, sender); err != nil {
+		return nil, err
+	}
+
+	key := authSessionKey{sessionID: packet.SessionID, peer: sender}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	s, ok := a.sessions[key]
+
+	if len(packet.Challenge) == 0 {
+		// a hello, the sender opens the session. If this node is opening the same session,
+		// its hello is answered with the same nonce, and the two handshakes complete together.
+		if !ok || s.ready {
+			nonce, err := GetRandomNonce()
+			if err != nil {
+				return nil, err
+			}
+			s = &authSession{localNonce: nonce, established: make(chan struct{})}
+			a.sessions[key] = s
+		}
+		s.establish(identity, packet.Nonce)
+		auditLogger.Debugf("session [%s] opened by [%s] from [%s]", packet.SessionID, identity, sender)
+
+		reply := &ViewPacket{SessionID: packet.SessionID, Nonce: s.localNonce, Challenge: packet.Nonce}
+		if err := a.sign(reply, sender, nil); err != nil {
+			return nil, err
+		}
+		return reply, nil
+	}
+
+	// a reply to the hello of this node
+	if !ok || !bytes.Equal(s.localNonce, packet.Challenge) {
+		return nil, errors.Errorf("unexpected handshake reply from [%s] on session [%s]", sender, packet.SessionID)
+	}
+	if s.ready {
+		if !s.identity.Equal(identity) || !bytes.Equal(s.remoteNonce, packet.Nonce) {
+			return nil, errors.Errorf("handshake reply from [%s] on session [%s] does not match the session", sender, packet.SessionID)
+		}
+		return nil, nil
+	}
+	s.establish(identity, packet.Nonce)
+	auditLogger.Debugf("session [%s] with [%s] from [%s] opened", packet.SessionID, identity, sender)
+	return nil, nil
+}
+
+// Verify checks that the passed packet, received by recipient from sender, is signed by the identity the sender
+// proved to control during the handshake of the session, and that it was not received already.
+// It returns this identity.
+func (a *Authenticator) Verify(packet *ViewPacket, sender, recipient string) (view.Identity, error) {
+	key := authSessionKey{sessionID: packet.SessionID, peer: sender}
+	a.lock.RLock()
+	s, ok := a.sessions[key]
+	ready := ok && s.ready
+	a.lock.RUnlock()
+	if !ready {
+		return nil, errors.Errorf("no handshake with [%s] on session [%s]", sender, packet.SessionID)
+	}
+	if !s.identity.Equal(packet.Identity) {
+		return nil, errors.Errorf("packet on session [%s] from [%s] signed by [%s], expected [%s]", packet.SessionID, sender, view.Identity(packet.Identity), s.identity)
+	}
+
+	identity, err := a.verify(packet, sender, recipient, s.localNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err := s.received.accept(packet.Counter); err != nil {
+		return nil, errors.WithMessagef(err, "packet on session [%s] from [%s] rejected", packet.SessionID, sender)
+	}
+	return identity, nil
+}
+
+// Forget drops the state of the passed session with the passed peer
+func (a *Authenticator) Forget(sessionID, peer string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.sessions, authSessionKey{sessionID: sessionID, peer: peer})
+}
+
+// drop removes the passed session state, if it is still the current one for the passed key
+func (a *Authenticator) drop(key authSessionKey, s *authSession) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.sessions[key] == s {
+		delete(a.sessions, key)
+	}
+}
+
+// sign sets the identity and the signature of the passed packet, sent to the passed recipient
+func (a *Authenticator) sign(packet *ViewPacket, recipient string, nonce []byte) error {
+	signer, err := a.sigService.GetSigner(a.identity)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting signer for [%s]", a.identity)
+	}
+	packet.Identity = a.identity
+	raw, err := signedBytes(packet, recipient, nonce)
+	if err != nil {
+		return err
+	}
+	packet.Signature, err = signer.Sign(raw)
+	if err != nil {
+		return errors.WithMessagef(err, "failed signing packet on session [%s]", packet.SessionID)
+	}
+	return nil
+}
+
+// verify checks the signature of the passed packet, received by recipient from sender
+func (a *Authenticator) verify(packet *ViewPacket, sender, recipient string, nonce []byte) (view.Identity, error) {
+	if len(packet.Identity) == 0 || len(packet.Signature) == 0 {
+		return nil, errors.Errorf("packet on session [%s] from [%s] is not signed", packet.SessionID, sender)
+	}
+	identity := view.Identity(packet.Identity)
+	verifier, err := a.sigService.GetVerifier(identity)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting verifier for [%s]", identity)
+	}
+	raw, err := signedBytes(packet, recipient, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(raw, packet.Signature); err != nil {
+		return nil, errors.WithMessagef(err, "invalid signature from [%s] on session [%s]", identity, packet.SessionID)
+	}
+	return identity, nil
+}
+
+// checkBinding checks that the passed identity is bound to the passed peer.
+// The endpoint service is asked every time, so that a change of the endpoints applies to the next handshakes.
+func (a *Authenticator) checkBinding(identity view.Identity, sender string) error {
+	_, _, pkID, err := a.resolver.Resolve(identity)
+	if err != nil {
+		return errors.WithMessagef(err, "failed resolving [%s]", identity)
+	}
+	if string(pkID) != sender {
+		return errors.Errorf("identity [%s] is bound to [%s], not to sender [%s]", identity, string(pkID), sender)
+	}
+	return nil
+}
+
+// establish completes the handshake of this session with the passed identity, that signs with the passed nonce.
+// It must be called with the lock of the authenticator held.
+func (s *authSession) establish(identity view.Identity, remoteNonce []byte) {
+	s.identity, s.remoteNonce = identity, remoteNonce
+	if !s.ready {
+		s.ready = true
+		close(s.established)
+	}
+}
+
+// replayWindow accepts every counter at most once.
+// The counters can arrive out of order, as long as they are among the latest replayWindowSize ones.
+type replayWindow struct {
+	// last is the highest counter accepted, the bit i of seen is set if last-i has been accepted
+	last uint64
+	seen uint64
+}
+
+func (w *replayWindow) accept(counter uint64) error {
+	switch {
+	case counter == 0:
+		return errors.New("missing counter")
+	case counter > w.last:
+		if shift := counter - w.last; shift < replayWindowSize {
+			w.seen <<= shift
+		} else {
+			w.seen = 0
+		}
+		w.seen |= 1
+		w.last = counter
+		return nil
+	case w.last-counter >= replayWindowSize:
+		return errors.Errorf("counter [%d] too old, last is [%d]", counter, w.last)
+	default:
+		bit := uint64(1) << (w.last - counter)
+		if w.seen&bit != 0 {
+			return errors.Errorf("counter [%d] replayed", counter)
+		}
+		w.seen |= bit
+		return nil
+	}
+}
+
+// signedBytes returns the bytes covered by the signature of the passed packet.
+// The nonce is the one of the recipient for the session, nil for the handshake packets.
+func signedBytes(packet *ViewPacket, recipient string, nonce []byte) ([]byte, error) {
+	raw, err := proto.Marshal(&ViewPacket{
+		SessionID:   packet.SessionID,
+		ContextID:   packet.ContextID,
+		Status:      packet.Status,
+		Payload:     packet.Payload,
+		Caller:      packet.Caller,
+		Identity:    packet.Identity,
+		Chunk:       packet.Chunk,
+		Chunks:      packet.Chunks,
+		Traceparent: packet.Traceparent,
+		Tracestate:  packet.Tracestate,
+		Counter:     packet.Counter,
+		Nonce:       packet.Nonce,
+		Challenge:   packet.Challenge,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling packet")
+	}
+	raw = append(raw, []byte(recipient)...)
+	return append(raw, nonce...), nil
+}
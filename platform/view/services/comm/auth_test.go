@@ -0,0 +1,206 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type ecdsaKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (e *ecdsaKey) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return ecdsa.SignASN1(rand.Reader, e.key, digest[:])
+}
+
+func (e *ecdsaKey) Verify(message, sigma []byte) error {
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(&e.key.PublicKey, digest[:], sigma) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+type sigService map[string]*ecdsaKey
+
+func (s sigService) GetSigner(identity view.Identity) (driver.Signer, error) {
+	k, ok := s[identity.UniqueID()]
+	if !ok {
+		return nil, errors.Errorf("unknown identity [%s]", identity)
+	}
+	return k, nil
+}
+
+func (s sigService) GetVerifier(identity view.Identity) (driver.Verifier, error) {
+	k, ok := s[identity.UniqueID()]
+	if !ok {
+		return nil, errors.Errorf("unknown identity [%s]", identity)
+	}
+	return k, nil
+}
+
+type resolver map[string]string
+
+func (r resolver) Resolve(party view.Identity) (view.Identity, map[view2.PortName]string, []byte, error) {
+	id, ok := r[party.UniqueID()]
+	if !ok {
+		return nil, nil, nil, errors.Errorf("unknown identity [%s]", party)
+	}
+	return party, nil, []byte(id), nil
+}
+
+func (r resolver) GetIdentity(label string, pkID []byte) (view.Identity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newPeerID(t *testing.T) string {
+	_, pk, err := crypto.GenerateECDSAKeyPair(rand.Reader)
+	assert.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pk)
+	assert.NoError(t, err)
+	return id.String()
+}
+
+// connect returns the function that delivers the hellos of the node at from to the node at to,
+// and the replies back
+func connect(t *testing.T, fromAuth *Authenticator, from string, toAuth *Authenticator, to string) func(*ViewPacket) error {
+	return func(hello *ViewPacket) error {
+		reply, err := toAuth.Handshake(hello, from, to)
+		if err != nil {
+			return err
+		}
+		_, err = fromAuth.Handshake(reply, to, from)
+		assert.NoError(t, err)
+		return nil
+	}
+}
+
+func noHandshake(t *testing.T) func(*ViewPacket) error {
+	return func(*ViewPacket) error {
+		assert.Fail(t, "unexpected handshake")
+		return nil
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	alice, bob, eve := view.Identity("alice"), view.Identity("bob"), view.Identity("eve")
+	sigs := sigService{}
+	for _, id := range []view.Identity{alice, bob, eve} {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		sigs[id.UniqueID()] = &ecdsaKey{key: k}
+	}
+	alicePeer, bobPeer, evePeer := newPeerID(t), newPeerID(t), newPeerID(t)
+	r := resolver{alice.UniqueID(): alicePeer, bob.UniqueID(): bobPeer, eve.UniqueID(): evePeer}
+
+	aliceAuth := NewAuthenticator(alice, sigs, r)
+	bobAuth := NewAuthenticator(bob, sigs, r)
+	eveAuth := NewAuthenticator(eve, sigs, r)
+
+	// the first packet of a session runs the handshake
+	packet := &ViewPacket{SessionID: "s", ContextID: "c", Caller: "v", Status: view.OK, Payload: []byte("hello")}
+	assert.NoError(t, aliceAuth.Sign(packet, bobPeer, connect(t, aliceAuth, alicePeer, bobAuth, bobPeer)))
+	id, err := bobAuth.Verify(packet, alicePeer, bobPeer)
+	assert.NoError(t, err)
+	assert.Equal(t, alice, id)
+
+	// the packet cannot be replayed
+	_, err = bobAuth.Verify(packet, alicePeer, bobPeer)
+	assert.Error(t, err)
+	// the next packets do not run the handshake, and can arrive out of order
+	second, third := &ViewPacket{SessionID: "s", Payload: []byte("2")}, &ViewPacket{SessionID: "s", Payload: []byte("3")}
+	assert.NoError(t, aliceAuth.Sign(second, bobPeer, noHandshake(t)))
+	assert.NoError(t, aliceAuth.Sign(third, bobPeer, noHandshake(t)))
+	_, err = bobAuth.Verify(third, alicePeer, bobPeer)
+	assert.NoError(t, err)
+	_, err = bobAuth.Verify(second, alicePeer, bobPeer)
+	assert.NoError(t, err)
+	// bob replies on the session opened by alice without another handshake
+	reply := &ViewPacket{SessionID: "s", Payload: []byte("reply")}
+	assert.NoError(t, bobAuth.Sign(reply, alicePeer, noHandshake(t)))
+	id, err = aliceAuth.Verify(reply, bobPeer, alicePeer)
+	assert.NoError(t, err)
+	assert.Equal(t, bob, id)
+
+	// the identity must be bound to the sending peer
+	packet = &ViewPacket{SessionID: "s", Payload: []byte("hello")}
+	assert.NoError(t, aliceAuth.Sign(packet, bobPeer, noHandshake(t)))
+	_, err = bobAuth.Verify(packet, evePeer, bobPeer)
+	assert.Error(t, err)
+	// the packet cannot be sent to another recipient
+	_, err = bobAuth.Verify(packet, alicePeer, evePeer)
+	assert.Error(t, err)
+	// the packet cannot be tampered with
+	packet.Payload = []byte("bye")
+	_, err = bobAuth.Verify(packet, alicePeer, bobPeer)
+	assert.Error(t, err)
+	// unsigned packets are rejected
+	_, err = bobAuth.Verify(&ViewPacket{SessionID: "s"}, alicePeer, bobPeer)
+	assert.Error(t, err)
+	// packets on sessions without handshake are rejected
+	packet = &ViewPacket{SessionID: "s", Payload: []byte("hello")}
+	assert.NoError(t, aliceAuth.Sign(packet, bobPeer, noHandshake(t)))
+	packet.SessionID = "other"
+	_, err = bobAuth.Verify(packet, alicePeer, bobPeer)
+	assert.Error(t, err)
+
+	// the packets of a previous handshake cannot be replayed on a new one
+	packet = &ViewPacket{SessionID: "s", Payload: []byte("hello")}
+	assert.NoError(t, aliceAuth.Sign(packet, bobPeer, noHandshake(t)))
+	aliceAuth.Forget("s", bobPeer)
+	assert.NoError(t, aliceAuth.Sign(&ViewPacket{SessionID: "s"}, bobPeer, connect(t, aliceAuth, alicePeer, bobAuth, bobPeer)))
+	_, err = bobAuth.Verify(packet, alicePeer, bobPeer)
+	assert.Error(t, err)
+
+	// eve cannot claim to be alice, nor to be bound to the peer of alice
+	hello := &ViewPacket{SessionID: "e", Nonce: []byte("nonce")}
+	assert.NoError(t, eveAuth.sign(hello, bobPeer, nil))
+	hello.Identity = alice
+	_, err = bobAuth.Handshake(hello, evePeer, bobPeer)
+	assert.Error(t, err)
+	assert.Error(t, eveAuth.Sign(&ViewPacket{SessionID: "e"}, bobPeer, connect(t, eveAuth, alicePeer, bobAuth, bobPeer)))
+
+	// the bindings are checked again at every handshake
+	newAlicePeer := newPeerID(t)
+	r[alice.UniqueID()] = newAlicePeer
+	assert.Error(t, aliceAuth.Sign(&ViewPacket{SessionID: "n"}, bobPeer, connect(t, aliceAuth, alicePeer, bobAuth, bobPeer)))
+	assert.NoError(t, aliceAuth.Sign(&ViewPacket{SessionID: "n"}, bobPeer, connect(t, aliceAuth, newAlicePeer, bobAuth, bobPeer)))
+
+	// the sender gives up if the recipient does not reply to the handshake
+	aliceAuth.handshakeTimeout = 100 * time.Millisecond
+	assert.Error(t, aliceAuth.Sign(&ViewPacket{SessionID: "t"}, bobPeer, func(*ViewPacket) error { return nil }))
+}
+
+func TestReplayWindow(t *testing.T) {
+	w := &replayWindow{}
+	assert.Error(t, w.accept(0))
+	assert.NoError(t, w.accept(1))
+	assert.Error(t, w.accept(1))
+	assert.NoError(t, w.accept(3))
+	assert.NoError(t, w.accept(2))
+	assert.Error(t, w.accept(2))
+	assert.NoError(t, w.accept(3+replayWindowSize))
+	assert.Error(t, w.accept(3))
+	assert.NoError(t, w.accept(4))
+	assert.Error(t, w.accept(4))
+}
@@ -10,11 +10,16 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/gogo/protobuf/io"
+	protoio "github.com/gogo/protobuf/io"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	discovery "github.com/libp2p/go-libp2p-discovery"
@@ -22,7 +27,18 @@ import (
 	"github.com/multiformats/go-multiaddr"
 )
 
-func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
+// libp2pHost is the Transport built on libp2p
+type libp2pHost struct {
+	host       host.Host
+	dht        *dht.IpfsDHT
+	finder     *discovery.RoutingDiscovery
+	peersMutex sync.RWMutex
+	peers      map[string]peer.AddrInfo
+	stopFinder int32
+	finderWg   sync.WaitGroup
+}
+
+func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser) (*libp2pHost, error) {
 	priv, err := keyDispenser.PrivateKey()
 	if err != nil {
 		return nil, err
@@ -53,18 +69,12 @@ func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode,
 		return nil, err
 	}
 
-	node := &P2PNode{
-		host:             host,
-		dht:              kademliaDHT,
-		finder:           discovery.NewRoutingDiscovery(kademliaDHT),
-		peers:            make(map[string]peer.AddrInfo),
-		incomingMessages: make(chan *messageWithStream),
-		streams:          make(map[peer.ID][]*streamHandler),
-		sessions:         make(map[string]*NetworkStreamSession),
-		isStopping:       false,
-	}
-
-	return node, err
+	return &libp2pHost{
+		host:   host,
+		dht:    kademliaDHT,
+		finder: discovery.NewRoutingDiscovery(kademliaDHT),
+		peers:  make(map[string]peer.AddrInfo),
+	}, nil
 }
 
 type PrivateKeyFromCryptoKey struct {
@@ -89,20 +99,18 @@ func (p *PrivateKeyFromFile) PrivateKey() (crypto.PrivKey, error) {
 }
 
 func NewBootstrapNode(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
-	node, err := newHost(ListenAddress, keyDispenser)
+	h, err := newHost(ListenAddress, keyDispenser)
 	if err != nil {
 		return nil, err
 	}
 
-	node.host.Peerstore().AddAddrs(node.host.ID(), node.host.Addrs(), time.Hour)
-
-	node.start()
+	h.host.Peerstore().AddAddrs(h.host.ID(), h.host.Addrs(), time.Hour)
 
-	return node, nil
+	return NewNodeWithTransport(h)
 }
 
 func NewNode(ListenAddress, BootstrapNode string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
-	node, err := newHost(ListenAddress, keyDispenser)
+	h, err := newHost(ListenAddress, keyDispenser)
 	if err != nil {
 		return nil, err
 	}
@@ -117,41 +125,82 @@ func NewNode(ListenAddress, BootstrapNode string, keyDispenser PrivateKeyDispens
 		return nil, err
 	}
 
-	err = node.host.Connect(context.Background(), *peerinfo)
+	err = h.host.Connect(context.Background(), *peerinfo)
 	if err != nil {
 		return nil, err
 	}
 
-	node.start()
+	return NewNodeWithTransport(h)
+}
+
+func (h *libp2pHost) ID() string {
+	return h.host.ID().String()
+}
+
+func (h *libp2pHost) NewStream(ctx context.Context, address string, peerID string) (MessageStream, error) {
+	ID, err := peer.Decode(peerID)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := h.host.NewStream(ctx, ID, protocol.ID(viewProtocol))
+	if err != nil {
+		return nil, err
+	}
+	return newLibP2PStream(stream), nil
+}
+
+func (h *libp2pHost) Start(handler func(stream MessageStream)) error {
+	discovery.Advertise(context.Background(), h.finder, rendezVousString)
 
-	return node, nil
+	h.host.SetStreamHandler(protocol.ID(viewProtocol), func(stream network.Stream) {
+		handler(newLibP2PStream(stream))
+	})
+
+	h.finderWg.Add(1)
+	go h.startFinder()
+	return nil
+}
+
+func (h *libp2pHost) Close() error {
+	err := h.host.Close()
+	atomic.StoreInt32(&h.stopFinder, 1)
+	h.finderWg.Wait()
+	return err
 }
 
-func (p *P2PNode) startFinder() {
+func (h *libp2pHost) Lookup(peerID string) (peer.AddrInfo, bool) {
+	h.peersMutex.RLock()
+	defer h.peersMutex.RUnlock()
+
+	peer, in := h.peers[peerID]
+	return peer, in
+}
+
+func (h *libp2pHost) startFinder() {
 	for {
-		peerChan, err := p.finder.FindPeers(context.Background(), rendezVousString)
+		peerChan, err := h.finder.FindPeers(context.Background(), rendezVousString)
 		if err != nil {
 			fmt.Printf("got error from peer finder: %s\n", err.Error())
 			goto sleep
 		}
 
 		for peer := range peerChan {
-			if peer.ID == p.host.ID() {
+			if peer.ID == h.host.ID() {
 				continue
 			}
 
-			p.peersMutex.Lock()
-			if _, in := p.peers[peer.ID.String()]; !in {
+			h.peersMutex.Lock()
+			if _, in := h.peers[peer.ID.String()]; !in {
 				// fmt.Print("Found peer:", peer)
-				p.peers[peer.ID.String()] = peer
+				h.peers[peer.ID.String()] = peer
 			}
-			p.peersMutex.Unlock()
+			h.peersMutex.Unlock()
 		}
 
 	sleep:
 		for i := 0; i < 4; i++ {
-			if atomic.LoadInt32(&p.stopFinder) != 0 {
-				p.finderWg.Done()
+			if atomic.LoadInt32(&h.stopFinder) != 0 {
+				h.finderWg.Done()
 				return
 			}
 			time.Sleep(500 * time.Millisecond)
@@ -159,11 +208,42 @@ func (p *P2PNode) startFinder() {
 	}
 }
 
-func (p *P2PNode) start() {
-	discovery.Advertise(context.Background(), p.finder, rendezVousString)
+// libp2pStream carries length delimited ViewPackets over a libp2p stream
+type libp2pStream struct {
+	stream    network.Stream
+	reader    protoio.ReadCloser
+	writeLock sync.Mutex
+	writer    protoio.WriteCloser
+}
 
-	p.host.SetStreamHandler(protocol.ID(viewProtocol), p.handleStream())
+func newLibP2PStream(stream network.Stream) *libp2pStream {
+	return &libp2pStream{
+		stream: stream,
+		reader: NewDelimitedReader(stream, maxFrameSize),
+		writer: io.NewDelimitedWriter(stream),
+	}
+}
+
+func (s *libp2pStream) RemotePeerID() string {
+	return s.stream.Conn().RemotePeer().String()
+}
+
+func (s *libp2pStream) RemotePeerAddress() string {
+	return s.stream.Conn().RemoteMultiaddr().String()
+}
+
+func (s *libp2pStream) Send(packet *ViewPacket) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.writer.WriteMsg(packet)
+}
+
+func (s *libp2pStream) Recv(packet *ViewPacket) error {
+	return s.reader.ReadMsg(packet)
+}
 
-	p.finderWg.Add(1)
-	go p.startFinder()
+func (s *libp2pStream) Close() error {
+	s.reader.Close()
+	s.writer.Close()
+	return s.stream.Close()
 }
@@ -22,6 +22,9 @@ type EndpointService interface {
 
 type ConfigService interface {
 	GetString(key string) string
+	GetBool(key string) bool
+	IsSet(key string) bool
+	UnmarshalKey(key string, rawVal interface{}) error
 }
 
 type Service struct {
@@ -29,6 +32,7 @@ type Service struct {
 	EndpointService     EndpointService
 	ConfigService       ConfigService
 	DefaultIdentity     view2.Identity
+	SigService          SigService
 	Node                *P2PNode
 }
 
@@ -37,12 +41,14 @@ func NewService(
 	endpointService EndpointService,
 	configService ConfigService,
 	defaultIdentity view2.Identity,
+	sigService SigService,
 ) (*Service, error) {
 	s := &Service{
 		PrivateKeyDispenser: privateKeyDispenser,
 		EndpointService:     endpointService,
 		ConfigService:       configService,
 		DefaultIdentity:     defaultIdentity,
+		SigService:          sigService,
 	}
 	if err := s.init(); err != nil {
 		return nil, err
@@ -50,10 +56,41 @@ func NewService(
 	return s, nil
 }
 
+// NewServiceWithTransport returns a new communication service exchanging packets with the other nodes
+// over the passed transport
+func NewServiceWithTransport(
+	transport Transport,
+	endpointService EndpointService,
+	configService ConfigService,
+	defaultIdentity view2.Identity,
+	sigService SigService,
+) (*Service, error) {
+	node, err := NewNodeWithTransport(transport)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed initializing p2p node with transport [%s]", transport.ID())
+	}
+	s := &Service{
+		EndpointService: endpointService,
+		ConfigService:   configService,
+		DefaultIdentity: defaultIdentity,
+		SigService:      sigService,
+		Node:            node,
+	}
+	if err := s.configureNode(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 func (s *Service) Start(ctx context.Context) {
 	s.Node.Start(ctx)
 }
 
+// HealthCheck returns an error if the p2p node of this service is not running
+func (s *Service) HealthCheck(ctx context.Context) error {
+	return s.Node.HealthCheck(ctx)
+}
+
 func (s *Service) Stop() {
 	s.Node.Stop()
 }
@@ -105,5 +142,47 @@ func (s *Service) init() error {
 			return errors.Wrapf(err, "failed initializing node p2p manager [%s,%s]", p2pListenAddress, AddressToEndpoint(endpoints[view.P2PPort])+"/p2p/"+string(pkID))
 		}
 	}
+
+	return s.configureNode()
+}
+
+func (s *Service) configureNode() error {
+	flowControl, err := s.flowControl()
+	if err != nil {
+		return err
+	}
+	if err := s.Node.SetFlowControl(flowControl); err != nil {
+		return errors.WithMessage(err, "invalid p2p flow control")
+	}
+
+	if s.ConfigService.GetBool("fsc.p2p.authentication.enabled") {
+		logger.Infof("p2p sessions authenticated with identity [%s]", s.DefaultIdentity)
+		s.Node.EnableAuthentication(NewAuthenticator(s.DefaultIdentity, s.SigService, s.EndpointService))
+	} else if _, ok := s.Node.transport.(*grpcTransport); ok {
+		// the grpc transport takes the pki id of the caller from the caller itself,
+		// only the authentication of the packets binds it to an identity
+		return errors.New("the grpc p2p transport requires fsc.p2p.authentication.enabled")
+	}
 	return nil
 }
+
+// flowControl returns the flow control of the p2p sessions, the parameters that are not configured take their default
+func (s *Service) flowControl() (FlowControl, error) {
+	flowControl := DefaultFlowControl()
+	for key, value := range map[string]interface{}{
+		"fsc.p2p.session.queueSize": &flowControl.QueueSize,
+		"fsc.p2p.session.overflow":  &flowControl.Overflow,
+		"fsc.p2p.maxMessageSize":    &flowControl.MaxMessageSize,
+		"fsc.p2p.chunkSize":         &flowControl.ChunkSize,
+		"fsc.p2p.maxPendingChunked": &flowControl.MaxPendingChunked,
+		"fsc.p2p.chunkTimeout":      &flowControl.ChunkTimeout,
+	} {
+		if !s.ConfigService.IsSet(key) {
+			continue
+		}
+		if err := s.ConfigService.UnmarshalKey(key, value); err != nil {
+			return FlowControl{}, errors.Wrapf(err, "failed loading [%s]", key)
+		}
+	}
+	return flowControl, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The messages are pushed into the session queues by the goroutine reading the stream they come from, which is
+// shared by all the sessions with the same remote node. Then, the overflow policies never wait for a session
+// to make room, so that a session nobody reads does not stall the others.
+const (
+	// OverflowDrop drops the messages that do not fit in the session queue
+	OverflowDrop = "drop"
+	// OverflowClose closes the sessions whose queue is full
+	OverflowClose = "close"
+
+	DefaultQueueSize         = 100
+	DefaultMaxMessageSize    = 64 * 1024 * 1024
+	DefaultChunkSize         = 512 * 1024
+	DefaultMaxPendingChunked = 64
+	DefaultChunkTimeout      = time.Minute
+
+	// maxFrameSize is the largest packet a transport accepts
+	maxFrameSize = 655360 * 2
+	// frameOverhead is the room left in a frame for the packet fields other than the payload
+	frameOverhead = 64 * 1024
+)
+
+// FlowControl regulates the flow of the messages on the P2P sessions
+type FlowControl struct {
+	// QueueSize is the number of received messages a session buffers before its overflow policy kicks in
+	QueueSize int
+	// Overflow is the policy applied to a message received on a session whose queue is full:
+	// OverflowDrop or OverflowClose. The master session always drops.
+	Overflow string
+	// MaxMessageSize is the largest payload a session sends or receives
+	MaxMessageSize int
+	// ChunkSize is the largest payload carried by a single packet, larger payloads are sent in chunks
+	ChunkSize int
+	// MaxPendingChunked is the number of chunked payloads a stream reassembles at once,
+	// the payloads started beyond that are dropped
+	MaxPendingChunked int
+	// ChunkTimeout is the time an incomplete chunked payload waits for its next chunk before being dropped
+	ChunkTimeout time.Duration
+}
+
+// DefaultFlowControl returns the flow control used if none is configured
+func DefaultFlowControl() FlowControl {
+	return FlowControl{
+		QueueSize:         DefaultQueueSize,
+		Overflow:          OverflowDrop,
+		MaxMessageSize:    DefaultMaxMessageSize,
+		ChunkSize:         DefaultChunkSize,
+		MaxPendingChunked: DefaultMaxPendingChunked,
+		ChunkTimeout:      DefaultChunkTimeout,
+	}
+}
+
+// Validate checks that the flow control parameters are consistent
+func (f FlowControl) Validate() error {
+	if f.QueueSize < 1 {
+		return errors.Errorf("invalid session queue size [%d], must be positive", f.QueueSize)
+	}
+	switch f.Overflow {
+	case OverflowDrop, OverflowClose:
+	default:
+		return errors.Errorf("invalid session overflow policy [%s], expected one of [%s,%s]", f.Overflow, OverflowDrop, OverflowClose)
+	}
+	if f.ChunkSize < 1 || f.ChunkSize > maxFrameSize-frameOverhead {
+		return errors.Errorf("invalid chunk size [%d], must be between 1 and %d", f.ChunkSize, maxFrameSize-frameOverhead)
+	}
+	if f.MaxMessageSize < f.ChunkSize {
+		return errors.Errorf("invalid max message size [%d], must be at least the chunk size [%d]", f.MaxMessageSize, f.ChunkSize)
+	}
+	if f.MaxPendingChunked < 1 {
+		return errors.Errorf("invalid max pending chunked payloads [%d], must be positive", f.MaxPendingChunked)
+	}
+	if f.ChunkTimeout <= 0 {
+		return errors.Errorf("invalid chunk timeout [%s], must be positive", f.ChunkTimeout)
+	}
+	return nil
+}
+
+// chunkedPayload collects the chunks of a payload split by the sender
+type chunkedPayload struct {
+	payload []byte
+	next    uint32
+	chunks  uint32
+	// received is the time the last chunk has been received
+	received time.Time
+}
+
+// reassemble collects the chunks of the packets received on this stream. It returns the payload of the passed
+// packet and true if the packet is not chunked or is the last chunk of its payload.
+// Chunks received out of order, or exceeding the maximum message size, make the whole payload be dropped.
+// So do payloads whose next chunk does not arrive in time, and payloads started while the stream
+// already reassembles the maximum number of payloads.
+func (s *streamHandler) reassemble(msg *ViewPacket) ([]byte, bool) {
+	if msg.Chunks <= 1 {
+		if len(msg.Payload) > s.node.flowControl.MaxMessageSize {
+			logger.Warnf("dropping message of [%d] bytes on session [%s], max is [%d]", len(msg.Payload), msg.SessionID, s.node.flowControl.MaxMessageSize)
+			return nil, false
+		}
+		return msg.Payload, true
+	}
+
+	now := time.Now()
+	s.expireChunks(now)
+	pending, ok := s.chunks[msg.SessionID]
+	switch {
+	case msg.Chunk == 0:
+		if !ok && len(s.chunks) >= s.node.flowControl.MaxPendingChunked {
+			logger.Warnf("dropping message on session [%s], [%d] chunked messages pending on the stream", msg.SessionID, len(s.chunks))
+			return nil, false
+		}
+		pending = &chunkedPayload{chunks: msg.Chunks}
+		s.chunks[msg.SessionID] = pending
+	case !ok:
+		// the previous chunks have been dropped
+		return nil, false
+	case msg.Chunk != pending.next || msg.Chunks != pending.chunks:
+		logger.Warnf("dropping message on session [%s], got chunk [%d/%d], expected [%d/%d]", msg.SessionID, msg.Chunk, msg.Chunks, pending.next, pending.chunks)
+		delete(s.chunks, msg.SessionID)
+		return nil, false
+	}
+
+	if len(pending.payload)+len(msg.Payload) > s.node.flowControl.MaxMessageSize {
+		logger.Warnf("dropping message on session [%s], exceeds max size [%d]", msg.SessionID, s.node.flowControl.MaxMessageSize)
+		delete(s.chunks, msg.SessionID)
+		return nil, false
+	}
+	pending.payload = append(pending.payload, msg.Payload...)
+	pending.next++
+	pending.received = now
+	if pending.next < pending.chunks {
+		return nil, false
+	}
+	delete(s.chunks, msg.SessionID)
+	return pending.payload, true
+}
+
+// expireChunks drops the chunked payloads whose last chunk has been received more than the chunk timeout ago
+func (s *streamHandler) expireChunks(now time.Time) {
+	for sessionID, pending := range s.chunks {
+		if now.Sub(pending.received) > s.node.flowControl.ChunkTimeout {
+			logger.Warnf("dropping message on session [%s], got [%d/%d] chunks, timed out", sessionID, pending.next, pending.chunks)
+			delete(s.chunks, sessionID)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/io"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+func TestFlowControlValidate(t *testing.T) {
+	assert.NoError(t, DefaultFlowControl().Validate())
+
+	fc := DefaultFlowControl()
+	fc.QueueSize = 0
+	assert.Error(t, fc.Validate())
+
+	fc = DefaultFlowControl()
+	fc.Overflow = "wait"
+	assert.Error(t, fc.Validate())
+
+	fc = DefaultFlowControl()
+	fc.ChunkSize = maxFrameSize
+	assert.Error(t, fc.Validate())
+
+	fc = DefaultFlowControl()
+	fc.MaxMessageSize = fc.ChunkSize - 1
+	assert.Error(t, fc.Validate())
+
+	fc = DefaultFlowControl()
+	fc.MaxPendingChunked = 0
+	assert.Error(t, fc.Validate())
+
+	fc = DefaultFlowControl()
+	fc.ChunkTimeout = 0
+	assert.Error(t, fc.Validate())
+}
+
+func TestDelimitedReaderSkipsLargeFrames(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := io.NewDelimitedWriter(buf)
+	assert.NoError(t, w.WriteMsg(&ViewPacket{Payload: make([]byte, 1024)}))
+	assert.NoError(t, w.WriteMsg(&ViewPacket{Payload: []byte("hello")}))
+
+	r := NewDelimitedReader(buf, 512)
+	msg := &ViewPacket{}
+	assert.Equal(t, errFrameTooLarge, r.ReadMsg(msg))
+	assert.NoError(t, r.ReadMsg(msg))
+	assert.Equal(t, []byte("hello"), msg.Payload)
+}
+
+func TestChunking(t *testing.T) {
+	aliceID := idForParty(t, "testdata/dht.pub")
+	bobID := idForParty(t, "testdata/dht1.pub")
+	alice := getGRPCNode(t, "127.0.0.1:1238", &PrivateKeyFromFile{"testdata/dht.priv"})
+	bob := getGRPCNode(t, "127.0.0.1:1239", &PrivateKeyFromFile{"testdata/dht1.priv"})
+	fc := DefaultFlowControl()
+	fc.ChunkSize = 1000
+	fc.MaxMessageSize = 10000
+	assert.NoError(t, alice.SetFlowControl(fc))
+	assert.NoError(t, bob.SetFlowControl(fc))
+	defer alice.Stop()
+	defer bob.Stop()
+
+	session, err := alice.NewSession("", "", "127.0.0.1:1239", []byte(bobID))
+	assert.NoError(t, err)
+	payload := make([]byte, 9500)
+	_, err = rand.Read(payload)
+	assert.NoError(t, err)
+	assert.NoError(t, session.Send(payload))
+	assert.Error(t, session.Send(make([]byte, 10001)))
+
+	masterSession, err := bob.MasterSession()
+	assert.NoError(t, err)
+	msg := <-masterSession.Receive()
+	assert.Equal(t, payload, msg.Payload)
+	assert.Equal(t, aliceID, string(msg.FromPKID))
+}
+
+func TestSlowSessionDoesNotBlockOthers(t *testing.T) {
+	bobID := idForParty(t, "testdata/dht1.pub")
+	alice := getGRPCNode(t, "127.0.0.1:1240", &PrivateKeyFromFile{"testdata/dht.priv"})
+	bob := getGRPCNode(t, "127.0.0.1:1241", &PrivateKeyFromFile{"testdata/dht1.priv"})
+	// the default overflow policy never waits for the slow session
+	fc := DefaultFlowControl()
+	fc.QueueSize = 1
+	assert.NoError(t, bob.SetFlowControl(fc))
+	alice.Start(context.Background())
+	bob.Start(context.Background())
+	defer alice.Stop()
+	defer bob.Stop()
+
+	// open two sessions at bob
+	sessions := map[string]view.Session{}
+	for _, id := range []string{"slow", "fast"} {
+		session, err := alice.NewSessionWithID(id, "", "127.0.0.1:1241", []byte(bobID), nil, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, session.Send([]byte("open")))
+	}
+	masterSession, err := bob.MasterSession()
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		msg := <-masterSession.Receive()
+		session, err := bob.NewSessionWithID(msg.SessionID, msg.ContextID, msg.FromEndpoint, msg.FromPKID, nil, nil)
+		assert.NoError(t, err)
+		sessions[msg.SessionID] = session
+	}
+
+	// nobody reads the slow session, its queue fills up and the next messages are dropped
+	slow, _ := alice.NewSessionWithID("slow", "", "127.0.0.1:1241", []byte(bobID), nil, nil)
+	fast, _ := alice.NewSessionWithID("fast", "", "127.0.0.1:1241", []byte(bobID), nil, nil)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, slow.Send([]byte("slow")))
+	}
+	assert.NoError(t, fast.Send([]byte("fast")))
+
+	select {
+	case msg := <-sessions["fast"].Receive():
+		assert.Equal(t, []byte("fast"), msg.Payload)
+	case <-time.After(10 * time.Second):
+		t.Fatal("fast session blocked by the slow one")
+	}
+	assert.Len(t, sessions["slow"].Receive(), 1)
+}
+
+func TestReassemblePendingBounds(t *testing.T) {
+	fc := DefaultFlowControl()
+	fc.MaxPendingChunked = 2
+	fc.ChunkTimeout = 50 * time.Millisecond
+	s := &streamHandler{node: &P2PNode{flowControl: fc}, chunks: map[string]*chunkedPayload{}}
+	chunk := func(session string, i uint32) *ViewPacket {
+		return &ViewPacket{SessionID: session, Chunk: i, Chunks: 2, Payload: []byte{byte(i)}}
+	}
+
+	// the payloads started beyond the limit are dropped
+	_, complete := s.reassemble(chunk("s1", 0))
+	assert.False(t, complete)
+	_, complete = s.reassemble(chunk("s2", 0))
+	assert.False(t, complete)
+	_, complete = s.reassemble(chunk("s3", 0))
+	assert.False(t, complete)
+	assert.Len(t, s.chunks, 2)
+	_, complete = s.reassemble(chunk("s3", 1))
+	assert.False(t, complete)
+
+	// the pending ones complete
+	payload, complete := s.reassemble(chunk("s1", 1))
+	assert.True(t, complete)
+	assert.Equal(t, []byte{0, 1}, payload)
+	assert.Len(t, s.chunks, 1)
+
+	// incomplete payloads expire, making room for new ones
+	time.Sleep(100 * time.Millisecond)
+	_, complete = s.reassemble(chunk("s4", 0))
+	assert.False(t, complete)
+	assert.Len(t, s.chunks, 1)
+	_, complete = s.reassemble(chunk("s2", 1))
+	assert.False(t, complete)
+	payload, complete = s.reassemble(chunk("s4", 1))
+	assert.True(t, complete)
+	assert.Equal(t, []byte{0, 1}, payload)
+	assert.Len(t, s.chunks, 0)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	peer2 "google.golang.org/grpc/peer"
+
+	grpc2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+// pkidMetadataKey carries the PKI ID of the node opening a grpc stream
+const pkidMetadataKey = "fsc-pkid"
+
+// grpcTransport is the Transport built on gRPC bidirectional streams.
+// Nodes are reached directly at the P2P address returned by the endpoint service, there is no discovery.
+// The PKI ID the caller declares when opening a stream is not authenticated by the transport itself,
+// this is why the communication service requires session authentication with this transport: the packets
+// are accepted only if signed by an FSC identity bound to the declared PKI ID.
+type grpcTransport struct {
+	id     string
+	server *grpc2.GRPCServer
+	client *grpc2.Client
+
+	handler func(stream MessageStream)
+}
+
+// NewGRPCTransport returns a Transport that listens for gRPC streams at the passed address, and opens
+// gRPC streams to the other nodes with the passed client configuration.
+// The listen address is either a multiaddr, as used by libp2p, or a host:port pair.
+func NewGRPCTransport(listenAddress string, keyDispenser PrivateKeyDispenser, serverConfig grpc2.ServerConfig, clientConfig grpc2.ClientConfig) (*grpcTransport, error) {
+	priv, err := keyDispenser.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	ID, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed computing pki id")
+	}
+
+	address, err := toHostPort(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	server, err := grpc2.NewGRPCServer(address, serverConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating grpc server at [%s]", address)
+	}
+	client, err := grpc2.NewGRPCClient(clientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating grpc client")
+	}
+
+	return &grpcTransport{
+		id:     ID.String(),
+		server: server,
+		client: client,
+	}, nil
+}
+
+func (t *grpcTransport) ID() string {
+	return t.id
+}
+
+// Address returns the address the transport listens to
+func (t *grpcTransport) Address() string {
+	return t.server.Address()
+}
+
+func (t *grpcTransport) NewStream(ctx context.Context, address string, peerID string) (MessageStream, error) {
+	conn, err := t.client.NewConnection(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed connecting to [%s] at [%s]", peerID, address)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, pkidMetadataKey, t.id)
+	stream, err := NewP2PServiceClient(conn).OpenStream(ctx)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "failed opening stream to [%s] at [%s]", peerID, address)
+	}
+	return &grpcClientStream{
+		conn:    conn,
+		stream:  stream,
+		peerID:  peerID,
+		address: address,
+	}, nil
+}
+
+func (t *grpcTransport) Start(handler func(stream MessageStream)) error {
+	t.handler = handler
+	RegisterP2PServiceServer(t.server.Server(), t)
+	go func() {
+		if err := t.server.Start(); err != nil {
+			logger.Errorf("grpc transport stopped [%s]", err)
+		}
+	}()
+	return nil
+}
+
+func (t *grpcTransport) Close() error {
+	t.server.Stop()
+	t.client.Close()
+	return nil
+}
+
+// OpenStream serves the streams opened by the other nodes, it returns when the stream gets closed
+func (t *grpcTransport) OpenStream(stream P2PService_OpenStreamServer) error {
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok || len(md.Get(pkidMetadataKey)) == 0 {
+		return errors.New("pki id of the caller not found")
+	}
+	s := &grpcServerStream{
+		stream: stream,
+		peerID: md.Get(pkidMetadataKey)[0],
+		done:   make(chan struct{}),
+	}
+	if p, ok := peer2.FromContext(stream.Context()); ok {
+		s.address = p.Addr.String()
+	}
+
+	t.handler(s)
+
+	select {
+	case <-s.done:
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+// grpcClientStream is the MessageStream of the node that opened the stream
+type grpcClientStream struct {
+	conn      *grpc.ClientConn
+	stream    P2PService_OpenStreamClient
+	sendMutex sync.Mutex
+	peerID    string
+	address   string
+}
+
+func (s *grpcClientStream) RemotePeerID() string {
+	return s.peerID
+}
+
+func (s *grpcClientStream) RemotePeerAddress() string {
+	return s.address
+}
+
+func (s *grpcClientStream) Send(packet *ViewPacket) error {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+	return s.stream.Send(packet)
+}
+
+func (s *grpcClientStream) Recv(packet *ViewPacket) error {
+	msg, err := s.stream.Recv()
+	if err != nil {
+		return err
+	}
+	*packet = *msg
+	return nil
+}
+
+func (s *grpcClientStream) Close() error {
+	s.sendMutex.Lock()
+	err := s.stream.CloseSend()
+	s.sendMutex.Unlock()
+	if err2 := s.conn.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// grpcServerStream is the MessageStream of the node that accepted the stream
+type grpcServerStream struct {
+	stream    P2PService_OpenStreamServer
+	sendMutex sync.Mutex
+	peerID    string
+	address   string
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (s *grpcServerStream) RemotePeerID() string {
+	return s.peerID
+}
+
+func (s *grpcServerStream) RemotePeerAddress() string {
+	return s.address
+}
+
+func (s *grpcServerStream) Send(packet *ViewPacket) error {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+	return s.stream.Send(packet)
+}
+
+func (s *grpcServerStream) Recv(packet *ViewPacket) error {
+	msg, err := s.stream.Recv()
+	if err != nil {
+		return err
+	}
+	*packet = *msg
+	return nil
+}
+
+func (s *grpcServerStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+// toHostPort converts a multiaddr of the form /ip4/<host>/tcp/<port> to <host>:<port>.
+// Any other address is returned as is.
+func toHostPort(address string) (string, error) {
+	if !strings.HasPrefix(address, "/") {
+		return address, nil
+	}
+	parts := strings.Split(address, "/")
+	if len(parts) < 5 || parts[3] != "tcp" {
+		return "", errors.Errorf("invalid listen address [%s], expected /ip4/<host>/tcp/<port>", address)
+	}
+	return net.JoinHostPort(parts[2], parts[4]), nil
+}
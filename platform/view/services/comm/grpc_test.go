@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/assert"
+
+	grpc2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type configService map[string]interface{}
+
+func (c configService) GetString(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c configService) GetBool(key string) bool {
+	v, _ := c[key].(bool)
+	return v
+}
+
+func (c configService) IsSet(key string) bool {
+	_, ok := c[key]
+	return ok
+}
+
+func (c configService) UnmarshalKey(key string, rawVal interface{}) error {
+	return nil
+}
+
+func getGRPCNode(t *testing.T, endpoint string, keyDispenser PrivateKeyDispenser) *P2PNode {
+	transport, err := NewGRPCTransport(
+		endpoint,
+		keyDispenser,
+		grpc2.ServerConfig{ConnectionTimeout: 5 * time.Second},
+		grpc2.ClientConfig{Timeout: 5 * time.Second, KaOpts: grpc2.DefaultKeepaliveOptions},
+	)
+	assert.NoError(t, err)
+	node, err := NewNodeWithTransport(transport)
+	assert.NoError(t, err)
+	assert.NotNil(t, node)
+
+	return node
+}
+
+func TestGRPCTransport(t *testing.T) {
+	aliceID := idForParty(t, "testdata/dht.pub")
+	bobID := idForParty(t, "testdata/dht1.pub")
+	alice := getGRPCNode(t, "/ip4/127.0.0.1/tcp/1236", &PrivateKeyFromFile{"testdata/dht.priv"})
+	bob := getGRPCNode(t, "127.0.0.1:1237", &PrivateKeyFromFile{"testdata/dht1.priv"})
+	assert.Equal(t, aliceID, alice.transport.ID())
+	assert.Equal(t, bobID, bob.transport.ID())
+
+	ctx := context.Background()
+	alice.Start(ctx)
+	bob.Start(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		session, err := alice.NewSession("", "", "127.0.0.1:1237", []byte(bobID))
+		assert.NoError(t, err)
+		assert.NotNil(t, session)
+
+		err = session.Send([]byte("ciao"))
+		assert.NoError(t, err)
+
+		msg := <-session.Receive()
+		assert.Equal(t, []byte("ciaoback"), msg.Payload)
+
+		session.Close()
+	}()
+
+	masterSession, err := bob.MasterSession()
+	assert.NoError(t, err)
+
+	msg := <-masterSession.Receive()
+	assert.Equal(t, []byte("ciao"), msg.Payload)
+	assert.Equal(t, aliceID, string(msg.FromPKID))
+
+	// the answer goes back on the stream opened by alice
+	session, err := bob.NewSessionWithID(msg.SessionID, msg.ContextID, "", msg.FromPKID, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, session.Send([]byte("ciaoback")))
+
+	session.Close()
+
+	wg.Wait()
+
+	alice.Stop()
+	bob.Stop()
+}
+
+func TestGRPCTransportRequiresAuthentication(t *testing.T) {
+	transport, err := NewGRPCTransport(
+		"127.0.0.1:1238",
+		&PrivateKeyFromFile{"testdata/dht.priv"},
+		grpc2.ServerConfig{ConnectionTimeout: 5 * time.Second},
+		grpc2.ClientConfig{Timeout: 5 * time.Second, KaOpts: grpc2.DefaultKeepaliveOptions},
+	)
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	_, err = NewServiceWithTransport(transport, resolver{}, configService{}, view.Identity("alice"), sigService{})
+	assert.EqualError(t, err, "the grpc p2p transport requires fsc.p2p.authentication.enabled")
+}
+
+func TestGRPCTransportSpoofedPKID(t *testing.T) {
+	aliceID := idForParty(t, "testdata/dht.pub")
+	alice := getGRPCNode(t, "127.0.0.1:1239", &PrivateKeyFromFile{"testdata/dht.priv"})
+	bob := getGRPCNode(t, "127.0.0.1:1240", &PrivateKeyFromFile{"testdata/dht1.priv"})
+	eveKey, _, err := crypto.GenerateECDSAKeyPair(rand.Reader)
+	assert.NoError(t, err)
+	eve := getGRPCNode(t, "127.0.0.1:1241", &PrivateKeyFromCryptoKey{Key: eveKey})
+	eveID := eve.transport.ID()
+	// eve declares the pki id of alice when opening streams
+	eve.transport.(*grpcTransport).id = aliceID
+
+	aliceIdentity, bobIdentity, eveIdentity := view.Identity("alice"), view.Identity("bob"), view.Identity("eve")
+	sigs := sigService{}
+	for _, id := range []view.Identity{aliceIdentity, bobIdentity, eveIdentity} {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		sigs[id.UniqueID()] = &ecdsaKey{key: k}
+	}
+	r := resolver{
+		aliceIdentity.UniqueID(): aliceID,
+		bobIdentity.UniqueID():   bob.transport.ID(),
+		eveIdentity.UniqueID():   eveID,
+	}
+	alice.EnableAuthentication(NewAuthenticator(aliceIdentity, sigs, r))
+	bob.EnableAuthentication(NewAuthenticator(bobIdentity, sigs, r))
+	eveAuth := NewAuthenticator(eveIdentity, sigs, r)
+	eveAuth.handshakeTimeout = 500 * time.Millisecond
+	eve.EnableAuthentication(eveAuth)
+
+	ctx := context.Background()
+	alice.Start(ctx)
+	bob.Start(ctx)
+	eve.Start(ctx)
+	defer alice.Stop()
+	defer bob.Stop()
+	defer eve.Stop()
+
+	masterSession, err := bob.MasterSession()
+	assert.NoError(t, err)
+
+	// the handshake of eve is rejected, its identity is not bound to the pki id it declares
+	session, err := eve.NewSession("", "", "127.0.0.1:1240", []byte(bob.transport.ID()))
+	assert.NoError(t, err)
+	assert.Error(t, session.Send([]byte("spoofed")))
+	select {
+	case msg := <-masterSession.Receive():
+		assert.Fail(t, "spoofed message delivered", "payload [%s]", string(msg.Payload))
+	case <-time.After(500 * time.Millisecond):
+	}
+	session.Close()
+
+	// alice gets through
+	session, err = alice.NewSession("", "", "127.0.0.1:1240", []byte(bob.transport.ID()))
+	assert.NoError(t, err)
+	assert.NoError(t, session.Send([]byte("genuine")))
+	select {
+	case msg := <-masterSession.Receive():
+		assert.Equal(t, []byte("genuine"), msg.Payload)
+		assert.Equal(t, aliceID, string(msg.FromPKID))
+		assert.Equal(t, aliceIdentity, msg.FromIdentity)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "message not delivered")
+	}
+	session.Close()
+}
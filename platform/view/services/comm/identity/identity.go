@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
+	pb "github.com/libp2p/go-libp2p-core/crypto/pb"
 	"github.com/pkg/errors"
 )
 
@@ -42,3 +43,54 @@ func NewCryptoPrivKeyFromMSP(secretKeyPath string) (crypto.PrivKey, error) {
 
 	return priv, nil
 }
+
+// Signer signs messages with a private key that might not be exportable
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+}
+
+// signerPrivKey is a libp2p private key backed by a signer, for the keys that cannot be exported,
+// like those in a hardware security module.
+type signerPrivKey struct {
+	signer Signer
+	pub    crypto.PubKey
+}
+
+// NewCryptoPrivKeyFromSigner returns a libp2p private key that signs with the passed signer.
+// The signer must produce ASN.1 encoded ECDSA signatures of the SHA-256 digest of the messages.
+func NewCryptoPrivKeyFromSigner(signer Signer, pk *ecdsa.PublicKey) (crypto.PrivKey, error) {
+	raw, err := x509.MarshalPKIXPublicKey(pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling public key")
+	}
+	pub, err := crypto.UnmarshalECDSAPublicKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &signerPrivKey{signer: signer, pub: pub}, nil
+}
+
+func (k *signerPrivKey) Bytes() ([]byte, error) {
+	return nil, errors.New("private key not exportable")
+}
+
+func (k *signerPrivKey) Equals(o crypto.Key) bool {
+	other, ok := o.(*signerPrivKey)
+	return ok && k.pub.Equals(other.pub)
+}
+
+func (k *signerPrivKey) Raw() ([]byte, error) {
+	return nil, errors.New("private key not exportable")
+}
+
+func (k *signerPrivKey) Type() pb.KeyType {
+	return pb.KeyType_ECDSA
+}
+
+func (k *signerPrivKey) Sign(data []byte) ([]byte, error) {
+	return k.signer.Sign(data)
+}
+
+func (k *signerPrivKey) GetPublic() crypto.PubKey {
+	return k.pub
+}
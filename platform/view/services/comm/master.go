@@ -36,8 +36,9 @@ func (p *P2PNode) getOrCreateSession(sessionID, endpointAddress, contextID, call
 		caller:          caller,
 		sessionID:       sessionID,
 		node:            p,
-		incoming:        make(chan *view.Message, 1),
+		incoming:        make(chan *view.Message, p.flowControl.QueueSize),
 		streams:         make(map[*streamHandler]struct{}),
+		done:            make(chan struct{}),
 	}
 
 	if msg != nil {
@@ -48,6 +49,7 @@ func (p *P2PNode) getOrCreateSession(sessionID, endpointAddress, contextID, call
 	}
 
 	p.sessions[internalSessionID] = s
+	p.metrics.SessionsOpened.Add(1)
 
 	logger.Infof("session [%s] as internal session [%s] ready", sessionID, internalSessionID)
 
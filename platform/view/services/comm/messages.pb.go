@@ -4,10 +4,14 @@
 package comm
 
 import (
+	context "context"
 	fmt "fmt"
 	math "math"
 
 	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -22,11 +26,30 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type ViewPacket struct {
-	SessionID            string   `protobuf:"bytes,1,opt,name=sessionID,proto3" json:"sessionID,omitempty"`
-	ContextID            string   `protobuf:"bytes,2,opt,name=contextID,proto3" json:"contextID,omitempty"`
-	Status               int32    `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
-	Payload              []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
-	Caller               string   `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	SessionID string `protobuf:"bytes,1,opt,name=sessionID,proto3" json:"sessionID,omitempty"`
+	ContextID string `protobuf:"bytes,2,opt,name=contextID,proto3" json:"contextID,omitempty"`
+	Status    int32  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Payload   []byte `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Caller    string `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	// identity is the FSC identity of the sender, set when session authentication is enabled
+	Identity []byte `protobuf:"bytes,6,opt,name=identity,proto3" json:"identity,omitempty"`
+	// signature is the signature of the sender identity over the packet, the recipient peer ID and, after the handshake,
+	// the nonce of the recipient for the session
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	// chunk is the index of this packet among the chunks of a payload split by the sender
+	Chunk uint32 `protobuf:"varint,8,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// chunks is the number of chunks the payload is split into, zero or one if the payload is not split
+	Chunks uint32 `protobuf:"varint,9,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	// traceparent is the W3C trace context of the view that sent the packet, empty if the view is not traced
+	Traceparent string `protobuf:"bytes,10,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	// tracestate is the vendor specific W3C trace state that accompanies traceparent
+	Tracestate string `protobuf:"bytes,11,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
+	// counter is the sequence number of the packet among the packets sent on the session, set when session authentication is enabled
+	Counter uint64 `protobuf:"varint,12,opt,name=counter,proto3" json:"counter,omitempty"`
+	// nonce is the nonce of the sender for the session, set only in the handshake packets
+	Nonce []byte `protobuf:"bytes,13,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// challenge is the nonce of the handshake packet this packet answers, set only in the handshake replies
+	Challenge            []byte   `protobuf:"bytes,14,opt,name=challenge,proto3" json:"challenge,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -92,6 +115,69 @@ func (m *ViewPacket) GetCaller() string {
 	return ""
 }
 
+func (m *ViewPacket) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+func (m *ViewPacket) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ViewPacket) GetChunk() uint32 {
+	if m != nil {
+		return m.Chunk
+	}
+	return 0
+}
+
+func (m *ViewPacket) GetChunks() uint32 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *ViewPacket) GetTraceparent() string {
+	if m != nil {
+		return m.Traceparent
+	}
+	return ""
+}
+
+func (m *ViewPacket) GetTracestate() string {
+	if m != nil {
+		return m.Tracestate
+	}
+	return ""
+}
+
+func (m *ViewPacket) GetCounter() uint64 {
+	if m != nil {
+		return m.Counter
+	}
+	return 0
+}
+
+func (m *ViewPacket) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *ViewPacket) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ViewPacket)(nil), "comm.ViewPacket")
 }
@@ -99,16 +185,137 @@ func init() {
 func init() { proto.RegisterFile("support/comm/messages.proto", fileDescriptor_cfe10148d8664c22) }
 
 var fileDescriptor_cfe10148d8664c22 = []byte{
-	// 164 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x92, 0x2e, 0x2e, 0x2d, 0x28,
-	0xc8, 0x2f, 0x2a, 0xd1, 0x4f, 0xce, 0xcf, 0xcd, 0xd5, 0xcf, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0x4f,
-	0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01, 0x09, 0x2a, 0x4d, 0x62, 0xe4, 0xe2,
-	0x72, 0xcb, 0xc9, 0x2f, 0x0f, 0x48, 0x4c, 0xce, 0x4e, 0x2d, 0x11, 0x92, 0xe1, 0xe2, 0x2c, 0x06,
-	0x2a, 0xcb, 0xcc, 0xcf, 0xf3, 0x74, 0x91, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x42, 0x08, 0x80,
-	0x64, 0x93, 0xf3, 0xf3, 0x4a, 0x52, 0x2b, 0x4a, 0x80, 0xb2, 0x4c, 0x10, 0x59, 0xb8, 0x80, 0x90,
-	0x18, 0x17, 0x5b, 0x71, 0x49, 0x62, 0x49, 0x69, 0xb1, 0x04, 0x33, 0x50, 0x8a, 0x35, 0x08, 0xca,
-	0x13, 0x92, 0xe0, 0x62, 0x2f, 0x48, 0xac, 0xcc, 0xc9, 0x4f, 0x4c, 0x91, 0x60, 0x01, 0x4a, 0xf0,
-	0x04, 0xc1, 0xb8, 0x20, 0x1d, 0xc9, 0x89, 0x39, 0x39, 0xa9, 0x45, 0x12, 0xac, 0x60, 0xc3, 0xa0,
-	0xbc, 0x24, 0x36, 0xb0, 0x0b, 0x8d, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x17, 0x5c, 0x1c, 0xb7,
-	0xc0, 0x00, 0x00, 0x00,
+	// 315 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcd, 0x4a, 0x03, 0x31,
+	0x10, 0xc7, 0x89, 0xfd, 0x9e, 0xb6, 0x22, 0x41, 0x64, 0xa8, 0x22, 0x8b, 0xa7, 0x3d, 0xb5, 0x52,
+	0x7d, 0x02, 0xe9, 0xa5, 0x27, 0xcb, 0x16, 0xbc, 0xc7, 0x74, 0x68, 0x97, 0x76, 0x93, 0x25, 0x99,
+	0x55, 0xfb, 0x0a, 0x3e, 0xb5, 0x24, 0xdb, 0x2f, 0xf0, 0xb6, 0xbf, 0xdf, 0xec, 0x24, 0xff, 0xc9,
+	0xc0, 0xbd, 0xaf, 0xca, 0xd2, 0x3a, 0x9e, 0x68, 0x5b, 0x14, 0x93, 0x82, 0xbc, 0x57, 0x6b, 0xf2,
+	0xe3, 0xd2, 0x59, 0xb6, 0xb2, 0x19, 0xe4, 0xd3, 0x6f, 0x03, 0xe0, 0x23, 0xa7, 0xef, 0x85, 0xd2,
+	0x5b, 0x62, 0xf9, 0x00, 0x3d, 0x4f, 0xde, 0xe7, 0xd6, 0xcc, 0x67, 0x28, 0x12, 0x91, 0xf6, 0xb2,
+	0xb3, 0x08, 0x55, 0x6d, 0x0d, 0xd3, 0x0f, 0xcf, 0x67, 0x78, 0x55, 0x57, 0x4f, 0x42, 0xde, 0x41,
+	0xdb, 0xb3, 0xe2, 0xca, 0x63, 0x23, 0x11, 0x69, 0x2b, 0x3b, 0x90, 0x44, 0xe8, 0x94, 0x6a, 0xbf,
+	0xb3, 0x6a, 0x85, 0xcd, 0x44, 0xa4, 0x83, 0xec, 0x88, 0xa1, 0x43, 0xab, 0xdd, 0x8e, 0x1c, 0xb6,
+	0xe2, 0x61, 0x07, 0x92, 0x23, 0xe8, 0xe6, 0x2b, 0x32, 0x9c, 0xf3, 0x1e, 0xdb, 0xb1, 0xe5, 0xc4,
+	0x31, 0x61, 0xbe, 0x36, 0x8a, 0x2b, 0x47, 0xd8, 0x89, 0xc5, 0xb3, 0x90, 0xb7, 0xd0, 0xd2, 0x9b,
+	0xca, 0x6c, 0xb1, 0x9b, 0x88, 0x74, 0x98, 0xd5, 0x10, 0xef, 0x09, 0x1f, 0x1e, 0x7b, 0x51, 0x1f,
+	0x48, 0x26, 0xd0, 0x67, 0xa7, 0x34, 0x95, 0xca, 0x91, 0x61, 0x84, 0x18, 0xe2, 0x52, 0xc9, 0x47,
+	0x80, 0x88, 0x61, 0x14, 0xc2, 0x7e, 0xfc, 0xe1, 0xc2, 0x84, 0xd9, 0xb4, 0xad, 0x0c, 0x93, 0xc3,
+	0x41, 0x22, 0xd2, 0x66, 0x76, 0xc4, 0x90, 0xc4, 0x58, 0xa3, 0x09, 0x87, 0x31, 0x63, 0x0d, 0xf1,
+	0x05, 0x37, 0x61, 0x48, 0xb3, 0x26, 0xbc, 0xae, 0xd3, 0x9f, 0xc4, 0xf4, 0x0d, 0x60, 0x31, 0x5d,
+	0x2c, 0xc9, 0x7d, 0xe5, 0x9a, 0xe4, 0x2b, 0xc0, 0x7b, 0x49, 0x66, 0xc9, 0x8e, 0x54, 0x21, 0x6f,
+	0xc6, 0x61, 0x5f, 0xe3, 0xf3, 0xae, 0x46, 0xff, 0x4c, 0x2a, 0x9e, 0xc5, 0x67, 0x3b, 0x6e, 0xf7,
+	0xe5, 0x6f, 0x00, 0x70, 0xbf, 0x6e, 0x10, 0xfc, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// P2PServiceClient is the client API for P2PService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type P2PServiceClient interface {
+	OpenStream(ctx context.Context, opts ...grpc.CallOption) (P2PService_OpenStreamClient, error)
+}
+
+type p2PServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewP2PServiceClient(cc *grpc.ClientConn) P2PServiceClient {
+	return &p2PServiceClient{cc}
+}
+
+func (c *p2PServiceClient) OpenStream(ctx context.Context, opts ...grpc.CallOption) (P2PService_OpenStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2PService_serviceDesc.Streams[0], "/comm.P2PService/OpenStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2PServiceOpenStreamClient{stream}
+	return x, nil
+}
+
+type P2PService_OpenStreamClient interface {
+	Send(*ViewPacket) error
+	Recv() (*ViewPacket, error)
+	grpc.ClientStream
+}
+
+type p2PServiceOpenStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2PServiceOpenStreamClient) Send(m *ViewPacket) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *p2PServiceOpenStreamClient) Recv() (*ViewPacket, error) {
+	m := new(ViewPacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// P2PServiceServer is the server API for P2PService service.
+type P2PServiceServer interface {
+	OpenStream(P2PService_OpenStreamServer) error
+}
+
+// UnimplementedP2PServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedP2PServiceServer struct {
+}
+
+func (*UnimplementedP2PServiceServer) OpenStream(srv P2PService_OpenStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method OpenStream not implemented")
+}
+
+func RegisterP2PServiceServer(s *grpc.Server, srv P2PServiceServer) {
+	s.RegisterService(&_P2PService_serviceDesc, srv)
+}
+
+func _P2PService_OpenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(P2PServiceServer).OpenStream(&p2PServiceOpenStreamServer{stream})
+}
+
+type P2PService_OpenStreamServer interface {
+	Send(*ViewPacket) error
+	Recv() (*ViewPacket, error)
+	grpc.ServerStream
+}
+
+type p2PServiceOpenStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2PServiceOpenStreamServer) Send(m *ViewPacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *p2PServiceOpenStreamServer) Recv() (*ViewPacket, error) {
+	m := new(ViewPacket)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _P2PService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "comm.P2PService",
+	HandlerType: (*P2PServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "OpenStream",
+			Handler:       _P2PService_OpenStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "support/comm/messages.proto",
 }
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+)
+
+var (
+	sessionsOpenedCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "sessions_opened",
+		Help:      "Sessions opened. Opened minus closed is the number of active sessions.",
+	}
+
+	sessionsClosedCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "sessions_closed",
+		Help:      "Sessions closed. Opened minus closed is the number of active sessions.",
+	}
+
+	messagesSentCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "messages_sent",
+		Help:      "Messages sent on the sessions.",
+	}
+
+	messagesReceivedCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "messages_received",
+		Help:      "Messages received on the sessions.",
+	}
+
+	messagesDroppedCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "messages_dropped",
+		Help:      "Messages dropped because their session queue was full or closed, or their authentication failed.",
+	}
+)
+
+// Metrics are the metrics of the sessions of a node
+type Metrics struct {
+	SessionsOpened   metrics.Counter
+	SessionsClosed   metrics.Counter
+	MessagesSent     metrics.Counter
+	MessagesReceived metrics.Counter
+	MessagesDropped  metrics.Counter
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		SessionsOpened:   p.NewCounter(sessionsOpenedCounterOpts),
+		SessionsClosed:   p.NewCounter(sessionsClosedCounterOpts),
+		MessagesSent:     p.NewCounter(messagesSentCounterOpts),
+		MessagesReceived: p.NewCounter(messagesReceivedCounterOpts),
+		MessagesDropped:  p.NewCounter(messagesDroppedCounterOpts),
+	}
+}
+
+var disabledMetrics = NewMetrics(&disabled.Provider{})
@@ -12,18 +12,12 @@ import (
 	"encoding/binary"
 	"errors"
 	io2 "io"
+	"io/ioutil"
 	"sync"
-	"sync/atomic"
 
 	"github.com/gogo/protobuf/io"
-	protoio "github.com/gogo/protobuf/io"
 	"github.com/gogo/protobuf/proto"
-	"github.com/libp2p/go-libp2p-core/host"
-	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
-	"github.com/libp2p/go-libp2p-core/protocol"
-	discovery "github.com/libp2p/go-libp2p-discovery"
-	dht "github.com/libp2p/go-libp2p-kad-dht"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
@@ -35,7 +29,10 @@ const (
 	masterSession    = "master of puppets I'm pulling your strings"
 )
 
-var errStreamNotFound = errors.New("stream not found")
+var (
+	errStreamNotFound = errors.New("stream not found")
+	errFrameTooLarge  = errors.New("frame too large")
+)
 
 var logger = flogging.MustGetLogger("view-sdk")
 
@@ -45,24 +42,67 @@ type messageWithStream struct {
 }
 
 type P2PNode struct {
-	host             host.Host
-	dht              *dht.IpfsDHT
-	finder           *discovery.RoutingDiscovery
-	peersMutex       sync.RWMutex
-	peers            map[string]peer.AddrInfo
-	incomingMessages chan *messageWithStream
-	streamsMutex     sync.RWMutex
-	streams          map[peer.ID][]*streamHandler
-	dispatchMutex    sync.Mutex
-	sessionsMutex    sync.Mutex
-	sessions         map[string]*NetworkStreamSession
-	stopFinder       int32
-	finderWg         sync.WaitGroup
-	isStopping       bool
+	transport     Transport
+	streamsMutex  sync.RWMutex
+	streams       map[string][]*streamHandler
+	sessionsMutex sync.Mutex
+	sessions      map[string]*NetworkStreamSession
+	isStopping    bool
+	flowControl   FlowControl
+	// auth, if set, authenticates the packets exchanged with the other nodes
+	auth    *Authenticator
+	metrics *Metrics
+}
+
+// NewNodeWithTransport returns a new node exchanging packets with the other nodes over the passed transport
+func NewNodeWithTransport(transport Transport) (*P2PNode, error) {
+	node := &P2PNode{
+		transport:   transport,
+		streams:     make(map[string][]*streamHandler),
+		sessions:    make(map[string]*NetworkStreamSession),
+		isStopping:  false,
+		flowControl: DefaultFlowControl(),
+		metrics:     disabledMetrics,
+	}
+	if err := transport.Start(node.handleStream); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// EnableAuthentication makes this node run a handshake with the other nodes when a session opens, sign the outgoing
+// packets, and drop the incoming packets that are not signed by the identity bound to the sender
+func (p *P2PNode) EnableAuthentication(auth *Authenticator) {
+	p.auth = auth
+}
+
+// SetFlowControl sets the flow control of the sessions of this node.
+// It must be called before any session is opened.
+func (p *P2PNode) SetFlowControl(flowControl FlowControl) error {
+	if err := flowControl.Validate(); err != nil {
+		return err
+	}
+	p.flowControl = flowControl
+	return nil
+}
+
+// SetMetrics sets the metrics the sessions of this node are reported to.
+// It must be called before any session is opened.
+func (p *P2PNode) SetMetrics(metrics *Metrics) {
+	p.metrics = metrics
+}
+
+// HealthCheck returns an error if this node is stopping
+func (p *P2PNode) HealthCheck(context.Context) error {
+	p.streamsMutex.RLock()
+	defer p.streamsMutex.RUnlock()
+	if p.isStopping {
+		return errors.New("p2p node stopping")
+	}
+	return nil
 }
 
 func (p *P2PNode) Start(ctx context.Context) {
-	go p.dispatchMessages(ctx)
 	go func() {
 		<-ctx.Done()
 		p.Stop()
@@ -74,78 +114,61 @@ func (p *P2PNode) Stop() {
 	p.isStopping = true
 	p.streamsMutex.Unlock()
 
-	p.host.Close()
-	atomic.StoreInt32(&p.stopFinder, 1)
+	if err := p.transport.Close(); err != nil {
+		logger.Debugf("failed closing transport [%s]", err)
+	}
 
 	for _, streams := range p.streams {
 		for _, stream := range streams {
 			stream.close()
 		}
 	}
-
-	close(p.incomingMessages)
-	p.finderWg.Wait()
 }
 
-func (p *P2PNode) dispatchMessages(ctx context.Context) {
-	for {
-		select {
-		case msg, ok := <-p.incomingMessages:
-			if !ok {
-				logger.Debugf("channel closed, returning")
-				return
-			}
-
-			logger.Debugf("dispatch message from [%s,%s] on session [%s]", msg.message.FromEndpoint, view.Identity(msg.message.FromPKID).String(), msg.message.SessionID)
-
-			p.dispatchMutex.Lock()
-
-			p.sessionsMutex.Lock()
-			internalSessionID := computeInternalSessionID(msg.message.SessionID, msg.message.FromEndpoint, msg.message.FromPKID)
-			logger.Debugf("dispatch message on internal session [%s]", internalSessionID)
-			session, in := p.sessions[internalSessionID]
-			if in {
-				logger.Debugf("internal session exists [%s]", internalSessionID)
-				session.mutex.Lock()
-				session.callerViewID = msg.message.Caller
-				session.contextID = msg.message.ContextID
-				session.endpointAddress = msg.message.FromEndpoint
-				// here we know that msg.stream is used for session:
-				// 1) increment the used counter for msg.stream
-				msg.stream.refCtr++
-				// 2) add msg.stream to the list of streams used by session
-				session.streams[msg.stream] = struct{}{}
-				session.mutex.Unlock()
-			}
-			p.sessionsMutex.Unlock()
-
-			if !in {
-				// create session but redirect this first message to master
-				//_, _ = p.getOrCreateSession(
-				//	msg.message.SessionID,
-				//	msg.message.FromEndpoint,
-				//	msg.message.ContextID,
-				//	"",
-				//	nil,
-				//	msg.message.FromPKID,
-				//	nil,
-				//)
-
-				logger.Debugf("internal session does not exists [%s], dispatching to master session", internalSessionID)
-				session, _ = p.getOrCreateSession(masterSession, "", "", "", nil, []byte{}, nil)
-			}
-			p.dispatchMutex.Unlock()
-
-			logger.Debugf("pushing message to [%s], [%s]", internalSessionID, msg.message)
-			session.incoming <- msg.message
-		case <-ctx.Done():
-			logger.Info("closing p2p comm...")
-			return
-		}
+// dispatch delivers the passed message to its session, or to the master session if the session does not exist yet.
+// It runs on the goroutine reading the stream the message comes from, which is shared by all the sessions with
+// the same node, then it never waits for a session queue to have room: the overflow policy applies instead.
+func (p *P2PNode) dispatch(msg *messageWithStream) {
+	logger.Debugf("dispatch message from [%s,%s] on session [%s]", msg.message.FromEndpoint, view.Identity(msg.message.FromPKID).String(), msg.message.SessionID)
+
+	p.sessionsMutex.Lock()
+	internalSessionID := computeInternalSessionID(msg.message.SessionID, msg.message.FromEndpoint, msg.message.FromPKID)
+	logger.Debugf("dispatch message on internal session [%s]", internalSessionID)
+	session, in := p.sessions[internalSessionID]
+	if in && len(msg.message.FromIdentity) != 0 && len(session.caller) != 0 && !session.caller.Equal(msg.message.FromIdentity) {
+		auditLogger.Warnf("dropping message on session [%s] from [%s], expected [%s]", msg.message.SessionID, msg.message.FromIdentity, session.caller)
+		p.sessionsMutex.Unlock()
+		p.metrics.MessagesDropped.Add(1)
+		return
+	}
+	if in {
+		logger.Debugf("internal session exists [%s]", internalSessionID)
+		session.mutex.Lock()
+		session.callerViewID = msg.message.Caller
+		session.contextID = msg.message.ContextID
+		session.endpointAddress = msg.message.FromEndpoint
+		// here we know that msg.stream is used for session:
+		// 1) increment the used counter for msg.stream
+		msg.stream.refCtr++
+		// 2) add msg.stream to the list of streams used by session
+		session.streams[msg.stream] = struct{}{}
+		session.mutex.Unlock()
+	}
+	p.sessionsMutex.Unlock()
+
+	overflow := p.flowControl.Overflow
+	if !in {
+		logger.Debugf("internal session does not exists [%s], dispatching to master session", internalSessionID)
+		session, _ = p.getOrCreateSession(masterSession, "", "", "", nil, []byte{}, nil)
+		// the master session is never closed, the first messages of the sessions are dropped if it is overwhelmed
+		overflow = OverflowDrop
 	}
+
+	logger.Debugf("pushing message to [%s], [%s]", internalSessionID, msg.message)
+	session.enqueue(msg.message, overflow)
 }
 
-func (p *P2PNode) sendWithCachedStreams(ID peer.ID, msg proto.Message) error {
+func (p *P2PNode) sendWithCachedStreams(ID string, msg *ViewPacket) error {
 	p.streamsMutex.RLock()
 	defer p.streamsMutex.RUnlock()
 	for _, stream := range p.streams[ID] {
@@ -157,69 +180,80 @@ func (p *P2PNode) sendWithCachedStreams(ID peer.ID, msg proto.Message) error {
 	return errStreamNotFound
 }
 
-func (p *P2PNode) sendTo(IDString string, msg proto.Message) error {
-	ID, err := peer.Decode(IDString)
-	if err != nil {
-		return err
+// sendTo sends the passed packet to the node with the passed PKI ID, reachable at the passed address
+func (p *P2PNode) sendTo(ID string, address string, msg *ViewPacket) error {
+	if p.auth != nil {
+		send := func(hello *ViewPacket) error {
+			return p.send(ID, address, hello)
+		}
+		if err := p.auth.Sign(msg, ID, send); err != nil {
+			return err
+		}
 	}
+	return p.send(ID, address, msg)
+}
 
+// send sends the passed packet as it is, opening a new stream if no stream to the node is open yet
+func (p *P2PNode) send(ID string, address string, msg *ViewPacket) error {
 	if err := p.sendWithCachedStreams(ID, msg); err != errStreamNotFound {
 		return err
 	}
 
-	nwStream, err := p.host.NewStream(context.Background(), ID, protocol.ID(viewProtocol))
+	stream, err := p.transport.NewStream(context.Background(), address, ID)
 	if err != nil {
 		return err
 	}
 
-	p.handleStream()(nwStream)
+	p.handleStream(stream)
 
 	return p.sendWithCachedStreams(ID, msg)
 }
 
-func (p *P2PNode) handleStream() network.StreamHandler {
-	return func(stream network.Stream) {
-		sh := &streamHandler{
-			stream: stream,
-			reader: NewDelimitedReader(stream, 655360*2),
-			writer: io.NewDelimitedWriter(stream),
-			node:   p,
-		}
+func (p *P2PNode) handleStream(stream MessageStream) {
+	sh := &streamHandler{
+		stream: stream,
+		node:   p,
+		chunks: make(map[string]*chunkedPayload),
+	}
 
-		p.streamsMutex.Lock()
-		p.streams[sh.stream.Conn().RemotePeer()] = append(p.streams[sh.stream.Conn().RemotePeer()], sh)
-		p.streamsMutex.Unlock()
+	p.streamsMutex.Lock()
+	p.streams[stream.RemotePeerID()] = append(p.streams[stream.RemotePeerID()], sh)
+	p.streamsMutex.Unlock()
 
-		go sh.handleIncoming()
-	}
+	go sh.handleIncoming()
 }
 
+// Lookup returns the addresses of the passed peer, if discovered. Only the libp2p transport discovers peers.
 func (p *P2PNode) Lookup(peerID string) (peer.AddrInfo, bool) {
-	p.peersMutex.RLock()
-	defer p.peersMutex.RUnlock()
-
-	peer, in := p.peers[peerID]
-	return peer, in
+	h, ok := p.transport.(*libp2pHost)
+	if !ok {
+		return peer.AddrInfo{}, false
+	}
+	return h.Lookup(peerID)
 }
 
 type streamHandler struct {
-	stream network.Stream
-	reader protoio.ReadCloser
-	writer protoio.WriteCloser
+	stream MessageStream
 	node   *P2PNode
 	wg     sync.WaitGroup
 	refCtr int
+	// chunks collects, by session, the chunks received on this stream
+	chunks map[string]*chunkedPayload
 }
 
-func (s *streamHandler) send(msg proto.Message) error {
-	return s.writer.WriteMsg(msg)
+func (s *streamHandler) send(msg *ViewPacket) error {
+	return s.stream.Send(msg)
 }
 
 func (s *streamHandler) handleIncoming() {
 	s.wg.Add(1)
 	for {
 		msg := &ViewPacket{}
-		err := s.reader.ReadMsg(msg)
+		err := s.stream.Recv(msg)
+		if err == errFrameTooLarge {
+			logger.Warnf("dropping message from [%s]: [%s]", s.stream.RemotePeerID(), err)
+			continue
+		}
 		if err != nil {
 			s.node.streamsMutex.Lock()
 			if s.node.isStopping {
@@ -229,9 +263,9 @@ func (s *streamHandler) handleIncoming() {
 			logger.Errorf("caught error: %s", err.Error())
 			defer s.node.streamsMutex.Unlock()
 
-			for i, thisSH := range s.node.streams[s.stream.Conn().RemotePeer()] {
+			for i, thisSH := range s.node.streams[s.stream.RemotePeerID()] {
 				if thisSH == s {
-					s.node.streams[s.stream.Conn().RemotePeer()] = append(s.node.streams[s.stream.Conn().RemotePeer()][:i], s.node.streams[s.stream.Conn().RemotePeer()][i+1:]...)
+					s.node.streams[s.stream.RemotePeerID()] = append(s.node.streams[s.stream.RemotePeerID()][:i], s.node.streams[s.stream.RemotePeerID()][i+1:]...)
 					s.wg.Done()
 					return
 				}
@@ -241,24 +275,62 @@ func (s *streamHandler) handleIncoming() {
 		}
 		logger.Debugf("incoming message from [%s] on session [%s]", msg.Caller, msg.SessionID)
 
-		s.node.incomingMessages <- &messageWithStream{
+		var identity view.Identity
+		if s.node.auth != nil {
+			if IsHandshake(msg) {
+				s.handshake(msg)
+				continue
+			}
+			identity, err = s.node.auth.Verify(msg, s.stream.RemotePeerID(), s.node.transport.ID())
+			if err != nil {
+				auditLogger.Warnf("dropping message from [%s] on session [%s]: [%s]", s.stream.RemotePeerID(), msg.SessionID, err)
+				s.node.metrics.MessagesDropped.Add(1)
+				continue
+			}
+			auditLogger.Debugf("message from [%s] on session [%s] signed by [%s]", s.stream.RemotePeerID(), msg.SessionID, identity)
+		}
+
+		payload, complete := s.reassemble(msg)
+		if !complete {
+			continue
+		}
+
+		s.node.metrics.MessagesReceived.Add(1)
+		s.node.dispatch(&messageWithStream{
 			message: &view.Message{
 				ContextID:    msg.ContextID,
 				SessionID:    msg.SessionID,
 				Status:       msg.Status,
-				Payload:      msg.Payload,
+				Payload:      payload,
 				Caller:       msg.Caller,
-				FromEndpoint: s.stream.Conn().RemoteMultiaddr().String(),
-				FromPKID:     []byte(s.stream.Conn().RemotePeer().String()),
+				FromEndpoint: s.stream.RemotePeerAddress(),
+				FromPKID:     []byte(s.stream.RemotePeerID()),
+				FromIdentity: identity,
+				TraceParent:  msg.Traceparent,
+				TraceState:   msg.Tracestate,
 			},
 			stream: s,
-		}
+		})
+	}
+}
+
+// handshake processes the passed handshake packet and sends the reply, if any, back on this stream
+func (s *streamHandler) handshake(msg *ViewPacket) {
+	reply, err := s.node.auth.Handshake(msg, s.stream.RemotePeerID(), s.node.transport.ID())
+	if err != nil {
+		auditLogger.Warnf("dropping handshake from [%s] on session [%s]: [%s]", s.stream.RemotePeerID(), msg.SessionID, err)
+		s.node.metrics.MessagesDropped.Add(1)
+		return
+	}
+	if reply == nil {
+		return
+	}
+	if err := s.send(reply); err != nil {
+		logger.Warnf("failed replying to handshake from [%s] on session [%s]: [%s]", s.stream.RemotePeerID(), msg.SessionID, err)
 	}
 }
 
 func (s *streamHandler) close() {
-	s.reader.Close()
-	s.writer.Close()
 	s.stream.Close()
 	//s.wg.Wait()
 }
@@ -287,12 +359,17 @@ func (r *varintReader) ReadMsg(msg proto.Message) error {
 	if length < 0 {
 		return io2.ErrShortBuffer
 	}
+	if length > r.maxSize {
+		// skip the frame to keep reading the next ones
+		if _, err := io2.CopyN(ioutil.Discard, r.r, int64(length)); err != nil {
+			return err
+		}
+		logger.Warnf("skipped message of length [%d], max is [%d]", length64, r.maxSize)
+		return errFrameTooLarge
+	}
 	if len(r.buf) < length {
 		r.buf = make([]byte, length)
 	}
-	if len(r.buf) >= r.maxSize {
-		logger.Warnf("reading message length [%d]", length64)
-	}
 	buf := r.buf[:length]
 	if _, err := io2.ReadFull(r.r, buf); err != nil {
 		return err
@@ -121,29 +121,33 @@ func P2PLayerTestRound(t *testing.T, bootstrapNode, node *P2PNode, bootstrapNode
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		messages := bootstrapNode.incomingMessages
+		masterSession, err := bootstrapNode.MasterSession()
+		assert.NoError(t, err)
+		messages := masterSession.Receive()
 
-		err := bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg1")})
+		err = bootstrapNode.sendTo(nodeID, "", &ViewPacket{Payload: []byte("msg1")})
 		assert.NoError(t, err)
 
-		err = bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg2")})
+		err = bootstrapNode.sendTo(nodeID, "", &ViewPacket{Payload: []byte("msg2")})
 		assert.NoError(t, err)
 
 		msg := <-messages
 		assert.NotNil(t, msg)
-		assert.Equal(t, []byte("msg3"), msg.message.Payload)
+		assert.Equal(t, []byte("msg3"), msg.Payload)
 	}()
 
-	messages := node.incomingMessages
+	masterSession, err := node.MasterSession()
+	assert.NoError(t, err)
+	messages := masterSession.Receive()
 	msg := <-messages
 	assert.NotNil(t, msg)
-	assert.Equal(t, []byte("msg1"), msg.message.Payload)
+	assert.Equal(t, []byte("msg1"), msg.Payload)
 
 	msg = <-messages
 	assert.NotNil(t, msg)
-	assert.Equal(t, []byte("msg2"), msg.message.Payload)
+	assert.Equal(t, []byte("msg2"), msg.Payload)
 
-	err := node.sendTo(bootstrapNodeID, &ViewPacket{Payload: []byte("msg3")})
+	err = node.sendTo(bootstrapNodeID, "", &ViewPacket{Payload: []byte("msg3")})
 	assert.NoError(t, err)
 
 	wg.Wait()
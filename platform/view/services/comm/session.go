@@ -7,8 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	"context"
 	"sync"
 
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -24,23 +28,43 @@ type NetworkStreamSession struct {
 	incoming        chan *view.Message
 	streams         map[*streamHandler]struct{}
 	closed          bool
+	traceParent     string
+	traceState      string
 	mutex           sync.Mutex
+
+	// done is closed when the session gets closed
+	done      chan struct{}
+	closeOnce sync.Once
+	// queueLock prevents incoming from being closed while a message is pushed into it
+	queueLock sync.RWMutex
+	// sendMutex keeps the chunks of a payload together on the wire
+	sendMutex sync.Mutex
 }
 
 func (n *NetworkStreamSession) Info() view.SessionInfo {
 	n.mutex.Lock()
 	ret := view.SessionInfo{
-		ID:           n.sessionID,
-		Caller:       n.caller,
-		CallerViewID: n.callerViewID,
-		Endpoint:     n.endpointAddress,
-		EndpointPKID: n.endpointID,
-		Closed:       n.closed,
+		ID:            n.sessionID,
+		Caller:        n.caller,
+		CallerViewID:  n.callerViewID,
+		Endpoint:      n.endpointAddress,
+		EndpointPKID:  n.endpointID,
+		Closed:        n.closed,
+		Authenticated: n.node.auth != nil,
 	}
 	n.mutex.Unlock()
 	return ret
 }
 
+// SetContext binds the session to the span of the passed context.
+// The messages sent afterwards carry the span context to the endpoint, whose views become child spans of it.
+func (n *NetworkStreamSession) SetContext(ctx context.Context) {
+	traceParent, traceState := tracing.Inject(ctx)
+	n.mutex.Lock()
+	n.traceParent, n.traceState = traceParent, traceState
+	n.mutex.Unlock()
+}
+
 // Send sends the payload to the endpoint
 func (n *NetworkStreamSession) Send(payload []byte) error {
 	return n.sendWithStatus(payload, view.OK)
@@ -58,6 +82,10 @@ func (n *NetworkStreamSession) Receive() <-chan *view.Message {
 
 // Close releases all the resources allocated by this session
 func (n *NetworkStreamSession) Close() {
+	n.closeOnce.Do(n.close)
+}
+
+func (n *NetworkStreamSession) close() {
 	defer logger.Debugf("Closing session [%s]", n.sessionID)
 	n.node.sessionsMutex.Lock()
 	toClose := make([]*streamHandler, 0, len(n.streams))
@@ -75,20 +103,87 @@ func (n *NetworkStreamSession) Close() {
 	}
 
 	logger.Debugf("Closing session incoming [%s]", n.sessionID)
+	close(n.done)
+	n.queueLock.Lock()
 	close(n.incoming)
+	n.queueLock.Unlock()
+	n.mutex.Lock()
 	n.closed = true
+	n.mutex.Unlock()
+	if n.node.auth != nil {
+		n.node.auth.Forget(n.sessionID, string(n.endpointID))
+	}
+	n.node.metrics.SessionsClosed.Add(1)
 
 	logger.Debugf("Closing session [%s] done", n.sessionID)
 }
 
+// enqueue pushes the passed message into the session queue. If the queue is full, the passed overflow policy applies.
+// It never waits, the stream the message comes from is shared with the other sessions with the same node.
+func (n *NetworkStreamSession) enqueue(msg *view.Message, overflow string) {
+	n.queueLock.RLock()
+	defer n.queueLock.RUnlock()
+
+	select {
+	case <-n.done:
+		logger.Debugf("dropping message on closed session [%s]", n.sessionID)
+		n.node.metrics.MessagesDropped.Add(1)
+		return
+	case n.incoming <- msg:
+		return
+	default:
+	}
+
+	n.node.metrics.MessagesDropped.Add(1)
+	if overflow == OverflowClose {
+		logger.Warnf("session [%s] queue is full, closing session", n.sessionID)
+		// close waits for the queue lock held by this call
+		go n.Close()
+		return
+	}
+	logger.Warnf("session [%s] queue is full, dropping message", n.sessionID)
+}
+
 func (n *NetworkStreamSession) sendWithStatus(payload []byte, status int32) error {
-	err := n.node.sendTo(string(n.endpointID), &ViewPacket{
-		ContextID: n.contextID,
-		SessionID: n.sessionID,
-		Caller:    n.callerViewID,
-		Status:    status,
-		Payload:   payload,
-	})
-	logger.Debugf("sent message [len:%d] to [%s] with err [%s]", len(payload), string(n.endpointID), err)
-	return err
+	flowControl := n.node.flowControl
+	if len(payload) > flowControl.MaxMessageSize {
+		return errors.Errorf("message of [%d] bytes exceeds the max message size [%d]", len(payload), flowControl.MaxMessageSize)
+	}
+	chunks := (len(payload) + flowControl.ChunkSize - 1) / flowControl.ChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+
+	n.mutex.Lock()
+	traceParent, traceState := n.traceParent, n.traceState
+	n.mutex.Unlock()
+
+	n.sendMutex.Lock()
+	defer n.sendMutex.Unlock()
+	for i := 0; i < chunks; i++ {
+		start, end := i*flowControl.ChunkSize, (i+1)*flowControl.ChunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		packet := &ViewPacket{
+			ContextID:   n.contextID,
+			SessionID:   n.sessionID,
+			Caller:      n.callerViewID,
+			Status:      status,
+			Payload:     payload[start:end],
+			Traceparent: traceParent,
+			Tracestate:  traceState,
+		}
+		if chunks > 1 {
+			packet.Chunk = uint32(i)
+			packet.Chunks = uint32(chunks)
+		}
+		if err := n.node.sendTo(string(n.endpointID), n.endpointAddress, packet); err != nil {
+			logger.Debugf("failed sending message [len:%d] to [%s]: [%s]", len(payload), string(n.endpointID), err)
+			return errors.WithMessagef(err, "failed sending chunk [%d/%d]", i+1, chunks)
+		}
+	}
+	n.node.metrics.MessagesSent.Add(1)
+	logger.Debugf("sent message [len:%d] to [%s] in [%d] chunks", len(payload), string(n.endpointID), chunks)
+	return nil
 }
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+)
+
+const (
+	// LibP2PTransport carries the packets over libp2p streams, the nodes discover each other with a Kademlia DHT
+	LibP2PTransport = "libp2p"
+	// GRPCTransport carries the packets over (mutual) TLS gRPC streams to the addresses of the endpoint resolvers
+	GRPCTransport = "grpc"
+)
+
+// Transport carries the ViewPackets exchanged by a P2PNode with the other nodes.
+// Nodes are identified by their PKI ID, as returned by the endpoint service.
+type Transport interface {
+	// ID returns the PKI ID of this node
+	ID() string
+	// NewStream opens a stream to the node with the passed PKI ID, reachable at the passed address.
+	// The address is the P2P endpoint of the node, transports that discover the nodes on their own can ignore it.
+	NewStream(ctx context.Context, address string, peerID string) (MessageStream, error)
+	// Start makes the transport accept the streams opened by the other nodes and pass them to the handler
+	Start(handler func(stream MessageStream)) error
+	// Close releases the resources allocated by the transport
+	Close() error
+}
+
+// MessageStream is a bidirectional stream of ViewPackets between two nodes
+type MessageStream interface {
+	// RemotePeerID returns the PKI ID of the node at the other end of the stream
+	RemotePeerID() string
+	// RemotePeerAddress returns the address of the node at the other end of the stream
+	RemotePeerAddress() string
+	// Send sends the passed packet, it can be called concurrently
+	Send(packet *ViewPacket) error
+	// Recv blocks until a packet is received
+	Recv(packet *ViewPacket) error
+	// Close closes the stream
+	Close() error
+}
@@ -147,6 +147,10 @@ func (db *badgerDB) SetState(namespace, key string, value []byte, block, txnum u
 		return err
 	}
 
+	if err := db.updateIndexes(namespace, key, v.Value, value); err != nil {
+		return err
+	}
+
 	v.Value = value
 	v.Block = block
 	v.Txnum = txnum
@@ -200,7 +204,15 @@ func (db *badgerDB) DeleteState(namespace, key string) error {
 
 	dbKey := dbKey(namespace, key)
 
-	err := db.txn.Delete([]byte(dbKey))
+	v, err := db.versionedValue(db.txn, dbKey)
+	if err != nil {
+		return err
+	}
+	if err := db.updateIndexes(namespace, key, v.Value, nil); err != nil {
+		return err
+	}
+
+	err = db.txn.Delete([]byte(dbKey))
 	if err != nil {
 		return errors.Wrapf(err, "could not delete value for key %s", dbKey)
 	}
@@ -237,15 +249,15 @@ func (db *badgerDB) GetStateMetadata(namespace, key string) (map[string][]byte,
 }
 
 type rangeScanIterator struct {
-	txn       *badger.Txn
 	it        *badger.Iterator
+	release   func()
 	startKey  string
 	endKey    string
 	namespace string
 }
 
 func (r *rangeScanIterator) Next() (*driver.VersionedRead, error) {
-	if !r.it.Valid() {
+	if !r.it.ValidForPrefix([]byte(dbKey(r.namespace, ""))) {
 		return nil, nil
 	}
 
@@ -274,19 +286,105 @@ func (r *rangeScanIterator) Next() (*driver.VersionedRead, error) {
 
 func (r *rangeScanIterator) Close() {
 	r.it.Close()
-	r.txn.Discard()
+	r.release()
 }
 
 func (db *badgerDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
 	txn := db.db.NewTransaction(false)
+	return newRangeScanIterator(txn, txn.Discard, namespace, startKey, endKey), nil
+}
+
+// newRangeScanIterator returns an iterator on the passed transaction.
+// The release function is called when the iterator is closed.
+func newRangeScanIterator(txn *badger.Txn, release func(), namespace string, startKey string, endKey string) *rangeScanIterator {
 	it := txn.NewIterator(badger.DefaultIteratorOptions)
 	it.Seek([]byte(dbKey(namespace, startKey)))
 
 	return &rangeScanIterator{
-		txn:       txn,
 		it:        it,
+		release:   release,
 		startKey:  startKey,
 		endKey:    endKey,
 		namespace: namespace,
-	}, nil
+	}
+}
+
+// NewSnapshot returns a snapshot backed by a read-only badger transaction.
+// Badger transactions read at a fixed timestamp, therefore later commits are not visible to the snapshot.
+// The snapshot should be closed as soon as possible because it prevents the garbage collection of the versions it can see.
+// The transaction is discarded once the snapshot and all the iterators obtained from it are closed.
+func (db *badgerDB) NewSnapshot() (driver.VersionedSnapshot, error) {
+	return &snapshot{db: db, txn: db.db.NewTransaction(false)}, nil
+}
+
+type snapshot struct {
+	db  *badgerDB
+	txn *badger.Txn
+
+	lock      sync.Mutex
+	iterators int
+	closed    bool
+}
+
+func (s *snapshot) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
+	v, err := s.db.versionedValue(s.txn, dbKey(namespace, key))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return v.Value, v.Block, v.Txnum, nil
+}
+
+func (s *snapshot) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	v, err := s.db.versionedValue(s.txn, dbKey(namespace, key))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return v.Meta, v.Block, v.Txnum, nil
+}
+
+func (s *snapshot) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	if err := s.acquire(); err != nil {
+		return nil, err
+	}
+	return newRangeScanIterator(s.txn, s.release, namespace, startKey, endKey), nil
+}
+
+func (s *snapshot) GetStateByQuery(namespace string, q string) (driver.VersionedResultsIterator, error) {
+	if err := s.acquire(); err != nil {
+		return nil, err
+	}
+	return s.db.query(s.txn, s.release, namespace, q)
+}
+
+func (s *snapshot) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	if s.iterators == 0 {
+		s.txn.Discard()
+	}
+}
+
+func (s *snapshot) acquire() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return errors.New("snapshot closed")
+	}
+	s.iterators++
+	return nil
+}
+
+func (s *snapshot) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.iterators--
+	if s.closed && s.iterators == 0 {
+		s.txn.Discard()
+	}
 }
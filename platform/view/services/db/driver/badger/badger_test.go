@@ -609,3 +609,75 @@ func TestCompositeKeys(t *testing.T) {
 		{Key: "\x00prefix\x00a\x00b\x003\x00", Raw: []uint8{0x0, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x0, 0x61, 0x0, 0x62, 0x0, 0x33, 0x0}, Block: 0x23, IndexInBlock: 1},
 	}, res)
 }
+
+func queryKeys(t *testing.T, it driver.VersionedResultsIterator, err error) []string {
+	assert.NoError(t, err)
+	defer it.Close()
+
+	var res []string
+	for {
+		read, err := it.Next()
+		assert.NoError(t, err)
+		if read == nil {
+			return res
+		}
+		res = append(res, read.Key)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	ns := "ns"
+
+	db, err := OpenDB(filepath.Join(tempDir, "DB-TestQueries"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k1", []byte(`{"owner": "alice", "amount": 5}`), 35, 1))
+	assert.NoError(t, db.SetState(ns, "k2", []byte(`{"owner": "bob", "amount": 10}`), 35, 2))
+	assert.NoError(t, db.SetState(ns, "k3", []byte(`{"owner": "alice", "amount": 20}`), 35, 3))
+	assert.NoError(t, db.SetState(ns, "k4", []byte(`not json`), 35, 4))
+	assert.NoError(t, db.SetState("other", "k1", []byte(`{"owner": "alice", "amount": 50}`), 35, 5))
+	assert.NoError(t, db.Commit())
+
+	// full scan
+	it, err := db.GetStateByQuery(ns, `{"selector": {"owner": "alice"}, "sort": [{"amount": "desc"}]}`)
+	assert.Equal(t, []string{"k3", "k1"}, queryKeys(t, it, err))
+
+	// index on existing values
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.CreateIndex(ns, &driver.Index{Name: "amount", Fields: []string{"amount"}}))
+	assert.NoError(t, db.Commit())
+
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	assert.Equal(t, []string{"k2", "k3"}, queryKeys(t, it, err))
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}, "owner": "alice"}}`)
+	assert.Equal(t, []string{"k3"}, queryKeys(t, it, err))
+
+	// the index follows updates and deletions
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k2", []byte(`{"owner": "bob", "amount": 1}`), 36, 1))
+	assert.NoError(t, db.DeleteState(ns, "k3"))
+	assert.NoError(t, db.Commit())
+
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	assert.Empty(t, queryKeys(t, it, err))
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$lte": 5}}, "sort": ["amount"]}`)
+	assert.Equal(t, []string{"k2", "k1"}, queryKeys(t, it, err))
+
+	// snapshots do not see later commits
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(t, err)
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k5", []byte(`{"owner": "carl", "amount": 30}`), 37, 1))
+	assert.NoError(t, db.Commit())
+
+	it, err = snapshot.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	snapshot.Close()
+	assert.Empty(t, queryKeys(t, it, err))
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	assert.Equal(t, []string{"k5"}, queryKeys(t, it, err))
+
+	_, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$foo": 7}}}`)
+	assert.Error(t, err)
+}
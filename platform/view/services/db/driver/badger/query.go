@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package badger
+
+import (
+	"bytes"
+	"encoding/json"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/keys"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/query"
+)
+
+// Index definitions and entries are stored next to the data, under keys that start with
+// the namespace separator and therefore cannot collide with the keys of a namespace.
+const (
+	indexDefinitionPrefix = keys.NamespaceSeparator + "index" + keys.NamespaceSeparator
+	indexEntryPrefix      = keys.NamespaceSeparator + "idx" + keys.NamespaceSeparator
+)
+
+func indexDefinitionKey(namespace, name string) []byte {
+	return []byte(indexDefinitionPrefix + namespace + keys.NamespaceSeparator + name)
+}
+
+func indexEntryKey(namespace, name string, entry []byte) []byte {
+	return append([]byte(indexEntryPrefix+namespace+keys.NamespaceSeparator+name+keys.NamespaceSeparator), entry...)
+}
+
+// indexes returns the indexes of the passed namespace as seen by the passed transaction
+func (db *badgerDB) indexes(txn *badger.Txn, namespace string) ([]*driver.Index, error) {
+	prefix := []byte(indexDefinitionPrefix + namespace + keys.NamespaceSeparator)
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	var indexes []*driver.Index
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		index := &driver.Index{}
+		err := it.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, index)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load index definition %s", string(it.Item().Key()))
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// updateIndexes replaces the index entries of the old value of the passed key with those of the new value
+func (db *badgerDB) updateIndexes(namespace, key string, oldValue, newValue []byte) error {
+	indexes, err := db.indexes(db.txn, namespace)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if entry, ok := query.IndexEntry(index, key, oldValue); ok {
+			if err := db.txn.Delete(indexEntryKey(namespace, index.Name, entry)); err != nil {
+				return errors.Wrapf(err, "could not delete entry of index %s for key %s", index.Name, key)
+			}
+		}
+		if entry, ok := query.IndexEntry(index, key, newValue); ok {
+			if err := db.txn.Set(indexEntryKey(namespace, index.Name, entry), []byte(key)); err != nil {
+				return errors.Wrapf(err, "could not set entry of index %s for key %s", index.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+func (db *badgerDB) CreateIndex(namespace string, index *driver.Index) error {
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	// collect first, a read-write transaction supports a single iterator at a time
+	var stale [][]byte
+	prefix := indexEntryKey(namespace, index.Name, nil)
+	it := db.txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+	for it.Rewind(); it.ValidForPrefix(prefix); it.Next() {
+		stale = append(stale, it.Item().KeyCopy(nil))
+	}
+	it.Close()
+
+	var entries [][]byte
+	var values [][]byte
+	prefix = []byte(dbKey(namespace, ""))
+	it = db.txn.NewIterator(badger.DefaultIteratorOptions)
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		v, err := versionedValue(item, string(item.Key()))
+		if err != nil {
+			it.Close()
+			return err
+		}
+		key := string(item.Key()[len(prefix):])
+		if entry, ok := query.IndexEntry(index, key, v.Value); ok {
+			entries = append(entries, indexEntryKey(namespace, index.Name, entry))
+			values = append(values, []byte(key))
+		}
+	}
+	it.Close()
+
+	for _, k := range stale {
+		if err := db.txn.Delete(k); err != nil {
+			return errors.Wrapf(err, "could not delete stale entry of index %s", index.Name)
+		}
+	}
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal index %s", index.Name)
+	}
+	if err := db.txn.Set(indexDefinitionKey(namespace, index.Name), raw); err != nil {
+		return errors.Wrapf(err, "could not set index %s", index.Name)
+	}
+	for i, entry := range entries {
+		if err := db.txn.Set(entry, values[i]); err != nil {
+			return errors.Wrapf(err, "could not set entry of index %s", index.Name)
+		}
+	}
+
+	return nil
+}
+
+func (db *badgerDB) GetStateByQuery(namespace string, q string) (driver.VersionedResultsIterator, error) {
+	txn := db.db.NewTransaction(false)
+	return db.query(txn, txn.Discard, namespace, q)
+}
+
+// query answers the passed query on the passed transaction, release is called when the results are closed
+func (db *badgerDB) query(txn *badger.Txn, release func(), namespace string, q string) (driver.VersionedResultsIterator, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	indexes, err := db.indexes(txn, namespace)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	var candidates driver.VersionedResultsIterator
+	if index, start, end := parsed.SelectIndex(indexes); index != nil {
+		var matching []string
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		prefix := indexEntryKey(namespace, index.Name, nil)
+		endKey := indexEntryKey(namespace, index.Name, end)
+		for it.Seek(indexEntryKey(namespace, index.Name, start)); it.ValidForPrefix(prefix) && bytes.Compare(it.Item().Key(), endKey) < 0; it.Next() {
+			err := it.Item().Value(func(val []byte) error {
+				matching = append(matching, string(val))
+				return nil
+			})
+			if err != nil {
+				it.Close()
+				release()
+				return nil, errors.Wrapf(err, "could not read entry of index %s", index.Name)
+			}
+		}
+		it.Close()
+		candidates = &keysIterator{db: db, txn: txn, release: release, namespace: namespace, keys: matching}
+	} else {
+		candidates = newRangeScanIterator(txn, release, namespace, "", "")
+	}
+
+	return parsed.Results(candidates)
+}
+
+// keysIterator iterates over the values of the passed keys
+type keysIterator struct {
+	db        *badgerDB
+	txn       *badger.Txn
+	release   func()
+	namespace string
+	keys      []string
+}
+
+func (k *keysIterator) Next() (*driver.VersionedRead, error) {
+	for len(k.keys) != 0 {
+		key := k.keys[0]
+		k.keys = k.keys[1:]
+
+		v, err := k.db.versionedValue(k.txn, dbKey(k.namespace, key))
+		if err != nil {
+			return nil, err
+		}
+		if len(v.Value) == 0 {
+			continue
+		}
+		return &driver.VersionedRead{
+			Key:          key,
+			Block:        v.Block,
+			IndexInBlock: int(v.Txnum),
+			Raw:          v.Value,
+		}, nil
+	}
+	return nil, nil
+}
+
+func (k *keysIterator) Close() {
+	k.release()
+}
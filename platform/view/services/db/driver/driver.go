@@ -6,6 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package driver
 
+import "strings"
+
+// namespaceSeparator separates the data source from the namespace in a data source name
+const namespaceSeparator = "#"
+
 type Read struct {
 	Key string
 	Raw []byte
@@ -34,6 +39,15 @@ type VersionedResultsIterator interface {
 	Close()
 }
 
+// Index describes a secondary index on the JSON values stored in a namespace
+type Index struct {
+	// Name identifies the index in its namespace
+	Name string
+	// Fields are the indexed fields, nested fields are expressed in dot notation.
+	// Queries can use the index when they constrain its first field.
+	Fields []string
+}
+
 // VersionedPersistence models a versioned key-value storage place
 type VersionedPersistence interface {
 	// SetState sets the given value for the given namespace, key, and version
@@ -52,6 +66,13 @@ type VersionedPersistence interface {
 	// can be supplied as empty strings. However, a full scan should be used judiciously for performance reasons.
 	// The returned VersionedResultsIterator contains results of type *VersionedRead.
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (VersionedResultsIterator, error)
+	// GetStateByQuery returns an iterator over the key-values of the given namespace whose values, JSON documents,
+	// satisfy the given query. Queries follow the syntax of CouchDB Mango queries (selector, sort, limit, skip
+	// and use_index). The indexes of the namespace are used, when possible, to avoid a full scan.
+	GetStateByQuery(namespace string, query string) (VersionedResultsIterator, error)
+	// CreateIndex declares the given index on the given namespace and indexes the values already stored.
+	// An index with the same name is replaced. As SetState, it must be called between BeginUpdate and Commit.
+	CreateIndex(namespace string, index *Index) error
 	// Close closes this persistence instance
 	Close() error
 	// BeginUpdate starts the session
@@ -62,6 +83,29 @@ type VersionedPersistence interface {
 	Discard() error
 }
 
+// VersionedSnapshot models a read-only, point-in-time view of a VersionedPersistence.
+// Changes committed after the snapshot has been taken are not visible through it.
+type VersionedSnapshot interface {
+	// GetState gets the value and version for given namespace and key
+	GetState(namespace, key string) ([]byte, uint64, uint64, error)
+	// GetStateMetadata gets the metadata and version for given namespace and key
+	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
+	// GetStateRangeScanIterator returns an iterator that contains all the key-values between given key ranges.
+	// It follows the same semantics as VersionedPersistence.GetStateRangeScanIterator.
+	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (VersionedResultsIterator, error)
+	// GetStateByQuery returns an iterator over the key-values matching the given query.
+	// It follows the same semantics as VersionedPersistence.GetStateByQuery.
+	GetStateByQuery(namespace string, query string) (VersionedResultsIterator, error)
+	// Close releases the resources held by this snapshot
+	Close()
+}
+
+// Snapshotter is implemented by the VersionedPersistence instances that support snapshot reads
+type Snapshotter interface {
+	// NewSnapshot returns a snapshot of the state committed so far
+	NewSnapshot() (VersionedSnapshot, error)
+}
+
 // Persistence models a key-value storage place
 type Persistence interface {
 	// SetState sets the given value for the given namespace and key
@@ -92,3 +136,18 @@ type Driver interface {
 	// New returns a new Persistence for the passed data source
 	New(dataSourceName string) (Persistence, error)
 }
+
+// DataSourceName returns the data source name that makes a driver storing several namespaces in the same database,
+// such as the sql ones, store the passed namespace in the database identified by the passed driver-specific data source.
+func DataSourceName(dataSource, namespace string) string {
+	return dataSource + namespaceSeparator + namespace
+}
+
+// SplitDataSourceName splits the passed data source name into the driver-specific data source and the namespace,
+// empty if not set
+func SplitDataSourceName(dataSourceName string) (string, string) {
+	if i := strings.LastIndex(dataSourceName, namespaceSeparator); i >= 0 {
+		return dataSourceName[:i], dataSourceName[i+1:]
+	}
+	return dataSourceName, ""
+}
@@ -25,18 +25,21 @@ type versionedValue struct {
 }
 
 type database struct {
-	keys  map[string]map[string]*versionedValue
-	mutex sync.Mutex
-	txn   map[string]map[string]*versionedValue
+	keys    map[string]map[string]*versionedValue
+	indexes map[string]map[string]*index
+	mutex   sync.Mutex
+	txn     map[string]map[string]*versionedValue
+	// txnIndexes are the indexes as modified by the ongoing update
+	txnIndexes map[string]map[string]*index
 }
 
 type rangeIterator struct {
-	beg  int
-	cur  int
-	end  int
-	keys []string
-	db   *database
-	ns   string
+	beg      int
+	cur      int
+	end      int
+	keys     []string
+	snapshot *snapshot
+	ns       string
 }
 
 func (r *rangeIterator) Next() (*driver.VersionedRead, error) {
@@ -47,7 +50,7 @@ func (r *rangeIterator) Next() (*driver.VersionedRead, error) {
 	var err error
 	var idx uint64
 	kv := &driver.VersionedRead{Key: r.keys[r.cur]}
-	kv.Raw, kv.Block, idx, err = r.db.GetState(r.ns, r.keys[r.cur])
+	kv.Raw, kv.Block, idx, err = r.snapshot.GetState(r.ns, r.keys[r.cur])
 	kv.IndexInBlock = int(idx)
 	if err != nil {
 		return nil, err
@@ -60,10 +63,70 @@ func (r *rangeIterator) Next() (*driver.VersionedRead, error) {
 
 func (r *rangeIterator) Close() {}
 
+// snapshot gives read access to the keys committed at a given point in time.
+// Commit replaces the committed keys with a fresh copy, therefore the maps a snapshot refers to are never modified.
+type snapshot struct {
+	keys    map[string]map[string]*versionedValue
+	indexes map[string]map[string]*index
+}
+
+func (s *snapshot) Close() {}
+
+func (s *snapshot) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	vv := s.keys[namespace]
+	sortedKeys := make([]string, 0, len(vv))
+	for k := range vv {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	beg := sort.SearchStrings(sortedKeys, startKey)
+	end := sort.SearchStrings(sortedKeys, endKey)
+
+	if startKey == "" {
+		beg = 0
+	}
+	if endKey == "" {
+		end = len(sortedKeys)
+	}
+
+	return &rangeIterator{
+		beg:      beg,
+		cur:      beg,
+		end:      end,
+		ns:       namespace,
+		snapshot: s,
+		keys:     sortedKeys,
+	}, nil
+}
+
+func (s *snapshot) GetState(namespace string, key string) ([]byte, uint64, uint64, error) {
+	vv, in := s.keys[namespace][key]
+	if !in {
+		return nil, 0, 0, nil
+	}
+
+	return append([]byte(nil), vv.value...), vv.block, vv.txnum, nil
+}
+
+func (s *snapshot) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	vv, in := s.keys[namespace][key]
+	if !in {
+		return nil, 0, 0, nil
+	}
+
+	metadata := map[string][]byte{}
+	for k, v := range vv.metadata {
+		metadata[k] = append([]byte(nil), v...)
+	}
+	return metadata, vv.block, vv.txnum, nil
+}
+
 func New() *database {
 	return &database{
-		keys:  map[string]map[string]*versionedValue{},
-		mutex: sync.Mutex{},
+		keys:    map[string]map[string]*versionedValue{},
+		indexes: map[string]map[string]*index{},
+		mutex:   sync.Mutex{},
 	}
 }
 
@@ -79,6 +142,7 @@ func (db *database) BeginUpdate() error {
 		return errors.New("previous commit in progress")
 	}
 
+	db.txnIndexes = cloneIndexes(db.indexes)
 	db.txn = map[string]map[string]*versionedValue{}
 	for k := range db.keys {
 		db.txn[k] = map[string]*versionedValue{}
@@ -116,7 +180,9 @@ func (db *database) Commit() error {
 	}
 
 	db.keys = db.txn
+	db.indexes = db.txnIndexes
 	db.txn = nil
+	db.txnIndexes = nil
 
 	return nil
 }
@@ -130,14 +196,11 @@ func (db *database) Discard() error {
 	}
 
 	db.txn = nil
+	db.txnIndexes = nil
 
 	return nil
 }
 
-func (db *database) mapForNamespaceForReading(ns string, add bool) map[string]*versionedValue {
-	return db.mapForNamespace(ns, add, db.keys)
-}
-
 func (db *database) mapForNamespaceForWriting(ns string, add bool) map[string]*versionedValue {
 	return db.mapForNamespace(ns, add, db.txn)
 }
@@ -152,60 +215,28 @@ func (db *database) mapForNamespace(ns string, add bool, mm map[string]map[strin
 	return m
 }
 
-func (db *database) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
-	vv := db.mapForNamespaceForReading(namespace, false)
-	sortedKeys := make([]string, 0, len(vv))
-	for k := range vv {
-		sortedKeys = append(sortedKeys, k)
-	}
-	sort.Strings(sortedKeys)
-
-	beg := sort.SearchStrings(sortedKeys, startKey)
-	end := sort.SearchStrings(sortedKeys, endKey)
-
-	if startKey == "" {
-		beg = 0
-	}
-	if endKey == "" {
-		end = len(sortedKeys)
-	}
-
-	return &rangeIterator{
-		beg:  beg,
-		cur:  beg,
-		end:  end,
-		ns:   namespace,
-		db:   db,
-		keys: sortedKeys,
-	}, nil
+// NewSnapshot returns a snapshot of the keys committed so far
+func (db *database) NewSnapshot() (driver.VersionedSnapshot, error) {
+	return db.snapshot(), nil
 }
 
-func (db *database) GetState(namespace string, key string) ([]byte, uint64, uint64, error) {
+func (db *database) snapshot() *snapshot {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
-	vv, in := db.mapForNamespaceForReading(namespace, false)[key]
-	if !in {
-		return nil, 0, 0, nil
-	}
-
-	return append([]byte(nil), vv.value...), vv.block, vv.txnum, nil
+	return &snapshot{keys: db.keys, indexes: db.indexes}
 }
 
-func (db *database) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
-	db.mutex.Lock()
-	defer db.mutex.Unlock()
+func (db *database) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	return db.snapshot().GetStateRangeScanIterator(namespace, startKey, endKey)
+}
 
-	vv, in := db.mapForNamespaceForReading(namespace, false)[key]
-	if !in {
-		return nil, 0, 0, nil
-	}
+func (db *database) GetState(namespace string, key string) ([]byte, uint64, uint64, error) {
+	return db.snapshot().GetState(namespace, key)
+}
 
-	metadata := map[string][]byte{}
-	for k, v := range vv.metadata {
-		metadata[k] = append([]byte(nil), v...)
-	}
-	return metadata, vv.block, vv.txnum, nil
+func (db *database) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	return db.snapshot().GetStateMetadata(namespace, key)
 }
 
 func (db *database) SetState(namespace string, key string, value []byte, block, txnum uint64) error {
@@ -224,6 +255,11 @@ func (db *database) SetState(namespace string, key string, value []byte, block,
 		db.mapForNamespaceForWriting(namespace, true)[key] = vv
 	}
 
+	for _, i := range db.txnIndexes[namespace] {
+		i.remove(key, vv.value)
+		i.add(key, value)
+	}
+
 	vv.block = block
 	vv.txnum = txnum
 	vv.value = append([]byte(nil), value...)
@@ -264,6 +300,11 @@ func (db *database) DeleteState(namespace string, key string) error {
 	}
 
 	nsm := db.mapForNamespaceForWriting(namespace, false)
+	if vv, in := nsm[key]; in {
+		for _, i := range db.txnIndexes[namespace] {
+			i.remove(key, vv.value)
+		}
+	}
 	delete(nsm, key)
 	if len(nsm) == 0 {
 		delete(db.txn, namespace)
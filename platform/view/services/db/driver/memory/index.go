@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/query"
+)
+
+type indexEntry struct {
+	entry []byte
+	key   string
+}
+
+// index keeps the entries of a secondary index sorted
+type index struct {
+	def     *driver.Index
+	entries []indexEntry
+}
+
+func (i *index) clone() *index {
+	return &index{
+		def:     i.def,
+		entries: append([]indexEntry(nil), i.entries...),
+	}
+}
+
+func (i *index) search(entry []byte) int {
+	return sort.Search(len(i.entries), func(n int) bool {
+		return bytes.Compare(i.entries[n].entry, entry) >= 0
+	})
+}
+
+func (i *index) add(key string, value []byte) {
+	entry, ok := query.IndexEntry(i.def, key, value)
+	if !ok {
+		return
+	}
+	pos := i.search(entry)
+	i.entries = append(i.entries, indexEntry{})
+	copy(i.entries[pos+1:], i.entries[pos:])
+	i.entries[pos] = indexEntry{entry: entry, key: key}
+}
+
+func (i *index) remove(key string, value []byte) {
+	entry, ok := query.IndexEntry(i.def, key, value)
+	if !ok {
+		return
+	}
+	pos := i.search(entry)
+	if pos < len(i.entries) && bytes.Equal(i.entries[pos].entry, entry) {
+		i.entries = append(i.entries[:pos], i.entries[pos+1:]...)
+	}
+}
+
+// scan returns the keys whose entries are in the range [start, end)
+func (i *index) scan(start, end []byte) []string {
+	var keys []string
+	for pos := i.search(start); pos < len(i.entries) && bytes.Compare(i.entries[pos].entry, end) < 0; pos++ {
+		keys = append(keys, i.entries[pos].key)
+	}
+	return keys
+}
+
+func cloneIndexes(indexes map[string]map[string]*index) map[string]map[string]*index {
+	res := map[string]map[string]*index{}
+	for ns, nsIndexes := range indexes {
+		res[ns] = map[string]*index{}
+		for name, i := range nsIndexes {
+			res[ns][name] = i.clone()
+		}
+	}
+	return res
+}
+
+func (s *snapshot) GetStateByQuery(namespace string, q string) (driver.VersionedResultsIterator, error) {
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(s.indexes[namespace]))
+	for name := range s.indexes[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	defs := make([]*driver.Index, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, s.indexes[namespace][name].def)
+	}
+
+	var candidates driver.VersionedResultsIterator
+	if def, start, end := parsed.SelectIndex(defs); def != nil {
+		logger.Debugf("query on [%s] uses index [%s]", namespace, def.Name)
+		keys := s.indexes[namespace][def.Name].scan(start, end)
+		candidates = &rangeIterator{
+			end:      len(keys),
+			keys:     keys,
+			snapshot: s,
+			ns:       namespace,
+		}
+	} else {
+		candidates, err = s.GetStateRangeScanIterator(namespace, "", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parsed.Results(candidates)
+}
+
+func (db *database) GetStateByQuery(namespace string, query string) (driver.VersionedResultsIterator, error) {
+	return db.snapshot().GetStateByQuery(namespace, query)
+}
+
+func (db *database) CreateIndex(namespace string, def *driver.Index) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	i := &index{def: def}
+	for key, vv := range db.txn[namespace] {
+		i.add(key, vv.value)
+	}
+	if _, ok := db.txnIndexes[namespace]; !ok {
+		db.txnIndexes[namespace] = map[string]*index{}
+	}
+	db.txnIndexes[namespace][def.Name] = i
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/unversioned"
+)
+
+const (
+	// Postgres is the name of the driver backed by a PostgreSQL server
+	Postgres = "postgres"
+	// SQLite is the name of the driver backed by an embedded SQLite database
+	SQLite = "sqlite"
+
+	defaultTable = "state"
+)
+
+// dialect captures the differences between the supported databases
+type dialect struct {
+	// driverName is the name of the database/sql driver
+	driverName string
+	blobType   string
+	// numberedParams is true if the placeholders are $1, $2, ... instead of ?
+	numberedParams bool
+	// dataSource adapts the data source passed by the user
+	dataSource func(string) string
+	// snapshotOptions are the options of the transactions backing snapshots
+	snapshotOptions *sql.TxOptions
+}
+
+var dialects = map[string]*dialect{
+	Postgres: {
+		driverName:     "postgres",
+		blobType:       "BYTEA",
+		numberedParams: true,
+		dataSource:     func(dsn string) string { return dsn },
+		snapshotOptions: &sql.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+			ReadOnly:  true,
+		},
+	},
+	SQLite: {
+		driverName: "sqlite3",
+		blobType:   "BLOB",
+		dataSource: func(dsn string) string {
+			// WAL lets readers proceed while an update is in progress,
+			// the busy timeout lets concurrent writers wait instead of failing.
+			for _, param := range []string{"_journal_mode=WAL", "_busy_timeout=5000"} {
+				if strings.Contains(dsn, param[:strings.Index(param, "=")+1]) {
+					continue
+				}
+				if strings.Contains(dsn, "?") {
+					dsn += "&" + param
+				} else {
+					dsn += "?" + param
+				}
+			}
+			return dsn
+		},
+	},
+}
+
+var invalidTableChars = regexp.MustCompile("[^a-z0-9_]")
+
+// parseDataSourceName splits the passed data source name, see driver.DataSourceName, into the driver-specific
+// data source (a connection string for postgres, a file path or URI for sqlite) and the sanitized table name.
+// Each namespace is stored in its own tables, so that different tables of the same database can back
+// different persistence instances.
+func parseDataSourceName(dataSourceName string) (string, string) {
+	dataSource, table := driver.SplitDataSourceName(dataSourceName)
+	if len(table) == 0 {
+		table = defaultTable
+	}
+	table = invalidTableChars.ReplaceAllString(strings.ToLower(table), "_")
+	return dataSource, fmt.Sprintf("fsc_%s", table)
+}
+
+type Driver struct {
+	dialect string
+}
+
+func (d *Driver) NewVersioned(dataSourceName string) (driver.VersionedPersistence, error) {
+	return OpenDB(d.dialect, dataSourceName)
+}
+
+func (d *Driver) New(dataSourceName string) (driver.Persistence, error) {
+	db, err := OpenDB(d.dialect, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return &unversioned.Unversioned{Versioned: db}, nil
+}
+
+func init() {
+	db.Register(Postgres, &Driver{dialect: Postgres})
+	db.Register(SQLite, &Driver{dialect: SQLite})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/query"
+)
+
+// indexes returns the indexes of the passed namespace
+func (db *database) indexes(q queryer, namespace string) ([]*driver.Index, error) {
+	rows, err := q.Query(db.rebind(fmt.Sprintf(`SELECT definition FROM %s_indexes WHERE ns = ? ORDER BY name`, db.table)), namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load indexes of namespace %s", namespace)
+	}
+	defer rows.Close()
+
+	var indexes []*driver.Index
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, errors.Wrapf(err, "could not load indexes of namespace %s", namespace)
+		}
+		index := &driver.Index{}
+		if err := json.Unmarshal(raw, index); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal index definition in namespace %s", namespace)
+		}
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "could not load indexes of namespace %s", namespace)
+	}
+	return indexes, nil
+}
+
+// updateIndexes replaces the index entries of the current value of the passed key with those of the new value
+func (db *database) updateIndexes(namespace, key string, newValue []byte) error {
+	indexes, err := db.indexes(db.txn, namespace)
+	if err != nil || len(indexes) == 0 {
+		return err
+	}
+	oldValue, _, _, err := db.getState(db.txn, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if entry, ok := query.IndexEntry(index, key, oldValue); ok {
+			if err := db.deleteEntry(namespace, index.Name, entry); err != nil {
+				return err
+			}
+		}
+		if entry, ok := query.IndexEntry(index, key, newValue); ok {
+			if err := db.insertEntry(namespace, index.Name, entry, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (db *database) deleteEntry(namespace, name string, entry []byte) error {
+	_, err := db.txn.Exec(db.rebind(fmt.Sprintf(`DELETE FROM %s_entries WHERE ns = ? AND name = ? AND entry = ?`, db.table)),
+		namespace, name, entry)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete entry of index %s", name)
+	}
+	return nil
+}
+
+func (db *database) insertEntry(namespace, name string, entry []byte, key string) error {
+	_, err := db.txn.Exec(db.rebind(fmt.Sprintf(`INSERT INTO %s_entries (ns, name, entry, pkey) VALUES (?, ?, ?, ?)`, db.table)),
+		namespace, name, entry, []byte(key))
+	if err != nil {
+		return errors.Wrapf(err, "could not set entry of index %s for key %s", name, key)
+	}
+	return nil
+}
+
+func (db *database) CreateIndex(namespace string, index *driver.Index) error {
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal index %s", index.Name)
+	}
+	_, err = db.txn.Exec(db.rebind(fmt.Sprintf(
+		`INSERT INTO %s_indexes (ns, name, definition) VALUES (?, ?, ?)
+		ON CONFLICT (ns, name) DO UPDATE SET definition = excluded.definition`, db.table)),
+		namespace, index.Name, raw)
+	if err != nil {
+		return errors.Wrapf(err, "could not set index %s", index.Name)
+	}
+	_, err = db.txn.Exec(db.rebind(fmt.Sprintf(`DELETE FROM %s_entries WHERE ns = ? AND name = ?`, db.table)), namespace, index.Name)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete stale entries of index %s", index.Name)
+	}
+
+	// collect first, a transaction serves one query at a time
+	it, err := db.rangeScan(db.txn, namespace, "", "")
+	if err != nil {
+		return err
+	}
+	values, err := drain(it)
+	if err != nil {
+		return err
+	}
+	for {
+		read, _ := values.Next()
+		if read == nil {
+			break
+		}
+		if entry, ok := query.IndexEntry(index, read.Key, read.Raw); ok {
+			if err := db.insertEntry(namespace, index.Name, entry, read.Key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *database) GetStateByQuery(namespace string, q string) (driver.VersionedResultsIterator, error) {
+	return db.query(db.db, namespace, q)
+}
+
+// query answers the passed query using, if possible, one of the indexes of the namespace
+func (db *database) query(q queryer, namespace string, raw string) (driver.VersionedResultsIterator, error) {
+	parsed, err := query.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := db.indexes(q, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates driver.VersionedResultsIterator
+	if index, start, end := parsed.SelectIndex(indexes); index != nil {
+		rows, err := q.Query(db.rebind(fmt.Sprintf(
+			`SELECT s.pkey, s.val, s.block, s.txnum FROM %s_entries e JOIN %s s ON s.ns = e.ns AND s.pkey = e.pkey
+			WHERE e.ns = ? AND e.name = ? AND e.entry >= ? AND e.entry < ? ORDER BY e.entry`, db.table, db.table)),
+			namespace, index.Name, start, end)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not query index %s", index.Name)
+		}
+		candidates = &rowsIterator{rows: rows}
+	} else {
+		candidates, err = db.rangeScan(q, namespace, "", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed.Results(candidates)
+}
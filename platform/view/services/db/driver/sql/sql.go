@@ -0,0 +1,426 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.db.sql")
+
+// queryer is implemented by both sql.DB and sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// database stores the key-values of all the namespaces in a single table, keyed by namespace and key.
+// Keys are stored as binary strings so that range scans follow the byte order, as for the other drivers.
+// Reads outside BeginUpdate/Commit see the committed state only.
+type database struct {
+	db      *sql.DB
+	dialect *dialect
+	table   string
+
+	txn     *sql.Tx
+	txnLock sync.Mutex
+}
+
+// OpenDB opens the database of the given dialect, postgres or sqlite, identified by the passed data source name,
+// and creates the tables it needs if they do not exist yet. See driver.DataSourceName for the format of the data source name.
+func OpenDB(dialectName, dataSourceName string) (*database, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, errors.Errorf("unknown sql dialect [%s]", dialectName)
+	}
+	dataSource, table := parseDataSourceName(dataSourceName)
+	if len(dataSource) == 0 {
+		return nil, errors.Errorf("data source cannot be empty")
+	}
+
+	sqlDB, err := sql.Open(d.driverName, d.dataSource(dataSource))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open %s database", dialectName)
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, errors.Wrapf(err, "could not connect to %s database", dialectName)
+	}
+
+	db := &database{db: sqlDB, dialect: d, table: table}
+	if err := db.createTables(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	logger.Debugf("opened %s database, table [%s]", dialectName, table)
+
+	return db, nil
+}
+
+func (db *database) createTables() error {
+	for _, stmt := range []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			ns TEXT NOT NULL,
+			pkey %s NOT NULL,
+			val %s,
+			block BIGINT NOT NULL DEFAULT 0,
+			txnum BIGINT NOT NULL DEFAULT 0,
+			metadata %s,
+			PRIMARY KEY (ns, pkey))`, db.table, db.dialect.blobType, db.dialect.blobType, db.dialect.blobType),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s_indexes (
+			ns TEXT NOT NULL,
+			name TEXT NOT NULL,
+			definition %s NOT NULL,
+			PRIMARY KEY (ns, name))`, db.table, db.dialect.blobType),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s_entries (
+			ns TEXT NOT NULL,
+			name TEXT NOT NULL,
+			entry %s NOT NULL,
+			pkey %s NOT NULL,
+			PRIMARY KEY (ns, name, entry))`, db.table, db.dialect.blobType, db.dialect.blobType),
+	} {
+		if _, err := db.db.Exec(stmt); err != nil {
+			return errors.Wrapf(err, "could not create tables [%s]", db.table)
+		}
+	}
+	return nil
+}
+
+// rebind rewrites the ? placeholders of the passed statement for the dialect of this database
+func (db *database) rebind(stmt string) string {
+	if !db.dialect.numberedParams {
+		return stmt
+	}
+	var sb strings.Builder
+	n := 0
+	for _, c := range stmt {
+		if c == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+func (db *database) Close() error {
+	err := db.db.Close()
+	if err != nil {
+		return errors.Wrap(err, "could not close DB")
+	}
+
+	return nil
+}
+
+func (db *database) BeginUpdate() error {
+	db.txnLock.Lock()
+	defer db.txnLock.Unlock()
+
+	if db.txn != nil {
+		return errors.New("previous commit in progress")
+	}
+
+	txn, err := db.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	db.txn = txn
+
+	return nil
+}
+
+func (db *database) Commit() error {
+	db.txnLock.Lock()
+	defer db.txnLock.Unlock()
+
+	if db.txn == nil {
+		return errors.New("no commit in progress")
+	}
+
+	err := db.txn.Commit()
+	db.txn = nil
+	if err != nil {
+		return errors.Wrap(err, "could not commit transaction")
+	}
+
+	return nil
+}
+
+func (db *database) Discard() error {
+	db.txnLock.Lock()
+	defer db.txnLock.Unlock()
+
+	if db.txn == nil {
+		return errors.New("no commit in progress")
+	}
+
+	err := db.txn.Rollback()
+	db.txn = nil
+	if err != nil {
+		return errors.Wrap(err, "could not discard transaction")
+	}
+
+	return nil
+}
+
+func (db *database) SetState(namespace, key string, value []byte, block, txnum uint64) error {
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	if err := db.updateIndexes(namespace, key, value); err != nil {
+		return err
+	}
+
+	_, err := db.txn.Exec(db.rebind(fmt.Sprintf(
+		`INSERT INTO %s (ns, pkey, val, block, txnum) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (ns, pkey) DO UPDATE SET val = excluded.val, block = excluded.block, txnum = excluded.txnum`, db.table)),
+		namespace, []byte(key), value, block, txnum)
+	if err != nil {
+		return errors.Wrapf(err, "could not set value for key %s:%s", namespace, key)
+	}
+
+	return nil
+}
+
+func (db *database) SetStateMetadata(namespace, key string, metadata map[string][]byte, block, txnum uint64) error {
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal metadata for key %s:%s", namespace, key)
+	}
+
+	_, err = db.txn.Exec(db.rebind(fmt.Sprintf(
+		`INSERT INTO %s (ns, pkey, metadata, block, txnum) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (ns, pkey) DO UPDATE SET metadata = excluded.metadata, block = excluded.block, txnum = excluded.txnum`, db.table)),
+		namespace, []byte(key), raw, block, txnum)
+	if err != nil {
+		return errors.Wrapf(err, "could not set metadata for key %s:%s", namespace, key)
+	}
+
+	return nil
+}
+
+func (db *database) DeleteState(namespace, key string) error {
+	if db.txn == nil {
+		panic("programming error, writing without ongoing update")
+	}
+
+	if err := db.updateIndexes(namespace, key, nil); err != nil {
+		return err
+	}
+
+	_, err := db.txn.Exec(db.rebind(fmt.Sprintf(`DELETE FROM %s WHERE ns = ? AND pkey = ?`, db.table)), namespace, []byte(key))
+	if err != nil {
+		return errors.Wrapf(err, "could not delete value for key %s:%s", namespace, key)
+	}
+
+	return nil
+}
+
+func (db *database) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
+	return db.getState(db.db, namespace, key)
+}
+
+func (db *database) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	return db.getStateMetadata(db.db, namespace, key)
+}
+
+func (db *database) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	return db.rangeScan(db.db, namespace, startKey, endKey)
+}
+
+func (db *database) getState(q queryer, namespace, key string) ([]byte, uint64, uint64, error) {
+	var value []byte
+	var block, txnum uint64
+	err := q.QueryRow(db.rebind(fmt.Sprintf(`SELECT val, block, txnum FROM %s WHERE ns = ? AND pkey = ?`, db.table)),
+		namespace, []byte(key)).Scan(&value, &block, &txnum)
+	if err == sql.ErrNoRows {
+		return nil, 0, 0, nil
+	}
+	if err != nil {
+		return nil, 0, 0, errors.Wrapf(err, "could not get value for key %s:%s", namespace, key)
+	}
+
+	return value, block, txnum, nil
+}
+
+func (db *database) getStateMetadata(q queryer, namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	var raw []byte
+	var block, txnum uint64
+	err := q.QueryRow(db.rebind(fmt.Sprintf(`SELECT metadata, block, txnum FROM %s WHERE ns = ? AND pkey = ?`, db.table)),
+		namespace, []byte(key)).Scan(&raw, &block, &txnum)
+	if err == sql.ErrNoRows {
+		return nil, 0, 0, nil
+	}
+	if err != nil {
+		return nil, 0, 0, errors.Wrapf(err, "could not get metadata for key %s:%s", namespace, key)
+	}
+
+	var metadata map[string][]byte
+	if len(raw) != 0 {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, 0, 0, errors.Wrapf(err, "could not unmarshal metadata for key %s:%s", namespace, key)
+		}
+	}
+
+	return metadata, block, txnum, nil
+}
+
+func (db *database) rangeScan(q queryer, namespace string, startKey string, endKey string) (*rowsIterator, error) {
+	stmt := fmt.Sprintf(`SELECT pkey, val, block, txnum FROM %s WHERE ns = ?`, db.table)
+	args := []interface{}{namespace}
+	if startKey != "" {
+		stmt += ` AND pkey >= ?`
+		args = append(args, []byte(startKey))
+	}
+	if endKey != "" {
+		stmt += ` AND pkey < ?`
+		args = append(args, []byte(endKey))
+	}
+	stmt += ` ORDER BY pkey`
+
+	rows, err := q.Query(db.rebind(stmt), args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not query range %s:%s", startKey, endKey)
+	}
+
+	return &rowsIterator{rows: rows}, nil
+}
+
+// rowsIterator iterates over rows made of key, value, block and transaction number
+type rowsIterator struct {
+	rows *sql.Rows
+}
+
+func (r *rowsIterator) Next() (*driver.VersionedRead, error) {
+	if !r.rows.Next() {
+		return nil, r.rows.Err()
+	}
+
+	var key, value []byte
+	var block, txnum uint64
+	if err := r.rows.Scan(&key, &value, &block, &txnum); err != nil {
+		return nil, errors.Wrap(err, "could not read row")
+	}
+
+	return &driver.VersionedRead{
+		Key:          string(key),
+		Raw:          value,
+		Block:        block,
+		IndexInBlock: int(txnum),
+	}, nil
+}
+
+func (r *rowsIterator) Close() {
+	r.rows.Close()
+}
+
+// drain returns an iterator over the reads still available from the passed iterator, and closes it
+func drain(it driver.VersionedResultsIterator) (driver.VersionedResultsIterator, error) {
+	defer it.Close()
+
+	var reads []*driver.VersionedRead
+	for {
+		read, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if read == nil {
+			return &sliceIterator{reads: reads}, nil
+		}
+		reads = append(reads, read)
+	}
+}
+
+type sliceIterator struct {
+	reads []*driver.VersionedRead
+}
+
+func (s *sliceIterator) Next() (*driver.VersionedRead, error) {
+	if len(s.reads) == 0 {
+		return nil, nil
+	}
+	read := s.reads[0]
+	s.reads = s.reads[1:]
+	return read, nil
+}
+
+func (s *sliceIterator) Close() {}
+
+// NewSnapshot returns a snapshot backed by a read-only transaction.
+// Postgres runs it at the repeatable read isolation level, sqlite in WAL mode reads from the
+// version of the database current when the transaction performs its first read;
+// in both cases later commits are not visible to the snapshot.
+// Iterators obtained from the snapshot load their results eagerly, because a transaction can
+// serve one query at a time; therefore they remain usable after the snapshot is closed.
+func (db *database) NewSnapshot() (driver.VersionedSnapshot, error) {
+	txn, err := db.db.BeginTx(context.Background(), db.dialect.snapshotOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not begin snapshot transaction")
+	}
+
+	// the first read fixes the snapshot
+	var count int
+	err = txn.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE 1 = 0`, db.table)).Scan(&count)
+	if err != nil {
+		txn.Rollback()
+		return nil, errors.Wrap(err, "could not begin snapshot transaction")
+	}
+
+	return &snapshot{db: db, txn: txn}, nil
+}
+
+type snapshot struct {
+	db  *database
+	txn *sql.Tx
+}
+
+func (s *snapshot) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
+	return s.db.getState(s.txn, namespace, key)
+}
+
+func (s *snapshot) GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error) {
+	return s.db.getStateMetadata(s.txn, namespace, key)
+}
+
+func (s *snapshot) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	it, err := s.db.rangeScan(s.txn, namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return drain(it)
+}
+
+func (s *snapshot) GetStateByQuery(namespace string, query string) (driver.VersionedResultsIterator, error) {
+	it, err := s.db.query(s.txn, namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	return drain(it)
+}
+
+func (s *snapshot) Close() {
+	if err := s.txn.Rollback(); err != nil {
+		logger.Warnf("could not close snapshot: %s", err)
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+var tempDir string
+
+func TestMain(m *testing.M) {
+	var err error
+	tempDir, err = ioutil.TempDir("", "sql-fsc-test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temporary directory: %v", err)
+		os.Exit(-1)
+	}
+	defer os.RemoveAll(tempDir)
+
+	m.Run()
+}
+
+func openSQLite(t *testing.T, name string) *database {
+	db, err := OpenDB(SQLite, driver.DataSourceName(filepath.Join(tempDir, name+".sqlite"), name))
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	return db
+}
+
+func TestParseDataSourceName(t *testing.T) {
+	dataSource, table := parseDataSourceName("host=localhost dbname=fsc#my-Channel.1")
+	assert.Equal(t, "host=localhost dbname=fsc", dataSource)
+	assert.Equal(t, "fsc_my_channel_1", table)
+
+	dataSource, table = parseDataSourceName("/tmp/db.sqlite")
+	assert.Equal(t, "/tmp/db.sqlite", dataSource)
+	assert.Equal(t, "fsc_state", table)
+
+	_, err := OpenDB(SQLite, driver.DataSourceName("", "table"))
+	assert.Error(t, err)
+	_, err = OpenDB("mysql", driver.DataSourceName("/tmp/db", "table"))
+	assert.Error(t, err)
+}
+
+func TestRebind(t *testing.T) {
+	pg := &database{dialect: dialects[Postgres]}
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1 AND c = $2", pg.rebind("SELECT a FROM t WHERE b = ? AND c = ?"))
+	lite := &database{dialect: dialects[SQLite]}
+	assert.Equal(t, "SELECT a FROM t WHERE b = ? AND c = ?", lite.rebind("SELECT a FROM t WHERE b = ? AND c = ?"))
+}
+
+func TestSimpleReadWrite(t *testing.T) {
+	ns := "ns"
+	key := "key"
+
+	db := openSQLite(t, "TestSimpleReadWrite")
+	defer db.Close()
+
+	v, bn, tn, err := db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(nil), v)
+	assert.Equal(t, uint64(0), bn)
+	assert.Equal(t, uint64(0), tn)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.Error(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, key, []byte("val"), 35, 1))
+	assert.NoError(t, db.Commit())
+
+	v, bn, tn, err = db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+	assert.Equal(t, uint64(35), bn)
+	assert.Equal(t, uint64(1), tn)
+
+	// uncommitted changes are not visible
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, key, []byte("val1"), 36, 2))
+	v, bn, tn, err = db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+	assert.Equal(t, uint64(35), bn)
+	assert.Equal(t, uint64(1), tn)
+	assert.NoError(t, db.Commit())
+
+	v, bn, tn, err = db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val1"), v)
+	assert.Equal(t, uint64(36), bn)
+	assert.Equal(t, uint64(2), tn)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, key, []byte("val0"), 37, 3))
+	assert.NoError(t, db.Discard())
+	assert.Error(t, db.Commit())
+
+	v, bn, tn, err = db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val1"), v)
+	assert.Equal(t, uint64(36), bn)
+	assert.Equal(t, uint64(2), tn)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.DeleteState(ns, key))
+	assert.NoError(t, db.Commit())
+
+	v, bn, tn, err = db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(nil), v)
+	assert.Equal(t, uint64(0), bn)
+	assert.Equal(t, uint64(0), tn)
+}
+
+func TestMetadata(t *testing.T) {
+	ns := "namespace"
+	key := "foo"
+
+	db := openSQLite(t, "TestMetadata")
+	defer db.Close()
+
+	md, bn, tn, err := db.GetStateMetadata(ns, key)
+	assert.NoError(t, err)
+	assert.Nil(t, md)
+	assert.Equal(t, uint64(0), bn)
+	assert.Equal(t, uint64(0), tn)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, key, []byte("val"), 35, 1))
+	assert.NoError(t, db.SetStateMetadata(ns, key, map[string][]byte{"foo": []byte("bar")}, 36, 2))
+	assert.NoError(t, db.Commit())
+
+	v, bn, tn, err := db.GetState(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+	assert.Equal(t, uint64(36), bn)
+	assert.Equal(t, uint64(2), tn)
+
+	md, bn, tn, err = db.GetStateMetadata(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo": []byte("bar")}, md)
+	assert.Equal(t, uint64(36), bn)
+	assert.Equal(t, uint64(2), tn)
+
+	// setting the value preserves the metadata
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, key, []byte("val1"), 37, 3))
+	assert.NoError(t, db.Commit())
+
+	md, bn, tn, err = db.GetStateMetadata(ns, key)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo": []byte("bar")}, md)
+	assert.Equal(t, uint64(37), bn)
+	assert.Equal(t, uint64(3), tn)
+}
+
+func TestRangeQueries(t *testing.T) {
+	ns := "namespace"
+
+	db := openSQLite(t, "TestRangeQueries")
+	defer db.Close()
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k2", []byte("k2_value"), 35, 1))
+	assert.NoError(t, db.SetState(ns, "k3", []byte("k3_value"), 35, 2))
+	assert.NoError(t, db.SetState(ns, "k1", []byte("k1_value"), 35, 3))
+	assert.NoError(t, db.SetState(ns, "k111", []byte("k111_value"), 35, 4))
+	assert.NoError(t, db.SetState(ns, "\x00k\x00a\x00", []byte("composite"), 35, 5))
+	assert.NoError(t, db.SetState("other", "k1", []byte("other_value"), 35, 6))
+	assert.NoError(t, db.Commit())
+
+	collect := func(it driver.VersionedResultsIterator, err error) []driver.VersionedRead {
+		assert.NoError(t, err)
+		defer it.Close()
+		var res []driver.VersionedRead
+		for n, err := it.Next(); n != nil; n, err = it.Next() {
+			assert.NoError(t, err)
+			res = append(res, *n)
+		}
+		return res
+	}
+
+	assert.Equal(t, []driver.VersionedRead{
+		{Key: "\x00k\x00a\x00", Raw: []byte("composite"), Block: 35, IndexInBlock: 5},
+		{Key: "k1", Raw: []byte("k1_value"), Block: 35, IndexInBlock: 3},
+		{Key: "k111", Raw: []byte("k111_value"), Block: 35, IndexInBlock: 4},
+		{Key: "k2", Raw: []byte("k2_value"), Block: 35, IndexInBlock: 1},
+		{Key: "k3", Raw: []byte("k3_value"), Block: 35, IndexInBlock: 2},
+	}, collect(db.GetStateRangeScanIterator(ns, "", "")))
+
+	assert.Equal(t, []driver.VersionedRead{
+		{Key: "k1", Raw: []byte("k1_value"), Block: 35, IndexInBlock: 3},
+		{Key: "k111", Raw: []byte("k111_value"), Block: 35, IndexInBlock: 4},
+		{Key: "k2", Raw: []byte("k2_value"), Block: 35, IndexInBlock: 1},
+	}, collect(db.GetStateRangeScanIterator(ns, "k1", "k3")))
+
+	assert.Equal(t, []driver.VersionedRead{
+		{Key: "\x00k\x00a\x00", Raw: []byte("composite"), Block: 35, IndexInBlock: 5},
+	}, collect(db.GetStateRangeScanIterator(ns, "\x00k\x00", "\x00k\x00\U0010ffff")))
+}
+
+func queryKeys(t *testing.T, it driver.VersionedResultsIterator, err error) []string {
+	assert.NoError(t, err)
+	defer it.Close()
+
+	var res []string
+	for {
+		read, err := it.Next()
+		assert.NoError(t, err)
+		if read == nil {
+			return res
+		}
+		res = append(res, read.Key)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	ns := "ns"
+
+	db := openSQLite(t, "TestQueries")
+	defer db.Close()
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k1", []byte(`{"owner": "alice", "amount": 5}`), 35, 1))
+	assert.NoError(t, db.SetState(ns, "k2", []byte(`{"owner": "bob", "amount": 10}`), 35, 2))
+	assert.NoError(t, db.SetState(ns, "k3", []byte(`{"owner": "alice", "amount": 20}`), 35, 3))
+	assert.NoError(t, db.SetState(ns, "k4", []byte(`not json`), 35, 4))
+	assert.NoError(t, db.SetState("other", "k1", []byte(`{"owner": "alice", "amount": 50}`), 35, 5))
+	assert.NoError(t, db.Commit())
+
+	// full scan
+	it, err := db.GetStateByQuery(ns, `{"selector": {"owner": "alice"}, "sort": [{"amount": "desc"}]}`)
+	assert.Equal(t, []string{"k3", "k1"}, queryKeys(t, it, err))
+
+	// index on existing values
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.CreateIndex(ns, &driver.Index{Name: "amount", Fields: []string{"amount"}}))
+	assert.NoError(t, db.Commit())
+
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	assert.Equal(t, []string{"k2", "k3"}, queryKeys(t, it, err))
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}, "owner": "alice"}}`)
+	assert.Equal(t, []string{"k3"}, queryKeys(t, it, err))
+
+	// the index follows updates and deletions
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k2", []byte(`{"owner": "bob", "amount": 1}`), 36, 1))
+	assert.NoError(t, db.DeleteState(ns, "k3"))
+	assert.NoError(t, db.Commit())
+
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 7}}}`)
+	assert.Empty(t, queryKeys(t, it, err))
+	it, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$lte": 5}}, "sort": ["amount"]}`)
+	assert.Equal(t, []string{"k2", "k1"}, queryKeys(t, it, err))
+
+	_, err = db.GetStateByQuery(ns, `{"selector": {"amount": {"$foo": 7}}}`)
+	assert.Error(t, err)
+}
+
+func TestSnapshot(t *testing.T) {
+	ns := "ns"
+
+	db := openSQLite(t, "TestSnapshot")
+	defer db.Close()
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k1", []byte(`{"amount": 5}`), 35, 1))
+	assert.NoError(t, db.Commit())
+
+	snapshot, err := db.NewSnapshot()
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState(ns, "k1", []byte(`{"amount": 6}`), 36, 1))
+	assert.NoError(t, db.SetState(ns, "k2", []byte(`{"amount": 7}`), 36, 2))
+	assert.NoError(t, db.Commit())
+
+	v, bn, tn, err := snapshot.GetState(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"amount": 5}`), v)
+	assert.Equal(t, uint64(35), bn)
+	assert.Equal(t, uint64(1), tn)
+
+	it, err := snapshot.GetStateRangeScanIterator(ns, "", "")
+	assert.Equal(t, []string{"k1"}, queryKeys(t, it, err))
+	it, err = snapshot.GetStateByQuery(ns, `{"selector": {"amount": {"$gt": 0}}}`)
+	snapshot.Close()
+	assert.Equal(t, []string{"k1"}, queryKeys(t, it, err))
+
+	it, err = db.GetStateRangeScanIterator(ns, "", "")
+	assert.Equal(t, []string{"k1", "k2"}, queryKeys(t, it, err))
+}
+
+func TestDriver(t *testing.T) {
+	p, err := db.Open(SQLite, driver.DataSourceName(filepath.Join(tempDir, "TestDriver.sqlite"), "kvs"))
+	assert.NoError(t, err)
+	defer p.Close()
+
+	assert.NoError(t, p.BeginUpdate())
+	assert.NoError(t, p.SetState("ns", "key", []byte("val")))
+	assert.NoError(t, p.Commit())
+
+	// tables of the same database are independent
+	vp, err := db.OpenVersioned(SQLite, driver.DataSourceName(filepath.Join(tempDir, "TestDriver.sqlite"), "vault"))
+	assert.NoError(t, err)
+	defer vp.Close()
+
+	v, err := p.GetState("ns", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("val"), v)
+	v, _, _, err = vp.GetState("ns", "key")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+const (
+	healthNamespace = "_health"
+	healthKey       = "probe"
+)
+
+// HealthChecker checks that a persistence can still serve reads
+type HealthChecker struct {
+	Persistence driver.Persistence
+}
+
+func (h *HealthChecker) HealthCheck(context.Context) error {
+	if _, err := h.Persistence.GetState(healthNamespace, healthKey); err != nil {
+		return errors.WithMessage(err, "persistence not readable")
+	}
+	return nil
+}
+
+// VersionedHealthChecker checks that a versioned persistence can still serve reads
+type VersionedHealthChecker struct {
+	Persistence driver.VersionedPersistence
+}
+
+func (h *VersionedHealthChecker) HealthCheck(context.Context) error {
+	if _, _, _, err := h.Persistence.GetState(healthNamespace, healthKey); err != nil {
+		return errors.WithMessage(err, "persistence not readable")
+	}
+	return nil
+}
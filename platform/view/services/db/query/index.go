@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+// Index entries are made of the order-preserving encoding of the indexed fields followed by
+// entrySeparator and the key of the indexed value.
+// Each encoded field starts with a tag identifying its type. Only null, booleans, numbers and strings
+// are indexed; a value whose indexed fields are missing or of other types has no entry.
+const (
+	tagNull   byte = 0x01
+	tagFalse  byte = 0x02
+	tagTrue   byte = 0x03
+	tagNumber byte = 0x04
+	tagString byte = 0x05
+
+	entrySeparator byte = 0x00
+	// rangeEnd is greater than any byte that can follow an encoded field
+	rangeEnd byte = 0xFF
+)
+
+// IndexEntry returns the entry of the passed index for the passed key and raw value.
+// It returns false if the value is not indexed.
+func IndexEntry(index *driver.Index, key string, raw []byte) ([]byte, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, false
+	}
+
+	var entry []byte
+	for _, field := range index.Fields {
+		v, found := lookup(doc, strings.Split(field, "."))
+		if !found {
+			return nil, false
+		}
+		encoded, ok := encode(v)
+		if !ok {
+			return nil, false
+		}
+		entry = append(entry, encoded...)
+	}
+	entry = append(entry, entrySeparator)
+	return append(entry, key...), true
+}
+
+// IndexRange returns the range [start, end) of the entries of the passed index that might match this query.
+// It returns false if the index cannot be used to answer this query.
+func (q *Query) IndexRange(index *driver.Index) ([]byte, []byte, bool) {
+	if len(index.Fields) == 0 {
+		return nil, nil, false
+	}
+	// only the first field of the index is used, the other conditions are checked on the candidates
+	var conditions []*condition
+	var and andSelector
+	switch s := q.Selector.(type) {
+	case andSelector:
+		and = s
+	default:
+		and = andSelector{s}
+	}
+	for _, s := range flatten(and) {
+		fs, ok := s.(*fieldSelector)
+		if ok && fs.field == index.Fields[0] {
+			conditions = append(conditions, fs.conditions...)
+		}
+	}
+
+	var start, end []byte
+	var tag byte
+	for _, c := range conditions {
+		var lower, upper []byte
+		switch c.op {
+		case "$eq", "$gt", "$gte", "$lt", "$lte":
+		default:
+			continue
+		}
+		encoded, ok := encode(c.value)
+		if !ok {
+			continue
+		}
+		if tag != 0 && tag != encoded[0] {
+			// conditions on different types cannot be satisfied at the same time
+			return []byte{}, []byte{}, true
+		}
+		tag = encoded[0]
+
+		switch c.op {
+		case "$eq":
+			lower = encoded
+			upper = append(append([]byte{}, encoded...), rangeEnd)
+		case "$gt":
+			lower = append(append([]byte{}, encoded...), rangeEnd)
+		case "$gte":
+			lower = encoded
+		case "$lt":
+			upper = encoded
+		case "$lte":
+			upper = append(append([]byte{}, encoded...), rangeEnd)
+		}
+		if lower != nil && (start == nil || bytes.Compare(lower, start) > 0) {
+			start = lower
+		}
+		if upper != nil && (end == nil || bytes.Compare(upper, end) < 0) {
+			end = upper
+		}
+	}
+	if tag == 0 {
+		return nil, nil, false
+	}
+	// stay within the values of the same type
+	if start == nil {
+		start = []byte{tag}
+	}
+	if end == nil {
+		end = []byte{tag + 1}
+	}
+	if bytes.Compare(start, end) > 0 {
+		end = start
+	}
+	return start, end, true
+}
+
+// SelectIndex returns the index, among the passed ones, to use to answer this query, and the range of its entries
+// to scan. It returns nil if no index can be used.
+func (q *Query) SelectIndex(indexes []*driver.Index) (*driver.Index, []byte, []byte) {
+	for _, index := range indexes {
+		if len(q.UseIndex) != 0 && index.Name != q.UseIndex {
+			continue
+		}
+		if start, end, ok := q.IndexRange(index); ok {
+			return index, start, end
+		}
+	}
+	if len(q.UseIndex) != 0 {
+		// the requested index is not usable, fallback to any index
+		q2 := *q
+		q2.UseIndex = ""
+		return q2.SelectIndex(indexes)
+	}
+	return nil, nil, nil
+}
+
+func flatten(and andSelector) []Selector {
+	var res []Selector
+	for _, s := range and {
+		if sub, ok := s.(andSelector); ok {
+			res = append(res, flatten(sub)...)
+			continue
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// encode returns the order-preserving encoding of the passed value, if it can be indexed
+func encode(v interface{}) ([]byte, bool) {
+	switch v := v.(type) {
+	case nil:
+		return []byte{tagNull}, true
+	case bool:
+		if v {
+			return []byte{tagTrue}, true
+		}
+		return []byte{tagFalse}, true
+	case float64:
+		bits := math.Float64bits(v)
+		if v < 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		res := make([]byte, 9)
+		res[0] = tagNumber
+		binary.BigEndian.PutUint64(res[1:], bits)
+		return res, true
+	case string:
+		// escape 0x00 as 0x00 0xFF and terminate with 0x00 0x00 so that prefixes sort first
+		res := []byte{tagString}
+		for _, b := range []byte(v) {
+			if b == 0x00 {
+				res = append(res, 0x00, 0xFF)
+				continue
+			}
+			res = append(res, b)
+		}
+		return append(res, 0x00, 0x00), true
+	}
+	return nil, false
+}
@@ -0,0 +1,520 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Query models a query over JSON documents in the style of CouchDB Mango queries.
+// For instance:
+//
+//	{"selector": {"owner": "alice", "amount": {"$gt": 10}}, "sort": [{"amount": "desc"}], "limit": 10}
+type Query struct {
+	// Selector selects the documents returned by the query
+	Selector Selector
+	// Sort lists the fields the results are sorted by
+	Sort []SortField
+	// Limit is the maximum number of results returned, 0 means no limit
+	Limit int
+	// Skip is the number of results to skip
+	Skip int
+	// UseIndex is the name of the index the query should use, if possible
+	UseIndex string
+}
+
+// SortField is a field the results of a query are sorted by
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+type rawQuery struct {
+	Selector map[string]interface{} `json:"selector"`
+	Sort     []interface{}          `json:"sort,omitempty"`
+	Limit    int                    `json:"limit,omitempty"`
+	Skip     int                    `json:"skip,omitempty"`
+	UseIndex interface{}            `json:"use_index,omitempty"`
+}
+
+// Parse parses the passed query
+func Parse(raw string) (*Query, error) {
+	rq := &rawQuery{}
+	d := json.NewDecoder(strings.NewReader(raw))
+	d.DisallowUnknownFields()
+	if err := d.Decode(rq); err != nil {
+		return nil, errors.Wrapf(err, "failed parsing query [%s]", raw)
+	}
+	if rq.Selector == nil {
+		return nil, errors.Errorf("query [%s] has no selector", raw)
+	}
+	if rq.Limit < 0 || rq.Skip < 0 {
+		return nil, errors.Errorf("query [%s] has a negative limit or skip", raw)
+	}
+
+	selector, err := parseSelector("", rq.Selector)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid selector in query [%s]", raw)
+	}
+	q := &Query{
+		Selector: selector,
+		Limit:    rq.Limit,
+		Skip:     rq.Skip,
+	}
+
+	for _, s := range rq.Sort {
+		switch v := s.(type) {
+		case string:
+			q.Sort = append(q.Sort, SortField{Field: v})
+		case map[string]interface{}:
+			if len(v) != 1 {
+				return nil, errors.Errorf("invalid sort [%v] in query [%s]", v, raw)
+			}
+			for field, direction := range v {
+				switch direction {
+				case "asc":
+					q.Sort = append(q.Sort, SortField{Field: field})
+				case "desc":
+					q.Sort = append(q.Sort, SortField{Field: field, Descending: true})
+				default:
+					return nil, errors.Errorf("invalid sort direction [%v] in query [%s]", direction, raw)
+				}
+			}
+		default:
+			return nil, errors.Errorf("invalid sort [%v] in query [%s]", s, raw)
+		}
+	}
+
+	switch v := rq.UseIndex.(type) {
+	case nil:
+	case string:
+		q.UseIndex = v
+	case []interface{}:
+		// [design document, index name], only the name is relevant here
+		if len(v) > 0 {
+			name, ok := v[len(v)-1].(string)
+			if !ok {
+				return nil, errors.Errorf("invalid use_index [%v] in query [%s]", v, raw)
+			}
+			q.UseIndex = name
+		}
+	default:
+		return nil, errors.Errorf("invalid use_index [%v] in query [%s]", v, raw)
+	}
+
+	return q, nil
+}
+
+// Match returns true if the passed raw JSON document satisfies the selector of this query
+func (q *Query) Match(raw []byte) bool {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return false
+	}
+	return q.Selector.Match(doc)
+}
+
+// Selector selects JSON documents
+type Selector interface {
+	// Match returns true if the passed document, decoded with encoding/json, is selected
+	Match(doc interface{}) bool
+}
+
+type andSelector []Selector
+
+func (s andSelector) Match(doc interface{}) bool {
+	for _, selector := range s {
+		if !selector.Match(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+type orSelector []Selector
+
+func (s orSelector) Match(doc interface{}) bool {
+	for _, selector := range s {
+		if selector.Match(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+type norSelector []Selector
+
+func (s norSelector) Match(doc interface{}) bool {
+	return !orSelector(s).Match(doc)
+}
+
+type notSelector struct {
+	Selector
+}
+
+func (s *notSelector) Match(doc interface{}) bool {
+	return !s.Selector.Match(doc)
+}
+
+// fieldSelector selects the documents whose field satisfies all the conditions
+type fieldSelector struct {
+	field      string
+	path       []string
+	conditions []*condition
+}
+
+func (s *fieldSelector) Match(doc interface{}) bool {
+	v, found := lookup(doc, s.path)
+	for _, c := range s.conditions {
+		if !c.match(v, found) {
+			return false
+		}
+	}
+	return true
+}
+
+type condition struct {
+	op    string
+	value interface{}
+	regex *regexp.Regexp
+	not   []*condition
+}
+
+func (c *condition) match(v interface{}, found bool) bool {
+	switch c.op {
+	case "$exists":
+		return found == c.value.(bool)
+	case "$not":
+		for _, sub := range c.not {
+			if !sub.match(v, found) {
+				return true
+			}
+		}
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	switch c.op {
+	case "$eq":
+		return reflect.DeepEqual(v, c.value)
+	case "$ne":
+		return !reflect.DeepEqual(v, c.value)
+	case "$gt", "$gte", "$lt", "$lte":
+		cmp, ok := compareSameType(v, c.value)
+		if !ok {
+			return false
+		}
+		switch c.op {
+		case "$gt":
+			return cmp > 0
+		case "$gte":
+			return cmp >= 0
+		case "$lt":
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case "$in":
+		for _, e := range c.value.([]interface{}) {
+			if reflect.DeepEqual(v, e) {
+				return true
+			}
+		}
+		return false
+	case "$nin":
+		for _, e := range c.value.([]interface{}) {
+			if reflect.DeepEqual(v, e) {
+				return false
+			}
+		}
+		return true
+	case "$regex":
+		s, ok := v.(string)
+		return ok && c.regex.MatchString(s)
+	case "$size":
+		a, ok := v.([]interface{})
+		return ok && float64(len(a)) == c.value.(float64)
+	case "$all":
+		a, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, e := range c.value.([]interface{}) {
+			contained := false
+			for _, ae := range a {
+				if reflect.DeepEqual(ae, e) {
+					contained = true
+					break
+				}
+			}
+			if !contained {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func parseSelector(prefix string, m map[string]interface{}) (Selector, error) {
+	// sort the keys to get a deterministic selector
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var res andSelector
+	for _, k := range keys {
+		v := m[k]
+		switch k {
+		case "$and", "$or", "$nor":
+			if len(prefix) != 0 {
+				return nil, errors.Errorf("operator [%s] not supported inside field [%s]", k, prefix)
+			}
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("operator [%s] expects an array", k)
+			}
+			var selectors []Selector
+			for _, e := range list {
+				em, ok := e.(map[string]interface{})
+				if !ok {
+					return nil, errors.Errorf("operator [%s] expects an array of selectors", k)
+				}
+				s, err := parseSelector("", em)
+				if err != nil {
+					return nil, err
+				}
+				selectors = append(selectors, s)
+			}
+			switch k {
+			case "$and":
+				res = append(res, andSelector(selectors))
+			case "$or":
+				res = append(res, orSelector(selectors))
+			default:
+				res = append(res, norSelector(selectors))
+			}
+		case "$not":
+			if len(prefix) != 0 {
+				return nil, errors.Errorf("operator [%s] not supported inside field [%s]", k, prefix)
+			}
+			em, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("operator [%s] expects a selector", k)
+			}
+			s, err := parseSelector("", em)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &notSelector{Selector: s})
+		default:
+			if strings.HasPrefix(k, "$") {
+				return nil, errors.Errorf("operator [%s] not supported", k)
+			}
+			field := prefix + k
+			if sub, ok := v.(map[string]interface{}); ok && len(sub) != 0 && !hasOperators(sub) {
+				// nested fields
+				s, err := parseSelector(field+".", sub)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, s)
+				continue
+			}
+			conditions, err := parseConditions(field, v)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &fieldSelector{
+				field:      field,
+				path:       strings.Split(field, "."),
+				conditions: conditions,
+			})
+		}
+	}
+	return res, nil
+}
+
+func hasOperators(m map[string]interface{}) bool {
+	for k := range m {
+		if strings.HasPrefix(k, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseConditions(field string, v interface{}) ([]*condition, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok || !hasOperators(m) {
+		// implicit equality
+		return []*condition{{op: "$eq", value: v}}, nil
+	}
+
+	ops := make([]string, 0, len(m))
+	for op := range m {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var res []*condition
+	for _, op := range ops {
+		arg := m[op]
+		c := &condition{op: op, value: arg}
+		switch op {
+		case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+		case "$in", "$nin", "$all":
+			if _, ok := arg.([]interface{}); !ok {
+				return nil, errors.Errorf("operator [%s] on field [%s] expects an array", op, field)
+			}
+		case "$exists":
+			if _, ok := arg.(bool); !ok {
+				return nil, errors.Errorf("operator [%s] on field [%s] expects a boolean", op, field)
+			}
+		case "$size":
+			if _, ok := arg.(float64); !ok {
+				return nil, errors.Errorf("operator [%s] on field [%s] expects a number", op, field)
+			}
+		case "$regex":
+			s, ok := arg.(string)
+			if !ok {
+				return nil, errors.Errorf("operator [%s] on field [%s] expects a string", op, field)
+			}
+			regex, err := regexp.Compile(s)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regular expression on field [%s]", field)
+			}
+			c.regex = regex
+		case "$not":
+			not, err := parseConditions(field, arg)
+			if err != nil {
+				return nil, err
+			}
+			c.not = not
+		default:
+			return nil, errors.Errorf("operator [%s] on field [%s] not supported", op, field)
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// lookup returns the value at the passed path in the passed document
+func lookup(doc interface{}, path []string) (interface{}, bool) {
+	v := doc
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[p]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// compareSameType compares two values of the same type among booleans, numbers and strings
+func compareSameType(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av == bv:
+			return 0, true
+		case !av:
+			return -1, true
+		default:
+			return 1, true
+		}
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	}
+	return 0, false
+}
+
+// compare orders any two values: missing < null < false < true < numbers < strings < arrays < objects.
+// Arrays and objects are compared by their JSON representation.
+func compare(a interface{}, aFound bool, b interface{}, bFound bool) int {
+	if !aFound || !bFound {
+		switch {
+		case aFound == bFound:
+			return 0
+		case !aFound:
+			return -1
+		default:
+			return 1
+		}
+	}
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	if cmp, ok := compareSameType(a, b); ok {
+		return cmp
+	}
+	if a == nil {
+		return 0
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return strings.Compare(string(ja), string(jb))
+}
+
+func rank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+func TestMatch(t *testing.T) {
+	doc := []byte(`{"owner": {"name": "alice"}, "amount": 15, "type": "iou", "tags": ["a", "b"]}`)
+
+	for _, tc := range []struct {
+		selector string
+		match    bool
+	}{
+		{`{"type": "iou"}`, true},
+		{`{"type": "token"}`, false},
+		{`{"owner.name": "alice"}`, true},
+		{`{"owner": {"name": "alice"}}`, true},
+		{`{"amount": {"$gt": 10}}`, true},
+		{`{"amount": {"$gt": 10, "$lte": 15}}`, true},
+		{`{"amount": {"$lt": 10}}`, false},
+		{`{"amount": {"$gt": "10"}}`, false},
+		{`{"amount": {"$in": [1, 15]}}`, true},
+		{`{"amount": {"$nin": [1, 15]}}`, false},
+		{`{"amount": {"$ne": 15}}`, false},
+		{`{"amount": {"$not": {"$gt": 20}}}`, true},
+		{`{"missing": {"$exists": false}}`, true},
+		{`{"missing": {"$ne": 1}}`, false},
+		{`{"owner.name": {"$regex": "^al"}}`, true},
+		{`{"tags": {"$all": ["a"]}}`, true},
+		{`{"tags": {"$size": 2}}`, true},
+		{`{"$or": [{"type": "token"}, {"amount": 15}]}`, true},
+		{`{"$nor": [{"type": "token"}, {"amount": 15}]}`, false},
+		{`{"$and": [{"type": "iou"}, {"$not": {"amount": 15}}]}`, false},
+	} {
+		q, err := Parse(`{"selector": ` + tc.selector + `}`)
+		assert.NoError(t, err, tc.selector)
+		assert.Equal(t, tc.match, q.Match(doc), tc.selector)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		`{}`,
+		`{"selector": {"a": {"$foo": 1}}}`,
+		`{"selector": {"$foo": 1}}`,
+		`{"selector": {"a": {"$in": 1}}}`,
+		`{"selector": {"a": {"$regex": "("}}}`,
+		`{"selector": {}, "sort": [{"a": "up"}]}`,
+		`{"selector": {}, "limit": -1}`,
+		`{"selector": {}, "foo": 1}`,
+	} {
+		_, err := Parse(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestIndexRange(t *testing.T) {
+	index := &driver.Index{Name: "amount", Fields: []string{"amount"}}
+	entry := func(amount string) []byte {
+		e, ok := IndexEntry(index, "k", []byte(`{"amount": `+amount+`}`))
+		assert.True(t, ok)
+		return e
+	}
+	in := func(q *Query, e []byte) bool {
+		start, end, ok := q.IndexRange(index)
+		assert.True(t, ok)
+		return bytes.Compare(start, e) <= 0 && bytes.Compare(e, end) < 0
+	}
+
+	q, err := Parse(`{"selector": {"amount": {"$gt": 10, "$lte": 20}}}`)
+	assert.NoError(t, err)
+	assert.False(t, in(q, entry("10")))
+	assert.True(t, in(q, entry("10.5")))
+	assert.True(t, in(q, entry("20")))
+	assert.False(t, in(q, entry("21")))
+	assert.False(t, in(q, entry(`"15"`)))
+
+	q, err = Parse(`{"selector": {"amount": -5}}`)
+	assert.NoError(t, err)
+	assert.True(t, in(q, entry("-5")))
+	assert.False(t, in(q, entry("-4")))
+	assert.False(t, in(q, entry("-6")))
+
+	q, err = Parse(`{"selector": {"amount": {"$gte": "b"}}}`)
+	assert.NoError(t, err)
+	assert.True(t, in(q, entry(`"b"`)))
+	assert.True(t, in(q, entry(`"ba"`)))
+	assert.False(t, in(q, entry(`"a"`)))
+	assert.False(t, in(q, entry("100")))
+
+	// values without the indexed field, and queries not constraining it, do not use the index
+	_, ok := IndexEntry(index, "k", []byte(`{"owner": "alice"}`))
+	assert.False(t, ok)
+	q, err = Parse(`{"selector": {"owner": "alice"}}`)
+	assert.NoError(t, err)
+	_, _, ok = q.IndexRange(index)
+	assert.False(t, ok)
+	q, err = Parse(`{"selector": {"$or": [{"amount": 1}, {"amount": 2}]}}`)
+	assert.NoError(t, err)
+	_, _, ok = q.IndexRange(index)
+	assert.False(t, ok)
+}
+
+func TestResults(t *testing.T) {
+	reads := []*driver.VersionedRead{
+		{Key: "k1", Raw: []byte(`{"owner": "alice", "amount": 5}`)},
+		{Key: "k2", Raw: []byte(`{"owner": "bob", "amount": 10}`)},
+		{Key: "k3", Raw: []byte(`{"owner": "alice", "amount": 20}`)},
+		{Key: "k4", Raw: []byte(`not json`)},
+		{Key: "k5", Raw: []byte(`{"owner": "alice", "amount": 15}`)},
+	}
+	run := func(raw string) []string {
+		q, err := Parse(raw)
+		assert.NoError(t, err)
+		it, err := q.Results(&sliceIterator{reads: reads})
+		assert.NoError(t, err)
+		defer it.Close()
+		var keys []string
+		for {
+			read, err := it.Next()
+			assert.NoError(t, err)
+			if read == nil {
+				return keys
+			}
+			keys = append(keys, read.Key)
+		}
+	}
+
+	assert.Equal(t, []string{"k1", "k3", "k5"}, run(`{"selector": {"owner": "alice"}}`))
+	assert.Equal(t, []string{"k3", "k5"}, run(`{"selector": {"owner": "alice"}, "skip": 1}`))
+	assert.Equal(t, []string{"k1"}, run(`{"selector": {"owner": "alice"}, "limit": 1}`))
+	assert.Equal(t, []string{"k3", "k5", "k1"}, run(`{"selector": {"owner": "alice"}, "sort": [{"amount": "desc"}]}`))
+	assert.Equal(t, []string{"k5"}, run(`{"selector": {"owner": "alice"}, "sort": ["amount"], "skip": 1, "limit": 1}`))
+	assert.Equal(t, []string{"k2", "k5", "k3"}, run(`{"selector": {"amount": {"$gte": 10}}, "sort": ["amount"]}`))
+}
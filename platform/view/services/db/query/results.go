@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+// Results returns an iterator over the passed candidates that satisfy this query, sorted, skipped and limited
+// as requested by the query. Closing the returned iterator closes the candidates.
+func (q *Query) Results(candidates driver.VersionedResultsIterator) (driver.VersionedResultsIterator, error) {
+	if len(q.Sort) == 0 {
+		return &filterIterator{query: q, candidates: candidates}, nil
+	}
+
+	// sort requires all the results
+	defer candidates.Close()
+	type sortable struct {
+		read *driver.VersionedRead
+		doc  interface{}
+	}
+	var reads []*sortable
+	for {
+		read, err := candidates.Next()
+		if err != nil {
+			return nil, err
+		}
+		if read == nil {
+			break
+		}
+		var doc interface{}
+		if err := json.Unmarshal(read.Raw, &doc); err != nil || !q.Selector.Match(doc) {
+			continue
+		}
+		reads = append(reads, &sortable{read: read, doc: doc})
+	}
+
+	sort.SliceStable(reads, func(i, j int) bool {
+		for _, field := range q.Sort {
+			path := strings.Split(field.Field, ".")
+			a, aFound := lookup(reads[i].doc, path)
+			b, bFound := lookup(reads[j].doc, path)
+			cmp := compare(a, aFound, b, bFound)
+			if cmp == 0 {
+				continue
+			}
+			if field.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	res := make([]*driver.VersionedRead, 0, len(reads))
+	for _, r := range reads {
+		res = append(res, r.read)
+	}
+	if q.Skip >= len(res) {
+		res = nil
+	} else {
+		res = res[q.Skip:]
+	}
+	if q.Limit > 0 && q.Limit < len(res) {
+		res = res[:q.Limit]
+	}
+	return &sliceIterator{reads: res}, nil
+}
+
+type filterIterator struct {
+	query      *Query
+	candidates driver.VersionedResultsIterator
+	skipped    int
+	returned   int
+}
+
+func (f *filterIterator) Next() (*driver.VersionedRead, error) {
+	for {
+		if f.query.Limit > 0 && f.returned >= f.query.Limit {
+			return nil, nil
+		}
+		read, err := f.candidates.Next()
+		if err != nil || read == nil {
+			return nil, err
+		}
+		if !f.query.Match(read.Raw) {
+			continue
+		}
+		if f.skipped < f.query.Skip {
+			f.skipped++
+			continue
+		}
+		f.returned++
+		return read, nil
+	}
+}
+
+func (f *filterIterator) Close() {
+	f.candidates.Close()
+}
+
+type sliceIterator struct {
+	reads []*driver.VersionedRead
+}
+
+func (s *sliceIterator) Next() (*driver.VersionedRead, error) {
+	if len(s.reads) == 0 {
+		return nil, nil
+	}
+	read := s.reads[0]
+	s.reads = s.reads[1:]
+	return read, nil
+}
+
+func (s *sliceIterator) Close() {}
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	x5092 "crypto/x509"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/id/x509"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// Record describes how to reach an FSC node. Nodes publish their records, signed with their identity,
+// at the registry node, where the other nodes look them up.
+type Record struct {
+	// Name is the name of the node, as in the static resolvers.
+	// It must be the common name or a DNS name of the certificate of the identity.
+	Name string
+	// Identity is the identity of the node, it signs the record.
+	// It is a PEM encoded x509 certificate that chains to one of the trusted roots of the discovery service.
+	Identity view.Identity
+	// Addresses maps the port names to the addresses of the node
+	Addresses map[string]string
+	// Aliases are the other names of the node, bound to the certificate of the identity as the name
+	Aliases []string
+	// TLSRootCerts are the PEM encoded root certificates of the TLS certificates of the node
+	TLSRootCerts [][]byte
+	// Expiry is the time after which the record is no longer valid. Nodes refresh their records before expiry.
+	Expiry time.Time
+}
+
+// SignedRecord is a record together with the signature of its identity
+type SignedRecord struct {
+	Record    []byte
+	Signature []byte
+}
+
+// Query selects the records to look up. A record matches if any of the non-empty fields matches.
+type Query struct {
+	// Identity matches the identity of the record
+	Identity view.Identity
+	// Label matches the name, the aliases, and the addresses of the record
+	Label string
+	// PKID matches the PKI ID of the identity of the record
+	PKID []byte
+}
+
+// PKIResolver extracts public key ids from identities
+type PKIResolver interface {
+	GetPKIidOfCert(peerIdentity view.Identity) []byte
+}
+
+// Sign returns the record signed by the passed signer, that must be the signer of the identity of the record
+func (r *Record) Sign(signer driver.Signer) (*SignedRecord, error) {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling record")
+	}
+	sigma, err := signer.Sign(raw)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed signing record of [%s]", r.Name)
+	}
+	return &SignedRecord{Record: raw, Signature: sigma}, nil
+}
+
+// Expired returns true if the record is expired at the passed time
+func (r *Record) Expired(now time.Time) bool {
+	return !now.Before(r.Expiry)
+}
+
+// Match returns true if the record matches the passed query
+func (r *Record) Match(query *Query, pkiResolver PKIResolver) bool {
+	if len(query.Identity) != 0 && r.Identity.Equal(query.Identity) {
+		return true
+	}
+	if len(query.Label) != 0 {
+		if query.Label == r.Name {
+			return true
+		}
+		for _, alias := range r.Aliases {
+			if query.Label == alias {
+				return true
+			}
+		}
+		for _, address := range r.Addresses {
+			if query.Label == address {
+				return true
+			}
+		}
+	}
+	if len(query.PKID) != 0 && pkiResolver != nil {
+		if pkid := pkiResolver.GetPKIidOfCert(r.Identity); len(pkid) != 0 && bytes.Equal(pkid, query.PKID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Labels returns the name, the aliases, and the addresses of the record
+func (r *Record) Labels() []string {
+	labels := append([]string{r.Name}, r.Aliases...)
+	for _, address := range r.Addresses {
+		labels = append(labels, address)
+	}
+	return labels
+}
+
+// checkIdentity checks that the identity of the record is a certificate issued by one of the passed roots,
+// and that the name and the aliases of the record are names of this certificate
+func (r *Record) checkIdentity(roots *x5092.CertPool, now time.Time) error {
+	if roots == nil {
+		return errors.New("no trusted roots for the identities of the records")
+	}
+	cert, err := x509.PemDecodeCert(r.Identity)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid identity in record of [%s]", r.Name)
+	}
+	if _, err := cert.Verify(x5092.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x5092.ExtKeyUsage{x5092.ExtKeyUsageAny},
+	}); err != nil {
+		return errors.Wrapf(err, "identity of record of [%s] not trusted", r.Name)
+	}
+
+	names := map[string]bool{cert.Subject.CommonName: true}
+	for _, name := range cert.DNSNames {
+		names[name] = true
+	}
+	for _, name := range append([]string{r.Name}, r.Aliases...) {
+		if len(name) == 0 || !names[name] {
+			return errors.Errorf("name [%s] in record of [%s] not bound to the certificate of its identity", name, r.Name)
+		}
+	}
+	return nil
+}
+
+// Open verifies the identity and the signature of the record and returns it, if not expired at the passed time.
+// The identity must chain to one of the passed roots.
+func (s *SignedRecord) Open(sigService driver.SigService, roots *x5092.CertPool, now time.Time) (*Record, error) {
+	r := &Record{}
+	if err := json.Unmarshal(s.Record, r); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling record")
+	}
+	if err := r.checkIdentity(roots, now); err != nil {
+		return nil, err
+	}
+	verifier, err := sigService.GetVerifier(r.Identity)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting verifier for record of [%s]", r.Name)
+	}
+	if err := verifier.Verify(s.Record, s.Signature); err != nil {
+		return nil, errors.WithMessagef(err, "invalid signature on record of [%s]", r.Name)
+	}
+	if r.Expired(now) {
+		return nil, errors.Errorf("record of [%s] expired at [%s]", r.Name, r.Expiry)
+	}
+	return r, nil
+}
@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type ecdsaKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (e *ecdsaKey) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return ecdsa.SignASN1(rand.Reader, e.key, digest[:])
+}
+
+func (e *ecdsaKey) Verify(message, sigma []byte) error {
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(&e.key.PublicKey, digest[:], sigma) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+type sigService map[string]*ecdsaKey
+
+func (s sigService) GetSigner(identity view.Identity) (driver.Signer, error) {
+	k, ok := s[identity.UniqueID()]
+	if !ok {
+		return nil, errors.Errorf("unknown identity [%s]", identity)
+	}
+	return k, nil
+}
+
+func (s sigService) GetVerifier(identity view.Identity) (driver.Verifier, error) {
+	k, ok := s[identity.UniqueID()]
+	if !ok {
+		return nil, errors.Errorf("unknown identity [%s]", identity)
+	}
+	return k, nil
+}
+
+func (s sigService) GetSigningIdentity(identity view.Identity) (driver.SigningIdentity, error) {
+	return nil, errors.New("not implemented")
+}
+
+// ca issues the certificates of the identities of the records
+type ca struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T) *ca {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	assert.NoError(t, err)
+	return &ca{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})}
+}
+
+func (c *ca) roots() *x509.CertPool {
+	roots := x509.NewCertPool()
+	roots.AddCert(c.cert)
+	return roots
+}
+
+// issue returns a new identity certified by this ca with the passed names, its signer is added to the passed sig service
+func (c *ca) issue(t *testing.T, sigs sigService, commonName string, dnsNames ...string) view.Identity {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	parent := c.cert
+	signerKey := c.key
+	if c.key == nil {
+		// self-signed
+		parent, signerKey = template, key
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	identity := view.Identity(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}))
+	sigs[identity.UniqueID()] = &ecdsaKey{key: key}
+	return identity
+}
+
+type pkiResolver struct{}
+
+func (p *pkiResolver) GetPKIidOfCert(peerIdentity view.Identity) []byte {
+	return append([]byte("pkid-"), peerIdentity...)
+}
+
+type kvsConfig struct{}
+
+func (f *kvsConfig) GetString(key string) string                       { return "" }
+func (f *kvsConfig) GetDuration(key string) time.Duration              { return 0 }
+func (f *kvsConfig) GetBool(key string) bool                           { return false }
+func (f *kvsConfig) GetStringSlice(key string) []string                { return nil }
+func (f *kvsConfig) IsSet(key string) bool                             { return false }
+func (f *kvsConfig) UnmarshalKey(key string, rawVal interface{}) error { return nil }
+func (f *kvsConfig) ConfigFileUsed() string                            { return "" }
+func (f *kvsConfig) GetPath(key string) string                         { return "" }
+func (f *kvsConfig) TranslatePath(path string) string                  { return "" }
+
+func sign(t *testing.T, sigs sigService, r *Record) *SignedRecord {
+	signer, err := sigs.GetSigner(r.Identity)
+	assert.NoError(t, err)
+	signed, err := r.Sign(signer)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestRecord(t *testing.T) {
+	authority := newCA(t)
+	sigs := sigService{}
+	alice := authority.issue(t, sigs, "alice.example.com", "alice", "a")
+
+	r := &Record{
+		Name:      "alice",
+		Identity:  alice,
+		Addresses: map[string]string{"P2P": "127.0.0.1:2000"},
+		Aliases:   []string{"a"},
+		Expiry:    time.Now().Add(time.Minute),
+	}
+	signed := sign(t, sigs, r)
+	opened, err := signed.Open(sigs, authority.roots(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, r.Addresses, opened.Addresses)
+
+	// expired
+	_, err = signed.Open(sigs, authority.roots(), time.Now().Add(2*time.Minute))
+	assert.Error(t, err)
+	// not trusted
+	_, err = signed.Open(sigs, newCA(t).roots(), time.Now())
+	assert.Error(t, err)
+	_, err = signed.Open(sigs, nil, time.Now())
+	assert.Error(t, err)
+	// tampered
+	signed.Record[len(signed.Record)-2]++
+	_, err = signed.Open(sigs, authority.roots(), time.Now())
+	assert.Error(t, err)
+
+	// self-signed identities are rejected
+	selfSigned := (&ca{}).issue(t, sigs, "alice.example.com", "alice")
+	_, err = sign(t, sigs, &Record{Name: "alice", Identity: selfSigned, Expiry: time.Now().Add(time.Minute)}).Open(sigs, authority.roots(), time.Now())
+	assert.Error(t, err)
+	// names and aliases must be bound to the certificate
+	for _, other := range []*Record{
+		{Name: "bob", Identity: alice, Expiry: time.Now().Add(time.Minute)},
+		{Name: "alice", Aliases: []string{"bob"}, Identity: alice, Expiry: time.Now().Add(time.Minute)},
+		{Name: "", Identity: alice, Expiry: time.Now().Add(time.Minute)},
+	} {
+		_, err = sign(t, sigs, other).Open(sigs, authority.roots(), time.Now())
+		assert.Error(t, err)
+	}
+	_, err = sign(t, sigs, &Record{Name: "alice.example.com", Identity: alice, Expiry: time.Now().Add(time.Minute)}).Open(sigs, authority.roots(), time.Now())
+	assert.NoError(t, err)
+
+	pki := &pkiResolver{}
+	for _, q := range []*Query{
+		{Identity: alice},
+		{Label: "alice"},
+		{Label: "a"},
+		{Label: "127.0.0.1:2000"},
+		{PKID: append([]byte("pkid-"), alice...)},
+	} {
+		assert.True(t, r.Match(q, pki))
+	}
+	assert.False(t, r.Match(&Query{Identity: view.Identity("bob"), Label: "b", PKID: []byte("pkid-bob")}, pki))
+}
+
+func TestRegistry(t *testing.T) {
+	authority := newCA(t)
+	sigs := sigService{}
+	alice := authority.issue(t, sigs, "alice.example.com", "alice")
+	bob := authority.issue(t, sigs, "bob.example.com", "bob")
+	eve := authority.issue(t, sigs, "eve.example.com", "eve", "alice")
+	pki := &pkiResolver{}
+
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+	kvss, err := kvs.New("memory", "_default", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+	assert.NoError(t, registry.RegisterService(sigs))
+
+	s := NewService(registry, &Config{TTL: time.Minute, TrustedRoots: [][]byte{authority.pem}}, pki)
+
+	// a newer record replaces an older one, not the other way around
+	older := sign(t, sigs, &Record{Name: "alice", Identity: alice, Addresses: map[string]string{"P2P": "old"}, Expiry: time.Now().Add(time.Minute)})
+	newer := sign(t, sigs, &Record{Name: "alice", Identity: alice, Addresses: map[string]string{"P2P": "new"}, Expiry: time.Now().Add(2 * time.Minute)})
+	assert.NoError(t, s.store(newer, pki.GetPKIidOfCert(alice)))
+	assert.Error(t, s.store(older, pki.GetPKIidOfCert(alice)))
+
+	endpoints, err := s.Endpoints(alice)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", endpoints[driver.P2PPort])
+	id, err := s.Identity("", pki.GetPKIidOfCert(alice))
+	assert.NoError(t, err)
+	assert.Equal(t, alice, id)
+
+	// a node publishes its own record only
+	assert.EqualError(t, s.store(sign(t, sigs, &Record{Name: "bob", Identity: bob, Expiry: time.Now().Add(time.Minute)}), pki.GetPKIidOfCert(alice)), "record of [bob] not published by its owner")
+
+	// the name, the aliases and the addresses of a record cannot be taken by another identity
+	assert.EqualError(t, s.store(sign(t, sigs, &Record{Name: "eve", Aliases: []string{"alice"}, Identity: eve, Expiry: time.Now().Add(time.Minute)}), pki.GetPKIidOfCert(eve)), "[alice] in record of [eve] already taken by another identity")
+	assert.Error(t, s.store(sign(t, sigs, &Record{Name: "eve", Addresses: map[string]string{"P2P": "new"}, Identity: eve, Expiry: time.Now().Add(time.Minute)}), pki.GetPKIidOfCert(eve)))
+	assert.NoError(t, s.store(sign(t, sigs, &Record{Name: "eve", Addresses: map[string]string{"P2P": "other"}, Identity: eve, Expiry: time.Now().Add(time.Minute)}), pki.GetPKIidOfCert(eve)))
+	id, err = s.Identity("alice", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, alice, id)
+
+	// expired records are not returned and get removed
+	expiring := sign(t, sigs, &Record{Name: "bob", Identity: bob, Expiry: time.Now().Add(100 * time.Millisecond)})
+	assert.NoError(t, s.store(expiring, nil))
+	_, err = s.find(&Query{Label: "bob"})
+	assert.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+	_, err = s.find(&Query{Label: "bob"})
+	assert.Error(t, err)
+	assert.False(t, kvss.Exists(kvs.CreateCompositeKeyOrPanic(recordPrefix, []string{bob.UniqueID()})))
+
+	// records signed by another identity are rejected
+	aliceSigner, err := sigs.GetSigner(alice)
+	assert.NoError(t, err)
+	forged, err := (&Record{Name: "bob", Identity: bob, Expiry: time.Now().Add(time.Minute)}).Sign(aliceSigner)
+	assert.NoError(t, err)
+	assert.Error(t, s.store(forged, nil))
+}
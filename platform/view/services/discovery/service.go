@@ -0,0 +1,382 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.discovery")
+
+const (
+	recordPrefix = "platform.fsc.discovery.record"
+
+	DefaultTTL     = 10 * time.Minute
+	DefaultTimeout = 30 * time.Second
+)
+
+// Config tells the discovery service what to publish and where
+type Config struct {
+	// Registry is the name of the registry node, it must be among the static resolvers.
+	// If empty, this node is the registry.
+	Registry string
+	// TTL is the validity of the published records, they are refreshed after half of it
+	TTL time.Duration
+	// Timeout bounds the requests to the registry node
+	Timeout time.Duration
+	// Name, Addresses, Aliases and TLSRootCerts are published in the record of this node
+	Name         string
+	Addresses    map[string]string
+	Aliases      []string
+	TLSRootCerts [][]byte
+	// TrustedRoots are the PEM encoded CA certificates the identities of the records must chain to
+	TrustedRoots [][]byte
+}
+
+// LoadConfig loads the discovery configuration from the fsc.discovery section
+func LoadConfig(configService driver.ConfigService) (*Config, error) {
+	c := &Config{
+		Registry: configService.GetString("fsc.discovery.registry"),
+		TTL:      configService.GetDuration("fsc.discovery.ttl"),
+		Timeout:  configService.GetDuration("fsc.discovery.timeout"),
+		Name:     configService.GetString("fsc.id"),
+		Aliases:  configService.GetStringSlice("fsc.discovery.aliases"),
+	}
+	if c.TTL == 0 {
+		c.TTL = DefaultTTL
+	}
+	if c.Timeout == 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if configService.IsSet("fsc.discovery.addresses") {
+		if err := configService.UnmarshalKey("fsc.discovery.addresses", &c.Addresses); err != nil {
+			return nil, errors.Wrap(err, "failed loading fsc.discovery.addresses")
+		}
+	}
+	if configService.GetBool("fsc.tls.enabled") && len(configService.GetPath("fsc.tls.rootcert.file")) != 0 {
+		rootCert, err := ioutil.ReadFile(configService.GetPath("fsc.tls.rootcert.file"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed loading tls root certificate")
+		}
+		c.TLSRootCerts = [][]byte{rootCert}
+	}
+	for _, path := range configService.GetStringSlice("fsc.discovery.trustedRoots") {
+		root, err := ioutil.ReadFile(configService.TranslatePath(path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed loading trusted root [%s]", path)
+		}
+		c.TrustedRoots = append(c.TrustedRoots, root)
+	}
+	if len(c.TrustedRoots) == 0 {
+		return nil, errors.New("no trusted roots configured in fsc.discovery.trustedRoots")
+	}
+	return c, nil
+}
+
+// Service publishes the endpoint record of this node and looks up the records of the other nodes.
+// All the nodes keep the records they looked up until expiry, the registry node keeps the records
+// published by all the nodes.
+type Service struct {
+	sp          view2.ServiceProvider
+	config      *Config
+	pkiResolver PKIResolver
+	roots       *x509.CertPool
+
+	lock     sync.RWMutex
+	identity view.Identity
+	registry view.Identity
+	cache    map[string]*Record
+}
+
+// NewService returns a new discovery service. It must be started before use.
+// The records whose identities do not chain to the trusted roots of the passed configuration are rejected.
+func NewService(sp view2.ServiceProvider, config *Config, pkiResolver PKIResolver) *Service {
+	roots := x509.NewCertPool()
+	for _, root := range config.TrustedRoots {
+		if !roots.AppendCertsFromPEM(root) {
+			logger.Warnf("no certificate found in trusted root [%s]", string(root))
+		}
+	}
+	return &Service{
+		sp:          sp,
+		config:      config,
+		pkiResolver: pkiResolver,
+		roots:       roots,
+		cache:       map[string]*Record{},
+	}
+}
+
+// Start installs the registry responder, resolves the registry node, and keeps the record of this node
+// published until the passed context is done
+func (s *Service) Start(ctx context.Context, identity view.Identity) error {
+	driver.GetRegistry(s.sp).RegisterResponder(&registryView{service: s}, &registryClientView{})
+
+	var registry view.Identity
+	if len(s.config.Registry) != 0 {
+		var err error
+		registry, err = driver.GetEndpointService(s.sp).GetIdentity(s.config.Registry, nil)
+		if err != nil {
+			return errors.WithMessagef(err, "failed resolving discovery registry [%s]", s.config.Registry)
+		}
+	}
+	s.lock.Lock()
+	s.identity = identity
+	s.registry = registry
+	s.lock.Unlock()
+
+	if len(s.config.Addresses) == 0 {
+		logger.Infof("no addresses to publish, discovery in lookup mode only")
+		return nil
+	}
+	go s.refresh(ctx)
+	return nil
+}
+
+// Endpoints returns the addresses published by the passed party
+func (s *Service) Endpoints(party view.Identity) (map[driver.PortName]string, error) {
+	r, err := s.Lookup(&Query{Identity: party, Label: string(party)})
+	if err != nil {
+		return nil, err
+	}
+	endpoints := map[driver.PortName]string{}
+	for k, v := range r.Addresses {
+		endpoints[driver.PortName(k)] = v
+	}
+	return endpoints, nil
+}
+
+// Identity returns the identity of the node that published a record matching the passed label or PKI ID
+func (s *Service) Identity(label string, pkid []byte) (view.Identity, error) {
+	r, err := s.Lookup(&Query{Label: label, PKID: pkid})
+	if err != nil {
+		return nil, err
+	}
+	return r.Identity, nil
+}
+
+// Lookup returns a valid record matching the passed query. It asks the registry node if no cached record matches.
+func (s *Service) Lookup(query *Query) (*Record, error) {
+	now := time.Now()
+	s.lock.RLock()
+	for _, r := range s.cache {
+		if !r.Expired(now) && r.Match(query, s.pkiResolver) {
+			s.lock.RUnlock()
+			return r, nil
+		}
+	}
+	registry := s.registry
+	s.lock.RUnlock()
+
+	var signed *SignedRecord
+	if len(registry) == 0 {
+		if len(s.config.Registry) != 0 {
+			return nil, errors.New("discovery registry not resolved yet")
+		}
+		var err error
+		signed, err = s.find(query)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		res, err := view2.GetManager(s.sp).InitiateView(&registryClientView{
+			registry: registry,
+			request:  &Request{Lookup: query},
+			timeout:  s.config.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		signed = res.(*Response).Record
+		if signed == nil {
+			return nil, errors.New("registry returned no record")
+		}
+	}
+
+	// do not trust the registry, the record must be signed by its identity
+	r, err := signed.Open(driver.GetSigService(s.sp), s.roots, now)
+	if err != nil {
+		return nil, err
+	}
+	if !r.Match(query, s.pkiResolver) {
+		return nil, errors.Errorf("record of [%s] does not match the query", r.Name)
+	}
+	s.lock.Lock()
+	s.cache[r.Identity.UniqueID()] = r
+	s.lock.Unlock()
+	return r, nil
+}
+
+// Publish signs the record of this node, with the configured validity, and publishes it at the registry node
+func (s *Service) Publish() error {
+	s.lock.RLock()
+	identity, registry := s.identity, s.registry
+	s.lock.RUnlock()
+
+	record := &Record{
+		Name:         s.config.Name,
+		Identity:     identity,
+		Addresses:    s.config.Addresses,
+		Aliases:      s.config.Aliases,
+		TLSRootCerts: s.config.TLSRootCerts,
+		Expiry:       time.Now().Add(s.config.TTL),
+	}
+	signer, err := driver.GetSigService(s.sp).GetSigner(identity)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting signer for [%s]", identity)
+	}
+	signed, err := record.Sign(signer)
+	if err != nil {
+		return err
+	}
+
+	if len(registry) == 0 {
+		return s.store(signed, nil)
+	}
+	_, err = view2.GetManager(s.sp).InitiateView(&registryClientView{
+		registry: registry,
+		request:  &Request{Publish: signed},
+		timeout:  s.config.Timeout,
+	})
+	return err
+}
+
+// refresh publishes the record of this node every half TTL, retrying more often on failure
+func (s *Service) refresh(ctx context.Context) {
+	for {
+		next := s.config.TTL / 2
+		if err := s.Publish(); err != nil {
+			logger.Warnf("failed publishing endpoint record: [%s]", err)
+			next = s.config.TTL / 10
+		} else {
+			logger.Debugf("endpoint record published, next refresh in [%s]", next)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next):
+		}
+	}
+}
+
+// store verifies the passed record and stores it in the registry, unless a record of the same identity
+// expiring later is already there.
+// If caller is not nil, the record is published by the node with this PKI ID, that must be the owner of the record:
+// this way, each node stores at most one record at the registry.
+// The name, the aliases and the addresses of the record must not be taken by the valid records of other identities.
+func (s *Service) store(signed *SignedRecord, caller []byte) error {
+	now := time.Now()
+	r, err := signed.Open(driver.GetSigService(s.sp), s.roots, now)
+	if err != nil {
+		return err
+	}
+	if caller != nil && !bytes.Equal(s.pkiResolver.GetPKIidOfCert(r.Identity), caller) {
+		return errors.Errorf("record of [%s] not published by its owner", r.Name)
+	}
+	if err := s.checkLabels(r, now); err != nil {
+		return err
+	}
+
+	kvss := kvs.GetService(s.sp)
+	k, err := kvs.CreateCompositeKey(recordPrefix, []string{r.Identity.UniqueID()})
+	if err != nil {
+		return errors.Wrap(err, "failed creating record key")
+	}
+	if kvss.Exists(k) {
+		old := &SignedRecord{}
+		if err := kvss.Get(k, old); err == nil {
+			if or, err := old.Open(driver.GetSigService(s.sp), s.roots, now); err == nil && or.Expiry.After(r.Expiry) {
+				return errors.Errorf("record of [%s] older than the stored one", r.Name)
+			}
+		}
+	}
+	if err := kvss.Put(k, signed); err != nil {
+		return errors.WithMessagef(err, "failed storing record of [%s]", r.Name)
+	}
+	logger.Debugf("stored record of [%s] valid until [%s]", r.Name, r.Expiry)
+	return nil
+}
+
+// checkLabels returns an error if the name, an alias or an address of the passed record is taken by the valid record
+// of another identity stored in the registry
+func (s *Service) checkLabels(r *Record, now time.Time) error {
+	it, err := kvs.GetService(s.sp).GetByPartialCompositeID(recordPrefix, []string{})
+	if err != nil {
+		return errors.WithMessage(err, "failed scanning records")
+	}
+	defer it.Close()
+	for it.HasNext() {
+		signed := &SignedRecord{}
+		if err := it.Next(signed); err != nil {
+			return errors.WithMessage(err, "failed reading record")
+		}
+		other, err := signed.Open(driver.GetSigService(s.sp), s.roots, now)
+		if err != nil || other.Identity.Equal(r.Identity) {
+			continue
+		}
+		for _, label := range r.Labels() {
+			if other.Match(&Query{Label: label}, nil) {
+				return errors.Errorf("[%s] in record of [%s] already taken by another identity", label, r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// find returns a valid record matching the passed query among the records stored in the registry.
+// The expired records found along the way are removed.
+func (s *Service) find(query *Query) (*SignedRecord, error) {
+	kvss := kvs.GetService(s.sp)
+	it, err := kvss.GetByPartialCompositeID(recordPrefix, []string{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed scanning records")
+	}
+	now := time.Now()
+	var found *SignedRecord
+	var expired []string
+	for found == nil && it.HasNext() {
+		signed := &SignedRecord{}
+		if err := it.Next(signed); err != nil {
+			it.Close()
+			return nil, errors.WithMessage(err, "failed reading record")
+		}
+		r, err := signed.Open(driver.GetSigService(s.sp), s.roots, now)
+		if err != nil {
+			if r := (&Record{}); json.Unmarshal(signed.Record, r) == nil && r.Expired(now) {
+				expired = append(expired, r.Identity.UniqueID())
+			}
+			continue
+		}
+		if r.Match(query, s.pkiResolver) {
+			found = signed
+		}
+	}
+	it.Close()
+
+	for _, id := range expired {
+		if err := kvss.Delete(kvs.CreateCompositeKeyOrPanic(recordPrefix, []string{id})); err != nil {
+			logger.Warnf("failed removing expired record of [%s]: [%s]", id, err)
+		}
+	}
+	if found == nil {
+		return nil, errors.New("no record found")
+	}
+	return found, nil
+}
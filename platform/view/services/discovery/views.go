@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/session"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// Request is sent to the registry node either to publish a record or to look one up
+type Request struct {
+	Publish *SignedRecord
+	Lookup  *Query
+}
+
+// Response is the answer of the registry node to a request
+type Response struct {
+	// Record is the record found by a lookup
+	Record *SignedRecord
+	// Error is empty if the request succeeded
+	Error string
+}
+
+// registryClientView sends a request to the registry node and waits for the response
+type registryClientView struct {
+	registry view.Identity
+	request  *Request
+	timeout  time.Duration
+}
+
+func (r *registryClientView) Call(context view.Context) (interface{}, error) {
+	js, err := session.NewJSON(context, context.Initiator(), r.registry)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed opening session to registry [%s]", r.registry)
+	}
+	if err := js.Send(r.request); err != nil {
+		return nil, errors.WithMessagef(err, "failed sending request to registry [%s]", r.registry)
+	}
+	response := &Response{}
+	if err := js.ReceiveWithTimeout(response, r.timeout); err != nil {
+		return nil, errors.WithMessagef(err, "failed receiving response from registry [%s]", r.registry)
+	}
+	if len(response.Error) != 0 {
+		return nil, errors.Errorf("registry [%s] failed: %s", r.registry, response.Error)
+	}
+	return response, nil
+}
+
+// registryView serves the requests sent to the registry node
+type registryView struct {
+	service *Service
+}
+
+func (r *registryView) Call(context view.Context) (interface{}, error) {
+	js := session.JSON(context)
+	request := &Request{}
+	if err := js.Receive(request); err != nil {
+		return nil, errors.WithMessage(err, "failed receiving registry request")
+	}
+
+	response := &Response{}
+	switch {
+	case request.Publish != nil:
+		caller := context.Session().Info().EndpointPKID
+		if len(caller) == 0 {
+			response.Error = "unknown caller"
+			break
+		}
+		if err := r.service.store(request.Publish, caller); err != nil {
+			logger.Debugf("rejecting record: [%s]", err)
+			response.Error = err.Error()
+		}
+	case request.Lookup != nil:
+		record, err := r.service.find(request.Lookup)
+		if err != nil {
+			response.Error = err.Error()
+		}
+		response.Record = record
+	default:
+		response.Error = "empty request"
+	}
+	if err := js.Send(response); err != nil {
+		return nil, errors.WithMessage(err, "failed sending registry response")
+	}
+	return nil, nil
+}
+
+// AcceptsUnknownCallers tells the view manager to serve the nodes that have not published their record yet
+func (r *registryView) AcceptsUnknownCallers() bool {
+	return true
+}
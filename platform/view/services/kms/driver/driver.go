@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package driver
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+// Opts tells a key management driver where the private keys of an identity are and how to access them
+type Opts struct {
+	// Type is the name of the driver, file if empty
+	Type string `yaml:"type"`
+	// File is the key file or the keystore folder, file driver only.
+	// If empty, the key file configured for the identity is used.
+	File string `yaml:"file"`
+	// Password decrypts the keys encrypted with a password, file driver only
+	Password string `yaml:"password"`
+	// PasswordFile contains the password, file driver only
+	PasswordFile string `yaml:"passwordFile"`
+	// KEKFile contains the key encryption key, 32 bytes either raw or hex encoded, file driver only
+	KEKFile string `yaml:"kekFile"`
+	// RejectPlaintext makes the file driver fail on keys not encrypted
+	RejectPlaintext bool `yaml:"rejectPlaintext"`
+	// PKCS11 configures the pkcs11 driver
+	PKCS11 *PKCS11Opts `yaml:"pkcs11"`
+}
+
+// PKCS11Opts tells the pkcs11 driver which token holds the keys.
+// The keys are looked up by their CKA_ID, that must be the SKI of the public key.
+type PKCS11Opts struct {
+	// Library is the path of the PKCS#11 library
+	Library string `yaml:"library"`
+	// Label is the label of the token
+	Label string `yaml:"label"`
+	// Pin is the user pin of the token
+	Pin string `yaml:"pin"`
+	// Hash is the hash family, SHA2 if empty
+	Hash string `yaml:"hash"`
+	// Security is the security level, 256 if zero
+	Security int `yaml:"security"`
+	// SoftVerify verifies the signatures in software
+	SoftVerify bool `yaml:"softVerify"`
+}
+
+// Driver opens the key stores of a given type
+type Driver interface {
+	// NewCSP returns a BCCSP whose GetKey returns the private keys selected by the passed options
+	NewCSP(opts *Opts) (bccsp.BCCSP, error)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EncryptedBlockType is the type of the PEM blocks of the encrypted private keys
+	EncryptedBlockType = "FSC ENCRYPTED PRIVATE KEY"
+
+	kdfHeader   = "KDF"
+	saltHeader  = "Salt"
+	nonceHeader = "Nonce"
+	scryptKDF   = "scrypt"
+	kekKDF      = "kek"
+
+	// scrypt parameters, as recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keySize = 32
+)
+
+// Encrypt encrypts the private key in the passed PEM with AES-256-GCM, under either a key derived from
+// the password with scrypt or the passed key encryption key, and returns the encrypted PEM
+func Encrypt(keyPEM []byte, password []byte, kek []byte) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed decoding private key pem")
+	}
+	if block.Type == EncryptedBlockType {
+		return nil, errors.New("private key already encrypted")
+	}
+
+	headers := map[string]string{}
+	var key []byte
+	switch {
+	case len(password) != 0 && len(kek) != 0:
+		return nil, errors.New("either a password or a key encryption key must be passed, not both")
+	case len(password) != 0:
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, errors.Wrap(err, "failed generating salt")
+		}
+		var err error
+		key, err = scrypt.Key(password, salt, scryptN, scryptR, scryptP, keySize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed deriving key from password")
+		}
+		headers[kdfHeader] = scryptKDF
+		headers[saltHeader] = hex.EncodeToString(salt)
+	case len(kek) != 0:
+		key = kek
+		headers[kdfHeader] = kekKDF
+	default:
+		return nil, errors.New("a password or a key encryption key must be passed")
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed generating nonce")
+	}
+	headers[nonceHeader] = hex.EncodeToString(nonce)
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:    EncryptedBlockType,
+		Headers: headers,
+		Bytes:   gcm.Seal(nil, nonce, block.Bytes, []byte(EncryptedBlockType)),
+	}), nil
+}
+
+// Decrypt returns the DER encoded private key in the passed encrypted block
+func Decrypt(block *pem.Block, password []byte, kek []byte) ([]byte, error) {
+	if block.Type != EncryptedBlockType {
+		return nil, errors.Errorf("expected block of type [%s], got [%s]", EncryptedBlockType, block.Type)
+	}
+
+	var key []byte
+	switch kdf := block.Headers[kdfHeader]; kdf {
+	case scryptKDF:
+		if len(password) == 0 {
+			return nil, errors.New("private key encrypted with a password, no password configured")
+		}
+		salt, err := hex.DecodeString(block.Headers[saltHeader])
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid salt")
+		}
+		key, err = scrypt.Key(password, salt, scryptN, scryptR, scryptP, keySize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed deriving key from password")
+		}
+	case kekKDF:
+		if len(kek) == 0 {
+			return nil, errors.New("private key encrypted with a key encryption key, no key encryption key configured")
+		}
+		key = kek
+	default:
+		return nil, errors.Errorf("unknown key derivation [%s]", kdf)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(block.Headers[nonceHeader])
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	der, err := gcm.Open(nil, nonce, block.Bytes, []byte(EncryptedBlockType))
+	if err != nil {
+		return nil, errors.New("failed decrypting private key, wrong password or key encryption key")
+	}
+	return der, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, errors.Errorf("key encryption key must be %d bytes long, got %d", keySize, len(key))
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating cipher")
+	}
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating gcm")
+	}
+	return gcm, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.kms.file")
+
+// Driver opens the keys stored in files, either in plaintext or encrypted
+type Driver struct{}
+
+func (d *Driver) NewCSP(opts *driver.Opts) (bccsp.BCCSP, error) {
+	password := []byte(opts.Password)
+	if len(opts.PasswordFile) != 0 {
+		raw, err := ioutil.ReadFile(opts.PasswordFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading password file [%s]", opts.PasswordFile)
+		}
+		password = bytes.TrimSpace(raw)
+	}
+	var kek []byte
+	if len(opts.KEKFile) != 0 {
+		var err error
+		kek, err = LoadKEK(opts.KEKFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ks, err := NewKeyStore(opts.File, password, kek, opts.RejectPlaintext)
+	if err != nil {
+		return nil, err
+	}
+	return sw.NewDefaultSecurityLevelWithKeystore(ks)
+}
+
+// LoadKEK reads a key encryption key from the passed file, either raw or hex encoded
+func LoadKEK(path string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading key encryption key file [%s]", path)
+	}
+	if len(raw) == keySize {
+		return raw, nil
+	}
+	kek, err := hex.DecodeString(string(bytes.TrimSpace(raw)))
+	if err != nil || len(kek) != keySize {
+		return nil, errors.Errorf("key encryption key in [%s] must be %d bytes long, raw or hex encoded", path, keySize)
+	}
+	return kek, nil
+}
+
+func init() {
+	kms.Register(kms.FileDriver, &Driver{})
+}
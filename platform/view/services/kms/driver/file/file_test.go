@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+)
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	raw, err := x509.MarshalPKCS8PrivateKey(sk)
+	assert.NoError(t, err)
+	return sk, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw})
+}
+
+func checkSigner(t *testing.T, s *kms.Service, label string, keyFile string, sk *ecdsa.PrivateKey) {
+	signer, err := s.NewSigner(label, keyFile, &sk.PublicKey)
+	assert.NoError(t, err)
+	sigma, err := signer.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello"))
+	assert.True(t, ecdsa.VerifyASN1(&sk.PublicKey, digest[:], sigma))
+}
+
+func TestEncrypt(t *testing.T) {
+	_, keyPEM := newKey(t)
+	kek := make([]byte, keySize)
+	_, err := rand.Read(kek)
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		password, kek []byte
+	}{
+		{password: []byte("password")},
+		{kek: kek},
+	} {
+		encrypted, err := Encrypt(keyPEM, c.password, c.kek)
+		assert.NoError(t, err)
+		block, _ := pem.Decode(encrypted)
+		assert.Equal(t, EncryptedBlockType, block.Type)
+		plain, _ := pem.Decode(keyPEM)
+		der, err := Decrypt(block, c.password, c.kek)
+		assert.NoError(t, err)
+		assert.Equal(t, plain.Bytes, der)
+
+		_, err = Decrypt(block, []byte("wrong"), make([]byte, keySize))
+		assert.Error(t, err)
+		_, err = Encrypt(encrypted, c.password, c.kek)
+		assert.Error(t, err)
+	}
+	_, err = Encrypt(keyPEM, []byte("password"), kek)
+	assert.Error(t, err)
+	_, err = Encrypt(keyPEM, nil, []byte("short"))
+	assert.Error(t, err)
+}
+
+func TestSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kms")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// a plaintext key
+	plainSK, plainPEM := newKey(t)
+	plainFile := filepath.Join(dir, "plain_sk")
+	assert.NoError(t, ioutil.WriteFile(plainFile, plainPEM, 0600))
+
+	// a keystore folder with keys encrypted with the same password
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, ioutil.WriteFile(passwordFile, []byte("password\n"), 0600))
+	keystore := filepath.Join(dir, "keystore")
+	assert.NoError(t, os.Mkdir(keystore, 0700))
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 2; i++ {
+		sk, keyPEM := newKey(t)
+		encrypted, err := Encrypt(keyPEM, []byte("password"), nil)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(keystore, hex.EncodeToString(kms.SKI(&sk.PublicKey))+"_sk"), encrypted, 0600))
+		keys = append(keys, sk)
+	}
+
+	// a key encrypted with a key encryption key
+	kekSK, kekPEM := newKey(t)
+	kek := make([]byte, keySize)
+	_, err = rand.Read(kek)
+	assert.NoError(t, err)
+	kekFile := filepath.Join(dir, "kek")
+	assert.NoError(t, ioutil.WriteFile(kekFile, []byte(hex.EncodeToString(kek)), 0600))
+	encrypted, err := Encrypt(kekPEM, nil, kek)
+	assert.NoError(t, err)
+	kekKeyFile := filepath.Join(dir, "kek_sk")
+	assert.NoError(t, ioutil.WriteFile(kekKeyFile, encrypted, 0600))
+
+	s := kms.New(&kms.Config{
+		Default: &driver.Opts{PasswordFile: passwordFile, RejectPlaintext: true},
+		Identities: map[string]*driver.Opts{
+			"plain": {},
+			"kek":   {KEKFile: kekFile},
+		},
+	})
+	checkSigner(t, s, "plain", plainFile, plainSK)
+	checkSigner(t, s, "kek", kekKeyFile, kekSK)
+	for _, sk := range keys {
+		checkSigner(t, s, "any", keystore, sk)
+	}
+
+	// plaintext keys are rejected by default
+	_, err = s.NewSigner("any", plainFile, &plainSK.PublicKey)
+	assert.Error(t, err)
+	// keys encrypted with a kek cannot be opened with a password
+	_, err = s.NewSigner("any", kekKeyFile, &kekSK.PublicKey)
+	assert.Error(t, err)
+	// a key not in the keystore
+	_, err = s.NewSigner("any", keystore, &plainSK.PublicKey)
+	assert.Error(t, err)
+	// unknown driver
+	_, err = kms.NewCSP(&driver.Opts{Type: "unknown"})
+	assert.Error(t, err)
+}
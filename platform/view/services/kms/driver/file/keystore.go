@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package file
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+// keyStore is a read-only bccsp.KeyStore over a key file or a folder of key files.
+// The keys can be either in plaintext or encrypted with Encrypt.
+type keyStore struct {
+	path            string
+	password        []byte
+	kek             []byte
+	rejectPlaintext bool
+	importer        bccsp.BCCSP
+}
+
+// NewKeyStore returns a read-only key store over the passed key file or folder
+func NewKeyStore(path string, password []byte, kek []byte, rejectPlaintext bool) (bccsp.KeyStore, error) {
+	if len(path) == 0 {
+		return nil, errors.New("no key file or folder configured")
+	}
+	importer, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating key importer")
+	}
+	return &keyStore{
+		path:            path,
+		password:        password,
+		kek:             kek,
+		rejectPlaintext: rejectPlaintext,
+		importer:        importer,
+	}, nil
+}
+
+func (ks *keyStore) ReadOnly() bool {
+	return true
+}
+
+// GetKey returns the private key with the passed SKI among the keys in the key file or folder
+func (ks *keyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	files, err := ks.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading key file [%s]", file)
+		}
+		key, err := ks.load(raw)
+		if err != nil {
+			if errors.Cause(err) == errUnusable {
+				return nil, errors.WithMessagef(err, "failed loading key file [%s]", file)
+			}
+			logger.Debugf("skipping [%s]: [%s]", file, err)
+			continue
+		}
+		if bytes.Equal(key.SKI(), ski) {
+			return key, nil
+		}
+	}
+	return nil, errors.Errorf("key with SKI [%s] not found in [%s]", hex.EncodeToString(ski), ks.path)
+}
+
+func (ks *keyStore) StoreKey(k bccsp.Key) error {
+	return errors.New("read-only key store")
+}
+
+func (ks *keyStore) files() ([]string, error) {
+	info, err := os.Stat(ks.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed accessing [%s]", ks.path)
+	}
+	if !info.IsDir() {
+		return []string{ks.path}, nil
+	}
+	entries, err := ioutil.ReadDir(ks.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading folder [%s]", ks.path)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(ks.path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+var errUnusable = errors.New("unusable key")
+
+func (ks *keyStore) load(raw []byte) (bccsp.Key, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("not a pem file")
+	}
+	der := block.Bytes
+	if block.Type == EncryptedBlockType {
+		var err error
+		der, err = Decrypt(block, ks.password, ks.kek)
+		if err != nil {
+			return nil, errors.Wrap(errUnusable, err.Error())
+		}
+	} else if ks.rejectPlaintext {
+		return nil, errors.Wrap(errUnusable, "plaintext keys are rejected")
+	}
+	key, err := ks.importer.KeyImport(der, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed importing private key")
+	}
+	return key, nil
+}
@@ -0,0 +1,52 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/pkcs11"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+)
+
+// Driver opens the keys stored in a PKCS#11 token
+type Driver struct{}
+
+func (d *Driver) NewCSP(opts *driver.Opts) (bccsp.BCCSP, error) {
+	if opts.PKCS11 == nil {
+		return nil, errors.New("pkcs11 options not set")
+	}
+	p11Opts := pkcs11.PKCS11Opts{
+		SecLevel:   opts.PKCS11.Security,
+		HashFamily: opts.PKCS11.Hash,
+		Library:    opts.PKCS11.Library,
+		Label:      opts.PKCS11.Label,
+		Pin:        opts.PKCS11.Pin,
+		SoftVerify: opts.PKCS11.SoftVerify,
+	}
+	if p11Opts.SecLevel == 0 {
+		p11Opts.SecLevel = 256
+	}
+	if len(p11Opts.HashFamily) == 0 {
+		p11Opts.HashFamily = "SHA2"
+	}
+	csp, err := pkcs11.New(p11Opts, sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening token [%s] with library [%s]", p11Opts.Label, p11Opts.Library)
+	}
+	return csp, nil
+}
+
+func init() {
+	kms.Register(kms.PKCS11Driver, &Driver{})
+}
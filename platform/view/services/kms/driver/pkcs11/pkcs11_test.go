@@ -0,0 +1,65 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+)
+
+// TestSigner runs against a SoftHSM token, initialized for instance with:
+// softhsm2-util --init-token --slot 0 --label fsc --so-pin 1234 --pin 98765432
+// PKCS11_LIB, PKCS11_LABEL and PKCS11_PIN select the library and the token.
+func TestSigner(t *testing.T) {
+	lib := os.Getenv("PKCS11_LIB")
+	if len(lib) == 0 {
+		t.Skip("PKCS11_LIB not set")
+	}
+	opts := &driver.Opts{
+		Type: kms.PKCS11Driver,
+		PKCS11: &driver.PKCS11Opts{
+			Library: lib,
+			Label:   os.Getenv("PKCS11_LABEL"),
+			Pin:     os.Getenv("PKCS11_PIN"),
+		},
+	}
+	csp, err := kms.NewCSP(opts)
+	assert.NoError(t, err)
+
+	// generate a key in the token, it gets its SKI as CKA_ID
+	key, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	pkKey, err := key.PublicKey()
+	assert.NoError(t, err)
+	raw, err := pkKey.Bytes()
+	assert.NoError(t, err)
+	pk, err := x509.ParsePKIXPublicKey(raw)
+	assert.NoError(t, err)
+
+	s := kms.New(&kms.Config{Identities: map[string]*driver.Opts{"fsc": opts}})
+	signer, err := s.NewSigner("fsc", "", pk.(*ecdsa.PublicKey))
+	assert.NoError(t, err)
+	sigma, err := signer.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello"))
+	assert.True(t, ecdsa.VerifyASN1(pk.(*ecdsa.PublicKey), digest[:], sigma))
+
+	_, err = kms.NewCSP(&driver.Opts{Type: kms.PKCS11Driver})
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	driver2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kms/driver"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.kms")
+
+const (
+	// FileDriver is the name of the driver of the keys stored in files
+	FileDriver = "file"
+	// PKCS11Driver is the name of the driver of the keys stored in PKCS#11 tokens
+	PKCS11Driver = "pkcs11"
+	// NodeIdentityLabel is the label of the identity of the FSC node
+	NodeIdentityLabel = "fsc"
+)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]driver.Driver)
+)
+
+// Register makes a key management driver available by the provided name.
+// If Register is called twice with the same name or if driver is nil,
+// it panics.
+func Register(name string, driver driver.Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if driver == nil {
+		panic("Register driver is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("Register called twice for driver " + name)
+	}
+	drivers[name] = driver
+}
+
+// Drivers returns a sorted list of the names of the registered drivers.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	list := make([]string, 0, len(drivers))
+	for name := range drivers {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// NewCSP returns a BCCSP giving access to the keys selected by the passed options
+func NewCSP(opts *driver.Opts) (bccsp.BCCSP, error) {
+	name := opts.Type
+	if len(name) == 0 {
+		name = FileDriver
+	}
+	driversMu.RLock()
+	d, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("key management driver [%s] not found, available drivers are %v", name, Drivers())
+	}
+	csp, err := d.NewCSP(opts)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed opening key store with driver [%s]", name)
+	}
+	return csp, nil
+}
+
+// Config selects the key management options of each identity
+type Config struct {
+	// Default applies to the identities without their own options
+	Default *driver.Opts `yaml:"default"`
+	// Identities maps the identity labels to their options
+	Identities map[string]*driver.Opts `yaml:"identities"`
+}
+
+// ConfigService models the configuration the key management service is loaded from
+type ConfigService interface {
+	IsSet(key string) bool
+	UnmarshalKey(key string, rawVal interface{}) error
+	TranslatePath(path string) string
+}
+
+// LoadConfig loads the fsc.kms section, it returns nil if the section is not set
+func LoadConfig(configService ConfigService) (*Config, error) {
+	if !configService.IsSet("fsc.kms") {
+		return nil, nil
+	}
+	c := &Config{}
+	if err := configService.UnmarshalKey("fsc.kms", c); err != nil {
+		return nil, errors.Wrap(err, "failed loading fsc.kms")
+	}
+	for _, opts := range append([]*driver.Opts{c.Default}, values(c.Identities)...) {
+		if opts == nil {
+			continue
+		}
+		for _, path := range []*string{&opts.File, &opts.PasswordFile, &opts.KEKFile} {
+			if len(*path) != 0 {
+				*path = configService.TranslatePath(*path)
+			}
+		}
+	}
+	return c, nil
+}
+
+func values(m map[string]*driver.Opts) []*driver.Opts {
+	var res []*driver.Opts
+	for _, v := range m {
+		res = append(res, v)
+	}
+	return res
+}
+
+// Service selects the key store of each identity by label
+type Service struct {
+	config *Config
+}
+
+// New returns a key management service for the passed configuration
+func New(config *Config) *Service {
+	return &Service{config: config}
+}
+
+// Opts returns a copy of the options of the passed identity label.
+// If the options do not point to any file, keyFile is used.
+func (s *Service) Opts(label string, keyFile string) *driver.Opts {
+	opts, ok := s.config.Identities[label]
+	if !ok || opts == nil {
+		opts = s.config.Default
+	}
+	res := &driver.Opts{}
+	if opts != nil {
+		*res = *opts
+	}
+	if len(res.File) == 0 {
+		res.File = keyFile
+	}
+	return res
+}
+
+// NewCSP returns a BCCSP giving access to the keys of the passed identity label
+func (s *Service) NewCSP(label string, keyFile string) (bccsp.BCCSP, error) {
+	return NewCSP(s.Opts(label, keyFile))
+}
+
+// NewSigner returns a signer for the private key of the passed public key, held in the key store of the
+// passed identity label
+func (s *Service) NewSigner(label string, keyFile string, pk *ecdsa.PublicKey) (driver2.Signer, error) {
+	csp, err := s.NewCSP(label, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(csp, pk)
+}
+
+// GetService returns the key management service, nil if the keys are not managed
+func GetService(sp view2.ServiceProvider) *Service {
+	s, err := sp.GetService(&Service{})
+	if err != nil {
+		return nil
+	}
+	return s.(*Service)
+}
+
+type signer struct {
+	csp bccsp.BCCSP
+	key bccsp.Key
+}
+
+// NewSigner returns a signer for the private key of the passed public key, held by the passed BCCSP
+func NewSigner(csp bccsp.BCCSP, pk *ecdsa.PublicKey) (driver2.Signer, error) {
+	key, err := csp.GetKey(SKI(pk))
+	if err != nil {
+		return nil, errors.Wrap(err, "private key not found")
+	}
+	if !key.Private() {
+		return nil, errors.New("key found is not private")
+	}
+	return &signer{csp: csp, key: key}, nil
+}
+
+func (s *signer) Sign(message []byte) ([]byte, error) {
+	digest, err := s.csp.Hash(message, &bccsp.SHA256Opts{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed hashing message")
+	}
+	return s.csp.Sign(s.key, digest, nil)
+}
+
+// SKI returns the subject key identifier of the passed public key, as computed by the BCCSPs
+func SKI(pk *ecdsa.PublicKey) []byte {
+	hash := sha256.Sum256(elliptic.Marshal(pk.Curve, pk.X, pk.Y))
+	return hash[:]
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package kvs
 
 import (
+	"context"
 	"encoding/json"
 	"path/filepath"
 	"sync"
@@ -32,6 +33,10 @@ type KVS struct {
 
 type Opts struct {
 	Path string
+	// Driver is the sql driver, postgres or sqlite, used by the sql persistence type
+	Driver string
+	// DataSource identifies the database used by the sql drivers, each namespace is stored in its own tables
+	DataSource string
 }
 
 func New(driverName, namespace string, sp view.ServiceProvider) (*KVS, error) {
@@ -41,6 +46,10 @@ func New(driverName, namespace string, sp view.ServiceProvider) (*KVS, error) {
 		return nil, errors.Wrapf(err, "failed getting opts for vault")
 	}
 	path := filepath.Join(opts.Path, namespace)
+	if driverName == "sql" {
+		driverName = opts.Driver
+		path = driver.DataSourceName(opts.DataSource, namespace)
+	}
 
 	logger.Debugf("opening kvs at [%s]", path)
 	persistence, err := db.Open(driverName, path)
@@ -94,6 +103,34 @@ func (o *KVS) Put(id string, state interface{}) error {
 	return nil
 }
 
+func (o *KVS) Delete(id string) error {
+	logger.Debugf("delete state [%s,%s]", o.namespace, id)
+
+	o.putMutex.Lock()
+	defer o.putMutex.Unlock()
+
+	err := o.store.BeginUpdate()
+	if err != nil {
+		return errors.WithMessagef(err, "begin update for id [%s] failed", id)
+	}
+
+	err = o.store.DeleteState(o.namespace, id)
+	if err != nil {
+		if err1 := o.store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+
+		return errors.Errorf("failed to delete value for id [%s]", id)
+	}
+
+	err = o.store.Commit()
+	if err != nil {
+		return errors.WithMessagef(err, "committing delete for id [%s] failed", id)
+	}
+
+	return nil
+}
+
 func (o *KVS) Get(id string, state interface{}) error {
 	raw, err := o.store.GetState(o.namespace, id)
 	if err != nil {
@@ -161,3 +198,8 @@ func GetService(ctx view2.ServiceProvider) *KVS {
 	}
 	return s.(*KVS)
 }
+
+// HealthCheck checks that the persistence of this KVS can still serve reads
+func (o *KVS) HealthCheck(ctx context.Context) error {
+	return (&db.HealthChecker{Persistence: o.store}).HealthCheck(ctx)
+}
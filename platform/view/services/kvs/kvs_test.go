@@ -9,11 +9,13 @@ package kvs_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/badger"
 	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/sql"
 
 	"github.com/stretchr/testify/assert"
 
@@ -24,6 +26,7 @@ import (
 type fakeProv struct {
 	typ  string
 	path string
+	opts *kvs.Opts
 }
 
 func (f *fakeProv) GetString(key string) string {
@@ -47,6 +50,10 @@ func (f *fakeProv) IsSet(key string) bool {
 }
 
 func (f *fakeProv) UnmarshalKey(key string, rawVal interface{}) error {
+	if f.opts != nil {
+		*(rawVal.(*kvs.Opts)) = *f.opts
+		return nil
+	}
 	*(rawVal.(*kvs.Opts)) = kvs.Opts{
 		Path: f.path,
 	}
@@ -115,6 +122,10 @@ func testRound(t *testing.T, cfg *fakeProv) {
 			assert.Fail(t, "expected 2 entries in the range, found more")
 		}
 	}
+
+	assert.NoError(t, kvstore.Delete(k1))
+	assert.False(t, kvstore.Exists(k1))
+	assert.True(t, kvstore.Exists(k2))
 }
 
 func TestMemKVS(t *testing.T) {
@@ -123,4 +134,5 @@ func TestMemKVS(t *testing.T) {
 	defer os.RemoveAll(path)
 	testRound(t, &fakeProv{typ: "memory"})
 	testRound(t, &fakeProv{typ: "badger", path: path})
+	testRound(t, &fakeProv{typ: "sql", opts: &kvs.Opts{Driver: "sqlite", DataSource: filepath.Join(path, "kvs.sqlite")}})
 }
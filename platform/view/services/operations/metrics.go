@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/prometheus"
+)
+
+// prometheusProvider is shared by all the nodes in the process, the prometheus collectors are registered once
+// in the default registry.
+var prometheusProvider = &cachingProvider{
+	Provider:   &prometheus.Provider{},
+	counters:   map[string]metrics.Counter{},
+	gauges:     map[string]metrics.Gauge{},
+	histograms: map[string]metrics.Histogram{},
+}
+
+// cachingProvider returns the same metric each time a metric with the same fully qualified name is created.
+// The subsystems create their metrics per channel or per node, while the underlying provider
+// accepts each metric once.
+type cachingProvider struct {
+	metrics.Provider
+
+	lock       sync.Mutex
+	counters   map[string]metrics.Counter
+	gauges     map[string]metrics.Gauge
+	histograms map[string]metrics.Histogram
+}
+
+func (p *cachingProvider) NewCounter(o metrics.CounterOpts) metrics.Counter {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	name := fqname(o.Namespace, o.Subsystem, o.Name)
+	if c, ok := p.counters[name]; ok {
+		return c
+	}
+	c := p.Provider.NewCounter(o)
+	p.counters[name] = c
+	return c
+}
+
+func (p *cachingProvider) NewGauge(o metrics.GaugeOpts) metrics.Gauge {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	name := fqname(o.Namespace, o.Subsystem, o.Name)
+	if g, ok := p.gauges[name]; ok {
+		return g
+	}
+	g := p.Provider.NewGauge(o)
+	p.gauges[name] = g
+	return g
+}
+
+func (p *cachingProvider) NewHistogram(o metrics.HistogramOpts) metrics.Histogram {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	name := fqname(o.Namespace, o.Subsystem, o.Name)
+	if h, ok := p.histograms[name]; ok {
+		return h
+	}
+	h := p.Provider.NewHistogram(o)
+	p.histograms[name] = h
+	return h
+}
+
+func fqname(namespace, subsystem, name string) string {
+	var parts []string
+	for _, part := range []string{namespace, subsystem, name} {
+		if len(part) != 0 {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_")
+}
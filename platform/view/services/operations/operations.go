@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging/httpadmin"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/web"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.operations")
+
+const (
+	// PrometheusProvider exposes the metrics at /metrics in the Prometheus format
+	PrometheusProvider = "prometheus"
+	// DisabledProvider discards the metrics
+	DisabledProvider = "disabled"
+)
+
+var versionGaugeOpts = metrics.GaugeOpts{
+	Namespace:    "fsc",
+	Name:         "version",
+	Help:         "The active version of the fabric smart client.",
+	LabelNames:   []string{"version"},
+	StatsdFormat: "%{#fqname}.%{version}",
+}
+
+type ConfigProvider interface {
+	GetBool(key string) bool
+	GetString(key string) string
+	GetPath(key string) string
+	GetStringSlice(key string) []string
+	TranslatePath(path string) string
+}
+
+type Options struct {
+	ListenAddress string
+	TLS           web.TLS
+	// MetricsProvider is the provider of the metrics, prometheus or disabled
+	MetricsProvider string
+}
+
+// LoadOptions returns the options of the operations system configured under fsc.operations, nil if not enabled
+func LoadOptions(cp ConfigProvider) (*Options, error) {
+	if !cp.GetBool("fsc.operations.enabled") {
+		return nil, nil
+	}
+	o := &Options{
+		ListenAddress:   cp.GetString("fsc.operations.listenAddress"),
+		MetricsProvider: cp.GetString("fsc.operations.metrics.provider"),
+		TLS: web.TLS{
+			Enabled:  cp.GetBool("fsc.operations.tls.enabled"),
+			CertFile: cp.GetPath("fsc.operations.tls.cert.file"),
+			KeyFile:  cp.GetPath("fsc.operations.tls.key.file"),
+		},
+	}
+	if len(o.ListenAddress) == 0 {
+		return nil, errors.New("operations enabled but fsc.operations.listenAddress not set")
+	}
+	if len(o.MetricsProvider) == 0 {
+		o.MetricsProvider = PrometheusProvider
+	}
+	for _, path := range cp.GetStringSlice("fsc.operations.tls.clientRootCAs.files") {
+		o.TLS.ClientCACertFiles = append(o.TLS.ClientCACertFiles, cp.TranslatePath(path))
+	}
+	return o, nil
+}
+
+// System is the operations endpoint of the node. It serves the metrics, the health checks,
+// the logging spec and the version of the node over HTTP.
+// System is also the metrics provider the subsystems of the node are instrumented with.
+type System struct {
+	metrics.Provider
+
+	server        *web.Server
+	healthHandler *healthz.HealthHandler
+	versionGauge  metrics.Gauge
+}
+
+func NewSystem(o Options) *System {
+	s := &System{
+		server: web.NewServer(web.Options{
+			Logger:        logger,
+			ListenAddress: o.ListenAddress,
+			TLS:           o.TLS,
+		}),
+		healthHandler: healthz.NewHealthHandler(),
+	}
+
+	switch o.MetricsProvider {
+	case PrometheusProvider:
+		s.Provider = prometheusProvider
+		s.server.RegisterHandler("/metrics", promhttp.Handler())
+	default:
+		if o.MetricsProvider != DisabledProvider {
+			logger.Warnf("unknown metrics provider [%s], metrics disabled", o.MetricsProvider)
+		}
+		s.Provider = &disabled.Provider{}
+	}
+	s.versionGauge = s.Provider.NewGauge(versionGaugeOpts)
+
+	s.server.RegisterHandler("/healthz", s.healthHandler)
+	s.server.RegisterHandler("/logspec", httpadmin.NewSpecHandler())
+	s.server.RegisterHandler("/version", &VersionInfoHandler{Version: Version, CommitSHA: CommitSHA})
+
+	return s
+}
+
+func (s *System) Start() error {
+	s.versionGauge.With("version", Version).Set(1)
+	if err := s.server.Start(); err != nil {
+		return errors.Wrap(err, "failed starting operations server")
+	}
+	logger.Infof("operations endpoint listening at [%s]", s.server.Addr())
+	return nil
+}
+
+func (s *System) Stop() error {
+	return s.server.Stop()
+}
+
+// Addr returns the address the operations endpoint listens on, once started
+func (s *System) Addr() string {
+	return s.server.Addr()
+}
+
+// RegisterChecker adds the passed checker to the checks run by /healthz, under the passed component name
+func (s *System) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	return s.healthHandler.RegisterChecker(component, checker)
+}
+
+// RunChecks runs the health checks and returns the failed ones
+func (s *System) RunChecks(ctx context.Context) []healthz.FailedCheck {
+	return s.healthHandler.RunChecks(ctx)
+}
+
+// RegisterHandler serves the passed handler at the passed pattern, with the security of the operations endpoint
+func (s *System) RegisterHandler(pattern string, handler http.Handler) {
+	s.server.RegisterHandler(pattern, handler)
+}
+
+// GetSystem returns the operations system registered in the passed service provider, nil if not found
+func GetSystem(sp driver.ServiceProvider) *System {
+	s, err := sp.GetService(&System{})
+	if err != nil {
+		return nil
+	}
+	return s.(*System)
+}
+
+// GetMetricsProvider returns the metrics provider of the operations system registered in the passed service provider.
+// If the operations system is not enabled, the returned provider discards the metrics.
+func GetMetricsProvider(sp driver.ServiceProvider) metrics.Provider {
+	if s := GetSystem(sp); s != nil {
+		return s.Provider
+	}
+	return &disabled.Provider{}
+}
+
+// RegisterChecker adds the passed checker to the operations system registered in the passed service provider, if any
+func RegisterChecker(sp driver.ServiceProvider, component string, checker healthz.HealthChecker) {
+	s := GetSystem(sp)
+	if s == nil {
+		return
+	}
+	if err := s.RegisterChecker(component, checker); err != nil {
+		logger.Warnf("failed registering health checker [%s]: [%s]", component, err)
+	}
+}
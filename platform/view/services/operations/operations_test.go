@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
+)
+
+type checker struct {
+	err error
+}
+
+func (c *checker) HealthCheck(context.Context) error {
+	return c.err
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestSystem(t *testing.T) {
+	s := operations.NewSystem(operations.Options{
+		ListenAddress:   "127.0.0.1:0",
+		MetricsProvider: operations.PrometheusProvider,
+	})
+	assert.NoError(t, s.Start())
+	defer s.Stop()
+	url := "http://" + s.Addr()
+
+	// health checks
+	c := &checker{}
+	assert.NoError(t, s.RegisterChecker("component", c))
+	assert.Error(t, s.RegisterChecker("component", c))
+	code, _ := get(t, url+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	c.err = errors.New("broken")
+	code, body := get(t, url+"/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "broken")
+
+	// version
+	code, body = get(t, url+"/version")
+	assert.Equal(t, http.StatusOK, code)
+	version := &operations.VersionInfoHandler{}
+	assert.NoError(t, json.Unmarshal([]byte(body), version))
+	assert.Equal(t, operations.Version, version.Version)
+
+	// metrics, created twice as by the nodes sharing the process
+	counterOpts := metrics.CounterOpts{Namespace: "test", Name: "counter", Help: "A test counter."}
+	s.NewCounter(counterOpts).Add(1)
+	s.NewCounter(counterOpts).Add(1)
+	code, body = get(t, url+"/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "test_counter 2")
+	assert.Contains(t, body, "fsc_version")
+
+	// log spec
+	code, body = get(t, url+"/logspec")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "spec")
+	req, err := http.NewRequest(http.MethodPut, url+"/logspec", strings.NewReader(`{"spec":"unknown-level"}`))
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	s := operations.NewSystem(operations.Options{
+		ListenAddress:   "127.0.0.1:0",
+		MetricsProvider: operations.DisabledProvider,
+	})
+	assert.NoError(t, s.Start())
+	defer s.Stop()
+
+	code, _ := get(t, "http://"+s.Addr()+"/metrics")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = get(t, "http://"+s.Addr()+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Version and CommitSHA identify the build of the node, they are set at link time with
+// -ldflags "-X github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations.Version=..."
+var (
+	Version   = "latest"
+	CommitSHA = "development build"
+)
+
+// VersionInfoHandler serves the version of the node
+type VersionInfoHandler struct {
+	CommitSHA string `json:"CommitSHA,omitempty"`
+	Version   string `json:"Version,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"Error"`
+}
+
+func (m *VersionInfoHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		m.sendResponse(resp, http.StatusOK, m)
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		m.sendResponse(resp, http.StatusBadRequest, err)
+	}
+}
+
+func (m *VersionInfoHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &errorResponse{Error: err.Error()}
+	}
+	js, err := json.Marshal(payload)
+	if err != nil {
+		logger.Errorf("failed to encode payload [%s]", err)
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	resp.Write(js)
+}
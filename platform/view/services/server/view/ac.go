@@ -23,9 +23,26 @@ type VerifierProvider interface {
 	GetVerifier(identity view.Identity) (view2.Verifier, error)
 }
 
+type CertValidator interface {
+	ValidatePEM(raw []byte) error
+}
+
+// AdminViews tells the factories whose views are reserved to the admins
+type AdminViews interface {
+	IsAdminView(fid string) bool
+}
+
+// AccessControlChecker accepts commands from the node's default identity and the admins.
+// If Policies is set, it also accepts commands from the identities satisfying the policy of the view they refer to.
+// The views that AdminViews reports as reserved to the admins are never granted by the policies.
+// If CertValidator is set, the certificate of the creator must be within its validity window and not revoked.
+// Denials are audited.
 type AccessControlChecker struct {
 	IdentityProvider IdentityProvider
 	VerifierProvider VerifierProvider
+	Policies         *ViewPolicies
+	AdminViews       AdminViews
+	CertValidator    CertValidator
 }
 
 func NewAccessControlChecker(identityProvider IdentityProvider, verifierProvider VerifierProvider) *AccessControlChecker {
@@ -33,33 +50,60 @@ func NewAccessControlChecker(identityProvider IdentityProvider, verifierProvider
 }
 
 func (a *AccessControlChecker) Check(sc *protos2.SignedCommand, c *protos2.Command) error {
-	// Is the creator recognized
+	// Is the creator allowed
+	if err := a.Authorize(c.Header.Creator, ViewID(c)); err != nil {
+		return err
+	}
+	return a.Authenticate(c.Header.Creator, sc.Command, sc.Signature)
+}
+
+// Authenticate checks that the passed signature of the passed message is valid for the passed creator.
+// If CertValidator is set, the certificate of the creator must also be valid.
+func (a *AccessControlChecker) Authenticate(creator view.Identity, message, signature []byte) error {
+	if a.CertValidator != nil {
+		if err := a.CertValidator.ValidatePEM(creator); err != nil {
+			auditLogger.Warnf("identity [%s] denied access: [%s]", creator, err)
+			return errors.WithMessagef(err, "invalid creator [%s]", creator)
+		}
+	}
+
+	verifier, err := a.VerifierProvider.GetVerifier(creator)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting verifier for [%s]", creator)
+	}
+
+	if err := verifier.Verify(message, signature); err != nil {
+		return errors.WithMessagef(err, "failed verifying signature from [%s]", creator)
+	}
+
+	return nil
+}
+
+// Authorize checks that the passed identity is allowed to initiate the views created by the passed factory.
+// An empty fid refers to commands that do not initiate a view, such as tracking and listing views,
+// these are reserved to the default identity and the admins.
+func (a *AccessControlChecker) Authorize(creator view.Identity, fid string) error {
+	// The default identity and the admins can initiate any view
 	validIdentities := []view.Identity{a.IdentityProvider.DefaultIdentity()}
 	admins := a.IdentityProvider.Admins()
 	if len(admins) != 0 {
 		validIdentities = append(validIdentities, admins...)
 	}
-
-	found := false
 	for _, identity := range validIdentities {
-		if identity.Equal(c.Header.Creator) {
-			found = true
-			break
+		if identity.Equal(creator) {
+			return nil
 		}
 	}
 
-	if !found {
-		return errors.Errorf("identity [%s] not recognized", view.Identity(c.Header.Creator))
-	}
-
-	verifier, err := a.VerifierProvider.GetVerifier(c.Header.Creator)
-	if err != nil {
-		return errors.WithMessagef(err, "failed getting verifier for [%s]", view.Identity(c.Header.Creator))
+	adminView := a.AdminViews != nil && a.AdminViews.IsAdminView(fid)
+	if len(fid) != 0 && !adminView && a.Policies != nil && a.Policies.Satisfied(creator, fid) {
+		auditLogger.Debugf("identity [%s] granted access to view [%s]", creator, fid)
+		return nil
 	}
 
-	if err := verifier.Verify(sc.Command, sc.Signature); err != nil {
-		return errors.WithMessagef(err, "failed verifying signature from [%s]", view.Identity(c.Header.Creator))
+	auditLogger.Warnf("identity [%s] denied access to view [%s]", creator, fid)
+	if len(fid) == 0 || adminView {
+		return errors.Errorf("identity [%s] not allowed to run admin command [%s]", creator, fid)
 	}
-
-	return nil
+	return errors.Errorf("identity [%s] not allowed to initiate view [%s]", creator, fid)
 }
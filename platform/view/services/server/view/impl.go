@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package view
 
 import (
+	"bytes"
 	"context"
 	"log"
 	"reflect"
 	"runtime/debug"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -19,6 +21,7 @@ import (
 )
 
 var logger = flogging.MustGetLogger("view-sdk.server")
+var auditLogger = flogging.MustGetLogger("view-sdk.server.audit")
 
 //go:generate counterfeiter -o mock/marshaler.go -fake-name Marshaler . Marshaler
 
@@ -33,8 +36,9 @@ type server struct {
 	Marshaler     Marshaler
 	PolicyChecker PolicyChecker
 
-	processors map[reflect.Type]Processor
-	streamers  map[reflect.Type]Streamer
+	processors    map[reflect.Type]Processor
+	streamers     map[reflect.Type]Streamer
+	bidiStreamers map[reflect.Type]BidiStreamer
 }
 
 func NewViewServiceServer(Marshaler Marshaler, PolicyChecker PolicyChecker) (*server, error) {
@@ -43,6 +47,7 @@ func NewViewServiceServer(Marshaler Marshaler, PolicyChecker PolicyChecker) (*se
 		PolicyChecker: PolicyChecker,
 		processors:    map[reflect.Type]Processor{},
 		streamers:     map[reflect.Type]Streamer{},
+		bidiStreamers: map[reflect.Type]BidiStreamer{},
 	}, nil
 }
 
@@ -134,6 +139,44 @@ func (s *server) StreamCommand(sc *protos2.SignedCommand, commandServer protos2.
 	return nil
 }
 
+func (s *server) StreamView(viewServer protos2.ViewService_StreamViewServer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("StreamView triggered panic: %s\n%s\n", r, debug.Stack())
+			err = errors.Errorf("StreamView triggered panic: %s", r)
+		}
+	}()
+
+	logger.Debugf("Stream View invoked...")
+
+	sc, err := viewServer.Recv()
+	if err != nil {
+		return errors.Wrap(err, "failed receiving first command")
+	}
+	stream := &commandStream{server: s, viewServer: viewServer, sc: sc}
+
+	command, err := s.checkCommand(sc)
+	if err != nil {
+		return stream.sendError(err)
+	}
+	stream.command = command
+
+	streamer, ok := s.bidiStreamers[reflect.TypeOf(command.GetPayload())]
+	switch ok {
+	case true:
+		logger.Debugf("got a bidi streamer for [%s], invoke it...", reflect.TypeOf(command.GetPayload()))
+		err = streamer(command, stream)
+	default:
+		err = errors.Errorf("bidi stream command type not recognized: %T", reflect.TypeOf(command.GetPayload()))
+	}
+	if err != nil {
+		logger.Errorf("bidi stream command execution failed with err [%s]", err)
+		return stream.sendError(err)
+	}
+	logger.Debugf("Stream View invoked successfully")
+	return nil
+}
+
 func (s *server) ValidateHeader(header *protos2.Header) error {
 	if header == nil {
 		return errors.New("command header is required")
@@ -166,6 +209,45 @@ func (s *server) RegisterStreamer(typ reflect.Type, streamer Streamer) {
 	s.streamers[typ] = streamer
 }
 
+func (s *server) RegisterBidiStreamer(typ reflect.Type, streamer BidiStreamer) {
+	s.bidiStreamers[typ] = streamer
+}
+
+// checkCommand unmarshals the passed signed command, and checks its header and access control
+func (s *server) checkCommand(sc *protos2.SignedCommand) (*protos2.Command, error) {
+	command, err := UnmarshalCommand(sc.Command)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateHeader(command.Header); err != nil {
+		return nil, err
+	}
+	if err := s.PolicyChecker.Check(sc, command); err != nil {
+		return nil, err
+	}
+	return command, nil
+}
+
+// checkStreamCommand checks a command received on the stream opened by the passed command.
+// The command must come from the creator of the stream and is authorized against the view the stream initiated.
+func (s *server) checkStreamCommand(first *protos2.Command, sc *protos2.SignedCommand) (*protos2.Command, error) {
+	command, err := UnmarshalCommand(sc.Command)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateHeader(command.Header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(command.Header.Creator, first.Header.Creator) {
+		return nil, errors.New("stream commands must come from the creator of the stream")
+	}
+	// the signature is verified on the received command, the policy on the command that opened the stream
+	if err := s.PolicyChecker.Check(sc, &protos2.Command{Header: command.Header, Payload: first.Payload}); err != nil {
+		return nil, err
+	}
+	return command, nil
+}
+
 func (s *server) streamError(err error, sc *protos2.SignedCommand, commandServer protos2.ViewService_StreamCommandServer) error {
 	r, err2 := s.MarshalErrorResponse(sc.Command, err)
 	if err2 != nil {
@@ -179,3 +261,48 @@ func (s *server) streamError(err error, sc *protos2.SignedCommand, commandServer
 	return err
 
 }
+
+// commandStream implements CommandStream on top of a StreamView grpc stream.
+// All responses are bound to the first command received on the stream.
+type commandStream struct {
+	server     *server
+	viewServer protos2.ViewService_StreamViewServer
+	sc         *protos2.SignedCommand
+	command    *protos2.Command
+
+	sendLock sync.Mutex
+}
+
+func (c *commandStream) Recv() (*protos2.Command, error) {
+	sc, err := c.viewServer.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return c.server.checkStreamCommand(c.command, sc)
+}
+
+func (c *commandStream) Send(responsePayload interface{}) error {
+	r, err := c.server.Marshaler.MarshalCommandResponse(c.sc.Command, responsePayload)
+	if err != nil {
+		return errors.WithMessage(err, "failed creating response")
+	}
+
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+	return c.viewServer.Send(r)
+}
+
+func (c *commandStream) Context() context.Context {
+	return c.viewServer.Context()
+}
+
+func (c *commandStream) sendError(err error) error {
+	err2 := c.Send(&protos2.CommandResponse_Err{
+		Err: &protos2.Error{Message: err.Error()},
+	})
+	if err2 != nil {
+		return errors.WithMessagef(err, "failed sending error response [%s]", err2)
+	}
+	logger.Errorf("bidi stream error occurred [%s]", err)
+	return err
+}
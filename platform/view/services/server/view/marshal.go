@@ -109,6 +109,10 @@ func commandResponseFromPayload(payload interface{}) (*protos2.CommandResponse,
 		return &protos2.CommandResponse{Payload: t}, nil
 	case *protos2.CommandResponse_IsTxFinalResponse:
 		return &protos2.CommandResponse{Payload: t}, nil
+	case *protos2.CommandResponse_StreamViewEvent:
+		return &protos2.CommandResponse{Payload: t}, nil
+	case *protos2.CommandResponse_ListViewsResponse:
+		return &protos2.CommandResponse{Payload: t}, nil
 	default:
 		return nil, errors.Errorf("command type not recognized: %T", t)
 	}
@@ -7,12 +7,234 @@ SPDX-License-Identifier: Apache-2.0
 package view
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
 	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+// AnyView is the view identifier matching all the views in a policy
+const AnyView = "*"
+
 type YesPolicyChecker struct {
 }
 
 func (y YesPolicyChecker) Check(sc *protos2.SignedCommand, c *protos2.Command) error {
 	return nil
 }
+
+type MSPConfig struct {
+	// ID is the MSP identifier
+	ID string `yaml:"id"`
+	// RootCerts are the paths of the root certificates of the MSP
+	RootCerts []string `yaml:"rootCerts"`
+	// IntermediateCerts are the paths of the intermediate certificates of the MSP
+	IntermediateCerts []string `yaml:"intermediateCerts,omitempty"`
+}
+
+type PrincipalConfig struct {
+	// Identity is the path of the X.509 certificate of the principal
+	Identity string `yaml:"identity,omitempty"`
+	// MSPID, if set, requires the principal to be issued by this MSP
+	MSPID string `yaml:"mspID,omitempty"`
+	// OU, if set, requires the principal to carry this organizational unit, e.g. client or admin
+	OU string `yaml:"ou,omitempty"`
+}
+
+type PolicyConfig struct {
+	// Views are the identifiers of the view factories the policy applies to, * matches all
+	Views []string `yaml:"views"`
+	// Principals are the principals allowed to initiate the views, any of them suffices
+	Principals []PrincipalConfig `yaml:"principals"`
+}
+
+type ACLConfig struct {
+	MSPs     []MSPConfig    `yaml:"msps,omitempty"`
+	Policies []PolicyConfig `yaml:"policies,omitempty"`
+}
+
+type ConfigProvider interface {
+	IsSet(key string) bool
+	UnmarshalKey(key string, rawVal interface{}) error
+	TranslatePath(path string) string
+}
+
+// principal matches the identities satisfying all its set fields, at least one of identity and msp is set
+type principal struct {
+	identity *x509.Certificate
+	msp      *x509.VerifyOptions
+	ou       string
+}
+
+func (p *principal) match(cert *x509.Certificate) bool {
+	if p.identity != nil && !p.identity.Equal(cert) {
+		return false
+	}
+	if p.msp != nil {
+		if _, err := cert.Verify(*p.msp); err != nil {
+			return false
+		}
+	}
+	if len(p.ou) != 0 {
+		found := false
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if ou == p.ou {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ViewPolicies binds view factory identifiers to the principals allowed to initiate them
+type ViewPolicies struct {
+	policies map[string][]*principal
+}
+
+// LoadViewPolicies loads the view policies from the fsc.view.acl configuration section.
+// It returns nil if no policy is configured.
+func LoadViewPolicies(config ConfigProvider) (*ViewPolicies, error) {
+	if !config.IsSet("fsc.view.acl") {
+		return nil, nil
+	}
+	conf := &ACLConfig{}
+	if err := config.UnmarshalKey("fsc.view.acl", conf); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling view acl")
+	}
+	if len(conf.Policies) == 0 {
+		return nil, nil
+	}
+
+	msps := map[string]*x509.VerifyOptions{}
+	for _, msp := range conf.MSPs {
+		opts := &x509.VerifyOptions{
+			Roots:         x509.NewCertPool(),
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		for _, path := range msp.RootCerts {
+			cert, err := loadCert(config.TranslatePath(path))
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed loading root cert of msp [%s]", msp.ID)
+			}
+			opts.Roots.AddCert(cert)
+		}
+		for _, path := range msp.IntermediateCerts {
+			cert, err := loadCert(config.TranslatePath(path))
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed loading intermediate cert of msp [%s]", msp.ID)
+			}
+			opts.Intermediates.AddCert(cert)
+		}
+		msps[msp.ID] = opts
+	}
+
+	vp := &ViewPolicies{policies: map[string][]*principal{}}
+	for _, policy := range conf.Policies {
+		var principals []*principal
+		for _, pc := range policy.Principals {
+			if len(pc.Identity) == 0 && len(pc.MSPID) == 0 {
+				return nil, errors.Errorf("principal for views %v must set an mspID or an identity", policy.Views)
+			}
+			p := &principal{ou: pc.OU}
+			if len(pc.Identity) != 0 {
+				cert, err := loadCert(config.TranslatePath(pc.Identity))
+				if err != nil {
+					return nil, errors.WithMessagef(err, "failed loading principal identity for views %v", policy.Views)
+				}
+				p.identity = cert
+			}
+			if len(pc.MSPID) != 0 {
+				opts, ok := msps[pc.MSPID]
+				if !ok {
+					return nil, errors.Errorf("msp [%s] referenced by the policy for views %v not found", pc.MSPID, policy.Views)
+				}
+				p.msp = opts
+			}
+			principals = append(principals, p)
+		}
+		for _, fid := range policy.Views {
+			vp.policies[fid] = append(vp.policies[fid], principals...)
+		}
+	}
+	logger.Infof("loaded view policies for [%d] views", len(vp.policies))
+	return vp, nil
+}
+
+// Covers returns true if a policy applies to the passed view factory identifier
+func (v *ViewPolicies) Covers(fid string) bool {
+	_, ok := v.principals(fid)
+	return ok
+}
+
+// Satisfied returns true if the passed identity is allowed to initiate the views created by the passed factory
+func (v *ViewPolicies) Satisfied(identity view.Identity, fid string) bool {
+	cert, err := certFromIdentity(identity)
+	if err != nil {
+		logger.Debugf("identity [%s] is not an x509 certificate [%s]", identity, err)
+		return false
+	}
+
+	principals, _ := v.principals(fid)
+	return matchAny(principals, cert)
+}
+
+func (v *ViewPolicies) principals(fid string) ([]*principal, bool) {
+	if principals, ok := v.policies[fid]; ok {
+		return principals, true
+	}
+	principals, ok := v.policies[AnyView]
+	return principals, ok
+}
+
+func matchAny(principals []*principal, cert *x509.Certificate) bool {
+	for _, p := range principals {
+		if p.match(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+func certFromIdentity(identity view.Identity) (*x509.Certificate, error) {
+	block, _ := pem.Decode(identity)
+	if block == nil {
+		return nil, errors.New("no pem content")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func loadCert(path string) (*x509.Certificate, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading [%s]", path)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.Errorf("no pem content in [%s]", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ViewID returns the identifier of the view factory the passed command refers to, empty if the
+// command does not initiate a view
+func ViewID(c *protos2.Command) string {
+	switch t := c.Payload.(type) {
+	case *protos2.Command_CallView:
+		return t.CallView.Fid
+	case *protos2.Command_InitiateView:
+		return t.InitiateView.Fid
+	case *protos2.Command_StreamView:
+		return t.StreamView.Fid
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type aclConfig struct {
+	conf *ACLConfig
+}
+
+func (a *aclConfig) IsSet(key string) bool { return true }
+
+func (a *aclConfig) UnmarshalKey(key string, rawVal interface{}) error {
+	*rawVal.(*ACLConfig) = *a.conf
+	return nil
+}
+
+func (a *aclConfig) TranslatePath(path string) string { return path }
+
+type identityProvider struct {
+	admin view.Identity
+}
+
+func (i *identityProvider) DefaultIdentity() view.Identity { return []byte("node") }
+
+func (i *identityProvider) Admins() []view.Identity { return []view.Identity{i.admin} }
+
+type adminViews map[string]bool
+
+func (a adminViews) IsAdminView(fid string) bool { return a[fid] }
+
+type certifier struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newCert(t *testing.T, issuer *certifier, ou string, isCA bool) (*certifier, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: ou, OrganizationalUnit: []string{ou}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	parent, parentKey := template, key
+	if issuer != nil {
+		parent, parentKey = issuer.cert, issuer.key
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	assert.NoError(t, err)
+	return &certifier{key: key, cert: cert}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})
+}
+
+func TestViewPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "view-policies")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, caPEM := newCert(t, nil, "ca", true)
+	_, client := newCert(t, ca, "client", false)
+	_, peer := newCert(t, ca, "peer", false)
+	_, outsider := newCert(t, nil, "client", false)
+	_, admin := newCert(t, nil, "admin", false)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca.pem"), caPEM, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "peer.pem"), peer, 0644))
+
+	policies, err := LoadViewPolicies(&aclConfig{conf: &ACLConfig{
+		MSPs: []MSPConfig{{ID: "Org1MSP", RootCerts: []string{filepath.Join(dir, "ca.pem")}}},
+		Policies: []PolicyConfig{
+			{Views: []string{"pay"}, Principals: []PrincipalConfig{{MSPID: "Org1MSP", OU: "client"}}},
+			{Views: []string{"audit"}, Principals: []PrincipalConfig{{Identity: filepath.Join(dir, "peer.pem")}}},
+		},
+	}})
+	assert.NoError(t, err)
+	ac := &AccessControlChecker{IdentityProvider: &identityProvider{admin: admin}, Policies: policies}
+
+	// clients of Org1MSP can pay but not audit
+	assert.NoError(t, ac.Authorize(client, "pay"))
+	assert.Error(t, ac.Authorize(client, "audit"))
+	// the same OU from another MSP is not enough
+	assert.Error(t, ac.Authorize(outsider, "pay"))
+	// a single identity can audit
+	assert.NoError(t, ac.Authorize(peer, "audit"))
+	assert.Error(t, ac.Authorize(peer, "pay"))
+	// views without policy are reserved to the node and the admins
+	assert.Error(t, ac.Authorize(client, "issue"))
+	assert.NoError(t, ac.Authorize(admin, "issue"))
+	assert.NoError(t, ac.Authorize([]byte("node"), "issue"))
+	// commands that do not initiate views are reserved to the node and the admins
+	assert.Error(t, ac.Authorize(client, ""))
+	assert.Error(t, ac.Authorize(outsider, ""))
+	assert.NoError(t, ac.Authorize(admin, ""))
+	assert.NoError(t, ac.Authorize([]byte("node"), ""))
+
+	// the admin views are never granted by the policies, not even by a wildcard
+	policies, err = LoadViewPolicies(&aclConfig{conf: &ACLConfig{
+		MSPs:     []MSPConfig{{ID: "Org1MSP", RootCerts: []string{filepath.Join(dir, "ca.pem")}}},
+		Policies: []PolicyConfig{{Views: []string{"*"}, Principals: []PrincipalConfig{{MSPID: "Org1MSP"}}}},
+	}})
+	assert.NoError(t, err)
+	ac = &AccessControlChecker{IdentityProvider: &identityProvider{admin: admin}, Policies: policies, AdminViews: adminViews{"wallet": true}}
+	assert.NoError(t, ac.Authorize(client, "pay"))
+	assert.Error(t, ac.Authorize(client, "wallet"))
+	assert.NoError(t, ac.Authorize(admin, "wallet"))
+	assert.NoError(t, ac.Authorize([]byte("node"), "wallet"))
+
+	_, err = LoadViewPolicies(&aclConfig{conf: &ACLConfig{
+		Policies: []PolicyConfig{{Views: []string{"pay"}, Principals: []PrincipalConfig{{MSPID: "Org2MSP"}}}},
+	}})
+	assert.Error(t, err)
+
+	// a principal must be bound to an msp or an identity, an OU alone matches any issuer
+	_, err = LoadViewPolicies(&aclConfig{conf: &ACLConfig{
+		Policies: []PolicyConfig{{Views: []string{"pay"}, Principals: []PrincipalConfig{{OU: "client"}}}},
+	}})
+	assert.Error(t, err)
+}
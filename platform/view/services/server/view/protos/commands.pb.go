@@ -274,6 +274,306 @@ func (m *TrackViewResponse) GetPayload() []byte {
 	return nil
 }
 
+// ListViews is used to list the status of the recently initiated views
+type ListViews struct {
+	// fid, if not empty, selects the views created by this factory
+	Fid string `protobuf:"bytes,1,opt,name=fid,proto3" json:"fid,omitempty"`
+	// statuses, if not empty, selects the views whose status is one of these
+	Statuses []int32 `protobuf:"varint,2,rep,packed,name=statuses,proto3" json:"statuses,omitempty"`
+	// since, if set, selects the views started at or after this time
+	Since *timestamp.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// limit, if positive, is the maximum number of returned statuses
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListViews) Reset()         { *m = ListViews{} }
+func (m *ListViews) String() string { return proto.CompactTextString(m) }
+func (*ListViews) ProtoMessage()    {}
+func (*ListViews) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0dff099eb2e3dfdb, []int{6}
+}
+
+func (m *ListViews) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListViews.Unmarshal(m, b)
+}
+func (m *ListViews) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListViews.Marshal(b, m, deterministic)
+}
+func (m *ListViews) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListViews.Merge(m, src)
+}
+func (m *ListViews) XXX_Size() int {
+	return xxx_messageInfo_ListViews.Size(m)
+}
+func (m *ListViews) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListViews.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListViews proto.InternalMessageInfo
+
+func (m *ListViews) GetFid() string {
+	if m != nil {
+		return m.Fid
+	}
+	return ""
+}
+
+func (m *ListViews) GetStatuses() []int32 {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+func (m *ListViews) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *ListViews) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListViewsResponse struct {
+	Payload              []byte   `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListViewsResponse) Reset()         { *m = ListViewsResponse{} }
+func (m *ListViewsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListViewsResponse) ProtoMessage()    {}
+func (*ListViewsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0dff099eb2e3dfdb, []int{7}
+}
+
+func (m *ListViewsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListViewsResponse.Unmarshal(m, b)
+}
+func (m *ListViewsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListViewsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListViewsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListViewsResponse.Merge(m, src)
+}
+func (m *ListViewsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListViewsResponse.Size(m)
+}
+func (m *ListViewsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListViewsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListViewsResponse proto.InternalMessageInfo
+
+func (m *ListViewsResponse) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// StreamView is used to initiate a view whose progress is streamed back to the client
+type StreamView struct {
+	Fid                  string   `protobuf:"bytes,1,opt,name=fid,proto3" json:"fid,omitempty"`
+	Input                []byte   `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamView) Reset()         { *m = StreamView{} }
+func (m *StreamView) String() string { return proto.CompactTextString(m) }
+func (*StreamView) ProtoMessage()    {}
+func (*StreamView) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0dff099eb2e3dfdb, []int{8}
+}
+
+func (m *StreamView) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamView.Unmarshal(m, b)
+}
+func (m *StreamView) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamView.Marshal(b, m, deterministic)
+}
+func (m *StreamView) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamView.Merge(m, src)
+}
+func (m *StreamView) XXX_Size() int {
+	return xxx_messageInfo_StreamView.Size(m)
+}
+func (m *StreamView) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamView.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamView proto.InternalMessageInfo
+
+func (m *StreamView) GetFid() string {
+	if m != nil {
+		return m.Fid
+	}
+	return ""
+}
+
+func (m *StreamView) GetInput() []byte {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+// StreamViewInput carries further input for a view initiated with StreamView
+type StreamViewInput struct {
+	Input                []byte   `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamViewInput) Reset()         { *m = StreamViewInput{} }
+func (m *StreamViewInput) String() string { return proto.CompactTextString(m) }
+func (*StreamViewInput) ProtoMessage()    {}
+func (*StreamViewInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0dff099eb2e3dfdb, []int{9}
+}
+
+func (m *StreamViewInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamViewInput.Unmarshal(m, b)
+}
+func (m *StreamViewInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamViewInput.Marshal(b, m, deterministic)
+}
+func (m *StreamViewInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamViewInput.Merge(m, src)
+}
+func (m *StreamViewInput) XXX_Size() int {
+	return xxx_messageInfo_StreamViewInput.Size(m)
+}
+func (m *StreamViewInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamViewInput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamViewInput proto.InternalMessageInfo
+
+func (m *StreamViewInput) GetInput() []byte {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+// StreamViewEvent reports the progress of a view initiated with StreamView.
+// The first event carries only the context identifier, the last one carries the view result.
+type StreamViewEvent struct {
+	Cid string `protobuf:"bytes,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//	*StreamViewEvent_Report
+	//	*StreamViewEvent_Output
+	//	*StreamViewEvent_Result
+	Event                isStreamViewEvent_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *StreamViewEvent) Reset()         { *m = StreamViewEvent{} }
+func (m *StreamViewEvent) String() string { return proto.CompactTextString(m) }
+func (*StreamViewEvent) ProtoMessage()    {}
+func (*StreamViewEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0dff099eb2e3dfdb, []int{10}
+}
+
+func (m *StreamViewEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamViewEvent.Unmarshal(m, b)
+}
+func (m *StreamViewEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamViewEvent.Marshal(b, m, deterministic)
+}
+func (m *StreamViewEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamViewEvent.Merge(m, src)
+}
+func (m *StreamViewEvent) XXX_Size() int {
+	return xxx_messageInfo_StreamViewEvent.Size(m)
+}
+func (m *StreamViewEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamViewEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamViewEvent proto.InternalMessageInfo
+
+func (m *StreamViewEvent) GetCid() string {
+	if m != nil {
+		return m.Cid
+	}
+	return ""
+}
+
+type isStreamViewEvent_Event interface {
+	isStreamViewEvent_Event()
+}
+
+type StreamViewEvent_Report struct {
+	Report string `protobuf:"bytes,2,opt,name=report,proto3,oneof"`
+}
+
+type StreamViewEvent_Output struct {
+	Output []byte `protobuf:"bytes,3,opt,name=output,proto3,oneof"`
+}
+
+type StreamViewEvent_Result struct {
+	Result []byte `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+}
+
+func (*StreamViewEvent_Report) isStreamViewEvent_Event() {}
+
+func (*StreamViewEvent_Output) isStreamViewEvent_Event() {}
+
+func (*StreamViewEvent_Result) isStreamViewEvent_Event() {}
+
+func (m *StreamViewEvent) GetEvent() isStreamViewEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *StreamViewEvent) GetReport() string {
+	if x, ok := m.GetEvent().(*StreamViewEvent_Report); ok {
+		return x.Report
+	}
+	return ""
+}
+
+func (m *StreamViewEvent) GetOutput() []byte {
+	if x, ok := m.GetEvent().(*StreamViewEvent_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (m *StreamViewEvent) GetResult() []byte {
+	if x, ok := m.GetEvent().(*StreamViewEvent_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*StreamViewEvent) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*StreamViewEvent_Report)(nil),
+		(*StreamViewEvent_Output)(nil),
+		(*StreamViewEvent_Result)(nil),
+	}
+}
+
 // Header is a generic replay prevention and identity message to include in a signed command
 type Header struct {
 	// Timestamp is the local time when the message was created
@@ -296,7 +596,7 @@ func (m *Header) Reset()         { *m = Header{} }
 func (m *Header) String() string { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()    {}
 func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{6}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{11}
 }
 
 func (m *Header) XXX_Unmarshal(b []byte) error {
@@ -356,6 +656,9 @@ type Command struct {
 	//	*Command_TrackView
 	//	*Command_CallView
 	//	*Command_IsTxFinal
+	//	*Command_StreamView
+	//	*Command_StreamViewInput
+	//	*Command_ListViews
 	Payload              isCommand_Payload `protobuf_oneof:"payload"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -366,7 +669,7 @@ func (m *Command) Reset()         { *m = Command{} }
 func (m *Command) String() string { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()    {}
 func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{7}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{12}
 }
 
 func (m *Command) XXX_Unmarshal(b []byte) error {
@@ -414,6 +717,18 @@ type Command_IsTxFinal struct {
 	IsTxFinal *IsTxFinal `protobuf:"bytes,5,opt,name=isTxFinal,proto3,oneof"`
 }
 
+type Command_StreamView struct {
+	StreamView *StreamView `protobuf:"bytes,6,opt,name=streamView,proto3,oneof"`
+}
+
+type Command_StreamViewInput struct {
+	StreamViewInput *StreamViewInput `protobuf:"bytes,7,opt,name=streamViewInput,proto3,oneof"`
+}
+
+type Command_ListViews struct {
+	ListViews *ListViews `protobuf:"bytes,8,opt,name=listViews,proto3,oneof"`
+}
+
 func (*Command_InitiateView) isCommand_Payload() {}
 
 func (*Command_TrackView) isCommand_Payload() {}
@@ -422,6 +737,12 @@ func (*Command_CallView) isCommand_Payload() {}
 
 func (*Command_IsTxFinal) isCommand_Payload() {}
 
+func (*Command_StreamView) isCommand_Payload() {}
+
+func (*Command_StreamViewInput) isCommand_Payload() {}
+
+func (*Command_ListViews) isCommand_Payload() {}
+
 func (m *Command) GetPayload() isCommand_Payload {
 	if m != nil {
 		return m.Payload
@@ -457,6 +778,27 @@ func (m *Command) GetIsTxFinal() *IsTxFinal {
 	return nil
 }
 
+func (m *Command) GetStreamView() *StreamView {
+	if x, ok := m.GetPayload().(*Command_StreamView); ok {
+		return x.StreamView
+	}
+	return nil
+}
+
+func (m *Command) GetStreamViewInput() *StreamViewInput {
+	if x, ok := m.GetPayload().(*Command_StreamViewInput); ok {
+		return x.StreamViewInput
+	}
+	return nil
+}
+
+func (m *Command) GetListViews() *ListViews {
+	if x, ok := m.GetPayload().(*Command_ListViews); ok {
+		return x.ListViews
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Command) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -464,6 +806,9 @@ func (*Command) XXX_OneofWrappers() []interface{} {
 		(*Command_TrackView)(nil),
 		(*Command_CallView)(nil),
 		(*Command_IsTxFinal)(nil),
+		(*Command_StreamView)(nil),
+		(*Command_StreamViewInput)(nil),
+		(*Command_ListViews)(nil),
 	}
 }
 
@@ -482,7 +827,7 @@ func (m *SignedCommand) Reset()         { *m = SignedCommand{} }
 func (m *SignedCommand) String() string { return proto.CompactTextString(m) }
 func (*SignedCommand) ProtoMessage()    {}
 func (*SignedCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{8}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{13}
 }
 
 func (m *SignedCommand) XXX_Unmarshal(b []byte) error {
@@ -537,7 +882,7 @@ func (m *CommandResponseHeader) Reset()         { *m = CommandResponseHeader{} }
 func (m *CommandResponseHeader) String() string { return proto.CompactTextString(m) }
 func (*CommandResponseHeader) ProtoMessage()    {}
 func (*CommandResponseHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{9}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{14}
 }
 
 func (m *CommandResponseHeader) XXX_Unmarshal(b []byte) error {
@@ -594,7 +939,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{10}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{15}
 }
 
 func (m *Error) XXX_Unmarshal(b []byte) error {
@@ -641,6 +986,8 @@ type CommandResponse struct {
 	//	*CommandResponse_TrackViewResponse
 	//	*CommandResponse_CallViewResponse
 	//	*CommandResponse_IsTxFinalResponse
+	//	*CommandResponse_StreamViewEvent
+	//	*CommandResponse_ListViewsResponse
 	Payload              isCommandResponse_Payload `protobuf_oneof:"payload"`
 	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
 	XXX_unrecognized     []byte                    `json:"-"`
@@ -651,7 +998,7 @@ func (m *CommandResponse) Reset()         { *m = CommandResponse{} }
 func (m *CommandResponse) String() string { return proto.CompactTextString(m) }
 func (*CommandResponse) ProtoMessage()    {}
 func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{11}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{16}
 }
 
 func (m *CommandResponse) XXX_Unmarshal(b []byte) error {
@@ -703,6 +1050,14 @@ type CommandResponse_IsTxFinalResponse struct {
 	IsTxFinalResponse *IsTxFinalResponse `protobuf:"bytes,6,opt,name=isTxFinalResponse,proto3,oneof"`
 }
 
+type CommandResponse_StreamViewEvent struct {
+	StreamViewEvent *StreamViewEvent `protobuf:"bytes,7,opt,name=streamViewEvent,proto3,oneof"`
+}
+
+type CommandResponse_ListViewsResponse struct {
+	ListViewsResponse *ListViewsResponse `protobuf:"bytes,8,opt,name=listViewsResponse,proto3,oneof"`
+}
+
 func (*CommandResponse_Err) isCommandResponse_Payload() {}
 
 func (*CommandResponse_InitiateViewResponse) isCommandResponse_Payload() {}
@@ -713,6 +1068,10 @@ func (*CommandResponse_CallViewResponse) isCommandResponse_Payload() {}
 
 func (*CommandResponse_IsTxFinalResponse) isCommandResponse_Payload() {}
 
+func (*CommandResponse_StreamViewEvent) isCommandResponse_Payload() {}
+
+func (*CommandResponse_ListViewsResponse) isCommandResponse_Payload() {}
+
 func (m *CommandResponse) GetPayload() isCommandResponse_Payload {
 	if m != nil {
 		return m.Payload
@@ -755,6 +1114,20 @@ func (m *CommandResponse) GetIsTxFinalResponse() *IsTxFinalResponse {
 	return nil
 }
 
+func (m *CommandResponse) GetStreamViewEvent() *StreamViewEvent {
+	if x, ok := m.GetPayload().(*CommandResponse_StreamViewEvent); ok {
+		return x.StreamViewEvent
+	}
+	return nil
+}
+
+func (m *CommandResponse) GetListViewsResponse() *ListViewsResponse {
+	if x, ok := m.GetPayload().(*CommandResponse_ListViewsResponse); ok {
+		return x.ListViewsResponse
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*CommandResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -763,6 +1136,8 @@ func (*CommandResponse) XXX_OneofWrappers() []interface{} {
 		(*CommandResponse_TrackViewResponse)(nil),
 		(*CommandResponse_CallViewResponse)(nil),
 		(*CommandResponse_IsTxFinalResponse)(nil),
+		(*CommandResponse_StreamViewEvent)(nil),
+		(*CommandResponse_ListViewsResponse)(nil),
 	}
 }
 
@@ -781,7 +1156,7 @@ func (m *SignedCommandResponse) Reset()         { *m = SignedCommandResponse{} }
 func (m *SignedCommandResponse) String() string { return proto.CompactTextString(m) }
 func (*SignedCommandResponse) ProtoMessage()    {}
 func (*SignedCommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0dff099eb2e3dfdb, []int{12}
+	return fileDescriptor_0dff099eb2e3dfdb, []int{17}
 }
 
 func (m *SignedCommandResponse) XXX_Unmarshal(b []byte) error {
@@ -823,6 +1198,11 @@ func init() {
 	proto.RegisterType((*CallViewResponse)(nil), "protos.CallViewResponse")
 	proto.RegisterType((*TrackView)(nil), "protos.TrackView")
 	proto.RegisterType((*TrackViewResponse)(nil), "protos.TrackViewResponse")
+	proto.RegisterType((*ListViews)(nil), "protos.ListViews")
+	proto.RegisterType((*ListViewsResponse)(nil), "protos.ListViewsResponse")
+	proto.RegisterType((*StreamView)(nil), "protos.StreamView")
+	proto.RegisterType((*StreamViewInput)(nil), "protos.StreamViewInput")
+	proto.RegisterType((*StreamViewEvent)(nil), "protos.StreamViewEvent")
 	proto.RegisterType((*Header)(nil), "protos.Header")
 	proto.RegisterType((*Command)(nil), "protos.Command")
 	proto.RegisterType((*SignedCommand)(nil), "protos.SignedCommand")
@@ -835,45 +1215,58 @@ func init() {
 func init() { proto.RegisterFile("commands.proto", fileDescriptor_0dff099eb2e3dfdb) }
 
 var fileDescriptor_0dff099eb2e3dfdb = []byte{
-	// 629 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe3, 0xe6, 0x69, 0xda, 0x8c, 0xd3, 0x3e, 0xe9, 0x2a, 0x45, 0x26, 0x6a, 0x45, 0xeb,
-	0x03, 0xaa, 0x90, 0x70, 0x45, 0x10, 0x08, 0xc1, 0xad, 0x15, 0xc5, 0x39, 0xb2, 0x54, 0x1c, 0xb8,
-	0x54, 0x5b, 0x7b, 0x9b, 0xac, 0x70, 0xec, 0x68, 0x77, 0x23, 0xe8, 0x8d, 0x4f, 0xd0, 0x23, 0x9f,
-	0x81, 0x8f, 0x89, 0xf6, 0x35, 0x8e, 0x13, 0x21, 0x21, 0x4e, 0xd9, 0xf1, 0xce, 0xcc, 0x7f, 0x5e,
-	0x7e, 0xb1, 0x61, 0x3f, 0xab, 0x66, 0x33, 0x52, 0xe6, 0x22, 0x99, 0xf3, 0x4a, 0x56, 0xa8, 0xa3,
-	0x7f, 0xc4, 0xf0, 0xc9, 0xa4, 0xaa, 0x26, 0x05, 0x3d, 0xd7, 0xe6, 0xed, 0xe2, 0xee, 0x5c, 0xb2,
-	0x19, 0x15, 0x92, 0xcc, 0xe6, 0xc6, 0x71, 0xb8, 0x7f, 0xc7, 0x4a, 0x52, 0x30, 0x79, 0x6f, 0xec,
-	0xf8, 0x35, 0xf4, 0xc6, 0x25, 0x93, 0x8c, 0x48, 0xfa, 0x99, 0xd1, 0x6f, 0xa8, 0x0f, 0xed, 0x3b,
-	0x96, 0x47, 0xc1, 0x49, 0x70, 0xd6, 0xc5, 0xea, 0x88, 0x06, 0xb0, 0xcd, 0xca, 0xf9, 0x42, 0x46,
-	0x5b, 0x27, 0xc1, 0x59, 0x0f, 0x1b, 0x23, 0x3e, 0x83, 0x41, 0x3d, 0x0e, 0x53, 0x31, 0xaf, 0x4a,
-	0x41, 0x55, 0x7c, 0xb6, 0x8c, 0xcf, 0x58, 0x1e, 0x8f, 0x60, 0xf7, 0x92, 0x14, 0xc5, 0x5f, 0x65,
-	0x7f, 0x06, 0x7d, 0x17, 0xe3, 0x33, 0x3f, 0x82, 0x0e, 0xa7, 0x62, 0x51, 0x48, 0x1d, 0xde, 0xc3,
-	0xd6, 0x8a, 0x8f, 0xa1, 0x7b, 0xcd, 0x49, 0xf6, 0xd5, 0x09, 0x34, 0xe4, 0x9f, 0xc3, 0x81, 0xbf,
-	0xf6, 0xb9, 0x22, 0xd8, 0x99, 0x93, 0xfb, 0xa2, 0x22, 0xb9, 0x4d, 0xe6, 0xcc, 0xf8, 0x67, 0x00,
-	0x9d, 0x94, 0x92, 0x9c, 0x72, 0xf4, 0x06, 0xba, 0x7e, 0x7a, 0xda, 0x2d, 0x1c, 0x0d, 0x13, 0x33,
-	0xdf, 0xc4, 0xcd, 0x37, 0xb9, 0x76, 0x1e, 0x78, 0xe9, 0xac, 0x9a, 0x2a, 0xab, 0x32, 0xa3, 0x51,
-	0xdb, 0x34, 0xa5, 0x0d, 0x25, 0x9a, 0x71, 0x4a, 0x64, 0xc5, 0xa3, 0xff, 0x8c, 0xa8, 0x35, 0x51,
-	0x0c, 0x7b, 0xb2, 0x10, 0x37, 0x19, 0xe5, 0xf2, 0x66, 0x4a, 0xc4, 0x34, 0xda, 0xd6, 0xf7, 0xa1,
-	0x2c, 0xc4, 0x25, 0xe5, 0x32, 0x25, 0x62, 0x1a, 0x3f, 0x6c, 0xc1, 0xce, 0xa5, 0x59, 0x3a, 0x7a,
-	0x0a, 0x9d, 0xa9, 0xae, 0xd1, 0x96, 0xb5, 0x6f, 0xea, 0x11, 0x89, 0xa9, 0x1c, 0xdb, 0x5b, 0xf4,
-	0x16, 0x7a, 0xac, 0xb6, 0x24, 0x3d, 0xe3, 0x70, 0x34, 0x70, 0xde, 0xf5, 0x05, 0xa6, 0x2d, 0xbc,
-	0xe2, 0x8b, 0x5e, 0x40, 0x57, 0xba, 0xb9, 0xe9, 0x3e, 0xc2, 0xd1, 0x81, 0x0b, 0xf4, 0x03, 0x4d,
-	0x5b, 0x78, 0xe9, 0x85, 0x12, 0xd8, 0xcd, 0xec, 0xd6, 0x74, 0x87, 0xe1, 0xa8, 0xef, 0x22, 0xdc,
-	0x36, 0xd3, 0x16, 0xf6, 0x3e, 0x4a, 0x82, 0x89, 0xeb, 0xef, 0x57, 0x8a, 0x48, 0xdd, 0x72, 0x4d,
-	0x62, 0xec, 0x2e, 0x94, 0x84, 0xf7, 0xba, 0xe8, 0xfa, 0xc5, 0xc5, 0x1f, 0x60, 0xef, 0x13, 0x9b,
-	0x94, 0x34, 0x77, 0x53, 0x51, 0xf3, 0x35, 0x47, 0xb7, 0x54, 0x6b, 0xa2, 0x23, 0xe8, 0x0a, 0x36,
-	0x29, 0x89, 0x5c, 0x70, 0x6a, 0x41, 0x5b, 0x3e, 0x88, 0x1f, 0x02, 0x38, 0xb4, 0x39, 0x1c, 0x20,
-	0xff, 0x4c, 0xc0, 0x29, 0xf4, 0xac, 0xb8, 0x59, 0xa8, 0x11, 0x0d, 0xed, 0x33, 0xb5, 0xd0, 0x3a,
-	0x0e, 0xed, 0x15, 0x1c, 0xe2, 0x77, 0xb0, 0xfd, 0x9e, 0xf3, 0x8a, 0x2b, 0x97, 0x19, 0x15, 0x82,
-	0x4c, 0xa8, 0x25, 0xda, 0x99, 0x75, 0x80, 0xb7, 0x56, 0x01, 0xfe, 0xd5, 0x86, 0xff, 0x1b, 0xdd,
-	0xa0, 0x57, 0x0d, 0x5e, 0x8e, 0xfd, 0x5a, 0x36, 0xb5, 0xed, 0xf1, 0x39, 0x85, 0x36, 0xe5, 0xdc,
-	0x52, 0xb3, 0xe7, 0x62, 0x74, 0x69, 0x69, 0x0b, 0xab, 0x3b, 0x84, 0x61, 0xc0, 0x36, 0xbc, 0x06,
-	0x2c, 0x30, 0x47, 0x9b, 0x48, 0xf3, 0x62, 0x2d, 0xbc, 0x31, 0x16, 0x8d, 0xe1, 0x40, 0x36, 0xff,
-	0xb1, 0x96, 0xa7, 0xc7, 0x6b, 0x04, 0xd6, 0xb2, 0xad, 0x47, 0xa1, 0x2b, 0xe8, 0x67, 0x8d, 0xf7,
-	0x88, 0x05, 0x2d, 0x6a, 0x92, 0x59, 0x4b, 0xb4, 0x16, 0xa3, 0x4a, 0xf2, 0x0c, 0xfa, 0x44, 0x9d,
-	0xd5, 0x92, 0xc6, 0x4d, 0x07, 0x55, 0xd2, 0x5a, 0x54, 0x9d, 0xe0, 0x8f, 0x70, 0xb8, 0x42, 0xb0,
-	0x97, 0x1b, 0xc2, 0x2e, 0x77, 0x2a, 0x06, 0x65, 0x6f, 0xff, 0x99, 0xe5, 0x8b, 0xf0, 0x8b, 0xfd,
-	0x12, 0xfc, 0x08, 0x82, 0x5b, 0x73, 0x7c, 0xf9, 0x3b, 0x00, 0x00, 0xff, 0xff, 0xf2, 0x52, 0xf8,
-	0x32, 0x2d, 0x06, 0x00, 0x00,
+	// 839 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x4d, 0x8f, 0xe3, 0x44,
+	0x10, 0xb5, 0x27, 0xe3, 0x24, 0xae, 0x64, 0x66, 0x32, 0xad, 0x2c, 0x98, 0x68, 0x57, 0x64, 0x7d,
+	0x80, 0x08, 0x89, 0x2c, 0x84, 0x05, 0x21, 0xb8, 0x4d, 0xb4, 0x8b, 0x23, 0x71, 0xa1, 0x77, 0xc4,
+	0x81, 0xcb, 0xaa, 0xd7, 0xe9, 0x49, 0x5a, 0x38, 0x76, 0xd4, 0xdd, 0x01, 0x86, 0x13, 0xfc, 0x01,
+	0x8e, 0xfc, 0x16, 0x0e, 0xfc, 0x38, 0xd4, 0xee, 0x8f, 0xf8, 0x23, 0x7c, 0x8c, 0x38, 0xc5, 0xe5,
+	0xaa, 0x57, 0xf5, 0xba, 0xfb, 0xbd, 0x76, 0xe0, 0x32, 0x2d, 0x76, 0x3b, 0x92, 0xaf, 0xc5, 0x7c,
+	0xcf, 0x0b, 0x59, 0xa0, 0x6e, 0xf9, 0x23, 0x26, 0xef, 0x6e, 0x8a, 0x62, 0x93, 0xd1, 0x67, 0x65,
+	0xf8, 0xe6, 0x70, 0xf7, 0x4c, 0xb2, 0x1d, 0x15, 0x92, 0xec, 0xf6, 0xba, 0x70, 0x72, 0x79, 0xc7,
+	0x72, 0x92, 0x31, 0x79, 0xaf, 0xe3, 0xf8, 0x33, 0x18, 0xae, 0x72, 0x26, 0x19, 0x91, 0xf4, 0x5b,
+	0x46, 0x7f, 0x44, 0x23, 0xe8, 0xdc, 0xb1, 0x75, 0xe4, 0x4f, 0xfd, 0x59, 0x88, 0xd5, 0x23, 0x1a,
+	0x43, 0xc0, 0xf2, 0xfd, 0x41, 0x46, 0x67, 0x53, 0x7f, 0x36, 0xc4, 0x3a, 0x88, 0x67, 0x30, 0xae,
+	0xe2, 0x30, 0x15, 0xfb, 0x22, 0x17, 0x54, 0xe1, 0xd3, 0x23, 0x3e, 0x65, 0xeb, 0x78, 0x01, 0xfd,
+	0x25, 0xc9, 0xb2, 0x07, 0x75, 0xff, 0x00, 0x46, 0x16, 0xe3, 0x3a, 0xbf, 0x05, 0x5d, 0x4e, 0xc5,
+	0x21, 0x93, 0x25, 0x7c, 0x88, 0x4d, 0x14, 0x3f, 0x81, 0xf0, 0x96, 0x93, 0xf4, 0x7b, 0x3b, 0xa0,
+	0x31, 0xfe, 0x43, 0xb8, 0x76, 0x69, 0xd7, 0x2b, 0x82, 0xde, 0x9e, 0xdc, 0x67, 0x05, 0x59, 0x9b,
+	0x66, 0x36, 0x8c, 0x7f, 0xf5, 0x21, 0xfc, 0x9a, 0x09, 0xa9, 0xca, 0xc5, 0x09, 0xbe, 0x13, 0xe8,
+	0x0b, 0x49, 0xe4, 0x41, 0x50, 0x11, 0x9d, 0x4d, 0x3b, 0xb3, 0x00, 0xbb, 0x18, 0x7d, 0x04, 0x81,
+	0x60, 0x79, 0x4a, 0xa3, 0xce, 0xd4, 0x9f, 0x0d, 0x16, 0x93, 0xb9, 0x3e, 0x8c, 0xb9, 0x3d, 0x8c,
+	0xf9, 0xad, 0x3d, 0x0c, 0xac, 0x0b, 0xd5, 0xea, 0x33, 0xb6, 0x63, 0x32, 0x3a, 0x9f, 0xfa, 0xb3,
+	0x00, 0xeb, 0x40, 0x51, 0x76, 0x14, 0xfe, 0x03, 0xe5, 0xe7, 0x00, 0xaf, 0x24, 0xa7, 0x64, 0xf7,
+	0xa0, 0x2d, 0x7e, 0x1f, 0xae, 0x8e, 0xa8, 0x95, 0x7a, 0x75, 0x2c, 0xf4, 0xab, 0x85, 0x3f, 0x57,
+	0x0b, 0x5f, 0xfc, 0x40, 0x73, 0xd9, 0xde, 0x65, 0x14, 0xa9, 0xc3, 0xd9, 0x17, 0x5c, 0x0f, 0x09,
+	0x13, 0x0f, 0x9b, 0x58, 0x65, 0x8a, 0x83, 0x54, 0x5d, 0xd5, 0xae, 0x0c, 0x55, 0x46, 0xc7, 0x1a,
+	0x53, 0x1e, 0xe8, 0xb9, 0xcd, 0xe8, 0xf8, 0xa6, 0x07, 0x01, 0x55, 0x83, 0xe2, 0xdf, 0x7d, 0xe8,
+	0x26, 0x94, 0xac, 0x29, 0x47, 0x9f, 0x43, 0xe8, 0xb4, 0x1c, 0xf9, 0xff, 0xba, 0xc1, 0xc7, 0x62,
+	0xb5, 0xac, 0xbc, 0xb0, 0xc7, 0x32, 0xc4, 0x3a, 0x50, 0xfb, 0x99, 0x72, 0x4a, 0x64, 0xc1, 0xf5,
+	0x78, 0x6c, 0x43, 0x14, 0xc3, 0x85, 0xcc, 0xc4, 0xeb, 0x94, 0x72, 0xf9, 0x7a, 0x4b, 0xc4, 0x36,
+	0x0a, 0xca, 0xfc, 0x40, 0x66, 0x62, 0x49, 0xb9, 0x4c, 0x88, 0xd8, 0xc6, 0x7f, 0x76, 0xa0, 0xb7,
+	0xd4, 0x16, 0x44, 0xef, 0x41, 0x77, 0x5b, 0x72, 0x34, 0xb4, 0x2e, 0x35, 0x1f, 0x31, 0xd7, 0xcc,
+	0xb1, 0xc9, 0xa2, 0x2f, 0x60, 0xc8, 0x2a, 0x96, 0x29, 0x77, 0x6a, 0xb0, 0x18, 0xdb, 0xea, 0xaa,
+	0x9d, 0x12, 0x0f, 0xd7, 0x6a, 0xd1, 0xc7, 0x10, 0x4a, 0xab, 0x62, 0x23, 0xaf, 0x6b, 0x0b, 0x74,
+	0xf2, 0x4e, 0x3c, 0x7c, 0xac, 0x42, 0x73, 0xe8, 0xa7, 0xc6, 0x43, 0xe5, 0x0a, 0x07, 0x8b, 0x91,
+	0x45, 0x58, 0x6f, 0x25, 0x1e, 0x76, 0x35, 0x6a, 0x04, 0x13, 0xb7, 0x3f, 0xbd, 0x54, 0xf7, 0x43,
+	0x14, 0xd4, 0x47, 0xac, 0x6c, 0x42, 0x8d, 0x70, 0x55, 0xe8, 0x39, 0x80, 0x70, 0xd2, 0x88, 0xba,
+	0x25, 0x06, 0x59, 0xcc, 0x51, 0x34, 0x89, 0x87, 0x2b, 0x75, 0x68, 0x09, 0x57, 0xa2, 0xae, 0xbc,
+	0xa8, 0x57, 0x42, 0xdf, 0x6e, 0x43, 0xcb, 0x74, 0xe2, 0xe1, 0x26, 0x42, 0xb1, 0xcd, 0xac, 0x47,
+	0xa2, 0x7e, 0x9d, 0xad, 0x33, 0x8f, 0x62, 0xeb, 0xaa, 0x6e, 0x42, 0xe7, 0xa0, 0xf8, 0x2b, 0xb8,
+	0x78, 0xc5, 0x36, 0x39, 0x5d, 0xdb, 0x33, 0x54, 0x6a, 0xd0, 0x8f, 0xd6, 0x5d, 0x26, 0x44, 0x8f,
+	0x21, 0x14, 0x6c, 0x93, 0x13, 0x79, 0xe0, 0xd4, 0x38, 0xe8, 0xf8, 0x22, 0xfe, 0xcd, 0x87, 0x47,
+	0xa6, 0x87, 0x75, 0xea, 0xff, 0xd6, 0xeb, 0x53, 0x18, 0x9a, 0xe1, 0x5a, 0x7e, 0x7a, 0xe8, 0xc0,
+	0xbc, 0x53, 0xf2, 0xab, 0x8a, 0xb7, 0x53, 0x13, 0x6f, 0xfc, 0x25, 0x04, 0x2f, 0x38, 0x2f, 0xb8,
+	0x2a, 0xd9, 0x51, 0x21, 0xc8, 0x86, 0x1a, 0x9f, 0xda, 0xb0, 0x7a, 0x93, 0x9c, 0xd5, 0x6f, 0x92,
+	0x3f, 0xce, 0xe1, 0xaa, 0xb1, 0x1a, 0xf4, 0x69, 0x43, 0xdd, 0x4f, 0x9c, 0x88, 0x4e, 0x2d, 0xdb,
+	0x89, 0xfd, 0x29, 0x74, 0x28, 0xe7, 0x46, 0xe3, 0x17, 0x16, 0x53, 0x52, 0x4b, 0x3c, 0xac, 0x72,
+	0x08, 0xc3, 0x98, 0x9d, 0xf8, 0x84, 0x18, 0x79, 0x3f, 0x3e, 0xe5, 0x0b, 0x37, 0xcc, 0xc3, 0x27,
+	0xb1, 0x68, 0x05, 0xd7, 0xb2, 0x79, 0xdb, 0x1b, 0xf5, 0xbf, 0xd3, 0xf2, 0x4b, 0xa5, 0x5b, 0x1b,
+	0x85, 0x5e, 0xc2, 0x28, 0x6d, 0x7c, 0x83, 0x8c, 0x2d, 0xa2, 0xa6, 0x8f, 0x2a, 0x8d, 0x5a, 0x18,
+	0x45, 0xc9, 0x39, 0xc6, 0x35, 0xea, 0xd6, 0x29, 0xad, 0x9a, 0x05, 0x8a, 0x52, 0x0b, 0x55, 0x77,
+	0x4e, 0x79, 0x15, 0xff, 0xbd, 0x73, 0xca, 0x74, 0xdd, 0x39, 0xe5, 0x2b, 0xc5, 0x27, 0x6b, 0x7e,
+	0x5d, 0xa2, 0x7e, 0x9d, 0x4f, 0xeb, 0xf3, 0xa3, 0xf8, 0xb4, 0x50, 0x55, 0x47, 0x7d, 0x03, 0x8f,
+	0x6a, 0x8e, 0x72, 0x9c, 0x27, 0xd0, 0xe7, 0x76, 0x8a, 0xb6, 0x96, 0x8b, 0xff, 0xd9, 0x5b, 0x37,
+	0x83, 0xef, 0xcc, 0xbf, 0x9a, 0x5f, 0x7c, 0xff, 0x8d, 0x7e, 0xfc, 0xe4, 0xaf, 0x01, 0x00, 0xee,
+	0x51, 0xfe, 0x82, 0xf9, 0x08, 0x00, 0x00,
 }
@@ -28,16 +28,17 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 func init() { proto.RegisterFile("service.proto", fileDescriptor_a0b84a42fa06f626) }
 
 var fileDescriptor_a0b84a42fa06f626 = []byte{
-	// 136 bytes of a gzipped FileDescriptorProto
+	// 148 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x4e, 0x2d, 0x2a,
 	0xcb, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x03, 0x53, 0xc5, 0x52, 0x7c,
-	0xc9, 0xf9, 0xb9, 0xb9, 0x89, 0x79, 0x29, 0xc5, 0x10, 0x71, 0xa3, 0x05, 0x8c, 0x5c, 0xdc, 0x61,
+	0xc9, 0xf9, 0xb9, 0xb9, 0x89, 0x79, 0x29, 0xc5, 0x10, 0x71, 0xa3, 0x57, 0x8c, 0x5c, 0xdc, 0x61,
 	0x99, 0xa9, 0xe5, 0xc1, 0x10, 0xd5, 0x42, 0x6e, 0x5c, 0x7c, 0x01, 0x45, 0xf9, 0xc9, 0xa9, 0xc5,
 	0xc5, 0xce, 0x10, 0x85, 0x42, 0xa2, 0x10, 0x95, 0xc5, 0x7a, 0xc1, 0x99, 0xe9, 0x79, 0xa9, 0x29,
 	0x50, 0x61, 0x29, 0x59, 0xac, 0xc2, 0x41, 0xa9, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x42, 0x9e,
-	0x5c, 0xbc, 0xc1, 0x25, 0x45, 0xa9, 0x89, 0xb9, 0x14, 0x19, 0xa3, 0xc4, 0x60, 0xc0, 0xe8, 0xc4,
-	0x1d, 0x05, 0x75, 0x7c, 0x03, 0x23, 0x63, 0x12, 0x84, 0x69, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff,
-	0x9c, 0x13, 0xf1, 0xec, 0xdf, 0x00, 0x00, 0x00,
+	0x5c, 0xbc, 0xc1, 0x25, 0x45, 0xa9, 0x89, 0xb9, 0x14, 0x19, 0xa3, 0xc4, 0x60, 0xc0, 0x28, 0xe4,
+	0xc1, 0xc5, 0x05, 0x31, 0x0a, 0xe4, 0x4e, 0x72, 0xcd, 0xd1, 0x60, 0x34, 0x60, 0x74, 0xe2, 0x8e,
+	0x82, 0x06, 0x43, 0x03, 0x23, 0x63, 0x12, 0x84, 0x69, 0x0c, 0x18, 0x00, 0xab, 0xe6, 0x77, 0xcd,
+	0x29, 0x01, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -58,6 +59,10 @@ type ViewServiceClient interface {
 	// reports the reason of the failure.
 	ProcessCommand(ctx context.Context, in *SignedCommand, opts ...grpc.CallOption) (*SignedCommandResponse, error)
 	StreamCommand(ctx context.Context, in *SignedCommand, opts ...grpc.CallOption) (ViewService_StreamCommandClient, error)
+	// StreamView initiates the view described by the first command received on the stream, that must be
+	// a StreamView command. Further commands on the stream must be StreamViewInput commands whose input is
+	// delivered to the running view. The server streams back the progress of the view as StreamViewEvent responses.
+	StreamView(ctx context.Context, opts ...grpc.CallOption) (ViewService_StreamViewClient, error)
 }
 
 type viewServiceClient struct {
@@ -109,6 +114,37 @@ func (x *viewServiceStreamCommandClient) Recv() (*SignedCommandResponse, error)
 	return m, nil
 }
 
+func (c *viewServiceClient) StreamView(ctx context.Context, opts ...grpc.CallOption) (ViewService_StreamViewClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ViewService_serviceDesc.Streams[1], "/protos.ViewService/StreamView", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &viewServiceStreamViewClient{stream}
+	return x, nil
+}
+
+type ViewService_StreamViewClient interface {
+	Send(*SignedCommand) error
+	Recv() (*SignedCommandResponse, error)
+	grpc.ClientStream
+}
+
+type viewServiceStreamViewClient struct {
+	grpc.ClientStream
+}
+
+func (x *viewServiceStreamViewClient) Send(m *SignedCommand) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *viewServiceStreamViewClient) Recv() (*SignedCommandResponse, error) {
+	m := new(SignedCommandResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ViewServiceServer is the server API for ViewService service.
 type ViewServiceServer interface {
 	// ProcessCommand processes the passed command ensuring proper access control.
@@ -117,6 +153,10 @@ type ViewServiceServer interface {
 	// reports the reason of the failure.
 	ProcessCommand(context.Context, *SignedCommand) (*SignedCommandResponse, error)
 	StreamCommand(*SignedCommand, ViewService_StreamCommandServer) error
+	// StreamView initiates the view described by the first command received on the stream, that must be
+	// a StreamView command. Further commands on the stream must be StreamViewInput commands whose input is
+	// delivered to the running view. The server streams back the progress of the view as StreamViewEvent responses.
+	StreamView(ViewService_StreamViewServer) error
 }
 
 // UnimplementedViewServiceServer can be embedded to have forward compatible implementations.
@@ -129,6 +169,9 @@ func (*UnimplementedViewServiceServer) ProcessCommand(ctx context.Context, req *
 func (*UnimplementedViewServiceServer) StreamCommand(req *SignedCommand, srv ViewService_StreamCommandServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamCommand not implemented")
 }
+func (*UnimplementedViewServiceServer) StreamView(srv ViewService_StreamViewServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamView not implemented")
+}
 
 func RegisterViewServiceServer(s *grpc.Server, srv ViewServiceServer) {
 	s.RegisterService(&_ViewService_serviceDesc, srv)
@@ -173,6 +216,32 @@ func (x *viewServiceStreamCommandServer) Send(m *SignedCommandResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ViewService_StreamView_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ViewServiceServer).StreamView(&viewServiceStreamViewServer{stream})
+}
+
+type ViewService_StreamViewServer interface {
+	Send(*SignedCommandResponse) error
+	Recv() (*SignedCommand, error)
+	grpc.ServerStream
+}
+
+type viewServiceStreamViewServer struct {
+	grpc.ServerStream
+}
+
+func (x *viewServiceStreamViewServer) Send(m *SignedCommandResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *viewServiceStreamViewServer) Recv() (*SignedCommand, error) {
+	m := new(SignedCommand)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _ViewService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.ViewService",
 	HandlerType: (*ViewServiceServer)(nil),
@@ -188,6 +257,12 @@ var _ViewService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ViewService_StreamCommand_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamView",
+			Handler:       _ViewService_StreamView_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "service.proto",
 }
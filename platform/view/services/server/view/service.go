@@ -23,11 +23,25 @@ type Processor func(ctx context.Context, command *protos2.Command) (interface{},
 
 type Streamer func(sc *protos2.SignedCommand, command *protos2.Command, commandServer protos2.ViewService_StreamCommandServer, marshaler Marshaler) error
 
+// CommandStream is the server side of a bidirectional command stream
+type CommandStream interface {
+	// Recv blocks until the next command is received on the stream.
+	// The command header and access control are checked before returning the command.
+	Recv() (*protos2.Command, error)
+	// Send marshals and signs the passed response payload, and sends it on the stream
+	Send(responsePayload interface{}) error
+	// Context returns the context of the stream
+	Context() context.Context
+}
+
+type BidiStreamer func(command *protos2.Command, stream CommandStream) error
+
 type Service interface {
 	protos2.ViewServiceServer
 
 	RegisterProcessor(typ reflect.Type, p Processor)
 	RegisterStreamer(typ reflect.Type, streamer Streamer)
+	RegisterBidiStreamer(typ reflect.Type, streamer BidiStreamer)
 }
 
 func GetService(sp view.ServiceProvider) Service {
@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+)
+
+// Streams keeps track of the streams bound to the contexts of the views initiated with StreamView.
+// It implements driver.ViewStreamProvider.
+type Streams struct {
+	lock    sync.RWMutex
+	streams map[string]*viewStream
+}
+
+func NewStreams() *Streams {
+	return &Streams{streams: map[string]*viewStream{}}
+}
+
+// Stream returns the stream bound to the passed context identifier, an error if no stream is bound
+func (s *Streams) Stream(contextID string) (driver.ViewStream, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	vs, ok := s.streams[contextID]
+	if !ok {
+		return nil, errors.Errorf("no stream bound to context [%s]", contextID)
+	}
+	return vs, nil
+}
+
+func (s *Streams) add(vs *viewStream) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.streams[vs.cid] = vs
+}
+
+func (s *Streams) remove(vs *viewStream) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.streams, vs.cid)
+	vs.close()
+}
+
+// viewStream implements driver.ViewStream on top of a CommandStream
+type viewStream struct {
+	cid    string
+	stream CommandStream
+	input  chan []byte
+	// eof is closed when the client stops sending input
+	eof  chan struct{}
+	done chan struct{}
+
+	closeOnce sync.Once
+}
+
+func newViewStream(cid string, stream CommandStream) *viewStream {
+	return &viewStream{
+		cid:    cid,
+		stream: stream,
+		input:  make(chan []byte),
+		eof:    make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (v *viewStream) Send(output []byte) error {
+	return v.send(&protos2.StreamViewEvent{
+		Cid:   v.cid,
+		Event: &protos2.StreamViewEvent_Output{Output: output},
+	})
+}
+
+func (v *viewStream) Report(msg string) error {
+	return v.send(&protos2.StreamViewEvent{
+		Cid:   v.cid,
+		Event: &protos2.StreamViewEvent_Report{Report: msg},
+	})
+}
+
+func (v *viewStream) Recv() ([]byte, error) {
+	select {
+	case in := <-v.input:
+		return in, nil
+	case <-v.eof:
+		return nil, errors.Errorf("no more input on stream bound to context [%s]", v.cid)
+	case <-v.done:
+		return nil, errors.Errorf("stream bound to context [%s] closed", v.cid)
+	}
+}
+
+func (v *viewStream) send(event *protos2.StreamViewEvent) error {
+	select {
+	case <-v.done:
+		return errors.Errorf("stream bound to context [%s] closed", v.cid)
+	default:
+	}
+	return v.stream.Send(&protos2.CommandResponse_StreamViewEvent{StreamViewEvent: event})
+}
+
+// receive delivers the input sent by the client to the view, until the stream is closed
+func (v *viewStream) receive() {
+	defer close(v.eof)
+
+	for {
+		command, err := v.stream.Recv()
+		if err != nil {
+			logger.Debugf("stream bound to context [%s] terminated [%s]", v.cid, err)
+			return
+		}
+		in, ok := command.Payload.(*protos2.Command_StreamViewInput)
+		if !ok {
+			logger.Errorf("unexpected command [%T] on stream bound to context [%s], discarding it", command.Payload, v.cid)
+			continue
+		}
+		select {
+		case v.input <- in.StreamViewInput.Input:
+		case <-v.done:
+			return
+		}
+	}
+}
+
+func (v *viewStream) close() {
+	v.closeOnce.Do(func() {
+		close(v.done)
+	})
+}
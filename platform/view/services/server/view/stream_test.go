@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	protos2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/view/protos"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type fakeCommandStream struct {
+	in  chan *protos2.Command
+	out chan *protos2.StreamViewEvent
+}
+
+func newFakeCommandStream() *fakeCommandStream {
+	return &fakeCommandStream{in: make(chan *protos2.Command), out: make(chan *protos2.StreamViewEvent, 10)}
+}
+
+func (f *fakeCommandStream) Recv() (*protos2.Command, error) {
+	command, ok := <-f.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return command, nil
+}
+
+func (f *fakeCommandStream) Send(responsePayload interface{}) error {
+	f.out <- responsePayload.(*protos2.CommandResponse_StreamViewEvent).StreamViewEvent
+	return nil
+}
+
+func (f *fakeCommandStream) Context() context.Context {
+	return context.Background()
+}
+
+func input(in string) *protos2.Command {
+	return &protos2.Command{Payload: &protos2.Command_StreamViewInput{StreamViewInput: &protos2.StreamViewInput{Input: []byte(in)}}}
+}
+
+func waitClosed(t *testing.T, ch chan struct{}) {
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestViewStreamRoundTrip(t *testing.T) {
+	fs := newFakeCommandStream()
+	vs := newViewStream("ctx", fs)
+	go vs.receive()
+
+	// commands other than input are discarded
+	fs.in <- &protos2.Command{Payload: &protos2.Command_CallView{CallView: &protos2.CallView{Fid: "pay"}}}
+	fs.in <- input("approve")
+	in, err := vs.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("approve"), in)
+
+	assert.NoError(t, vs.Report("approved"))
+	assert.NoError(t, vs.Send([]byte("receipt")))
+	event := <-fs.out
+	assert.Equal(t, "ctx", event.Cid)
+	assert.Equal(t, "approved", event.GetReport())
+	event = <-fs.out
+	assert.Equal(t, []byte("receipt"), event.GetOutput())
+}
+
+func TestViewStreamCancellation(t *testing.T) {
+	fs := newFakeCommandStream()
+	vs := newViewStream("ctx", fs)
+	go vs.receive()
+
+	streams := NewStreams()
+	streams.add(vs)
+	s, err := streams.Stream("ctx")
+	assert.NoError(t, err)
+	assert.Equal(t, vs, s)
+
+	// the view terminates, the stream is unbound and closed
+	streams.remove(vs)
+	_, err = streams.Stream("ctx")
+	assert.Error(t, err)
+	_, err = vs.Recv()
+	assert.Error(t, err)
+	assert.Error(t, vs.Send([]byte("late")))
+	assert.Error(t, vs.Report("late"))
+
+	// input still in flight does not block the receiver
+	fs.in <- input("late")
+	waitClosed(t, vs.eof)
+}
+
+func TestViewStreamClientDisconnect(t *testing.T) {
+	fs := newFakeCommandStream()
+	vs := newViewStream("ctx", fs)
+	go vs.receive()
+
+	fs.in <- input("first")
+	in, err := vs.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), in)
+
+	// the client goes away while the view waits for more input
+	close(fs.in)
+	waitClosed(t, vs.eof)
+	_, err = vs.Recv()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no more input")
+}
+
+type verifierProvider struct{}
+
+func (v *verifierProvider) GetVerifier(identity view.Identity) (view2.Verifier, error) {
+	return v, nil
+}
+
+func (v *verifierProvider) Verify(message, sigma []byte) error {
+	return nil
+}
+
+func signedCommand(t *testing.T, creator []byte, c *protos2.Command) *protos2.SignedCommand {
+	c.Header = &protos2.Header{Nonce: []byte("nonce"), Creator: creator}
+	raw, err := proto.Marshal(c)
+	assert.NoError(t, err)
+	return &protos2.SignedCommand{Command: raw, Signature: []byte("signature")}
+}
+
+func TestStreamInputAuthorization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream-input")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, client := newCert(t, nil, "client", false)
+	_, outsider := newCert(t, nil, "client", false)
+	_, admin := newCert(t, nil, "admin", false)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "client.pem"), client, 0644))
+	policies, err := LoadViewPolicies(&aclConfig{conf: &ACLConfig{
+		Policies: []PolicyConfig{{Views: []string{"pay"}, Principals: []PrincipalConfig{{Identity: filepath.Join(dir, "client.pem")}}}},
+	}})
+	assert.NoError(t, err)
+	s, err := NewViewServiceServer(nil, &AccessControlChecker{
+		IdentityProvider: &identityProvider{admin: admin},
+		VerifierProvider: &verifierProvider{},
+		Policies:         policies,
+	})
+	assert.NoError(t, err)
+
+	first, err := s.checkCommand(signedCommand(t, client, &protos2.Command{
+		Payload: &protos2.Command_StreamView{StreamView: &protos2.StreamView{Fid: "pay"}},
+	}))
+	assert.NoError(t, err)
+
+	// the input of the stream creator is authorized against the view of the stream,
+	// on its own it is a command reserved to the admins
+	sc := signedCommand(t, client, input("approve"))
+	_, err = s.checkCommand(sc)
+	assert.Error(t, err)
+	c, err := s.checkStreamCommand(first, sc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("approve"), c.GetStreamViewInput().Input)
+
+	// nobody else can feed the stream, not even the admins
+	_, err = s.checkStreamCommand(first, signedCommand(t, outsider, input("approve")))
+	assert.Error(t, err)
+	_, err = s.checkStreamCommand(first, signedCommand(t, admin, input("approve")))
+	assert.Error(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"reflect"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view"
@@ -27,7 +28,9 @@ func InstallViewHandler(sp view.ServiceProvider, server Service) {
 	fh := &viewHandler{sp: sp}
 	server.RegisterProcessor(reflect.TypeOf(&protos2.Command_InitiateView{}), fh.initiateView)
 	server.RegisterProcessor(reflect.TypeOf(&protos2.Command_TrackView{}), fh.trackView)
+	server.RegisterProcessor(reflect.TypeOf(&protos2.Command_ListViews{}), fh.listViews)
 	server.RegisterProcessor(reflect.TypeOf(&protos2.Command_CallView{}), fh.callView)
+	server.RegisterBidiStreamer(reflect.TypeOf(&protos2.Command_StreamView{}), fh.streamView)
 }
 
 func (s *viewHandler) initiateView(ctx context.Context, command *protos2.Command) (interface{}, error) {
@@ -42,7 +45,7 @@ func (s *viewHandler) initiateView(ctx context.Context, command *protos2.Command
 	if err != nil {
 		return nil, errors.Errorf("failed instantiating view [%s], err [%s]", fid, err)
 	}
-	contextID, err := s.RunView(viewManager, f)
+	contextID, err := s.RunView(viewManager, fid, f)
 	if err != nil {
 		return nil, errors.Errorf("failed running view [%s], err %s", fid, err)
 	}
@@ -57,15 +60,15 @@ func (s *viewHandler) trackView(ctx context.Context, command *protos2.Command) (
 	cid := trackView.Cid
 	log.Printf("Track context [%s]", cid)
 
-	_, err := view.GetManager(s.sp).Context(cid)
+	trackerService, err := tracker.GetService(s.sp)
 	if err != nil {
-		return nil, errors.Errorf("failed retrieving context [%s], err [%s]", cid, err)
+		return nil, errors.Errorf("failed getting tracker for context [%s], err [%s]", cid, err)
 	}
-	viewTracker, err := tracker.GetViewTracker(s.sp)
+	status, err := trackerService.ViewStatus(cid)
 	if err != nil {
-		return nil, errors.Errorf("failed getting tracker for context [%s], err [%s]", cid, err)
+		return nil, errors.Errorf("failed retrieving status of context [%s], err [%s]", cid, err)
 	}
-	payload, err := json.Marshal(viewTracker.ViewStatus())
+	payload, err := json.Marshal(status)
 	if err != nil {
 		return nil, errors.Errorf("failed marshalling view status for context [%s], err [%s]", cid, err)
 	}
@@ -76,6 +79,42 @@ func (s *viewHandler) trackView(ctx context.Context, command *protos2.Command) (
 	}}, nil
 }
 
+func (s *viewHandler) listViews(ctx context.Context, command *protos2.Command) (interface{}, error) {
+	listViews := command.Payload.(*protos2.Command_ListViews).ListViews
+
+	filter := &tracker.Filter{
+		FactoryID: listViews.Fid,
+		Limit:     int(listViews.Limit),
+	}
+	for _, status := range listViews.Statuses {
+		filter.Statuses = append(filter.Statuses, int(status))
+	}
+	if listViews.Since != nil {
+		since, err := ptypes.Timestamp(listViews.Since)
+		if err != nil {
+			return nil, errors.Errorf("invalid since timestamp, err [%s]", err)
+		}
+		filter.Since = since
+	}
+
+	trackerService, err := tracker.GetService(s.sp)
+	if err != nil {
+		return nil, errors.Errorf("failed getting tracker, err [%s]", err)
+	}
+	statuses, err := trackerService.ViewStatuses(filter)
+	if err != nil {
+		return nil, errors.Errorf("failed listing view statuses, err [%s]", err)
+	}
+	payload, err := json.Marshal(statuses)
+	if err != nil {
+		return nil, errors.Errorf("failed marshalling view statuses, err [%s]", err)
+	}
+
+	return &protos2.CommandResponse_ListViewsResponse{ListViewsResponse: &protos2.ListViewsResponse{
+		Payload: payload,
+	}}, nil
+}
+
 func (s *viewHandler) callView(ctx context.Context, command *protos2.Command) (interface{}, error) {
 	callView := command.Payload.(*protos2.Command_CallView).CallView
 
@@ -105,17 +144,74 @@ func (s *viewHandler) callView(ctx context.Context, command *protos2.Command) (i
 	}}, nil
 }
 
-func (s *viewHandler) RunView(manager *view.Manager, view view.View) (string, error) {
+func (s *viewHandler) streamView(command *protos2.Command, stream CommandStream) error {
+	streamView := command.Payload.(*protos2.Command_StreamView).StreamView
+
+	fid := streamView.Fid
+	input := streamView.Input
+	logger.Debugf("Stream view [%s] on input [%v]", fid, string(input))
+
+	streams, err := s.sp.GetService(&Streams{})
+	if err != nil {
+		return errors.Errorf("failed getting streams for view [%s], err [%s]", fid, err)
+	}
+	viewManager := view.GetManager(s.sp)
+	f, err := viewManager.NewView(fid, input)
+	if err != nil {
+		return errors.Errorf("failed instantiating view [%s], err [%s]", fid, err)
+	}
+	context, err := viewManager.InitiateContext(f)
+	if err != nil {
+		return errors.Errorf("failed initiating context for view [%s], err [%s]", fid, err)
+	}
+
+	trackerService, err := tracker.GetService(s.sp)
+	if err != nil {
+		return errors.Errorf("failed getting tracker for view [%s], err [%s]", fid, err)
+	}
+	viewTracker := trackerService.Track(context.ID(), fid)
+
+	// Bind a stream to the context before running the view, and announce the context to the client
+	vs := newViewStream(context.ID(), stream)
+	streams.(*Streams).add(vs)
+	defer streams.(*Streams).remove(vs)
+	if err := vs.send(&protos2.StreamViewEvent{Cid: context.ID()}); err != nil {
+		return errors.Errorf("failed announcing context [%s], err [%s]", context.ID(), err)
+	}
+	go vs.receive()
+
+	result, err := context.RunView(f)
+	if err != nil {
+		viewTracker.Error(err)
+		return errors.Errorf("failed running view [%s], err %s", fid, err)
+	}
+	viewTracker.Done(result)
+	raw, ok := result.([]byte)
+	if !ok {
+		raw, err = json.Marshal(result)
+		if err != nil {
+			return errors.Errorf("failed marshalling result produced by view [%s], err [%s]", fid, err)
+		}
+	}
+	logger.Debugf("Finished stream view [%s] on input [%v]", fid, string(input))
+	return vs.send(&protos2.StreamViewEvent{
+		Cid:   context.ID(),
+		Event: &protos2.StreamViewEvent_Result{Result: raw},
+	})
+}
+
+func (s *viewHandler) RunView(manager *view.Manager, fid string, view view.View) (string, error) {
 	context, err := manager.InitiateContext(view)
 	if err != nil {
 		return "", err
 	}
 
-	// Get the tracker
-	viewTracker, err := tracker.GetViewTracker(s.sp)
+	// Track the context
+	trackerService, err := tracker.GetService(s.sp)
 	if err != nil {
 		return "", err
 	}
+	viewTracker := trackerService.Track(context.ID(), fid)
 
 	// Run the view
 	go s.runViewWithTracking(view, context, viewTracker)
@@ -6,14 +6,63 @@ SPDX-License-Identifier: Apache-2.0
 
 package web
 
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// The headers authenticating a request: the caller signs the request with its identity,
+// see RequestToSign for the signed bytes.
+const (
+	// IdentityHeader carries the base64 encoding of the identity of the caller
+	IdentityHeader = "X-Fsc-Identity"
+	// TimestampHeader carries the time of the request, in RFC3339 format with nanoseconds
+	TimestampHeader = "X-Fsc-Timestamp"
+	// SignatureHeader carries the base64 encoding of the signature of the request by the caller
+	SignatureHeader = "X-Fsc-Signature"
+
+	// MaxRequestSkew bounds the distance between the timestamp of a request and the time it is received
+	MaxRequestSkew = 5 * time.Minute
+)
+
+// RequestToSign returns the bytes a caller signs to authenticate a request: the method, the request uri,
+// the timestamp and the body of the request
+func RequestToSign(method, uri, timestamp string, body []byte) []byte {
+	return bytes.Join([][]byte{[]byte(method), []byte(uri), []byte(timestamp), body}, []byte("\n"))
+}
+
 type ViewCaller interface {
 	CallView(fid string, input []byte) (interface{}, error)
 }
 
+type ViewTracker interface {
+	// TrackView returns the status of the view running, or that was running, in the passed context
+	TrackView(cid string) (interface{}, error)
+	// ListViews returns the status of the recently initiated views selected by the passed query
+	ListViews(query url.Values) (interface{}, error)
+}
+
+// Authorizer authenticates the callers and checks that they are allowed to initiate the views created by a given factory
+type Authorizer interface {
+	// Authenticate checks that the passed signature of the passed message is valid for the passed identity
+	Authenticate(creator view.Identity, message, signature []byte) error
+	// Authorize checks that the passed identity is allowed to initiate the views created by the passed factory.
+	// An empty fid refers to the requests that do not initiate a view.
+	Authorize(creator view.Identity, fid string) error
+}
+
 type Dispatcher struct {
 	vc      ViewCaller
 	Logger  logger
 	Handler *HttpHandler
+	// Authorizer authenticates and authorizes the requests, all requests are denied if it is not set
+	Authorizer Authorizer
 }
 
 func (rd *Dispatcher) HandleRequest(context *ReqContext) (response interface{}, statusCode int) {
@@ -24,6 +73,10 @@ func (rd *Dispatcher) HandleRequest(context *ReqContext) (response interface{},
 		return &ResponseErr{Reason: "internal error"}, 500
 	}
 
+	if response, statusCode := rd.authorize(context, context.Vars["View"]); statusCode != 200 {
+		return response, statusCode
+	}
+
 	res, err := rd.vc.CallView(context.Vars["View"], context.Query.([]byte))
 	if err != nil {
 		return &ResponseErr{Reason: err.Error()}, 500
@@ -32,6 +85,51 @@ func (rd *Dispatcher) HandleRequest(context *ReqContext) (response interface{},
 	return res, 200
 }
 
+// authorize authenticates the caller of the request, from the signature carried by the headers of the request,
+// and checks that the caller is allowed to initiate the views created by the passed factory
+func (rd *Dispatcher) authorize(context *ReqContext, fid string) (response interface{}, statusCode int) {
+	if rd.Authorizer == nil {
+		rd.Logger.Errorf("no authorizer set, deny request for view [%s] from %s", fid, context.Req.Host)
+		return &ResponseErr{Reason: "internal error"}, 500
+	}
+	creator, message, signature, err := signedRequest(context)
+	if err != nil {
+		rd.Logger.Warnf("request for view [%s] from %s not authenticated: [%s]", fid, context.Req.Host, err)
+		return &ResponseErr{Reason: err.Error()}, 401
+	}
+	if err := rd.Authorizer.Authenticate(creator, message, signature); err != nil {
+		rd.Logger.Warnf("request for view [%s] from %s not authenticated: [%s]", fid, context.Req.Host, err)
+		return &ResponseErr{Reason: "invalid signature"}, 401
+	}
+	if err := rd.Authorizer.Authorize(creator, fid); err != nil {
+		return &ResponseErr{Reason: err.Error()}, 403
+	}
+	return nil, 200
+}
+
+// signedRequest returns the caller, the signed bytes and the signature of the passed request
+func signedRequest(context *ReqContext) (view.Identity, []byte, []byte, error) {
+	header := context.Req.Header
+	creator, err := base64.StdEncoding.DecodeString(header.Get(IdentityHeader))
+	if err != nil || len(creator) == 0 {
+		return nil, nil, nil, errors.Errorf("missing or invalid header [%s]", IdentityHeader)
+	}
+	signature, err := base64.StdEncoding.DecodeString(header.Get(SignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return nil, nil, nil, errors.Errorf("missing or invalid header [%s]", SignatureHeader)
+	}
+	timestamp := header.Get(TimestampHeader)
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, nil, nil, errors.Errorf("missing or invalid header [%s]", TimestampHeader)
+	}
+	if skew := time.Since(t); skew > MaxRequestSkew || skew < -MaxRequestSkew {
+		return nil, nil, nil, errors.Errorf("request timestamp [%s] out of the accepted window", timestamp)
+	}
+	body, _ := context.Query.([]byte)
+	return creator, RequestToSign(context.Req.Method, context.Req.URL.RequestURI(), timestamp, body), signature, nil
+}
+
 func (rd *Dispatcher) ParsePayload(bytes []byte) (interface{}, error) {
 	return bytes, nil
 }
@@ -40,3 +138,50 @@ func (rd *Dispatcher) WireViewCaller(vc ViewCaller) {
 	rd.vc = vc
 	rd.Handler.RegisterURI("/Views/{View}", "PUT", rd)
 }
+
+func (rd *Dispatcher) WireViewTracker(vt ViewTracker) {
+	rd.Handler.RegisterURI("/Contexts/{Context}", "GET", &trackViewHandler{rd: rd, vt: vt})
+	rd.Handler.RegisterURI("/Contexts", "GET", &listViewsHandler{rd: rd, vt: vt})
+}
+
+type trackViewHandler struct {
+	rd *Dispatcher
+	vt ViewTracker
+}
+
+func (t *trackViewHandler) HandleRequest(context *ReqContext) (response interface{}, statusCode int) {
+	if response, statusCode := t.rd.authorize(context, ""); statusCode != 200 {
+		return response, statusCode
+	}
+
+	res, err := t.vt.TrackView(context.Vars["Context"])
+	if err != nil {
+		return &ResponseErr{Reason: err.Error()}, 404
+	}
+	return res, 200
+}
+
+func (t *trackViewHandler) ParsePayload(bytes []byte) (interface{}, error) {
+	return bytes, nil
+}
+
+type listViewsHandler struct {
+	rd *Dispatcher
+	vt ViewTracker
+}
+
+func (l *listViewsHandler) HandleRequest(context *ReqContext) (response interface{}, statusCode int) {
+	if response, statusCode := l.rd.authorize(context, ""); statusCode != 200 {
+		return response, statusCode
+	}
+
+	res, err := l.vt.ListViews(context.Req.URL.Query())
+	if err != nil {
+		return &ResponseErr{Reason: err.Error()}, 400
+	}
+	return res, 200
+}
+
+func (l *listViewsHandler) ParsePayload(bytes []byte) (interface{}, error) {
+	return bytes, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
+	web2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/web"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// authorizer knows the verifiers of the callers, and the views each caller can initiate
+type authorizer struct {
+	verifiers map[string]driver.Verifier
+	allowed   map[string][]string
+}
+
+func (a *authorizer) Authenticate(creator view.Identity, message, signature []byte) error {
+	verifier, ok := a.verifiers[creator.UniqueID()]
+	if !ok {
+		return errors.Errorf("unknown identity [%s]", creator)
+	}
+	return verifier.Verify(message, signature)
+}
+
+func (a *authorizer) Authorize(creator view.Identity, fid string) error {
+	for _, allowed := range a.allowed[creator.UniqueID()] {
+		if allowed == fid {
+			return nil
+		}
+	}
+	return errors.Errorf("identity [%s] not allowed to initiate view [%s]", creator, fid)
+}
+
+type viewCaller struct{}
+
+func (v *viewCaller) CallView(fid string, input []byte) (interface{}, error) { return string(input), nil }
+
+type viewTracker struct{}
+
+func (v *viewTracker) TrackView(cid string) (interface{}, error) { return cid, nil }
+
+func (v *viewTracker) ListViews(query url.Values) (interface{}, error) { return []string{}, nil }
+
+type caller struct {
+	id     view.Identity
+	signer driver.Signer
+}
+
+// request returns a request signed by the caller at the passed time, the body sent can differ from the one signed
+func (c *caller) request(t *testing.T, method, uri string, signed, sent []byte, at time.Time) *http.Request {
+	req := httptest.NewRequest(method, uri, bytes.NewBuffer(sent))
+	timestamp := at.UTC().Format(time.RFC3339Nano)
+	signature, err := c.signer.Sign(web2.RequestToSign(method, req.URL.RequestURI(), timestamp, signed))
+	require.NoError(t, err)
+	req.Header.Set(web2.IdentityHeader, base64.StdEncoding.EncodeToString(c.id))
+	req.Header.Set(web2.TimestampHeader, timestamp)
+	req.Header.Set(web2.SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	return req
+}
+
+func newCaller(t *testing.T, a *authorizer, allowed ...string) *caller {
+	id, signer, verifier, err := ecdsa.NewSigner()
+	require.NoError(t, err)
+	a.verifiers[id.UniqueID()] = verifier
+	a.allowed[id.UniqueID()] = allowed
+	return &caller{id: id, signer: signer}
+}
+
+func TestDispatcherAuthorization(t *testing.T) {
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	a := &authorizer{verifiers: map[string]driver.Verifier{}, allowed: map[string][]string{}}
+	alice := newCaller(t, a, "allowed")
+	admin := newCaller(t, a, "allowed", "denied", "")
+
+	h := web2.NewHttpHandler(l.Sugar())
+	d := &web2.Dispatcher{Logger: l.Sugar(), Handler: h, Authorizer: a}
+	d.WireViewCaller(&viewCaller{})
+	d.WireViewTracker(&viewTracker{})
+	serve := func(req *http.Request) int {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	now := time.Now()
+	in := []byte("input")
+
+	// the callers are allowed the views they are authorized for
+	require.Equal(t, 200, serve(alice.request(t, http.MethodPut, "/v1/Views/allowed", in, in, now)))
+	require.Equal(t, 403, serve(alice.request(t, http.MethodPut, "/v1/Views/denied", in, in, now)))
+	require.Equal(t, 200, serve(admin.request(t, http.MethodPut, "/v1/Views/denied", in, in, now)))
+
+	// the requests that do not initiate a view are reserved to the admins
+	require.Equal(t, 403, serve(alice.request(t, http.MethodGet, "/v1/Contexts/ctx", nil, nil, now)))
+	require.Equal(t, 403, serve(alice.request(t, http.MethodGet, "/v1/Contexts", nil, nil, now)))
+	require.Equal(t, 200, serve(admin.request(t, http.MethodGet, "/v1/Contexts/ctx", nil, nil, now)))
+	require.Equal(t, 200, serve(admin.request(t, http.MethodGet, "/v1/Contexts", nil, nil, now)))
+
+	// unsigned, tampered, stale and unknown callers' requests are not authenticated
+	require.Equal(t, 401, serve(httptest.NewRequest(http.MethodPut, "/v1/Views/allowed", bytes.NewBuffer(in))))
+	require.Equal(t, 401, serve(admin.request(t, http.MethodPut, "/v1/Views/allowed", in, []byte("tampered"), now)))
+	require.Equal(t, 401, serve(admin.request(t, http.MethodPut, "/v1/Views/allowed", in, in, now.Add(-2*web2.MaxRequestSkew))))
+	stranger := &caller{id: alice.id, signer: admin.signer}
+	require.Equal(t, 401, serve(stranger.request(t, http.MethodPut, "/v1/Views/allowed", in, in, now)))
+
+	// without an authorizer, every request is denied
+	h = web2.NewHttpHandler(l.Sugar())
+	d = &web2.Dispatcher{Logger: l.Sugar(), Handler: h}
+	d.WireViewCaller(&viewCaller{})
+	require.Equal(t, 500, serve(admin.request(t, http.MethodPut, "/v1/Views/allowed", in, in, now)))
+}
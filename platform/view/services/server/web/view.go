@@ -9,6 +9,9 @@ package web
 import (
 	"encoding/json"
 	"log"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -30,11 +33,13 @@ func (vcf viewCallFunc) CallView(fid string, input []byte) (interface{}, error)
 
 type dispatcher interface {
 	WireViewCaller(vc ViewCaller)
+	WireViewTracker(vt ViewTracker)
 }
 
 func InstallViewHandler(l logger, sp view.ServiceProvider, d dispatcher) {
 	fh := &viewHandler{logger: l, sp: sp}
 	d.WireViewCaller(viewCallFunc(fh.callView))
+	d.WireViewTracker(fh)
 }
 
 func (s *viewHandler) callView(fid string, input []byte) (interface{}, error) {
@@ -62,17 +67,60 @@ func (s *viewHandler) callView(fid string, input []byte) (interface{}, error) {
 	}}, nil
 }
 
-func (s *viewHandler) RunView(manager *view.Manager, view view.View) (string, error) {
+// TrackView returns the status of the view running, or that was running, in the passed context
+func (s *viewHandler) TrackView(cid string) (interface{}, error) {
+	trackerService, err := tracker.GetService(s.sp)
+	if err != nil {
+		return nil, errors.Errorf("failed getting tracker for context [%s], err [%s]", cid, err)
+	}
+	return trackerService.ViewStatus(cid)
+}
+
+// ListViews returns the status of the recently initiated views.
+// The query can contain the parameters fid, status (repeated), since (RFC3339) and limit.
+func (s *viewHandler) ListViews(query url.Values) (interface{}, error) {
+	filter := &tracker.Filter{FactoryID: query.Get("fid")}
+	for _, status := range query["status"] {
+		st, err := strconv.Atoi(status)
+		if err != nil {
+			return nil, errors.Errorf("invalid status [%s], err [%s]", status, err)
+		}
+		filter.Statuses = append(filter.Statuses, st)
+	}
+	if since := query.Get("since"); len(since) != 0 {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, errors.Errorf("invalid since [%s], err [%s]", since, err)
+		}
+		filter.Since = t
+	}
+	if limit := query.Get("limit"); len(limit) != 0 {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, errors.Errorf("invalid limit [%s], err [%s]", limit, err)
+		}
+		filter.Limit = l
+	}
+
+	trackerService, err := tracker.GetService(s.sp)
+	if err != nil {
+		return nil, errors.Errorf("failed getting tracker, err [%s]", err)
+	}
+	return trackerService.ViewStatuses(filter)
+}
+
+func (s *viewHandler) RunView(manager *view.Manager, fid string, view view.View) (string, error) {
 	context, err := manager.InitiateContext(view)
 	if err != nil {
 		return "", err
 	}
 
-	// Get the tracker
-	viewTracker, err := tracker.GetViewTracker(s.sp)
+	// Track the context
+	trackerService, err := tracker.GetService(s.sp)
 	if err != nil {
 		return "", err
 	}
+	viewTracker := trackerService.Track(context.ID(), fid)
 
 	// Run the view
 	go s.runViewWithTracking(view, context, viewTracker)
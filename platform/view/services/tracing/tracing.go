@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.tracing")
+
+const (
+	// NoneExporter disables tracing
+	NoneExporter = "none"
+	// MemoryExporter keeps the finished spans in memory, it is meant for tests
+	MemoryExporter = "memory"
+	// FileExporter appends the finished spans to a file, one JSON object per span
+	FileExporter = "file"
+
+	// TraceParentKey and TraceStateKey are the keys of the W3C trace context propagated to the remote parties
+	TraceParentKey = "traceparent"
+	TraceStateKey  = "tracestate"
+
+	tracerName = "github.com/hyperledger-labs/fabric-smart-client"
+)
+
+// propagator carries the span context across nodes in the W3C trace context format
+var propagator = propagation.TraceContext{}
+
+type ConfigProvider interface {
+	GetString(key string) string
+	GetPath(key string) string
+}
+
+type Options struct {
+	// Exporter is the exporter of the finished spans, none, memory or file
+	Exporter string
+	// File is the file the spans are appended to, if the exporter is file
+	File string
+	// SamplingRatio is the fraction of the traces started by this node that are recorded
+	SamplingRatio float64
+	// ServiceName names this node in the exported spans
+	ServiceName string
+}
+
+// LoadOptions returns the options of the tracing provider configured under fsc.tracing, nil if tracing is not enabled
+func LoadOptions(cp ConfigProvider) (*Options, error) {
+	o := &Options{
+		Exporter:      cp.GetString("fsc.tracing.exporter"),
+		File:          cp.GetPath("fsc.tracing.file"),
+		SamplingRatio: 1,
+		ServiceName:   cp.GetString("fsc.id"),
+	}
+	if len(o.Exporter) == 0 || o.Exporter == NoneExporter {
+		return nil, nil
+	}
+	if ratio := cp.GetString("fsc.tracing.sampling.ratio"); len(ratio) != 0 {
+		var err error
+		o.SamplingRatio, err = strconv.ParseFloat(ratio, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fsc.tracing.sampling.ratio [%s]", ratio)
+		}
+	}
+	return o, nil
+}
+
+// Provider creates the tracers of the node and exports the spans they record
+type Provider struct {
+	tracerProvider *sdktrace.TracerProvider
+	memory         *tracetest.InMemoryExporter
+	file           *os.File
+}
+
+func NewProvider(o Options) (*Provider, error) {
+	p := &Provider{}
+	res := resource.NewSchemaless(attribute.String("service.name", o.ServiceName))
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SamplingRatio))),
+	}
+
+	switch o.Exporter {
+	case MemoryExporter:
+		p.memory = tracetest.NewInMemoryExporter()
+		// spans are exported as soon as they end to let tests inspect them right away
+		opts = append(opts, sdktrace.WithSyncer(p.memory))
+	case FileExporter:
+		if len(o.File) == 0 {
+			return nil, errors.New("file exporter requires fsc.tracing.file to be set")
+		}
+		if err := os.MkdirAll(filepath.Dir(o.File), 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed creating folder for [%s]", o.File)
+		}
+		f, err := os.OpenFile(o.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed opening trace file [%s]", o.File)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, errors.Wrap(err, "failed creating file exporter")
+		}
+		p.file = f
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, errors.Errorf("unknown trace exporter [%s]", o.Exporter)
+	}
+
+	p.tracerProvider = sdktrace.NewTracerProvider(opts...)
+	logger.Infof("tracing enabled with exporter [%s]", o.Exporter)
+	return p, nil
+}
+
+// Tracer returns the tracer of the fabric smart client
+func (p *Provider) Tracer() trace.Tracer {
+	return p.tracerProvider.Tracer(tracerName)
+}
+
+// Spans returns the spans finished so far, if the memory exporter is in use
+func (p *Provider) Spans() tracetest.SpanStubs {
+	if p.memory == nil {
+		return nil
+	}
+	return p.memory.GetSpans()
+}
+
+// Shutdown flushes the pending spans and releases the exporter
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.tracerProvider.Shutdown(ctx)
+	if p.file != nil {
+		if closeErr := p.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// GetProvider returns the tracing provider registered in the passed service provider, nil if tracing is not enabled
+func GetProvider(sp driver.ServiceProvider) *Provider {
+	s, err := sp.GetService(&Provider{})
+	if err != nil {
+		return nil
+	}
+	return s.(*Provider)
+}
+
+// GetTracer returns the tracer registered in the passed service provider.
+// If tracing is not enabled, the returned tracer creates non-recording spans that only propagate the parent context.
+func GetTracer(sp driver.ServiceProvider) trace.Tracer {
+	if p := GetProvider(sp); p != nil {
+		return p.Tracer()
+	}
+	return trace.NewNoopTracerProvider().Tracer(tracerName)
+}
+
+// Start starts a span with the passed name, child of the span in the passed context, if any
+func Start(ctx context.Context, sp driver.ServiceProvider, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return GetTracer(sp).Start(ctx, name, opts...)
+}
+
+// End ends the passed span, recording the passed error, if any
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the trace context of the span in the passed context, in the W3C format
+func Inject(ctx context.Context) (traceParent string, traceState string) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier[TraceParentKey], carrier[TraceStateKey]
+}
+
+// Extract returns a copy of the passed context carrying the remote span described by the passed W3C trace context
+func Extract(ctx context.Context, traceParent string, traceState string) context.Context {
+	if len(traceParent) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{
+		TraceParentKey: traceParent,
+		TraceStateKey:  traceState,
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracing"
+)
+
+func TestPropagation(t *testing.T) {
+	alice, err := tracing.NewProvider(tracing.Options{Exporter: tracing.MemoryExporter, SamplingRatio: 1, ServiceName: "alice"})
+	assert.NoError(t, err)
+	bob, err := tracing.NewProvider(tracing.Options{Exporter: tracing.MemoryExporter, SamplingRatio: 1, ServiceName: "bob"})
+	assert.NoError(t, err)
+
+	// alice initiates a view and sends the span context to bob
+	ctx, initiator := alice.Tracer().Start(context.Background(), "initiator")
+	traceParent, traceState := tracing.Inject(ctx)
+	assert.NotEmpty(t, traceParent)
+
+	// bob responds as a child of alice's span
+	_, responder := bob.Tracer().Start(tracing.Extract(context.Background(), traceParent, traceState), "responder")
+	tracing.End(responder, errors.New("failed responding"))
+	tracing.End(initiator, nil)
+
+	assert.Len(t, alice.Spans(), 1)
+	assert.Len(t, bob.Spans(), 1)
+	initiatorSpan, responderSpan := alice.Spans()[0], bob.Spans()[0]
+	assert.Equal(t, initiatorSpan.SpanContext.TraceID(), responderSpan.SpanContext.TraceID())
+	assert.Equal(t, initiatorSpan.SpanContext.SpanID(), responderSpan.Parent.SpanID())
+	assert.True(t, responderSpan.Parent.IsRemote())
+	assert.Equal(t, codes.Error, responderSpan.Status.Code)
+	assert.Equal(t, codes.Unset, initiatorSpan.Status.Code)
+
+	// no trace context, no parent
+	_, root := bob.Tracer().Start(tracing.Extract(context.Background(), "", ""), "root")
+	root.End()
+	assert.False(t, bob.Spans()[1].Parent.IsValid())
+	traceParent, _ = tracing.Inject(context.Background())
+	assert.Empty(t, traceParent)
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "traces", "spans.json")
+
+	p, err := tracing.NewProvider(tracing.Options{Exporter: tracing.FileExporter, File: file, SamplingRatio: 1})
+	assert.NoError(t, err)
+	_, span := p.Tracer().Start(context.Background(), "view")
+	span.End()
+	assert.Nil(t, p.Spans())
+	assert.NoError(t, p.Shutdown(context.Background()))
+
+	raw, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `"Name":"view"`)
+	assert.Equal(t, 1, strings.Count(strings.TrimSpace(string(raw)), "\n")+1)
+
+	_, err = tracing.NewProvider(tracing.Options{Exporter: tracing.FileExporter})
+	assert.Error(t, err)
+	_, err = tracing.NewProvider(tracing.Options{Exporter: "unknown"})
+	assert.Error(t, err)
+}
@@ -7,11 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package tracker
 
 import (
-	"reflect"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
 
-	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/pkg/errors"
 
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
 var logger = flogging.MustGetLogger("view-sdk.tracker")
@@ -22,9 +28,32 @@ const (
 	ERROR
 )
 
+const statusPrefix = "view-status"
+
+var indexKey = kvs.CreateCompositeKeyOrPanic("view-status-index", nil)
+
+// Report is a status report produced by a view
+type Report struct {
+	Time    time.Time
+	Message string
+}
+
+// ViewStatus describes the execution of the view running in a given context
 type ViewStatus struct {
+	ContextID string
+	// FactoryID is the identifier of the factory that created the view, if known
+	FactoryID  string
 	Status     int
 	LastReport string
+	// Reports contains all the reports produced by the view, in order
+	Reports []Report
+	// Result is the result produced by the view, marshalled to json if not a byte slice
+	Result []byte
+	// Error is the error returned by the view, if any
+	Error string
+	Start time.Time
+	// End is zero while the view is running
+	End time.Time
 }
 
 type ViewTracker interface {
@@ -41,53 +70,445 @@ type ViewTracker interface {
 	ViewStatus() *ViewStatus
 }
 
+// Filter selects the view statuses returned by Service.ViewStatuses
+type Filter struct {
+	// FactoryID, if not empty, selects the views created by this factory
+	FactoryID string
+	// Statuses, if not empty, selects the views whose status is one of these
+	Statuses []int
+	// Since, if not zero, selects the views started at or after this time
+	Since time.Time
+	// Limit, if positive, is the maximum number of statuses returned
+	Limit int
+}
+
+func (f *Filter) match(s *ViewStatus) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.FactoryID) != 0 && f.FactoryID != s.FactoryID {
+		return false
+	}
+	if !f.Since.IsZero() && s.Start.Before(f.Since) {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, status := range f.Statuses {
+		if status == s.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRetention is how long the statuses of terminated views are kept by default
+const DefaultRetention = 24 * time.Hour
+
+// entry indexes a view status by the fields filters select on
+type entry struct {
+	ContextID string
+	FactoryID string
+	Status    int
+	Start     time.Time
+	End       time.Time
+}
+
+// Service tracks the execution of views, one tracker per context.
+// The statuses are stored in the KVS, when available, so that they survive restarts.
+// They are stored when the view starts and when it terminates, and are kept for Retention after that.
+// An index of the statuses is kept aside, to select them without scanning the KVS.
+type Service struct {
+	sp view2.ServiceProvider
+	// Retention is how long the statuses of terminated views are kept
+	Retention time.Duration
+
+	lock     sync.Mutex
+	trackers map[string]*contextTracker
+	// statuses are the statuses of the terminated views, when no KVS is available
+	statuses map[string]*ViewStatus
+	index    map[string]*entry
+}
+
+func NewService(sp view2.ServiceProvider) *Service {
+	return &Service{
+		sp:        sp,
+		Retention: DefaultRetention,
+		trackers:  map[string]*contextTracker{},
+		statuses:  map[string]*ViewStatus{},
+	}
+}
+
+// Track starts tracking the view created by the passed factory and running in the passed context
+func (s *Service) Track(contextID string, fid string) ViewTracker {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t := &contextTracker{
+		service: s,
+		status: &ViewStatus{
+			ContextID: contextID,
+			FactoryID: fid,
+			Status:    RUNNING,
+			Start:     time.Now(),
+		},
+	}
+	s.trackers[contextID] = t
+	t.store()
+	s.loadIndex()[contextID] = &entry{ContextID: contextID, FactoryID: fid, Status: RUNNING, Start: t.status.Start}
+	s.storeIndex()
+	return t
+}
+
+// Tracker returns the tracker bound to the passed context.
+// It returns an error if no view is tracked in the context.
+func (s *Service) Tracker(contextID string) (ViewTracker, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if t, ok := s.trackers[contextID]; ok {
+		return t, nil
+	}
+
+	// A context interrupted by a restart can be resumed, keep its history
+	e, ok := s.loadIndex()[contextID]
+	kvss := s.kvs()
+	if !ok || e.Status != RUNNING || kvss == nil {
+		return nil, errors.Errorf("no view tracked in context [%s]", contextID)
+	}
+	status := &ViewStatus{}
+	if err := kvss.Get(statusKey(contextID), status); err != nil {
+		return nil, errors.WithMessagef(err, "failed loading status of context [%s]", contextID)
+	}
+	t := &contextTracker{service: s, status: status}
+	s.trackers[contextID] = t
+	return t, nil
+}
+
+// ViewStatus returns the status of the view running, or that was running, in the passed context
+func (s *Service) ViewStatus(contextID string) (*ViewStatus, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.loadIndex()[contextID]; !ok {
+		return nil, errors.Errorf("no status found for context [%s]", contextID)
+	}
+	return s.viewStatus(contextID)
+}
+
+// ViewStatuses returns the statuses selected by the passed filter, most recently started first
+func (s *Service) ViewStatuses(filter *Filter) ([]*ViewStatus, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.purge(time.Now())
+	var selected []*entry
+	for _, e := range s.loadIndex() {
+		if filter.match(&ViewStatus{FactoryID: e.FactoryID, Status: e.Status, Start: e.Start}) {
+			selected = append(selected, e)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Start.After(selected[j].Start)
+	})
+	if filter != nil && filter.Limit > 0 && len(selected) > filter.Limit {
+		selected = selected[:filter.Limit]
+	}
+
+	res := make([]*ViewStatus, len(selected))
+	for i, e := range selected {
+		status, err := s.viewStatus(e.ContextID)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = status
+	}
+	return res, nil
+}
+
+// viewStatus returns the status of an indexed context, the lock must be held
+func (s *Service) viewStatus(contextID string) (*ViewStatus, error) {
+	if t, ok := s.trackers[contextID]; ok {
+		return t.ViewStatus(), nil
+	}
+	if status, ok := s.statuses[contextID]; ok {
+		return status, nil
+	}
+	kvss := s.kvs()
+	if kvss == nil {
+		return nil, errors.Errorf("no status found for context [%s]", contextID)
+	}
+	status := &ViewStatus{}
+	if err := kvss.Get(statusKey(contextID), status); err != nil {
+		return nil, errors.WithMessagef(err, "failed loading status of context [%s]", contextID)
+	}
+	return status, nil
+}
+
+// release drops the tracker of a terminated view, its status is served from the KVS, or from memory
+// if no KVS is available, until the retention expires
+func (s *Service) release(status *ViewStatus) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.trackers, status.ContextID)
+	if s.kvs() == nil {
+		s.statuses[status.ContextID] = status
+	}
+	s.loadIndex()[status.ContextID] = &entry{
+		ContextID: status.ContextID,
+		FactoryID: status.FactoryID,
+		Status:    status.Status,
+		Start:     status.Start,
+		End:       status.End,
+	}
+	s.purge(time.Now())
+	s.storeIndex()
+}
+
+// purge drops the statuses of the views terminated before the retention, and of the views left running
+// by a previous process and started before the retention. The lock must be held.
+func (s *Service) purge(now time.Time) {
+	kvss := s.kvs()
+	deadline := now.Add(-s.Retention)
+	for id, e := range s.loadIndex() {
+		if _, running := s.trackers[id]; running {
+			continue
+		}
+		if e.Status == RUNNING && !e.Start.Before(deadline) || e.Status != RUNNING && !e.End.Before(deadline) {
+			continue
+		}
+		delete(s.index, id)
+		delete(s.statuses, id)
+		if kvss != nil {
+			if err := kvss.Delete(statusKey(id)); err != nil {
+				logger.Errorf("failed deleting status of context [%s]: [%s]", id, err)
+			}
+		}
+	}
+}
+
+// loadIndex returns the index of the statuses, loading it from the KVS the first time. The lock must be held.
+func (s *Service) loadIndex() map[string]*entry {
+	if s.index != nil {
+		return s.index
+	}
+	s.index = map[string]*entry{}
+	if kvss := s.kvs(); kvss != nil && kvss.Exists(indexKey) {
+		var entries []*entry
+		if err := kvss.Get(indexKey, &entries); err != nil {
+			logger.Errorf("failed loading view status index: [%s]", err)
+		}
+		for _, e := range entries {
+			s.index[e.ContextID] = e
+		}
+	}
+	return s.index
+}
+
+// storeIndex persists the index in the KVS, if available. The lock must be held.
+func (s *Service) storeIndex() {
+	kvss := s.kvs()
+	if kvss == nil {
+		return
+	}
+	entries := make([]*entry, 0, len(s.index))
+	for _, e := range s.index {
+		entries = append(entries, e)
+	}
+	if err := kvss.Put(indexKey, entries); err != nil {
+		logger.Errorf("failed storing view status index: [%s]", err)
+	}
+}
+
+// kvs returns the KVS registered in the service provider, nil if none is available
+func (s *Service) kvs() *kvs.KVS {
+	kvss, err := s.sp.GetService(&kvs.KVS{})
+	if err != nil {
+		return nil
+	}
+	return kvss.(*kvs.KVS)
+}
+
+func statusKey(contextID string) string {
+	return kvs.CreateCompositeKeyOrPanic(statusPrefix, []string{contextID})
+}
+
+// GetService returns the tracker service registered in the passed service provider
+func GetService(sp view2.ServiceProvider) (*Service, error) {
+	s, err := sp.GetService(&Service{})
+	if err != nil {
+		return nil, err
+	}
+	return s.(*Service), nil
+}
+
+// GetViewTracker returns the tracker of the view running in the passed context.
+// If no view is tracked in the context, for instance because it was not initiated through the view service,
+// the returned tracker only logs the reports.
 func GetViewTracker(ctx view2.ServiceProvider) (ViewTracker, error) {
-	s, err := ctx.GetService(reflect.TypeOf((*ViewTracker)(nil)))
+	context, ok := ctx.(view.Context)
+	if !ok {
+		return nil, errors.New("view trackers are bound to view contexts")
+	}
+	s, err := GetService(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return s.(ViewTracker), nil
+	t, err := s.Tracker(context.ID())
+	if err != nil {
+		logger.Debugf("context [%s] not tracked: [%s]", context.ID(), err)
+		t = &logTracker{}
+	}
+
+	// The reports of a view initiated with a streaming invocation are delivered to the client as well
+	if stream, err := view2.GetStream(context); err == nil {
+		return &streamTracker{ViewTracker: t, stream: stream}, nil
+	}
+	return t, nil
 }
 
-type defaultTracker struct {
-	lastReport string
-	err        error
-	result     interface{}
-	status     int
+type contextTracker struct {
+	service *Service
+
+	lock   sync.RWMutex
+	status *ViewStatus
 }
 
-func NewTracker() *defaultTracker {
-	return &defaultTracker{status: RUNNING}
+func (c *contextTracker) ViewStatus() *ViewStatus {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	status := *c.status
+	status.Reports = append([]Report(nil), c.status.Reports...)
+	return &status
+}
+
+func (c *contextTracker) Status() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.status.Status
 }
 
-func (d *defaultTracker) ViewStatus() *ViewStatus {
-	return &ViewStatus{
-		Status:     d.status,
-		LastReport: d.lastReport,
+func (c *contextTracker) Report(msg string) {
+	logger.Debugf(msg)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.status.LastReport = msg
+	c.status.Reports = append(c.status.Reports, Report{Time: time.Now(), Message: msg})
+}
+
+func (c *contextTracker) LatestReport() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.status.LastReport
+}
+
+func (c *contextTracker) Error(err error) {
+	logger.Errorf(err.Error())
+
+	c.lock.Lock()
+	c.status.Status = ERROR
+	c.status.LastReport = err.Error()
+	c.status.Error = err.Error()
+	c.status.End = time.Now()
+	c.store()
+	c.lock.Unlock()
+
+	c.service.release(c.ViewStatus())
+}
+
+func (c *contextTracker) Done(result interface{}) {
+	raw, ok := result.([]byte)
+	if !ok && result != nil {
+		var err error
+		raw, err = json.Marshal(result)
+		if err != nil {
+			logger.Errorf("failed marshalling result of context [%s]: [%s]", c.status.ContextID, err)
+		}
 	}
+
+	c.lock.Lock()
+	c.status.Status = DONE
+	c.status.Result = raw
+	c.status.End = time.Now()
+	c.store()
+	c.lock.Unlock()
+
+	c.service.release(c.ViewStatus())
 }
 
-func (d *defaultTracker) Status() int {
-	return d.status
+// store persists the status in the KVS, if available
+func (c *contextTracker) store() {
+	kvss := c.service.kvs()
+	if kvss == nil {
+		return
+	}
+	if err := kvss.Put(statusKey(c.status.ContextID), c.status); err != nil {
+		logger.Errorf("failed storing status of context [%s]: [%s]", c.status.ContextID, err)
+	}
+}
+
+// logTracker tracks a view running in a context not tracked by the service, it only logs the reports
+type logTracker struct {
+	lock   sync.RWMutex
+	status int
+	report string
 }
 
-func (d *defaultTracker) Report(msg string) {
+func (l *logTracker) Status() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.status
+}
+
+func (l *logTracker) Report(msg string) {
 	logger.Debugf(msg)
-	d.lastReport = msg
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.report = msg
 }
 
-func (d *defaultTracker) LatestReport() string {
-	return d.lastReport
+func (l *logTracker) LatestReport() string {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.report
 }
 
-func (d *defaultTracker) Error(err error) {
+func (l *logTracker) Error(err error) {
 	logger.Errorf(err.Error())
-	d.status = ERROR
-	d.lastReport = err.Error()
-	d.err = err
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.status = ERROR
+	l.report = err.Error()
 }
 
-func (d *defaultTracker) Done(result interface{}) {
-	d.status = DONE
-	d.result = result
+func (l *logTracker) Done(result interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.status = DONE
+}
+
+func (l *logTracker) ViewStatus() *ViewStatus {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return &ViewStatus{Status: l.status, LastReport: l.report}
+}
+
+type streamTracker struct {
+	ViewTracker
+	stream *view2.Stream
+}
+
+func (s *streamTracker) Report(msg string) {
+	s.ViewTracker.Report(msg)
+	if err := s.stream.Report(msg); err != nil {
+		logger.Debugf("failed streaming report [%s]: [%s]", msg, err)
+	}
 }
@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	_ "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver/memory"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracker"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type kvsConfig struct{}
+
+func (f *kvsConfig) GetString(key string) string                       { return "" }
+func (f *kvsConfig) GetDuration(key string) time.Duration              { return 0 }
+func (f *kvsConfig) GetBool(key string) bool                           { return false }
+func (f *kvsConfig) GetStringSlice(key string) []string                { return nil }
+func (f *kvsConfig) IsSet(key string) bool                             { return false }
+func (f *kvsConfig) UnmarshalKey(key string, rawVal interface{}) error { return nil }
+func (f *kvsConfig) ConfigFileUsed() string                            { return "" }
+func (f *kvsConfig) GetPath(key string) string                         { return "" }
+func (f *kvsConfig) TranslatePath(path string) string                  { return "" }
+
+func TestPerContextTracking(t *testing.T) {
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+	kvss, err := kvs.New("memory", "_default", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+
+	s := tracker.NewService(registry)
+	alice := s.Track("alice", "pay")
+	bob := s.Track("bob", "issue")
+
+	// reports do not interfere
+	alice.Report("alice step 1")
+	bob.Report("bob step 1")
+	alice.Report("alice step 2")
+	assert.Equal(t, "alice step 2", alice.LatestReport())
+	assert.Equal(t, "bob step 1", bob.LatestReport())
+
+	alice.Done("paid")
+	bob.Error(errors.New("issue failed"))
+
+	// terminated contexts are served from the kvs
+	status, err := s.ViewStatus("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, tracker.DONE, status.Status)
+	assert.Equal(t, "pay", status.FactoryID)
+	assert.Equal(t, []byte(`"paid"`), status.Result)
+	assert.Len(t, status.Reports, 2)
+	assert.Equal(t, "alice step 1", status.Reports[0].Message)
+	assert.False(t, status.End.Before(status.Start))
+
+	status, err = s.ViewStatus("bob")
+	assert.NoError(t, err)
+	assert.Equal(t, tracker.ERROR, status.Status)
+	assert.Equal(t, "issue failed", status.Error)
+
+	_, err = s.ViewStatus("charlie")
+	assert.Error(t, err)
+
+	// statuses survive a restart
+	s = tracker.NewService(registry)
+	charlie := s.Track("charlie", "pay")
+	statuses, err := s.ViewStatuses(&tracker.Filter{FactoryID: "pay"})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "charlie", statuses[0].ContextID)
+	assert.Equal(t, "alice", statuses[1].ContextID)
+
+	statuses, err = s.ViewStatuses(&tracker.Filter{Statuses: []int{tracker.RUNNING, tracker.ERROR}})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+
+	statuses, err = s.ViewStatuses(&tracker.Filter{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "charlie", statuses[0].ContextID)
+
+	charlie.Done(nil)
+	statuses, err = s.ViewStatuses(&tracker.Filter{Statuses: []int{tracker.RUNNING}})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 0)
+}
+
+func TestTrackerLifecycle(t *testing.T) {
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&kvsConfig{}))
+	kvss, err := kvs.New("memory", "_default", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+
+	s := tracker.NewService(registry)
+
+	// unknown contexts are not tracked
+	_, err = s.Tracker("unknown")
+	assert.Error(t, err)
+	_, err = s.ViewStatus("unknown")
+	assert.Error(t, err)
+
+	alice := s.Track("alice", "pay")
+	alice.Report("step 1")
+	at, err := s.Tracker("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "step 1", at.LatestReport())
+
+	// reports are persisted when the view terminates, a context interrupted by a restart resumes from its start
+	s = tracker.NewService(registry)
+	at, err = s.Tracker("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, tracker.RUNNING, at.Status())
+	assert.Empty(t, at.ViewStatus().Reports)
+	at.Report("step 2")
+	at.Done(nil)
+	s = tracker.NewService(registry)
+	status, err := s.ViewStatus("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, tracker.DONE, status.Status)
+	assert.Len(t, status.Reports, 1)
+
+	// terminated contexts cannot be tracked again
+	_, err = s.Tracker("alice")
+	assert.Error(t, err)
+
+	// statuses are evicted after the retention
+	s.Retention = time.Millisecond
+	bob := s.Track("bob", "pay")
+	time.Sleep(10 * time.Millisecond)
+	statuses, err := s.ViewStatuses(nil)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "bob", statuses[0].ContextID)
+	_, err = s.ViewStatus("alice")
+	assert.Error(t, err)
+
+	bob.Error(errors.New("failed"))
+	time.Sleep(10 * time.Millisecond)
+	statuses, err = s.ViewStatuses(nil)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 0)
+	_, err = s.ViewStatus("bob")
+	assert.Error(t, err)
+
+	// the same holds without a kvs
+	s = tracker.NewService(registry2.New())
+	s.Track("charlie", "pay").Done("ok")
+	status, err = s.ViewStatus("charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`"ok"`), status.Result)
+	s.Retention = time.Millisecond
+	time.Sleep(10 * time.Millisecond)
+	statuses, err = s.ViewStatuses(nil)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 0)
+}
+
+// viewContext names the embedded context, whose Context method would be shadowed by a field named Context
+type viewContext = view.Context
+
+type streamContext struct {
+	viewContext
+	sp view2.ServiceProvider
+	id string
+}
+
+func (c *streamContext) GetService(v interface{}) (interface{}, error) { return c.sp.GetService(v) }
+
+func (c *streamContext) ID() string { return c.id }
+
+type reportStream struct {
+	reports []string
+	err     error
+}
+
+func (r *reportStream) Send(output []byte) error { return nil }
+
+func (r *reportStream) Report(msg string) error {
+	r.reports = append(r.reports, msg)
+	return r.err
+}
+
+func (r *reportStream) Recv() ([]byte, error) { return nil, errors.New("no input") }
+
+type streamProvider struct {
+	streams map[string]driver.ViewStream
+}
+
+func (s *streamProvider) Stream(contextID string) (driver.ViewStream, error) {
+	stream, ok := s.streams[contextID]
+	if !ok {
+		return nil, errors.Errorf("no stream bound to context [%s]", contextID)
+	}
+	return stream, nil
+}
+
+func TestStreamTracking(t *testing.T) {
+	registry := registry2.New()
+	s := tracker.NewService(registry)
+	assert.NoError(t, registry.RegisterService(s))
+	alice, bob, charlie := &reportStream{}, &reportStream{err: errors.New("client gone")}, &reportStream{}
+	assert.NoError(t, registry.RegisterService(&streamProvider{streams: map[string]driver.ViewStream{
+		"alice": alice, "bob": bob, "charlie": charlie,
+	}}))
+
+	// the reports of a tracked streaming view reach both the status and the client
+	s.Track("alice", "pay")
+	at, err := tracker.GetViewTracker(&streamContext{sp: registry, id: "alice"})
+	assert.NoError(t, err)
+	at.Report("step 1")
+	at.Report("step 2")
+	assert.Equal(t, []string{"step 1", "step 2"}, alice.reports)
+	status, err := s.ViewStatus("alice")
+	assert.NoError(t, err)
+	assert.Len(t, status.Reports, 2)
+
+	// a client gone mid-stream does not fail the view
+	s.Track("bob", "pay")
+	bt, err := tracker.GetViewTracker(&streamContext{sp: registry, id: "bob"})
+	assert.NoError(t, err)
+	bt.Report("step 1")
+	assert.Equal(t, "step 1", bt.LatestReport())
+
+	// the reports of untracked contexts are streamed, but not recorded
+	ct, err := tracker.GetViewTracker(&streamContext{sp: registry, id: "charlie"})
+	assert.NoError(t, err)
+	ct.Report("step 1")
+	assert.Equal(t, []string{"step 1"}, charlie.reports)
+	_, err = s.ViewStatus("charlie")
+	assert.Error(t, err)
+
+	// contexts without stream are tracked as usual
+	s.Track("dave", "pay")
+	dt, err := tracker.GetViewTracker(&streamContext{sp: registry, id: "dave"})
+	assert.NoError(t, err)
+	dt.Report("step 1")
+	dt.Done(nil)
+	status, err = s.ViewStatus("dave")
+	assert.NoError(t, err)
+	assert.Equal(t, tracker.DONE, status.Status)
+}
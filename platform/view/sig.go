@@ -84,6 +84,11 @@ func (s *SigService) RegisterSigner(identity view.Identity, signer Signer, verif
 	return s.sigRegistry.RegisterSigner(identity, signer, verifier)
 }
 
+// UnregisterSigner removes the signer bound to the passed identity
+func (s *SigService) UnregisterSigner(identity view.Identity) error {
+	return s.sigRegistry.UnregisterSigner(identity)
+}
+
 // RegisterVerifier binds the passed identity to the passed verifier
 func (s *SigService) RegisterVerifier(identity view.Identity, verifier Verifier) error {
 	return s.sigRegistry.RegisterVerifier(identity, verifier)
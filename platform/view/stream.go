@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package view
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// Stream is the bidirectional channel between a view and the client that initiated it with a streaming invocation.
+// The view uses it to send intermediate outputs and status reports to the client, and to receive
+// further input from the client, for example a human approval.
+type Stream struct {
+	s driver.ViewStream
+}
+
+// Send delivers the passed intermediate output to the client
+func (s *Stream) Send(output []byte) error {
+	return s.s.Send(output)
+}
+
+// Report delivers the passed status report to the client
+func (s *Stream) Report(msg string) error {
+	return s.s.Report(msg)
+}
+
+// Recv blocks until the client sends further input. It returns an error if the client closed the stream.
+func (s *Stream) Recv() ([]byte, error) {
+	return s.s.Recv()
+}
+
+// GetStream returns the stream bound to the passed context.
+// It returns an error if the context was not initiated with a streaming invocation.
+func GetStream(context view.Context) (*Stream, error) {
+	provider, err := driver.GetViewStreamProvider(context)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "no stream provider available for context [%s]", context.ID())
+	}
+	s, err := provider.Stream(context.ID())
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{s: s}, nil
+}
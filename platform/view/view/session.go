@@ -16,13 +16,16 @@ const (
 )
 
 type Message struct {
-	SessionID    string // Session Identifier
-	ContextID    string // Context Identifier
-	Caller       string // View Identifier of the caller
-	FromEndpoint string // Endpoint identifier of the caller
-	FromPKID     []byte // PK identifier of the caller
-	Status       int32  // Message Status (OK, ERROR)
-	Payload      []byte // Payload
+	SessionID    string   // Session Identifier
+	ContextID    string   // Context Identifier
+	Caller       string   // View Identifier of the caller
+	FromEndpoint string   // Endpoint identifier of the caller
+	FromPKID     []byte   // PK identifier of the caller
+	FromIdentity Identity // Identity that signed the message, set only if the sender has been authenticated
+	Status       int32    // Message Status (OK, ERROR)
+	Payload      []byte   // Payload
+	TraceParent  string   // W3C trace context of the view that sent the message, empty if not traced
+	TraceState   string   // W3C trace state that accompanies TraceParent
 }
 
 func (m *Message) String() string {
@@ -40,6 +43,9 @@ type SessionInfo struct {
 	Endpoint     string
 	EndpointPKID []byte
 	Closed       bool
+	// Authenticated is true if the messages received on this session are signed by an identity bound to the endpoint.
+	// In this case, Caller, if set, is the identity that signed the first message of the session.
+	Authenticated bool
 }
 
 func (i *SessionInfo) String() string {